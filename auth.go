@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/influxdb/v2/kit/platform"
 	"github.com/influxdata/influxdb/v2/kit/platform/errors"
@@ -27,12 +28,24 @@ type Authorization struct {
 	UserID      platform.ID  `json:"userID,omitempty"`
 	Permissions []Permission `json:"permissions"`
 	CRUDLog
+
+	// PreviousToken is the token replaced by the last rotation of the token.
+	// It stays valid until PreviousTokenExpiresAt.
+	PreviousToken          string     `json:"previousToken,omitempty"`
+	PreviousTokenExpiresAt *time.Time `json:"previousTokenExpiresAt,omitempty"`
 }
 
 // AuthorizationUpdate is the authorization update request.
 type AuthorizationUpdate struct {
 	Status      *Status `json:"status,omitempty"`
 	Description *string `json:"description,omitempty"`
+
+	// RotateToken replaces the token of the authorization with a new one.
+	RotateToken bool `json:"rotateToken,omitempty"`
+	// TokenGracePeriodSeconds is how long the replaced token stays valid,
+	// so its clients can be moved to the new token. It is invalidated right
+	// away when it is zero.
+	TokenGracePeriodSeconds int64 `json:"tokenGracePeriodSeconds,omitempty"`
 }
 
 // Valid ensures that the authorization is valid.
@@ -71,6 +84,12 @@ func (a *Authorization) IsActive() bool {
 	return a.Status == Active
 }
 
+// PreviousTokenValid returns true if the token replaced by the last rotation
+// is still valid at now.
+func (a *Authorization) PreviousTokenValid(now time.Time) bool {
+	return a.PreviousToken != "" && a.PreviousTokenExpiresAt != nil && now.Before(*a.PreviousTokenExpiresAt)
+}
+
 // GetUserID returns the user id.
 func (a *Authorization) GetUserID() platform.ID {
 	return a.UserID
@@ -147,6 +147,8 @@ type authResponse struct {
 	Links       map[string]string    `json:"links"`
 	CreatedAt   time.Time            `json:"createdAt"`
 	UpdatedAt   time.Time            `json:"updatedAt"`
+	// PreviousTokenExpiresAt is set while the token replaced by a rotation is still valid.
+	PreviousTokenExpiresAt *time.Time `json:"previousTokenExpiresAt,omitempty"`
 }
 
 // In the future, we would like only the service layer to look up the user and org to see if they are valid
@@ -180,6 +182,9 @@ func (h *AuthHandler) newAuthResponse(ctx context.Context, a *influxdb.Authoriza
 		CreatedAt: a.CreatedAt,
 		UpdatedAt: a.UpdatedAt,
 	}
+	if a.PreviousTokenValid(time.Now()) {
+		res.PreviousTokenExpiresAt = a.PreviousTokenExpiresAt
+	}
 	return res, nil
 }
 
@@ -172,7 +172,8 @@ func (s *Service) FindAuthorizations(ctx context.Context, filter influxdb.Author
 	return as, len(as), nil
 }
 
-// UpdateAuthorization updates the status and description if available.
+// UpdateAuthorization updates the status and description if available,
+// and rotates the token when it is requested.
 func (s *Service) UpdateAuthorization(ctx context.Context, id platform.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
 	var auth *influxdb.Authorization
 	err := s.store.View(ctx, func(tx kv.Tx) error {
@@ -198,9 +199,33 @@ func (s *Service) UpdateAuthorization(ctx context.Context, id platform.ID, upd *
 		auth.Description = *upd.Description
 	}
 
-	auth.SetUpdatedAt(time.Now())
+	var token string
+	if upd.RotateToken {
+		if upd.TokenGracePeriodSeconds < 0 {
+			return nil, &errors.Error{
+				Code: errors.EInvalid,
+				Msg:  "token grace period must not be negative",
+			}
+		}
+		token, err = s.tokenGenerator.Token()
+		if err != nil {
+			return nil, &errors.Error{
+				Err: err,
+			}
+		}
+	}
+
+	now := time.Now()
+	auth.SetUpdatedAt(now)
 
 	err = s.store.Update(ctx, func(tx kv.Tx) error {
+		if upd.RotateToken {
+			gracePeriod := time.Duration(upd.TokenGracePeriodSeconds) * time.Second
+			if err := s.store.RotateToken(ctx, tx, auth, token, gracePeriod, now); err != nil {
+				return err
+			}
+		}
+
 		a, e := s.store.UpdateAuthorization(ctx, tx, id, auth)
 		if e != nil {
 			return e
@@ -6,6 +6,7 @@ import (
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/authorization"
+	"github.com/influxdata/influxdb/v2/kit/platform/errors"
 	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/tenant"
 	influxdbtesting "github.com/influxdata/influxdb/v2/testing"
@@ -65,3 +66,73 @@ func TestBoltAuthService(t *testing.T) {
 	t.Parallel()
 	influxdbtesting.AuthorizationService(initBoltAuthService, t)
 }
+
+func TestService_RotateToken(t *testing.T) {
+	ctx := context.Background()
+	s, closeBolt := influxdbtesting.NewTestBoltStore(t)
+	defer closeBolt()
+
+	user := &influxdb.User{Name: "user"}
+	org := &influxdb.Organization{Name: "org"}
+	svc, closeSvc := initAuthService(s, influxdbtesting.AuthorizationFields{
+		Users: []*influxdb.User{user},
+		Orgs:  []*influxdb.Organization{org},
+	}, t)
+	defer closeSvc()
+
+	auth := &influxdb.Authorization{
+		OrgID:  org.ID,
+		UserID: user.ID,
+		Permissions: []influxdb.Permission{
+			{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.BucketsResourceType, OrgID: &org.ID}},
+		},
+	}
+	if err := svc.CreateAuthorization(ctx, auth); err != nil {
+		t.Fatal(err)
+	}
+	first := auth.Token
+
+	// The replaced token stays valid during the grace period.
+	rotated, err := svc.UpdateAuthorization(ctx, auth.ID, &influxdb.AuthorizationUpdate{
+		RotateToken:             true,
+		TokenGracePeriodSeconds: 3600,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := rotated.Token
+	if second == "" || second == first {
+		t.Fatalf("expected a new token, got %q", second)
+	}
+	for _, token := range []string{first, second} {
+		a, err := svc.FindAuthorizationByToken(ctx, token)
+		if err != nil {
+			t.Fatalf("token %q: %v", token, err)
+		}
+		if a.ID != auth.ID || a.Token != second {
+			t.Fatalf("unexpected authorization: %+v", a)
+		}
+	}
+
+	// Without a grace period, the replaced token is invalidated right away,
+	// and so is the token replaced by the previous rotation.
+	rotated, err = svc.UpdateAuthorization(ctx, auth.ID, &influxdb.AuthorizationUpdate{RotateToken: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range []string{first, second} {
+		if _, err := svc.FindAuthorizationByToken(ctx, token); errors.ErrorCode(err) != errors.ENotFound {
+			t.Fatalf("expected token %q to be invalid, got %v", token, err)
+		}
+	}
+	if _, err := svc.FindAuthorizationByToken(ctx, rotated.Token); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.UpdateAuthorization(ctx, auth.ID, &influxdb.AuthorizationUpdate{
+		RotateToken:             true,
+		TokenGracePeriodSeconds: -1,
+	}); errors.ErrorCode(err) != errors.EInvalid {
+		t.Fatalf("expected an invalid error, got %v", err)
+	}
+}
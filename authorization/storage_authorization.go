@@ -3,6 +3,7 @@ package authorization
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/buger/jsonparser"
 	"github.com/influxdata/influxdb/v2"
@@ -166,7 +167,20 @@ func (s *Store) GetAuthorizationByToken(ctx context.Context, tx kv.Tx, token str
 		}
 	}
 
-	return s.GetAuthorizationByID(ctx, tx, id)
+	a, err := s.GetAuthorizationByID(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the token replaced by a rotation is only valid during its grace period
+	if token != a.Token && !(token == a.PreviousToken && a.PreviousTokenValid(time.Now())) {
+		return nil, &errors.Error{
+			Code: errors.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+
+	return a, nil
 }
 
 // ListAuthorizations returns all the authorizations matching a set of FindOptions. This function is used for
@@ -267,6 +281,41 @@ func (s *Store) UpdateAuthorization(ctx context.Context, tx kv.Tx, id platform.I
 
 }
 
+// RotateToken replaces the token of the authorization with token. The replaced token
+// stays valid until now plus the grace period, and the token replaced by a previous
+// rotation is invalidated. The authorization must then be saved with UpdateAuthorization.
+func (s *Store) RotateToken(ctx context.Context, tx kv.Tx, a *influxdb.Authorization, token string, gracePeriod time.Duration, now time.Time) error {
+	if err := unique(ctx, tx, authIndex, authIndexKey(token)); err != nil {
+		return ErrTokenAlreadyExistsError
+	}
+
+	idx, err := authIndexBucket(tx)
+	if err != nil {
+		return err
+	}
+
+	if a.PreviousToken != "" {
+		if err := idx.Delete(authIndexKey(a.PreviousToken)); err != nil {
+			return ErrInternalServiceError(err)
+		}
+	}
+
+	if gracePeriod > 0 {
+		expiresAt := now.Add(gracePeriod)
+		a.PreviousToken = a.Token
+		a.PreviousTokenExpiresAt = &expiresAt
+	} else {
+		if err := idx.Delete(authIndexKey(a.Token)); err != nil {
+			return ErrInternalServiceError(err)
+		}
+		a.PreviousToken = ""
+		a.PreviousTokenExpiresAt = nil
+	}
+
+	a.Token = token
+	return nil
+}
+
 // DeleteAuthorization removes an authorization from storage
 func (s *Store) DeleteAuthorization(ctx context.Context, tx kv.Tx, id platform.ID) error {
 	a, err := s.GetAuthorizationByID(ctx, tx, id)
@@ -293,6 +342,12 @@ func (s *Store) DeleteAuthorization(ctx context.Context, tx kv.Tx, id platform.I
 		return ErrInternalServiceError(err)
 	}
 
+	if a.PreviousToken != "" {
+		if err := idx.Delete([]byte(a.PreviousToken)); err != nil {
+			return ErrInternalServiceError(err)
+		}
+	}
+
 	if err := b.Delete(encodedID); err != nil {
 		return ErrInternalServiceError(err)
 	}
@@ -3,6 +3,7 @@ package authorizer
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/platform"
@@ -39,6 +40,11 @@ type taskServiceValidator struct {
 	log *zap.Logger
 }
 
+var (
+	_ taskmodel.TaskTrashService    = (*taskServiceValidator)(nil)
+	_ taskmodel.TaskRevisionService = (*taskServiceValidator)(nil)
+)
+
 // TaskService wraps ts and checks appropriate permissions before calling requested methods on ts.
 // Authorization failures are logged to the logger.
 func NewTaskService(log *zap.Logger, ts taskmodel.TaskService) taskmodel.TaskService {
@@ -253,3 +259,160 @@ func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID platform.ID
 	}
 	return ts.TaskService.ForceRun(ctx, taskID, scheduledFor)
 }
+
+func (ts *taskServiceValidator) revisions() (taskmodel.TaskRevisionService, error) {
+	revisions, ok := ts.TaskService.(taskmodel.TaskRevisionService)
+	if !ok {
+		return nil, taskmodel.ErrTaskRevisionsNotSupported
+	}
+	return revisions, nil
+}
+
+func (ts *taskServiceValidator) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*taskmodel.TaskRevision, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	revisions, err := ts.revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Unauthenticated task lookup, to identify the task's organization.
+	task, err := ts.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	a, p, err := AuthorizeRead(ctx, influxdb.TasksResourceType, task.ID, task.OrganizationID)
+	loggerFields := []zap.Field{zap.String("method", "FindTaskRevisions"), zap.Stringer("task_id", taskID)}
+	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
+		return nil, err
+	}
+	return revisions.FindTaskRevisions(ctx, taskID)
+}
+
+func (ts *taskServiceValidator) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*taskmodel.Task, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	revisions, err := ts.revisions()
+	if err != nil {
+		return nil, err
+	}
+
+	// Unauthenticated task lookup, to identify the task's organization.
+	task, err := ts.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	a, p, err := AuthorizeWrite(ctx, influxdb.TasksResourceType, task.ID, task.OrganizationID)
+	loggerFields := []zap.Field{zap.String("method", "RollbackTask"), zap.Stringer("task_id", taskID)}
+	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
+		return nil, err
+	}
+	return revisions.RollbackTask(ctx, taskID, revision)
+}
+
+func (ts *taskServiceValidator) trash() (taskmodel.TaskTrashService, error) {
+	trash, ok := ts.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+	return trash, nil
+}
+
+func (ts *taskServiceValidator) FindDeletedTasks(ctx context.Context, orgID platform.ID) ([]*taskmodel.DeletedTask, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	trash, err := ts.trash()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the deleted tasks in the organization, without authentication.
+	unauthenticatedTasks, err := trash.FindDeletedTasks(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	tasks := unauthenticatedTasks[:0]
+	for _, t := range unauthenticatedTasks {
+		_, _, err := AuthorizeRead(ctx, influxdb.TasksResourceType, t.Task.ID, t.Task.OrganizationID)
+		if err != nil && errors.ErrorCode(err) != errors.EUnauthorized {
+			return nil, err
+		}
+		if errors.ErrorCode(err) == errors.EUnauthorized {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func (ts *taskServiceValidator) FindDeletedTaskByID(ctx context.Context, id platform.ID) (*taskmodel.DeletedTask, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	trash, err := ts.trash()
+	if err != nil {
+		return nil, err
+	}
+
+	// Unauthenticated task lookup, to identify the task's organization.
+	task, err := trash.FindDeletedTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	a, p, err := AuthorizeRead(ctx, influxdb.TasksResourceType, task.Task.ID, task.Task.OrganizationID)
+	loggerFields := []zap.Field{zap.String("method", "FindDeletedTaskByID"), zap.Stringer("task_id", id)}
+	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (ts *taskServiceValidator) RestoreTask(ctx context.Context, id platform.ID) (*taskmodel.Task, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	trash, err := ts.trash()
+	if err != nil {
+		return nil, err
+	}
+
+	// Unauthenticated task lookup, to identify the task's organization.
+	task, err := trash.FindDeletedTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	a, p, err := AuthorizeWrite(ctx, influxdb.TasksResourceType, task.Task.ID, task.Task.OrganizationID)
+	loggerFields := []zap.Field{zap.String("method", "RestoreTask"), zap.Stringer("task_id", id)}
+	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
+		return nil, err
+	}
+	return trash.RestoreTask(ctx, id)
+}
+
+func (ts *taskServiceValidator) PurgeDeletedTasks(ctx context.Context, olderThan time.Duration) ([]*taskmodel.DeletedTask, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	trash, err := ts.trash()
+	if err != nil {
+		return nil, err
+	}
+
+	// Purging spans every organization.
+	a, p, err := AuthorizeWriteGlobal(ctx, influxdb.TasksResourceType)
+	loggerFields := []zap.Field{zap.String("method", "PurgeDeletedTasks")}
+	if err := ts.processPermissionError(a, p, err, loggerFields...); err != nil {
+		return nil, err
+	}
+	return trash.PurgeDeletedTasks(ctx, olderThan)
+}
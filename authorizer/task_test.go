@@ -607,3 +607,151 @@ func newStore(t *testing.T) kv.Store {
 
 	return store
 }
+
+type trashTaskService struct {
+	taskmodel.TaskService
+	deleted *taskmodel.DeletedTask
+}
+
+func (s *trashTaskService) FindDeletedTasks(context.Context, platform.ID) ([]*taskmodel.DeletedTask, error) {
+	return []*taskmodel.DeletedTask{s.deleted}, nil
+}
+
+func (s *trashTaskService) FindDeletedTaskByID(context.Context, platform.ID) (*taskmodel.DeletedTask, error) {
+	return s.deleted, nil
+}
+
+func (s *trashTaskService) RestoreTask(context.Context, platform.ID) (*taskmodel.Task, error) {
+	return s.deleted.Task, nil
+}
+
+func (s *trashTaskService) PurgeDeletedTasks(context.Context, time.Duration) ([]*taskmodel.DeletedTask, error) {
+	return []*taskmodel.DeletedTask{s.deleted}, nil
+}
+
+func TestTaskTrashValidations(t *testing.T) {
+	var (
+		orgID  = platform.ID(0x1)
+		taskID = platform.ID(0x7456)
+	)
+
+	svc := authorizer.NewTaskService(zaptest.NewLogger(t), &trashTaskService{
+		TaskService: &mock.TaskService{},
+		deleted: &taskmodel.DeletedTask{
+			Task:      &taskmodel.Task{ID: taskID, OrganizationID: orgID},
+			DeletedAt: time.Now().UTC(),
+		},
+	})
+	trash, ok := svc.(taskmodel.TaskTrashService)
+	if !ok {
+		t.Fatal("expected the authorized task service to support deleted tasks")
+	}
+
+	readTask := []influxdb.Permission{
+		{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &taskID}},
+	}
+	writeTask := []influxdb.Permission{
+		{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &taskID}},
+	}
+	otherOrg := platform.ID(0x2)
+	writeOtherOrg := []influxdb.Permission{
+		{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &otherOrg}},
+	}
+	authCtx := func(ps []influxdb.Permission) context.Context {
+		return pctx.SetAuthorizer(context.Background(), &influxdb.Authorization{Status: "active", Permissions: ps})
+	}
+
+	tasks, err := trash.FindDeletedTasks(authCtx(readTask), orgID)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("expected the readable deleted task, got %v, %v", tasks, err)
+	}
+	tasks, err = trash.FindDeletedTasks(authCtx(writeOtherOrg), orgID)
+	if err != nil || len(tasks) != 0 {
+		t.Fatalf("expected no deleted tasks, got %v, %v", tasks, err)
+	}
+
+	if _, err := trash.FindDeletedTaskByID(authCtx(readTask), taskID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trash.FindDeletedTaskByID(authCtx(writeOtherOrg), taskID); err == nil {
+		t.Fatal("expected finding the deleted task of another org to fail")
+	}
+
+	if _, err := trash.RestoreTask(authCtx(writeTask), taskID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trash.RestoreTask(authCtx(readTask), taskID); err == nil {
+		t.Fatal("expected restoring without write permission to fail")
+	}
+	if _, err := trash.RestoreTask(authCtx(writeOtherOrg), taskID); err == nil {
+		t.Fatal("expected restoring the task of another org to fail")
+	}
+
+	if _, err := trash.PurgeDeletedTasks(authCtx(writeTask), time.Hour); err == nil {
+		t.Fatal("expected purging without global write permission to fail")
+	}
+}
+
+type revisionTaskService struct {
+	taskmodel.TaskService
+	task *taskmodel.Task
+}
+
+func (s *revisionTaskService) FindTaskByID(context.Context, platform.ID) (*taskmodel.Task, error) {
+	return s.task, nil
+}
+
+func (s *revisionTaskService) FindTaskRevisions(context.Context, platform.ID) ([]*taskmodel.TaskRevision, error) {
+	return []*taskmodel.TaskRevision{{TaskID: s.task.ID, Revision: 0, Flux: "option task = {}"}}, nil
+}
+
+func (s *revisionTaskService) RollbackTask(context.Context, platform.ID, int64) (*taskmodel.Task, error) {
+	return s.task, nil
+}
+
+func TestTaskRevisionValidations(t *testing.T) {
+	var (
+		orgID  = platform.ID(0x1)
+		taskID = platform.ID(0x7456)
+	)
+
+	svc := authorizer.NewTaskService(zaptest.NewLogger(t), &revisionTaskService{
+		TaskService: &mock.TaskService{},
+		task:        &taskmodel.Task{ID: taskID, OrganizationID: orgID},
+	})
+	revisions, ok := svc.(taskmodel.TaskRevisionService)
+	if !ok {
+		t.Fatal("expected the authorized task service to support task revisions")
+	}
+
+	readTask := []influxdb.Permission{
+		{Action: influxdb.ReadAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &taskID}},
+	}
+	writeTask := []influxdb.Permission{
+		{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &orgID, ID: &taskID}},
+	}
+	otherOrg := platform.ID(0x2)
+	writeOtherOrg := []influxdb.Permission{
+		{Action: influxdb.WriteAction, Resource: influxdb.Resource{Type: influxdb.TasksResourceType, OrgID: &otherOrg}},
+	}
+	authCtx := func(ps []influxdb.Permission) context.Context {
+		return pctx.SetAuthorizer(context.Background(), &influxdb.Authorization{Status: "active", Permissions: ps})
+	}
+
+	if _, err := revisions.FindTaskRevisions(authCtx(readTask), taskID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := revisions.FindTaskRevisions(authCtx(writeOtherOrg), taskID); err == nil {
+		t.Fatal("expected listing the revisions of a task of another org to fail")
+	}
+
+	if _, err := revisions.RollbackTask(authCtx(writeTask), taskID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := revisions.RollbackTask(authCtx(readTask), taskID, 0); err == nil {
+		t.Fatal("expected rolling back without write permission to fail")
+	}
+	if _, err := revisions.RollbackTask(authCtx(writeOtherOrg), taskID, 0); err == nil {
+		t.Fatal("expected rolling back the task of another org to fail")
+	}
+}
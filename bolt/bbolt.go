@@ -104,6 +104,7 @@ func (c *Client) initialize(ctx context.Context) error {
 			telegrafPluginsBucket,
 			remoteBucket,
 			replicationBucket,
+			taskBucket,
 			userBucket,
 		}
 		for _, bktName := range bkts {
@@ -16,6 +16,7 @@ import (
 	"github.com/influxdata/influxdb/v2/kv/migration"
 	"github.com/influxdata/influxdb/v2/kv/migration/all"
 	"github.com/influxdata/influxdb/v2/pkg/fs"
+	"github.com/prometheus/client_golang/prometheus"
 	bolt "go.etcd.io/bbolt"
 	"go.uber.org/zap"
 )
@@ -30,6 +31,9 @@ type KVStore struct {
 	db   *bolt.DB
 	log  *zap.Logger
 
+	// txDuration records the latency of the view and update transactions.
+	txDuration *prometheus.HistogramVec
+
 	noSync bool
 }
 
@@ -49,6 +53,12 @@ func NewKVStore(log *zap.Logger, path string, opts ...KVOption) *KVStore {
 	store := &KVStore{
 		path: path,
 		log:  log,
+		txDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "boltdb",
+			Name:      "transaction_duration_seconds",
+			Help:      "Duration of the boltdb transactions, by type of transaction",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+		}, []string{"type"}),
 	}
 
 	for _, opt := range opts {
@@ -58,7 +68,7 @@ func NewKVStore(log *zap.Logger, path string, opts ...KVOption) *KVStore {
 	return store
 }
 
-// tempPath returns the path to the temporary file used by Restore().
+// tempPath returns the path to the temporary file used by Restore() and Compact().
 func (s *KVStore) tempPath() string {
 	return s.path + ".tmp"
 }
@@ -161,6 +171,7 @@ func (s *KVStore) View(ctx context.Context, fn func(tx kv.Tx) error) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	defer s.observeTx("view", time.Now())
 	return s.DB().View(func(tx *bolt.Tx) error {
 		return fn(&Tx{
 			tx:  tx,
@@ -174,6 +185,7 @@ func (s *KVStore) Update(ctx context.Context, fn func(tx kv.Tx) error) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	defer s.observeTx("update", time.Now())
 	return s.DB().Update(func(tx *bolt.Tx) error {
 		return fn(&Tx{
 			tx:  tx,
@@ -182,6 +194,15 @@ func (s *KVStore) Update(ctx context.Context, fn func(tx kv.Tx) error) error {
 	})
 }
 
+func (s *KVStore) observeTx(typ string, start time.Time) {
+	s.txDuration.WithLabelValues(typ).Observe(time.Since(start).Seconds())
+}
+
+// PrometheusCollectors returns the metrics of the transactions of the store.
+func (s *KVStore) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{s.txDuration}
+}
+
 // CreateBucket creates a bucket in the underlying boltdb store if it
 // does not already exist
 func (s *KVStore) CreateBucket(ctx context.Context, name []byte) error {
@@ -285,6 +306,57 @@ func (s *KVStore) migrateRestored(ctx context.Context) error {
 	return migrator.Up(ctx)
 }
 
+// compactTxMaxSize is the maximum size of the transactions copying the data during a compaction.
+const compactTxMaxSize = 64 << 20
+
+// Compact copies the data of the store into a new file and swaps it with the
+// current one. The new file doesn't contain the pages freed by the deleted data,
+// so it is usually smaller when a lot of data was deleted, like old task runs.
+func (s *KVStore) Compact(ctx context.Context) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := func() error {
+		dst, err := bolt.Open(s.tempPath(), 0600, &bolt.Options{Timeout: 1 * time.Second})
+		if err != nil {
+			return err
+		}
+		defer dst.Close()
+
+		// New transactions wait for the data to be copied and the files to be swapped.
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if err := bolt.Compact(dst, s.db, compactTxMaxSize); err != nil {
+			return err
+		} else if err := dst.Sync(); err != nil {
+			return err
+		} else if err := dst.Close(); err != nil {
+			return err
+		}
+
+		if err := s.db.Close(); err != nil {
+			return err
+		}
+
+		// Atomically swap temporary file with current DB file.
+		if err := fs.RenameFileWithReplacement(s.tempPath(), s.path); err != nil {
+			// Keep serving the current file, which was left in place.
+			if openErr := s.openDB(); openErr != nil {
+				return fmt.Errorf("%v, and reopening the current file failed: %v", err, openErr)
+			}
+			return err
+		}
+
+		// Reopen with new database file.
+		return s.openDB()
+	}(); err != nil {
+		os.Remove(s.tempPath()) // clean up on error
+		return err
+	}
+	return nil
+}
+
 // Tx is a light wrapper around a boltdb transaction. It implements kv.Tx.
 type Tx struct {
 	tx  *bolt.Tx
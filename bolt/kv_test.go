@@ -3,6 +3,7 @@ package bolt_test
 import (
 	"context"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/influxdata/influxdb/v2/kv"
@@ -53,3 +54,91 @@ func mustCreateBucket(t testing.TB, store kv.SchemaStore, bucket []byte) {
 		t.Fatal(err)
 	}
 }
+
+func TestKVStore_Compact(t *testing.T) {
+	s, closeFn, err := NewTestKVStore(t)
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	bucket := []byte("runs")
+	mustCreateBucket(t, s, bucket)
+
+	value := make([]byte, 1024)
+	key := func(i int) []byte { return []byte(fmt.Sprintf("run-%05d", i)) }
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(bucket)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 4096; i++ {
+			if err := b.Put(key(i), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to put keys: %v", err)
+	}
+
+	// Delete all the keys but the first one, the file keeps its size.
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(bucket)
+		if err != nil {
+			return err
+		}
+		for i := 1; i < 4096; i++ {
+			if err := b.Delete(key(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to delete keys: %v", err)
+	}
+
+	path := s.DB().Path()
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Compact(ctx); err != nil {
+		t.Fatalf("failed to compact: %v", err)
+	}
+
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size() >= before.Size() {
+		t.Fatalf("expected the file to shrink: got %d bytes, was %d bytes", after.Size(), before.Size())
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected the temporary file to be removed: %v", err)
+	}
+
+	// The remaining data is still readable and the store still writable.
+	err = s.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket(bucket)
+		if err != nil {
+			return err
+		}
+		if v, err := b.Get(key(0)); err != nil {
+			return err
+		} else if len(v) != len(value) {
+			return fmt.Errorf("unexpected value of %d bytes", len(v))
+		}
+		if _, err := b.Get(key(1)); !kv.IsNotFound(err) {
+			return fmt.Errorf("expected deleted key to be missing: %v", err)
+		}
+		return b.Put(key(1), value)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -23,6 +23,7 @@ var (
 	telegrafPluginsBucket = []byte("telegrafPluginsv1")
 	remoteBucket          = []byte("remotesv2")
 	replicationBucket     = []byte("replicationsv2")
+	taskBucket            = []byte("tasksv1")
 	userBucket            = []byte("usersv1")
 )
 
@@ -77,6 +78,11 @@ var (
 		"Number of total replication configurations on the server",
 		nil, nil)
 
+	tasksDesc = prometheus.NewDesc(
+		"influxdb_tasks_total",
+		"Number of total tasks on the server",
+		nil, nil)
+
 	boltWritesDesc = prometheus.NewDesc(
 		"boltdb_writes_total",
 		"Total number of boltdb writes",
@@ -99,6 +105,7 @@ func (c *Client) Describe(ch chan<- *prometheus.Desc) {
 	ch <- telegrafsDesc
 	ch <- remoteDesc
 	ch <- replicationDesc
+	ch <- tasksDesc
 	ch <- boltWritesDesc
 	ch <- boltReadsDesc
 
@@ -223,7 +230,7 @@ func (c *Client) Collect(ch chan<- prometheus.Metric) {
 
 	orgs, buckets, users, tokens := 0, 0, 0, 0
 	dashboards, scrapers, telegrafs := 0, 0, 0
-	remotes, replications := 0, 0
+	remotes, replications, tasks := 0, 0, 0
 	_ = c.db.View(func(tx *bolt.Tx) error {
 		buckets = tx.Bucket(bucketBucket).Stats().KeyN
 		dashboards = tx.Bucket(dashboardBucket).Stats().KeyN
@@ -232,6 +239,7 @@ func (c *Client) Collect(ch chan<- prometheus.Metric) {
 		telegrafs = tx.Bucket(telegrafBucket).Stats().KeyN
 		remotes = tx.Bucket(remoteBucket).Stats().KeyN
 		replications = tx.Bucket(replicationBucket).Stats().KeyN
+		tasks = tx.Bucket(taskBucket).Stats().KeyN
 		tokens = tx.Bucket(authorizationBucket).Stats().KeyN
 		users = tx.Bucket(userBucket).Stats().KeyN
 		return nil
@@ -291,5 +299,11 @@ func (c *Client) Collect(ch chan<- prometheus.Metric) {
 		float64(replications),
 	)
 
+	ch <- prometheus.MustNewConstMetric(
+		tasksDesc,
+		prometheus.CounterValue,
+		float64(tasks),
+	)
+
 	c.pluginsCollector.Collect(ch)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/influxdb/v2/bolt"
 	"github.com/influxdata/influxdb/v2/kit/prom"
 	"github.com/influxdata/influxdb/v2/kit/prom/promtest"
+	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/kv/migration/all"
 	telegrafservice "github.com/influxdata/influxdb/v2/telegraf/service"
 	"github.com/stretchr/testify/require"
@@ -40,6 +41,7 @@ func TestInitialMetrics(t *testing.T) {
 		"influxdb_dashboards_total":    0,
 		"influxdb_remotes_total":       0,
 		"influxdb_replications_total":  0,
+		"influxdb_tasks_total":         0,
 		"boltdb_reads_total":           0,
 	}
 	for name, count := range metrics {
@@ -50,6 +52,31 @@ func TestInitialMetrics(t *testing.T) {
 	}
 }
 
+func TestKVStoreTransactionMetrics(t *testing.T) {
+	t.Parallel()
+
+	client, teardown, err := NewTestClient(t)
+	require.NoError(t, err)
+	defer teardown()
+
+	ctx := context.Background()
+	kvStore := bolt.NewKVStore(zaptest.NewLogger(t), client.Path)
+	kvStore.WithDB(client.DB())
+
+	reg := prom.NewRegistry(zaptest.NewLogger(t))
+	reg.MustRegister(kvStore.PrometheusCollectors()...)
+
+	require.NoError(t, kvStore.View(ctx, func(kv.Tx) error { return nil }))
+	require.NoError(t, kvStore.View(ctx, func(kv.Tx) error { return nil }))
+	require.NoError(t, kvStore.Update(ctx, func(kv.Tx) error { return nil }))
+
+	mfs := promtest.MustGather(t, reg)
+	h := promtest.MustFindMetric(t, mfs, "boltdb_transaction_duration_seconds", map[string]string{"type": "view"})
+	require.Equal(t, uint64(2), h.GetHistogram().GetSampleCount())
+	h = promtest.MustFindMetric(t, mfs, "boltdb_transaction_duration_seconds", map[string]string{"type": "update"})
+	require.Equal(t, uint64(1), h.GetHistogram().GetSampleCount())
+}
+
 func TestPluginMetrics(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,70 @@
+package compact_bolt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/v2/bolt"
+	"github.com/influxdata/influxdb/v2/logger"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type args struct {
+	boltPath string
+}
+
+func NewCompactBoltCommand() *cobra.Command {
+	var arguments args
+	cmd := &cobra.Command{
+		Use:   "compact-bolt",
+		Short: "Compact the BoltDB file holding the metadata and the task runs",
+		Long: `Copies the data of the BoltDB file into a new file and replaces the old file with it.
+The pages freed by deleted data, like the old runs of tasks, are not copied, so the file shrinks.
+The server must be stopped while the file is compacted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config := logger.NewConfig()
+			config.Level = zapcore.InfoLevel
+
+			log, err := config.New(cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			return arguments.run(cmd, log)
+		},
+	}
+
+	cmd.Flags().StringVar(&arguments.boltPath, "bolt-path", filepath.Join(os.Getenv("HOME"), ".influxdbv2", bolt.DefaultFilename),
+		"Path to the BoltDB file.")
+	return cmd
+}
+
+func (a args) run(cmd *cobra.Command, log *zap.Logger) error {
+	// Don't create a new file when the path is wrong.
+	before, err := os.Stat(a.boltPath)
+	if err != nil {
+		return fmt.Errorf("invalid bolt path %q: %w", a.boltPath, err)
+	}
+
+	ctx := context.Background()
+	store := bolt.NewKVStore(log.With(zap.String("system", "bolt-kvstore")), a.boltPath)
+	if err := store.Open(ctx); err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if err := store.Compact(ctx); err != nil {
+		return fmt.Errorf("failed to compact %q: %w", a.boltPath, err)
+	}
+
+	after, err := os.Stat(a.boltPath)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Compacted %s from %d to %d bytes\n", a.boltPath, before.Size(), after.Size())
+	return nil
+}
@@ -0,0 +1,60 @@
+package compact_bolt
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/bolt"
+	"github.com/influxdata/influxdb/v2/kv"
+	"github.com/influxdata/influxdb/v2/kv/migration"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestCompactBolt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), bolt.DefaultFilename)
+	ctx := context.Background()
+
+	store := bolt.NewKVStore(zaptest.NewLogger(t), path, bolt.WithNoSync)
+	require.NoError(t, store.Open(ctx))
+	require.NoError(t, migration.CreateBuckets("create bucket", []byte("b")).Up(ctx, store))
+	require.NoError(t, store.Update(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("b"))
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("k"), []byte("v"))
+	}))
+	require.NoError(t, store.Close())
+
+	cmd := NewCompactBoltCommand()
+	cmd.SetArgs([]string{"--bolt-path", path})
+	out := bytes.NewBufferString("")
+	cmd.SetOut(out)
+	cmd.SetErr(bytes.NewBufferString(""))
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, out.String(), "Compacted "+path)
+
+	store = bolt.NewKVStore(zaptest.NewLogger(t), path)
+	require.NoError(t, store.Open(ctx))
+	defer store.Close()
+	require.NoError(t, store.View(ctx, func(tx kv.Tx) error {
+		b, err := tx.Bucket([]byte("b"))
+		if err != nil {
+			return err
+		}
+		v, err := b.Get([]byte("k"))
+		require.Equal(t, "v", string(v))
+		return err
+	}))
+}
+
+func TestCompactBolt_MissingFile(t *testing.T) {
+	cmd := NewCompactBoltCommand()
+	cmd.SetArgs([]string{"--bolt-path", filepath.Join(t.TempDir(), "missing.bolt")})
+	cmd.SetOut(bytes.NewBufferString(""))
+	cmd.SetErr(bytes.NewBufferString(""))
+	require.Error(t, cmd.Execute())
+}
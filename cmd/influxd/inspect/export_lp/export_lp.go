@@ -3,6 +3,7 @@ package export_lp
 import (
 	"bufio"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -34,6 +35,7 @@ type exportFlags struct {
 
 	outputPath string
 	compress   bool
+	partition  time.Duration
 
 	logLevel zapcore.Level
 }
@@ -142,6 +144,11 @@ to line protocol for inspection and re-ingestion.`,
 			Flag:  "compress",
 			Desc:  "if true, compress output with GZIP",
 		},
+		{
+			DestP: &flags.partition,
+			Flag:  "partition",
+			Desc:  "optional: export each partition of this duration of the start to end range into its own file under output-path. Partitions already exported are skipped, so a failed export can be resumed",
+		},
 		{
 			DestP:   &flags.logLevel,
 			Flag:    "log-level",
@@ -168,6 +175,10 @@ func exportRunE(cmd *cobra.Command, flags *exportFlags) error {
 		return err
 	}
 
+	if flags.partition > 0 {
+		return exportPartitions(flags, filters, logger)
+	}
+
 	var w io.Writer
 	if flags.outputPath == "-" {
 		w = cmd.OutOrStdout()
@@ -180,16 +191,25 @@ func exportRunE(cmd *cobra.Command, flags *exportFlags) error {
 		w = f
 	}
 
+	if err := export(flags, filters, w, logger); err != nil {
+		return err
+	}
+
+	logger.Info("export complete")
+	return nil
+}
+
+// export writes the data of the bucket matching the filters to w.
+func export(flags *exportFlags, filters *exportFilters, w io.Writer, logger *zap.Logger) error {
 	// Because calling (*os.File).Write is relatively expensive,
 	// and we don't *need* to sync to disk on every written line of export,
 	// use a sized buffered writer so that we only sync the file every megabyte.
 	bw := bufio.NewWriterSize(w, 1024*1024)
-	defer bw.Flush()
 	w = bw
 
+	var gzw *gzip.Writer
 	if flags.compress {
-		gzw := gzip.NewWriter(w)
-		defer gzw.Close()
+		gzw = gzip.NewWriter(w)
 		w = gzw
 	}
 
@@ -201,10 +221,96 @@ func exportRunE(cmd *cobra.Command, flags *exportFlags) error {
 		return err
 	}
 
+	if gzw != nil {
+		if err := gzw.Close(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// exportPartitions exports the data between the start and end times into a file
+// for each partition. The files are named after the start time of their partition.
+// A file is only created once its partition is completely exported, so the
+// partitions whose file already exists are skipped to resume a failed export.
+//
+// N.B. each partition reads the TSM and WAL files overlapping it again.
+func exportPartitions(flags *exportFlags, filters *exportFilters, logger *zap.Logger) error {
+	if flags.startTime == "" || flags.endTime == "" {
+		return errors.New("start and end must be set to export partitions")
+	}
+	if flags.outputPath == "-" {
+		return errors.New("output-path must be a directory to export partitions")
+	}
+	if err := os.MkdirAll(flags.outputPath, 0755); err != nil {
+		return err
+	}
+
+	ext := ".lp"
+	if flags.compress {
+		ext += ".gz"
+	}
+
+	start, end := filters.start, filters.end
+	if start >= end {
+		return errors.New("end must be after start to export partitions")
+	}
+	for pstart := start; pstart < end; pstart += int64(flags.partition) {
+		pfilters := *filters
+		pfilters.start = pstart
+		// The end of the filters is inclusive, the partitions don't overlap.
+		// The last partition also includes the end time.
+		pfilters.end = pstart + int64(flags.partition) - 1
+		if next := pstart + int64(flags.partition); next >= end || next < pstart {
+			pfilters.end = end
+		}
+
+		path := filepath.Join(flags.outputPath, time.Unix(0, pstart).UTC().Format("20060102T150405Z")+ext)
+		if _, err := os.Stat(path); err == nil {
+			logger.Info("skipping exported partition", zap.String("path", path))
+			continue
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+
+		logger.Info("exporting partition", zap.String("path", path),
+			zap.Time("start", time.Unix(0, pfilters.start)), zap.Time("end", time.Unix(0, pfilters.end)))
+		if err := exportPartition(flags, &pfilters, path, logger); err != nil {
+			return err
+		}
+		if pfilters.end == end {
+			break
+		}
+	}
+
 	logger.Info("export complete")
 	return nil
 }
 
+// exportPartition exports a partition into a temporary file renamed to path once complete.
+func exportPartition(flags *exportFlags, filters *exportFilters, path string, logger *zap.Logger) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := export(flags, filters, f, logger); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
 // exportTSMs finds, reads, and exports all data stored in TSM files for a bucket that matches a set of filters.
 func exportTSMs(engineDir string, bucketID platform.ID, filters *exportFilters, out io.Writer, log *zap.Logger) error {
 	// TSM is stored under `<engine>/data/<bucket-id>/<rp>/<shard-id>/*.tsm`
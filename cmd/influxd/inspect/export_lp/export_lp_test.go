@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/snappy"
+	"github.com/influxdata/influxdb/v2/kit/platform"
 	"github.com/influxdata/influxdb/v2/tsdb/engine/tsm1"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 )
@@ -435,3 +439,60 @@ func writeCorpusToTSMFile(c corpus) (*os.File, error) {
 
 	return tsmFile, nil
 }
+
+func Test_exportPartitions(t *testing.T) {
+	log := zaptest.NewLogger(t)
+	hour := int64(time.Hour)
+	c := corpus{
+		tsm1.SeriesFieldKey("m,k=v", "f"): []tsm1.Value{
+			tsm1.NewValue(0, float64(1)),
+			tsm1.NewValue(hour/2, float64(2)),
+			tsm1.NewValue(2*hour+1, float64(3)),
+			tsm1.NewValue(3*hour, float64(4)),
+			tsm1.NewValue(4*hour, float64(5)),
+		},
+	}
+
+	bucketID := platform.ID(1)
+	enginePath := t.TempDir()
+	shardDir := filepath.Join(enginePath, "data", bucketID.String(), "autogen", "1")
+	require.NoError(t, os.MkdirAll(shardDir, 0755))
+	tsmFile, err := writeCorpusToTSMFile(c)
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(tsmFile.Name(), filepath.Join(shardDir, "000000001-000000001.tsm")))
+
+	flags := newFlags()
+	flags.enginePath = enginePath
+	flags.bucketID = bucketID
+	flags.startTime = "1970-01-01T00:00:00Z"
+	flags.endTime = "1970-01-01T03:00:00Z"
+	flags.outputPath = filepath.Join(t.TempDir(), "export")
+	flags.partition = time.Hour
+	filters, err := flags.filters()
+	require.NoError(t, err)
+
+	// The partition already exported is kept as is.
+	require.NoError(t, os.MkdirAll(flags.outputPath, 0755))
+	existing := filepath.Join(flags.outputPath, "19700101T010000Z.lp")
+	require.NoError(t, os.WriteFile(existing, []byte("exported\n"), 0644))
+
+	require.NoError(t, exportPartitions(flags, filters, log))
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(flags.outputPath, name))
+		require.NoError(t, err)
+		return string(b)
+	}
+	entries, err := os.ReadDir(flags.outputPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, fmt.Sprintf("m,k=v f=1 0\nm,k=v f=2 %d\n", hour/2), read("19700101T000000Z.lp"))
+	require.Equal(t, "exported\n", read("19700101T010000Z.lp"))
+	// The end time is included in the last partition.
+	require.Equal(t, fmt.Sprintf("m,k=v f=3 %d\nm,k=v f=4 %d\n", 2*hour+1, 3*hour), read("19700101T020000Z.lp"))
+
+	flags.endTime = flags.startTime
+	filters, err = flags.filters()
+	require.NoError(t, err)
+	require.Error(t, exportPartitions(flags, filters, log))
+}
@@ -2,6 +2,7 @@ package inspect
 
 import (
 	"github.com/influxdata/influxdb/v2/cmd/influxd/inspect/build_tsi"
+	"github.com/influxdata/influxdb/v2/cmd/influxd/inspect/compact_bolt"
 	"github.com/influxdata/influxdb/v2/cmd/influxd/inspect/delete_tsm"
 	"github.com/influxdata/influxdb/v2/cmd/influxd/inspect/dump_tsi"
 	"github.com/influxdata/influxdb/v2/cmd/influxd/inspect/dump_tsm"
@@ -64,6 +65,7 @@ func NewCommand(v *viper.Viper) (*cobra.Command, error) {
 	base.AddCommand(verify_wal.NewVerifyWALCommand())
 	base.AddCommand(report_tsm.NewReportTSMCommand())
 	base.AddCommand(build_tsi.NewBuildTSICommand())
+	base.AddCommand(compact_bolt.NewCompactBoltCommand())
 	base.AddCommand(reportDB)
 	base.AddCommand(checkSchema)
 	base.AddCommand(mergeSchema)
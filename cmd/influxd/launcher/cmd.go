@@ -12,6 +12,7 @@ import (
 	"github.com/influxdata/influxdb/v2/fluxinit"
 	"github.com/influxdata/influxdb/v2/internal/fs"
 	"github.com/influxdata/influxdb/v2/kit/cli"
+	"github.com/influxdata/influxdb/v2/kit/platform"
 	"github.com/influxdata/influxdb/v2/kit/signals"
 	influxlogger "github.com/influxdata/influxdb/v2/logger"
 	"github.com/influxdata/influxdb/v2/pprof"
@@ -171,11 +172,26 @@ type InfluxdOpts struct {
 	MetricsDisabled   bool
 	UIDisabled        bool
 
+	SelfMonitoringOrgID    platform.ID
+	SelfMonitoringInterval time.Duration
+
+	ResourceAlertQueryQueueDepth  int
+	ResourceAlertTaskQueuePercent int
+	ResourceAlertBoltMaxBytes     int64
+	ResourceAlertWebhookURL       string
+
 	NatsPort            int
 	NatsMaxPayloadBytes int
 
-	NoTasks      bool
-	FeatureFlags map[string]string
+	NoTasks                        bool
+	TaskRunTimeout                 time.Duration
+	TaskTrashRetention             time.Duration
+	TaskOrgMaxTasks                int
+	TaskOrgMaxConcurrency          int
+	TaskOrgMaxTasksOverrides       map[string]string
+	TaskOrgMaxConcurrencyOverrides map[string]string
+	TaskEncryptionKeyPath          string
+	FeatureFlags                   map[string]string
 
 	// Query options.
 	ConcurrencyQuota                int32
@@ -183,6 +199,15 @@ type InfluxdOpts struct {
 	MemoryBytesQuotaPerQuery        int64
 	MaxMemoryBytes                  int64
 	QueueSize                       int32
+	QueryOrgConcurrencyOverrides    map[string]string
+	QueryOrgMemoryBytesOverrides    map[string]string
+	QueryResultRetention            time.Duration
+	QueryResultMaxBytes             int64
+	QueryResultMaxTotalBytes        int64
+	QueryResultMaxCount             int
+	QuerySigningMaxExpiry           time.Duration
+	QuerySigningKeyPath             string
+	FluxHTTPRequestsPerSecond       int
 	CoordinatorConfig               coordinator.Config
 
 	// Storage options.
@@ -225,19 +250,25 @@ func NewOpts(viper *viper.Viper) *InfluxdOpts {
 		MetricsDisabled:   false,
 		UIDisabled:        false,
 
+		SelfMonitoringInterval: time.Minute,
+
 		StoreType:   DiskStore,
 		SecretStore: BoltStore,
 
 		NatsPort:            0,
 		NatsMaxPayloadBytes: 0,
 
-		NoTasks: false,
+		NoTasks:            false,
+		TaskTrashRetention: 7 * 24 * time.Hour,
 
 		ConcurrencyQuota:                1024,
 		InitialMemoryBytesQuotaPerQuery: 0,
 		MemoryBytesQuotaPerQuery:        0,
 		MaxMemoryBytes:                  0,
 		QueueSize:                       1024,
+		QueryResultMaxBytes:             10 * 1024 * 1024,
+		QueryResultMaxTotalBytes:        100 * 1024 * 1024,
+		QueryResultMaxCount:             1000,
 
 		Testing:                 false,
 		TestingAlwaysAllowSetup: false,
@@ -442,6 +473,46 @@ func (o *InfluxdOpts) BindCliOpts() []cli.Opt {
 			Default: o.NoTasks,
 			Desc:    "disables the task scheduler",
 		},
+		{
+			DestP:   &o.TaskRunTimeout,
+			Flag:    "task-run-timeout",
+			Default: o.TaskRunTimeout,
+			Desc:    "fail task runs that have not finished after this long, so runs left behind by a crash stop counting against task concurrency. 0 disables expiring runs",
+		},
+		{
+			DestP:   &o.TaskTrashRetention,
+			Flag:    "task-trash-retention",
+			Default: o.TaskTrashRetention,
+			Desc:    "how long deleted tasks are kept so they can be restored before they are purged. 0 keeps deleted tasks forever",
+		},
+		{
+			DestP:   &o.TaskOrgMaxTasks,
+			Flag:    "task-org-max-tasks",
+			Default: o.TaskOrgMaxTasks,
+			Desc:    "the number of tasks an organization can own. 0 allows an unlimited number of tasks",
+		},
+		{
+			DestP:   &o.TaskOrgMaxConcurrency,
+			Flag:    "task-org-max-concurrency",
+			Default: o.TaskOrgMaxConcurrency,
+			Desc:    "the number of runs of all the tasks of an organization that can be scheduled or running at once. 0 allows an unlimited number of runs",
+		},
+		{
+			DestP: &o.TaskOrgMaxTasksOverrides,
+			Flag:  "task-org-max-tasks-overrides",
+			Desc:  "overrides task-org-max-tasks for some organizations, given as organization ID=number of tasks",
+		},
+		{
+			DestP: &o.TaskOrgMaxConcurrencyOverrides,
+			Flag:  "task-org-max-concurrency-overrides",
+			Desc:  "overrides task-org-max-concurrency for some organizations, given as organization ID=number of runs",
+		},
+		{
+			DestP:   &o.TaskEncryptionKeyPath,
+			Flag:    "task-encryption-key-path",
+			Default: o.TaskEncryptionKeyPath,
+			Desc:    "path to a file holding a 16, 24 or 32 byte AES key used to encrypt the names and scripts of tasks at rest",
+		},
 		{
 			DestP:   &o.ConcurrencyQuota,
 			Flag:    "query-concurrency",
@@ -472,6 +543,58 @@ func (o *InfluxdOpts) BindCliOpts() []cli.Opt {
 			Default: o.QueueSize,
 			Desc:    "the number of queries that are allowed to be awaiting execution before new queries are rejected. Must be > 0 if query-concurrency is not unlimited",
 		},
+		{
+			DestP: &o.QueryOrgConcurrencyOverrides,
+			Flag:  "query-org-concurrency-overrides",
+			Desc:  "the number of queries of some organizations that are allowed to execute concurrently, given as organization ID=number of queries. Overrides set through /api/v2/quotas/query take precedence until the next restart",
+		},
+		{
+			DestP: &o.QueryOrgMemoryBytesOverrides,
+			Flag:  "query-org-memory-bytes-overrides",
+			Desc:  "overrides query-memory-bytes for some organizations, given as organization ID=number of bytes. Overrides set through /api/v2/quotas/query take precedence until the next restart",
+		},
+		{
+			DestP:   &o.QueryResultRetention,
+			Flag:    "query-result-retention",
+			Default: o.QueryResultRetention,
+			Desc:    "how long the result of a Flux query is kept after it completes so a disconnected client can download it again. Only the results of the queries sent with the Influx-Query-Resumable: true header are kept, and these queries keep running when their client disconnects. Set to 0 to not keep results",
+		},
+		{
+			DestP:   &o.QueryResultMaxBytes,
+			Flag:    "query-result-max-bytes",
+			Default: o.QueryResultMaxBytes,
+			Desc:    "the maximum size of a query result kept for query-result-retention. Larger results are not kept",
+		},
+		{
+			DestP:   &o.QueryResultMaxTotalBytes,
+			Flag:    "query-result-max-total-bytes",
+			Default: o.QueryResultMaxTotalBytes,
+			Desc:    "the maximum size of all the query results kept for query-result-retention. The oldest completed results are evicted to make room. Set to 0 for no limit",
+		},
+		{
+			DestP:   &o.QueryResultMaxCount,
+			Flag:    "query-result-max-count",
+			Default: o.QueryResultMaxCount,
+			Desc:    "the maximum number of query results kept for query-result-retention. The oldest completed results are evicted to make room. Set to 0 for no limit",
+		},
+		{
+			DestP:   &o.QuerySigningMaxExpiry,
+			Flag:    "query-signing-max-expiry",
+			Default: o.QuerySigningMaxExpiry,
+			Desc:    "the longest time a signed query URL, fetched without a token, is valid for. Unless query-signing-key-path is set, the URLs are invalidated when the server restarts. Set to 0 to disable signed query URLs",
+		},
+		{
+			DestP:   &o.QuerySigningKeyPath,
+			Flag:    "query-signing-key-path",
+			Default: o.QuerySigningKeyPath,
+			Desc:    "path to a file holding a key of at least 32 bytes used to sign query URLs, so they stay valid when the server restarts. A random key is generated at startup if not set",
+		},
+		{
+			DestP:   &o.FluxHTTPRequestsPerSecond,
+			Flag:    "flux-http-requests-per-second",
+			Default: o.FluxHTTPRequestsPerSecond,
+			Desc:    "the maximum number of HTTP requests per second made by all the queries, such as http.post calls from alerting tasks. 0 is unlimited",
+		},
 		{
 			DestP: &o.FeatureFlags,
 			Flag:  "feature-flags",
@@ -600,6 +723,16 @@ func (o *InfluxdOpts) BindCliOpts() []cli.Opt {
 			Flag:  "influxql-max-select-buckets",
 			Desc:  "The maximum number of group by time bucket a SELECT can create. A value of zero will max the maximum number of buckets unlimited.",
 		},
+		{
+			DestP: &o.CoordinatorConfig.SelectReadAhead,
+			Flag:  "influxql-select-read-ahead",
+			Desc:  "Read ahead on every shard of a SELECT in a separate goroutine, overlapping storage reads with query processing at the cost of additional memory.",
+		},
+		{
+			DestP: &o.CoordinatorConfig.SelectShardConcurrency,
+			Flag:  "influxql-select-shard-concurrency",
+			Desc:  "The maximum number of shards a SELECT creates iterators for at once. A value of zero or one processes the shards one after another.",
+		},
 
 		// NATS config
 		{
@@ -632,6 +765,37 @@ func (o *InfluxdOpts) BindCliOpts() []cli.Opt {
 			Desc:    "Don't expose metrics over HTTP at /metrics",
 			Default: o.MetricsDisabled,
 		},
+		{
+			DestP: &o.SelfMonitoringOrgID,
+			Flag:  "self-monitoring-org-id",
+			Desc:  "write the query controller, task and HTTP API metrics into the _monitoring bucket of this organization",
+		},
+		{
+			DestP:   &o.SelfMonitoringInterval,
+			Flag:    "self-monitoring-interval",
+			Default: o.SelfMonitoringInterval,
+			Desc:    "interval between each write of the metrics when self-monitoring-org-id is set",
+		},
+		{
+			DestP: &o.ResourceAlertQueryQueueDepth,
+			Flag:  "resource-alert-query-queue-depth",
+			Desc:  "alert when more queries than this are queued by the query controller. Set to 0 to disable",
+		},
+		{
+			DestP: &o.ResourceAlertTaskQueuePercent,
+			Flag:  "resource-alert-task-queue-percent",
+			Desc:  "alert when the task runs waiting for a worker fill more than this percentage of the task executor queue. Set to 0 to disable",
+		},
+		{
+			DestP: &o.ResourceAlertBoltMaxBytes,
+			Flag:  "resource-alert-bolt-max-bytes",
+			Desc:  "alert when the bolt file is larger than this number of bytes. Set to 0 to disable",
+		},
+		{
+			DestP: &o.ResourceAlertWebhookURL,
+			Flag:  "resource-alert-webhook-url",
+			Desc:  "URL the resource alerts are posted to as JSON. The alerts are checked every self-monitoring-interval, logged, and written into the _monitoring bucket of self-monitoring-org-id when it is set",
+		},
 		// UI Config
 		{
 			DestP:   &o.UIDisabled,
@@ -2,6 +2,7 @@ package launcher
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	nethttp "net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -50,6 +52,7 @@ import (
 	endpointservice "github.com/influxdata/influxdb/v2/notification/endpoint/service"
 	ruleservice "github.com/influxdata/influxdb/v2/notification/rule/service"
 	"github.com/influxdata/influxdb/v2/pkger"
+	"github.com/influxdata/influxdb/v2/preference"
 	infprom "github.com/influxdata/influxdb/v2/prometheus"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/query/control"
@@ -263,6 +266,25 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 
 	serviceConfig := kv.ServiceConfig{
 		FluxLanguageService: fluxlang.DefaultService,
+		TaskQuota: taskmodel.OrgQuota{
+			MaxTasks:       opts.TaskOrgMaxTasks,
+			MaxConcurrency: opts.TaskOrgMaxConcurrency,
+		},
+	}
+	if serviceConfig.TaskQuotaOverrides, err = taskQuotaOverrides(opts, serviceConfig.TaskQuota); err != nil {
+		m.log.Error("Failed to configure task quotas", zap.Error(err))
+		return err
+	}
+	if opts.TaskEncryptionKeyPath != "" {
+		key, err := os.ReadFile(opts.TaskEncryptionKeyPath)
+		if err != nil {
+			m.log.Error("Failed to read task encryption key", zap.Error(err))
+			return err
+		}
+		if serviceConfig.TaskCipher, err = kv.NewTaskCipher(key); err != nil {
+			m.log.Error("Failed to configure task encryption", zap.Error(err))
+			return err
+		}
 	}
 
 	m.kvService = kv.NewService(m.log.With(zap.String("store", "kv")), m.kvStore, ts, serviceConfig)
@@ -406,6 +428,7 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 		authorizer.NewSecretService(secretSvc),
 		nil,
 		influxdb.WithURLValidator(urlValidator),
+		influxdb.WithHTTPRequestLimit(opts.FluxHTTPRequestsPerSecond),
 	)
 	if err != nil {
 		m.log.Error("Failed to get query controller dependencies", zap.Error(err))
@@ -418,6 +441,11 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 		dependencyList = append(dependencyList, testing.FrameworkConfig{})
 	}
 
+	orgQuotas, err := queryOrgQuotas(opts)
+	if err != nil {
+		m.log.Error("Failed to configure query quotas", zap.Error(err))
+		return err
+	}
 	m.queryController, err = control.New(control.Config{
 		ConcurrencyQuota:                opts.ConcurrencyQuota,
 		InitialMemoryBytesQuotaPerQuery: opts.InitialMemoryBytesQuotaPerQuery,
@@ -426,6 +454,7 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 		QueueSize:                       opts.QueueSize,
 		ExecutorDependencies:            dependencyList,
 		FluxLogEnabled:                  opts.FluxLogEnabled,
+		OrgQuotas:                       orgQuotas,
 	}, m.log.With(zap.String("service", "storage-reads")))
 	if err != nil {
 		m.log.Error("Failed to create query controller", zap.Error(err))
@@ -443,6 +472,9 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
 	var taskSvc taskmodel.TaskService
 	{
+		// the scripts of encrypted tasks are kept out of their runs
+		redactScripts := serviceConfig.TaskCipher != nil
+
 		// create the task stack
 		combinedTaskService := taskbackend.NewAnalyticalStorage(
 			m.log.With(zap.String("service", "task-analytical-store")),
@@ -451,6 +483,7 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 			m.kvService,
 			pointsWriter,
 			query.QueryServiceBridge{AsyncQueryService: m.queryController},
+			taskbackend.WithRedactedScripts(redactScripts),
 		)
 
 		executor, executorMetrics := executor.NewExecutor(
@@ -458,14 +491,22 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 			query.QueryServiceBridge{AsyncQueryService: m.queryController},
 			ts.UserService,
 			combinedTaskService,
-			combinedTaskService,
+			taskbackend.NewTaskControlMetrics(m.reg, combinedTaskService),
 			executor.WithFlagger(m.flagger),
+			executor.WithRedactedScripts(redactScripts),
 		)
 		err = executor.LoadExistingScheduleRuns(ctx)
 		if err != nil {
 			m.log.Fatal("could not load existing scheduled runs", zap.Error(err))
 		}
 		m.executor = executor
+		m.closers = append(m.closers, labeledCloser{
+			label: "task-executor",
+			closer: func(context.Context) error {
+				executor.Close()
+				return nil
+			},
+		})
 		m.reg.MustRegister(executorMetrics.PrometheusCollectors()...)
 		schLogger := m.log.With(zap.String("service", "task-scheduler"))
 
@@ -497,6 +538,32 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 				},
 			})
 			m.reg.MustRegister(sm.PrometheusCollectors()...)
+
+			if opts.TaskRunTimeout > 0 {
+				reaperCtx, cancelReaper := context.WithCancel(ctx)
+				reaper := taskbackend.NewRunReaper(m.log.With(zap.String("service", "task-run-reaper")), m.kvService, opts.TaskRunTimeout)
+				go reaper.Run(reaperCtx)
+				m.closers = append(m.closers, labeledCloser{
+					label: "task-run-reaper",
+					closer: func(context.Context) error {
+						cancelReaper()
+						return nil
+					},
+				})
+			}
+
+			if opts.TaskTrashRetention > 0 {
+				purgerCtx, cancelPurger := context.WithCancel(ctx)
+				purger := taskbackend.NewTaskPurger(m.log.With(zap.String("service", "task-purger")), m.kvService, opts.TaskTrashRetention)
+				go purger.Run(purgerCtx)
+				m.closers = append(m.closers, labeledCloser{
+					label: "task-purger",
+					closer: func(context.Context) error {
+						cancelPurger()
+						return nil
+					},
+				})
+			}
 		}
 
 		m.scheduler = sch
@@ -540,13 +607,15 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 
 	qe := iqlquery.NewExecutor(m.log, cm)
 	se := &iqlcoordinator.StatementExecutor{
-		MetaClient:        metaClient,
-		TSDBStore:         m.engine.TSDBStore(),
-		ShardMapper:       mapper,
-		DBRP:              dbrpSvc,
-		MaxSelectPointN:   opts.CoordinatorConfig.MaxSelectPointN,
-		MaxSelectSeriesN:  opts.CoordinatorConfig.MaxSelectSeriesN,
-		MaxSelectBucketsN: opts.CoordinatorConfig.MaxSelectBucketsN,
+		MetaClient:             metaClient,
+		TSDBStore:              m.engine.TSDBStore(),
+		ShardMapper:            mapper,
+		DBRP:                   dbrpSvc,
+		MaxSelectPointN:        opts.CoordinatorConfig.MaxSelectPointN,
+		MaxSelectSeriesN:       opts.CoordinatorConfig.MaxSelectSeriesN,
+		MaxSelectBucketsN:      opts.CoordinatorConfig.MaxSelectBucketsN,
+		SelectReadAhead:        opts.CoordinatorConfig.SelectReadAhead,
+		SelectShardConcurrency: opts.CoordinatorConfig.SelectShardConcurrency,
 	}
 	qe.StatementExecutor = se
 	qe.StatementNormalizer = se
@@ -594,6 +663,58 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 		},
 	})
 
+	if opts.SelfMonitoringOrgID.Valid() {
+		selfMonitor := gather.NewSelfMonitor(m.log.With(zap.String("service", "self-monitor")), m.reg, ts.BucketService, pointsWriter, opts.SelfMonitoringOrgID, opts.SelfMonitoringInterval)
+		selfMonitor.Open()
+		m.closers = append(m.closers, labeledCloser{
+			label: "self-monitor",
+			closer: func(ctx context.Context) error {
+				selfMonitor.Close()
+				return nil
+			},
+		})
+	}
+
+	var resourceChecks []gather.ResourceCheck
+	if opts.ResourceAlertQueryQueueDepth > 0 {
+		resourceChecks = append(resourceChecks, gather.ResourceCheck{
+			Name:      "query_queue_depth",
+			Threshold: float64(opts.ResourceAlertQueryQueueDepth),
+			Usage:     gather.GaugeUsage(m.reg, "qc_queueing_active"),
+		})
+	}
+	if opts.ResourceAlertTaskQueuePercent > 0 {
+		resourceChecks = append(resourceChecks, gather.ResourceCheck{
+			Name:      "task_queue_percent",
+			Threshold: float64(opts.ResourceAlertTaskQueuePercent),
+			Usage: func() (float64, error) {
+				return 100 * m.executor.PromiseQueueUsage(), nil
+			},
+		})
+	}
+	if opts.ResourceAlertBoltMaxBytes > 0 {
+		resourceChecks = append(resourceChecks, gather.ResourceCheck{
+			Name:      "bolt_file_bytes",
+			Threshold: float64(opts.ResourceAlertBoltMaxBytes),
+			Usage:     gather.FileSizeUsage(opts.BoltPath),
+		})
+	}
+	if len(resourceChecks) > 0 {
+		resourceAlerter := gather.NewResourceAlerter(m.log.With(zap.String("service", "resource-alerter")), ts.BucketService, pointsWriter, resourceChecks, opts.SelfMonitoringInterval)
+		resourceAlerter.OrgID = opts.SelfMonitoringOrgID
+		if opts.ResourceAlertWebhookURL != "" {
+			resourceAlerter.Notify = gather.WebhookNotifier(&nethttp.Client{Timeout: 10 * time.Second}, opts.ResourceAlertWebhookURL)
+		}
+		resourceAlerter.Open()
+		m.closers = append(m.closers, labeledCloser{
+			label: "resource-alerter",
+			closer: func(ctx context.Context) error {
+				resourceAlerter.Close()
+				return nil
+			},
+		})
+	}
+
 	var sessionSvc platform.SessionService
 	{
 		sessionSvc = session.NewService(
@@ -742,6 +863,28 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 		FlagsHandler:                    feature.NewFlagsHandler(errorHandler, feature.ByKey),
 	}
 
+	if opts.QueryResultRetention > 0 {
+		m.apibackend.QueryResults = http.NewQueryResultStore(opts.QueryResultRetention, opts.QueryResultMaxBytes, opts.QueryResultMaxTotalBytes, opts.QueryResultMaxCount)
+	}
+	if opts.QuerySigningMaxExpiry > 0 {
+		key := make([]byte, 32)
+		if opts.QuerySigningKeyPath != "" {
+			if key, err = os.ReadFile(opts.QuerySigningKeyPath); err != nil {
+				m.log.Error("Failed to read the query signing key", zap.Error(err))
+				return err
+			}
+			if len(key) < 32 {
+				err := fmt.Errorf("query signing key in %s must be at least 32 bytes, got %d", opts.QuerySigningKeyPath, len(key))
+				m.log.Error("Failed to configure query signing", zap.Error(err))
+				return err
+			}
+		} else if _, err := rand.Read(key); err != nil {
+			m.log.Error("Failed to generate the query signing key", zap.Error(err))
+			return err
+		}
+		m.apibackend.QuerySigner = http.NewQuerySigner(key, opts.QuerySigningMaxExpiry)
+	}
+
 	m.reg.MustRegister(m.apibackend.PrometheusCollectors()...)
 
 	authAgent := new(authorizer.AuthAgent)
@@ -857,6 +1000,7 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 			labelSvc,
 			ts.UserService,
 			ts.OrganizationService,
+			authorizer.NewTaskService(m.log.With(zap.String("handler", "dashboards")), taskSvc),
 			urmHandler,
 			labelHandler,
 		)
@@ -889,6 +1033,18 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 		return err
 	}
 
+	preferenceStore, err := preference.NewStore(m.kvStore)
+	if err != nil {
+		m.log.Error("Failed creating new preferences store", zap.Error(err))
+		return err
+	}
+	preferenceServer := preference.NewPreferenceHandler(
+		m.log.With(zap.String("handler", "preferences")),
+		preference.NewService(preferenceStore),
+	)
+
+	queryQuotaHandler := http.NewQueryQuotaHandler(m.log.With(zap.String("handler", "query_quotas")), m.queryController)
+
 	platformHandler := http.NewPlatformHandler(
 		m.apibackend,
 		http.WithResourceHandler(stacksHTTPServer),
@@ -909,6 +1065,8 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 		http.WithResourceHandler(remotesServer),
 		http.WithResourceHandler(replicationServer),
 		http.WithResourceHandler(configHandler),
+		http.WithResourceHandler(queryQuotaHandler),
+		http.WithResourceHandler(preferenceServer),
 	)
 
 	httpLogger := m.log.With(zap.String("service", "http"))
@@ -940,6 +1098,81 @@ func (m *Launcher) run(ctx context.Context, opts *InfluxdOpts) (err error) {
 
 // initTracing sets up the global tracer for the influxd process.
 // Any errors encountered during setup are logged, but don't crash the process.
+// taskQuotaOverrides returns the task quotas of the organizations given their own limits.
+// An organization given only one of the limits keeps the default for the other.
+func taskQuotaOverrides(opts *InfluxdOpts, defaults taskmodel.OrgQuota) (map[platform2.ID]taskmodel.OrgQuota, error) {
+	maxTasks, err := parseOrgLimits("task-org-max-tasks-overrides", opts.TaskOrgMaxTasksOverrides, strconv.IntSize)
+	if err != nil {
+		return nil, err
+	}
+	maxConcurrency, err := parseOrgLimits("task-org-max-concurrency-overrides", opts.TaskOrgMaxConcurrencyOverrides, strconv.IntSize)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := make(map[platform2.ID]taskmodel.OrgQuota)
+	for orgID, n := range maxTasks {
+		quota, ok := quotas[orgID]
+		if !ok {
+			quota = defaults
+		}
+		quota.MaxTasks = int(n)
+		quotas[orgID] = quota
+	}
+	for orgID, n := range maxConcurrency {
+		quota, ok := quotas[orgID]
+		if !ok {
+			quota = defaults
+		}
+		quota.MaxConcurrency = int(n)
+		quotas[orgID] = quota
+	}
+	return quotas, nil
+}
+
+// queryOrgQuotas returns the query quotas of the organizations given their own limits.
+// The limits an organization is not given keep the defaults of the query controller.
+func queryOrgQuotas(opts *InfluxdOpts) (map[platform2.ID]control.OrgQuota, error) {
+	concurrency, err := parseOrgLimits("query-org-concurrency-overrides", opts.QueryOrgConcurrencyOverrides, 32)
+	if err != nil {
+		return nil, err
+	}
+	memoryBytes, err := parseOrgLimits("query-org-memory-bytes-overrides", opts.QueryOrgMemoryBytesOverrides, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := make(map[platform2.ID]control.OrgQuota)
+	for orgID, n := range concurrency {
+		quota := quotas[orgID]
+		quota.ConcurrencyQuota = int32(n)
+		quotas[orgID] = quota
+	}
+	for orgID, n := range memoryBytes {
+		quota := quotas[orgID]
+		quota.MemoryBytesQuotaPerQuery = n
+		quotas[orgID] = quota
+	}
+	return quotas, nil
+}
+
+// parseOrgLimits parses the limits of some organizations given to flag as organization ID=limit.
+func parseOrgLimits(flag string, limits map[string]string, bitSize int) (map[platform2.ID]int64, error) {
+	parsed := make(map[platform2.ID]int64, len(limits))
+	for org, v := range limits {
+		orgID, err := platform2.IDFromString(org)
+		if err != nil {
+			return nil, fmt.Errorf("invalid organization ID %q in %s: %w", org, flag, err)
+		}
+		n, err := strconv.ParseInt(v, 10, bitSize)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid limit %q for organization %s in %s", v, org, flag)
+		}
+		parsed[*orgID] = n
+	}
+	return parsed, nil
+}
+
 func (m *Launcher) initTracing(opts *InfluxdOpts) {
 	switch opts.TracingType {
 	case LogTracing:
@@ -1004,6 +1237,7 @@ func (m *Launcher) openMetaStores(ctx context.Context, opts *InfluxdOpts) (strin
 
 		boltKV := bolt.NewKVStore(m.log.With(zap.String("service", "kvstore-bolt")), opts.BoltPath)
 		boltKV.WithDB(boltClient.DB())
+		m.reg.MustRegister(boltKV.PrometheusCollectors()...)
 		kvStore = boltKV
 
 		// If a sqlite-path is not specified, store sqlite db in the same directory as bolt with the default filename.
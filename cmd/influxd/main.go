@@ -56,6 +56,7 @@ func main() {
 		handleErr(err.Error())
 	}
 	rootCmd.AddCommand(downgradeCmd)
+	rootCmd.AddCommand(completionCmd(rootCmd))
 
 	rootCmd.SilenceUsage = true
 	if err := rootCmd.Execute(); err != nil {
@@ -77,3 +78,34 @@ func versionCmd() *cobra.Command {
 		},
 	}
 }
+
+func completionCmd(rootCmd *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generates completion scripts",
+		Long: `Outputs the shell completion script for the given shell.
+
+To load the completions of influxd in the current bash session:
+
+	source <(influxd completion bash)
+
+To load them in every new zsh session:
+
+	influxd completion zsh > "${fpath[1]}/_influxd"`,
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return rootCmd.GenBashCompletion(w)
+			case "zsh":
+				return rootCmd.GenZshCompletion(w)
+			case "fish":
+				return rootCmd.GenFishCompletion(w, true)
+			default:
+				return rootCmd.GenPowerShellCompletion(w)
+			}
+		},
+	}
+}
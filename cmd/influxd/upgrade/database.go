@@ -12,6 +12,7 @@ import (
 	"github.com/influxdata/influx-cli/v2/clients"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/platform"
+	errors2 "github.com/influxdata/influxdb/v2/kit/platform/errors"
 	"github.com/influxdata/influxdb/v2/pkg/fs"
 	"github.com/influxdata/influxdb/v2/v1/services/meta"
 	"go.uber.org/zap"
@@ -66,20 +67,10 @@ func upgradeDatabases(ctx context.Context, cli clients.CLI, v1 *influxDBv1, v2 *
 		for _, rp := range db.RetentionPolicies {
 			sourcePath := filepath.Join(v1opts.dataDir, db.Name, rp.Name)
 
-			bucket := &influxdb.Bucket{
-				OrgID:               orgID,
-				Type:                influxdb.BucketTypeUser,
-				Name:                db.Name + "/" + rp.Name,
-				Description:         fmt.Sprintf("Upgraded from v1 database %s with retention policy %s", db.Name, rp.Name),
-				RetentionPolicyName: rp.Name,
-				RetentionPeriod:     rp.Duration,
-				ShardGroupDuration:  rp.ShardGroupDuration,
-			}
-			log.Debug("Creating bucket", zap.String("Bucket", bucket.Name))
-			err = v2.bucketSvc.CreateBucket(ctx, bucket)
+			// The databases of the buckets are created below, along with their shards.
+			bucket, err := createBucketAndMapping(ctx, v2, v2.bucketSvc, orgID, &db, &rp, "Upgraded", log)
 			if err != nil {
-				return nil, fmt.Errorf("error creating bucket %s: %w", bucket.Name, err)
-
+				return nil, err
 			}
 
 			db2BucketIds[db.Name] = append(db2BucketIds[db.Name], bucket.ID)
@@ -91,24 +82,6 @@ func upgradeDatabases(ctx context.Context, cli clients.CLI, v1 *influxDBv1, v2 *
 				return nil, fmt.Errorf("error creating database %s: %w", bucket.ID.String(), err)
 			}
 
-			mapping := &influxdb.DBRPMapping{
-				Database:        db.Name,
-				RetentionPolicy: rp.Name,
-				Default:         db.DefaultRetentionPolicy == rp.Name,
-				OrganizationID:  orgID,
-				BucketID:        bucket.ID,
-			}
-			log.Info(
-				"Creating mapping",
-				zap.String("database", mapping.Database),
-				zap.String("retention policy", mapping.RetentionPolicy),
-				zap.String("orgID", mapping.OrganizationID.String()),
-				zap.String("bucketID", mapping.BucketID.String()),
-			)
-			err = v2.dbrpSvc.Create(ctx, mapping)
-			if err != nil {
-				return nil, fmt.Errorf("error creating mapping  %s/%s -> Org %s, bucket %s: %w", mapping.Database, mapping.RetentionPolicy, mapping.OrganizationID.String(), mapping.BucketID.String(), err)
-			}
 			shardsNum := 0
 			for _, sg := range rp.ShardGroups {
 				log.Debug(
@@ -196,6 +169,68 @@ func upgradeDatabases(ctx context.Context, cli clients.CLI, v1 *influxDBv1, v2 *
 	return db2BucketIds, nil
 }
 
+// createBucketAndMapping creates with bucketSvc the bucket of a 1.x database and retention policy,
+// named db-name/rp-name, and its DBRP mapping. The description of the bucket starts with verb.
+// An existing bucket or mapping is kept, so that the metadata can be imported again.
+func createBucketAndMapping(ctx context.Context, v2 *influxDBv2, bucketSvc influxdb.BucketService, orgID platform.ID, db *meta.DatabaseInfo, rp *meta.RetentionPolicyInfo, verb string, log *zap.Logger) (*influxdb.Bucket, error) {
+	name := db.Name + "/" + rp.Name
+	bucket, err := bucketSvc.FindBucketByName(ctx, orgID, name)
+	switch {
+	case err == nil:
+		log.Info("Bucket already exists", zap.String("bucket", bucket.Name))
+	case errors2.ErrorCode(err) == errors2.ENotFound:
+		bucket = &influxdb.Bucket{
+			OrgID:               orgID,
+			Type:                influxdb.BucketTypeUser,
+			Name:                name,
+			Description:         fmt.Sprintf("%s from v1 database %s with retention policy %s", verb, db.Name, rp.Name),
+			RetentionPolicyName: rp.Name,
+			RetentionPeriod:     rp.Duration,
+			ShardGroupDuration:  rp.ShardGroupDuration,
+		}
+		log.Info("Creating bucket", zap.String("bucket", bucket.Name))
+		if err := bucketSvc.CreateBucket(ctx, bucket); err != nil {
+			return nil, fmt.Errorf("error creating bucket %s: %w", bucket.Name, err)
+		}
+	default:
+		return nil, fmt.Errorf("error finding bucket %s: %w", name, err)
+	}
+
+	virtual := false
+	mappings, _, err := v2.dbrpSvc.FindMany(ctx, influxdb.DBRPMappingFilter{
+		OrgID:           &orgID,
+		Database:        &db.Name,
+		RetentionPolicy: &rp.Name,
+		Virtual:         &virtual,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error finding mapping %s/%s in org %s: %w", db.Name, rp.Name, orgID.String(), err)
+	}
+	if len(mappings) > 0 {
+		log.Info("Mapping already exists", zap.String("database", db.Name), zap.String("retention policy", rp.Name))
+		return bucket, nil
+	}
+
+	mapping := &influxdb.DBRPMapping{
+		Database:        db.Name,
+		RetentionPolicy: rp.Name,
+		Default:         db.DefaultRetentionPolicy == rp.Name,
+		OrganizationID:  orgID,
+		BucketID:        bucket.ID,
+	}
+	log.Info(
+		"Creating mapping",
+		zap.String("database", mapping.Database),
+		zap.String("retention policy", mapping.RetentionPolicy),
+		zap.String("orgID", mapping.OrganizationID.String()),
+		zap.String("bucketID", mapping.BucketID.String()),
+	)
+	if err := v2.dbrpSvc.Create(ctx, mapping); err != nil {
+		return nil, fmt.Errorf("error creating mapping  %s/%s -> Org %s, bucket %s: %w", mapping.Database, mapping.RetentionPolicy, mapping.OrganizationID.String(), mapping.BucketID.String(), err)
+	}
+	return bucket, nil
+}
+
 // checkDiskSpace ensures there is enough room at the target path to store
 // a full copy of all V1 data.
 func checkDiskSpace(cli clients.CLI, opts *options, log *zap.Logger) error {
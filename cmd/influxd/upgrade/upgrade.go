@@ -286,6 +286,7 @@ func NewCommand(ctx context.Context, v *viper.Viper) (*cobra.Command, error) {
 	// add sub commands
 	cmd.AddCommand(v1DumpMetaCommand)
 	cmd.AddCommand(v2DumpMetaCommand)
+	cmd.AddCommand(v1MetaCommand)
 	return cmd, nil
 }
 
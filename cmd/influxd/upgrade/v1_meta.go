@@ -0,0 +1,145 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/fluxinit"
+	"github.com/influxdata/influxdb/v2/internal/fs"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	errors2 "github.com/influxdata/influxdb/v2/kit/platform/errors"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var v1MetaCommand = &cobra.Command{
+	Use:   "v1-meta",
+	Short: "Import the metadata of InfluxDB 1.x into InfluxDB 2.x",
+	Long: `
+    Imports the metadata of a 1.x meta.db into the bolt database of an InfluxDB 2.x
+    instance that is already set up, without copying any data:
+      1. Creates a bucket for each 1.x database and retention policy, named db-name/rp-name.
+      2. Creates the DBRP mappings of the buckets for the 1.x compatibility API.
+      3. Creates a 1.x compatible token for each non-admin 1.x user, with its password.
+
+    The organization is created when it does not exist, and owned by the user.
+    The influxd server must be stopped while importing.
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fluxinit.FluxInit()
+		log, err := zap.NewProduction()
+		if err != nil {
+			return err
+		}
+		return runV1MetaE(context.Background(), &v1MetaOptions, log)
+	},
+}
+
+var v1MetaOptions = options{}
+
+func init() {
+	flags := v1MetaCommand.Flags()
+
+	v1dir, err := influxDirV1()
+	if err != nil {
+		panic("error fetching default InfluxDB 1.x dir: " + err.Error())
+	}
+	v2dir, err := fs.InfluxDir()
+	if err != nil {
+		panic("error fetching default InfluxDB 2.0 dir: " + err.Error())
+	}
+
+	flags.StringVar(&v1MetaOptions.source.metaDir, "v1-meta-dir", filepath.Join(v1dir, "meta"), "Path to meta.db directory")
+	flags.StringVar(&v1MetaOptions.target.boltPath, "bolt-path", filepath.Join(v2dir, "influxd.bolt"), "Path to 2.0 metadata")
+	flags.StringVar(&v1MetaOptions.target.enginePath, "engine-path", filepath.Join(v2dir, "engine"), "Path to 2.0 persistent engine files")
+	flags.StringVarP(&v1MetaOptions.target.orgName, "org", "o", "", "Name of the organization the metadata is imported into")
+	flags.StringVarP(&v1MetaOptions.target.userName, "username", "u", "", "Name of the existing user owning the organization and the tokens")
+}
+
+func runV1MetaE(ctx context.Context, options *options, log *zap.Logger) error {
+	if options.target.orgName == "" || options.target.userName == "" {
+		return errors.New("both --org and --username must be specified")
+	}
+
+	v1, err := newInfluxDBv1(&options.source)
+	if err != nil {
+		return err
+	}
+
+	v2, err := newInfluxDBv2(ctx, &options.target, log)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := v2.close(); err != nil {
+			log.Error("Failed to close 2.0 services", zap.Error(err))
+		}
+	}()
+
+	db2BucketIds, err := importV1Meta(ctx, v1, v2, &options.target, log)
+	if err != nil {
+		return err
+	}
+	if _, err := upgradeUsers(ctx, v1, v2, &options.target, db2BucketIds, log); err != nil {
+		return err
+	}
+
+	log.Info("Metadata import successfully completed")
+	return nil
+}
+
+// importV1Meta creates the buckets and DBRP mappings of the 1.x databases and retention
+// policies in the organization of opts, and sets its orgID and userID.
+func importV1Meta(ctx context.Context, v1 *influxDBv1, v2 *influxDBv2, opts *optionsV2, log *zap.Logger) (map[string][]platform.ID, error) {
+	user, err := v2.ts.FindUser(ctx, influxdb.UserFilter{Name: &opts.userName})
+	if err != nil {
+		return nil, fmt.Errorf("error finding user %s: %w", opts.userName, err)
+	}
+	opts.userID = user.ID
+
+	org, err := v2.ts.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &opts.orgName})
+	if errors2.ErrorCode(err) == errors2.ENotFound {
+		log.Info("Creating organization", zap.String("org", opts.orgName))
+		org = &influxdb.Organization{Name: opts.orgName}
+		if err := v2.ts.CreateOrganization(ctx, org); err != nil {
+			return nil, fmt.Errorf("error creating organization %s: %w", opts.orgName, err)
+		}
+		if err := v2.ts.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+			UserID:       user.ID,
+			UserType:     influxdb.Owner,
+			MappingType:  influxdb.UserMappingType,
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   org.ID,
+		}); err != nil {
+			return nil, fmt.Errorf("error making user %s the owner of organization %s: %w", opts.userName, opts.orgName, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("error finding organization %s: %w", opts.orgName, err)
+	}
+	opts.orgID = org.ID
+
+	db2BucketIds := make(map[string][]platform.ID)
+	for _, db := range v1.meta.Databases() {
+		if db.Name == "_internal" {
+			log.Debug("Skipping _internal ")
+			continue
+		}
+		db2BucketIds[db.Name] = make([]platform.ID, 0, len(db.RetentionPolicies))
+
+		for _, rp := range db.RetentionPolicies {
+			// The bucket service of the tenant system also creates the database in the 2.x meta.
+			bucket, err := createBucketAndMapping(ctx, v2, v2.ts.BucketService, org.ID, &db, &rp, "Imported", log)
+			if err != nil {
+				return nil, err
+			}
+			db2BucketIds[db.Name] = append(db2BucketIds[db.Name], bucket.ID)
+		}
+	}
+
+	log.Info("Database import complete", zap.Int("imported_count", len(db2BucketIds)))
+	return db2BucketIds, nil
+}
@@ -0,0 +1,97 @@
+package upgrade
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/bolt"
+	"github.com/influxdata/influxdb/v2/internal/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestImportV1Meta(t *testing.T) {
+	ctx := context.Background()
+
+	tmpdir, err := os.MkdirTemp("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+	require.NoError(t, testutil.Unzip(filepath.Join("testdata", "v1db.zip"), tmpdir))
+
+	log := zaptest.NewLogger(t, zaptest.Level(zap.InfoLevel))
+	v1, err := newInfluxDBv1(&optionsV1{metaDir: filepath.Join(tmpdir, "v1db", "meta")})
+	require.NoError(t, err)
+
+	v2opts := &optionsV2{
+		boltPath:   filepath.Join(tmpdir, bolt.DefaultFilename),
+		enginePath: filepath.Join(tmpdir, "engine"),
+		orgName:    "imported",
+		userName:   "my-user",
+	}
+	v2, err := newInfluxDBv2(ctx, v2opts, log)
+	require.NoError(t, err)
+	defer v2.close()
+
+	_, err = setupAdmin(ctx, v2, &influxdb.OnboardingRequest{
+		User:     "my-user",
+		Password: "my-password",
+		Org:      "my-org",
+		Bucket:   "my-bucket",
+	})
+	require.NoError(t, err)
+
+	// The organization is created, owned by the user.
+	db2BucketIds, err := importV1Meta(ctx, v1, v2, v2opts, log)
+	require.NoError(t, err)
+	org, err := v2.ts.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &v2opts.orgName})
+	require.NoError(t, err)
+	require.Equal(t, org.ID, v2opts.orgID)
+	owners, _, err := v2.ts.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceType: influxdb.OrgsResourceType,
+		ResourceID:   org.ID,
+		UserType:     influxdb.Owner,
+	})
+	require.NoError(t, err)
+	require.Len(t, owners, 1)
+	require.Equal(t, v2opts.userID, owners[0].UserID)
+
+	require.Len(t, db2BucketIds, 3)
+	buckets, _, err := v2.ts.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &org.ID})
+	require.NoError(t, err)
+	var names []string
+	for _, b := range buckets {
+		if b.Type != influxdb.BucketTypeUser {
+			continue
+		}
+		names = append(names, b.Name)
+		// The data is not copied, the databases of the buckets are empty.
+		db := v2.meta.Database(b.ID.String())
+		require.NotNil(t, db)
+		require.Empty(t, db.ShardInfos())
+	}
+	require.ElementsMatch(t, []string{"mydb/autogen", "mydb/1week", "test/autogen", "empty/autogen"}, names)
+
+	virtual := false
+	mappings, _, err := v2.dbrpSvc.FindMany(ctx, influxdb.DBRPMappingFilter{OrgID: &org.ID, Virtual: &virtual})
+	require.NoError(t, err)
+	require.Len(t, mappings, len(names))
+	for _, m := range mappings {
+		require.Contains(t, db2BucketIds[m.Database], m.BucketID)
+	}
+
+	// The import can be run again, the existing buckets and mappings are kept.
+	again, err := importV1Meta(ctx, v1, v2, v2opts, log)
+	require.NoError(t, err)
+	require.Equal(t, db2BucketIds, again)
+	mappings, _, err = v2.dbrpSvc.FindMany(ctx, influxdb.DBRPMappingFilter{OrgID: &org.ID, Virtual: &virtual})
+	require.NoError(t, err)
+	require.Len(t, mappings, len(names))
+
+	n, err := upgradeUsers(ctx, v1, v2, v2opts, db2BucketIds, log)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+}
@@ -128,7 +128,9 @@ func SortDashboards(opts FindOptions, ds []*Dashboard) {
 type Cell struct {
 	ID platform.ID `json:"id,omitempty"`
 	CellProperty
-	View *View `json:"-"`
+	// TaskID is the task populating the bucket the cell is querying, if any.
+	TaskID *platform.ID `json:"taskID,omitempty"`
+	View   *View        `json:"-"`
 }
 
 // Marshals the cell
@@ -137,10 +139,12 @@ func (c *Cell) MarshalJSON() ([]byte, error) {
 		ID             *platform.ID    `json:"id,omitempty"`
 		Name           string          `json:"name,omitempty"`
 		ViewProperties json.RawMessage `json:"properties,omitempty"`
+		TaskID         *platform.ID    `json:"taskID,omitempty"`
 		CellProperty
 	}
 	response := resp{
 		CellProperty: c.CellProperty,
+		TaskID:       c.TaskID,
 	}
 	if c.ID != 0 {
 		response.ID = &c.ID
@@ -161,6 +165,7 @@ func (c *Cell) UnmarshalJSON(b []byte) error {
 		ID             platform.ID     `json:"id,omitempty"`
 		Name           string          `json:"name,omitempty"`
 		ViewProperties json.RawMessage `json:"properties,omitempty"`
+		TaskID         *platform.ID    `json:"taskID,omitempty"`
 		CellProperty
 	}
 	if err := json.Unmarshal(b, &newCell); err != nil {
@@ -169,6 +174,7 @@ func (c *Cell) UnmarshalJSON(b []byte) error {
 
 	c.ID = newCell.ID
 	c.CellProperty = newCell.CellProperty
+	c.TaskID = newCell.TaskID
 
 	if newCell.Name != "" {
 		if c.View == nil {
@@ -277,6 +283,53 @@ type CellUpdate struct {
 	Y *int32 `json:"y"`
 	W *int32 `json:"w"`
 	H *int32 `json:"h"`
+	// TaskID links the cell to the task populating the bucket it is querying.
+	// An invalid ID unlinks the cell from its task.
+	TaskID *platform.ID `json:"taskID,omitempty"`
+}
+
+// MarshalJSON encodes the update, with an empty taskID to unlink the cell from its task.
+func (u CellUpdate) MarshalJSON() ([]byte, error) {
+	type cellUpdate CellUpdate
+	upd := struct {
+		cellUpdate
+		TaskID *string `json:"taskID,omitempty"`
+	}{cellUpdate: cellUpdate(u)}
+	if u.TaskID != nil {
+		var taskID string
+		if u.TaskID.Valid() {
+			taskID = u.TaskID.String()
+		}
+		upd.TaskID = &taskID
+	}
+	return json.Marshal(upd)
+}
+
+// UnmarshalJSON decodes the update. A null or empty taskID unlinks the cell from its task.
+func (u *CellUpdate) UnmarshalJSON(b []byte) error {
+	type cellUpdate CellUpdate
+	var upd struct {
+		cellUpdate
+		TaskID json.RawMessage `json:"taskID"`
+	}
+	if err := json.Unmarshal(b, &upd); err != nil {
+		return err
+	}
+
+	*u = CellUpdate(upd.cellUpdate)
+	switch string(upd.TaskID) {
+	case "":
+		u.TaskID = nil
+	case "null", `""`:
+		u.TaskID = new(platform.ID)
+	default:
+		var taskID platform.ID
+		if err := json.Unmarshal(upd.TaskID, &taskID); err != nil {
+			return err
+		}
+		u.TaskID = &taskID
+	}
+	return nil
 }
 
 // Apply applies an update to a Cell.
@@ -297,12 +350,21 @@ func (u CellUpdate) Apply(c *Cell) error {
 		c.H = *u.H
 	}
 
+	if u.TaskID != nil {
+		if u.TaskID.Valid() {
+			taskID := *u.TaskID
+			c.TaskID = &taskID
+		} else {
+			c.TaskID = nil
+		}
+	}
+
 	return nil
 }
 
 // Valid returns an error if the cell update is invalid.
 func (u CellUpdate) Valid() *errors.Error {
-	if u.H == nil && u.W == nil && u.Y == nil && u.X == nil {
+	if u.H == nil && u.W == nil && u.Y == nil && u.X == nil && u.TaskID == nil {
 		return &errors.Error{
 			Code: errors.EInvalid,
 			Msg:  "must update at least one attribute",
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb/v2"
+	platform2 "github.com/influxdata/influxdb/v2/kit/platform"
 	platformtesting "github.com/influxdata/influxdb/v2/testing"
 )
 
@@ -237,6 +238,53 @@ func TestView_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestCellUpdate_TaskID(t *testing.T) {
+	taskID := platformtesting.MustIDBase16("020f755c3c082001")
+	tests := []struct {
+		name string
+		json string
+		// want is the task ID of a cell linked to another task once updated.
+		want *platform2.ID
+	}{
+		{name: "link", json: `{"taskID": "020f755c3c082001"}`, want: &taskID},
+		{name: "unlink with null", json: `{"taskID": null}`},
+		{name: "unlink with an empty ID", json: `{"taskID": ""}`},
+		{name: "keep", json: `{"x": 1}`, want: idPtr(platformtesting.MustIDBase16("020f755c3c082002"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var upd platform.CellUpdate
+			if err := json.Unmarshal([]byte(tt.json), &upd); err != nil {
+				t.Fatal(err)
+			}
+			cell := &platform.Cell{TaskID: idPtr(platformtesting.MustIDBase16("020f755c3c082002"))}
+			if err := upd.Apply(cell); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, cell.TaskID); diff != "" {
+				t.Errorf("unexpected task ID:\n%s", diff)
+			}
+
+			// The update is encoded back to the same change.
+			b, err := json.Marshal(upd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got platform.CellUpdate
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(upd, got); diff != "" {
+				t.Errorf("unexpected update after encoding %s:\n%s", b, diff)
+			}
+		})
+	}
+}
+
+func idPtr(id platform2.ID) *platform2.ID {
+	return &id
+}
+
 func jsonEqual(s1, s2 string) (eq bool, err error) {
 	var o1, o2 interface{}
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
@@ -14,6 +15,7 @@ import (
 	"github.com/influxdata/influxdb/v2/kit/platform/errors"
 	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
 	"github.com/influxdata/influxdb/v2/pkg/httpc"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +30,7 @@ type DashboardHandler struct {
 	labelService     influxdb.LabelService
 	userService      influxdb.UserService
 	orgService       influxdb.OrganizationService
+	taskService      taskmodel.TaskService
 }
 
 const (
@@ -41,6 +44,7 @@ func NewDashboardHandler(
 	labelService influxdb.LabelService,
 	userService influxdb.UserService,
 	orgService influxdb.OrganizationService,
+	taskService taskmodel.TaskService,
 	urmHandler, labelHandler http.Handler,
 ) *DashboardHandler {
 	h := &DashboardHandler{
@@ -50,6 +54,7 @@ func NewDashboardHandler(
 		labelService:     labelService,
 		userService:      userService,
 		orgService:       orgService,
+		taskService:      taskService,
 	}
 
 	// setup routing
@@ -77,6 +82,7 @@ func NewDashboardHandler(
 					r.Route("/{cellID}", func(r chi.Router) {
 						r.Delete("/", h.handleDeleteDashboardCell)
 						r.Patch("/", h.handlePatchDashboardCell)
+						r.Get("/task", h.handleGetDashboardCellTask)
 
 						r.Route("/view", func(r chi.Router) {
 							r.Get("/", h.handleGetDashboardCellView)
@@ -215,6 +221,10 @@ func newDashboardCellResponse(dashboardID platform.ID, c *influxdb.Cell) dashboa
 		},
 	}
 
+	if c.TaskID != nil {
+		resp.Links["task"] = fmt.Sprintf("/api/v2/dashboards/%s/cells/%s/task", dashboardID, c.ID)
+	}
+
 	if c.View != nil {
 		resp.Properties = c.View.Properties
 		resp.Name = c.View.Name
@@ -908,6 +918,13 @@ func (h *DashboardHandler) handlePatchDashboardCell(w http.ResponseWriter, r *ht
 		h.api.Err(w, r, err)
 		return
 	}
+	if req.upd.TaskID != nil && req.upd.TaskID.Valid() {
+		if _, err := h.findDashboardCellTask(ctx, req.dashboardID, *req.upd.TaskID); err != nil {
+			h.api.Err(w, r, err)
+			return
+		}
+	}
+
 	cell, err := h.dashboardService.UpdateDashboardCell(ctx, req.dashboardID, req.cellID, req.upd)
 	if err != nil {
 		h.api.Err(w, r, err)
@@ -919,6 +936,130 @@ func (h *DashboardHandler) handlePatchDashboardCell(w http.ResponseWriter, r *ht
 	h.api.Respond(w, r, http.StatusOK, newDashboardCellResponse(req.dashboardID, cell))
 }
 
+// findDashboardCellTask returns the task taskID that a cell of the dashboard dashboardID
+// is linked to. The task must be readable and belong to the organization of the dashboard.
+func (h *DashboardHandler) findDashboardCellTask(ctx context.Context, dashboardID, taskID platform.ID) (*taskmodel.Task, error) {
+	dashboard, err := h.dashboardService.FindDashboardByID(ctx, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := h.taskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.OrganizationID != dashboard.OrganizationID {
+		return nil, &errors.Error{
+			Code: errors.EInvalid,
+			Msg:  "task must belong to the organization of the dashboard",
+		}
+	}
+	return task, nil
+}
+
+// cellTask is the task of a dashboard cell, in the format of the task API.
+// The links lead to the task API for anything else.
+type cellTask struct {
+	ID              platform.ID `json:"id"`
+	OrganizationID  platform.ID `json:"orgID"`
+	Organization    string      `json:"org"`
+	OwnerID         platform.ID `json:"ownerID"`
+	Name            string      `json:"name"`
+	Description     string      `json:"description,omitempty"`
+	Status          string      `json:"status"`
+	Flux            string      `json:"flux"`
+	Every           string      `json:"every,omitempty"`
+	Cron            string      `json:"cron,omitempty"`
+	LatestCompleted string      `json:"latestCompleted,omitempty"`
+	LastRunStatus   string      `json:"lastRunStatus,omitempty"`
+	LastRunError    string      `json:"lastRunError,omitempty"`
+}
+
+type dashboardCellTaskResponse struct {
+	Task  cellTask          `json:"task"`
+	Links map[string]string `json:"links"`
+}
+
+func newDashboardCellTaskResponse(dashboardID, cellID platform.ID, task *taskmodel.Task) dashboardCellTaskResponse {
+	latestCompleted := ""
+	if !task.LatestCompleted.IsZero() {
+		latestCompleted = task.LatestCompleted.Format(time.RFC3339)
+	}
+
+	return dashboardCellTaskResponse{
+		Task: cellTask{
+			ID:              task.ID,
+			OrganizationID:  task.OrganizationID,
+			Organization:    task.Organization,
+			OwnerID:         task.OwnerID,
+			Name:            task.Name,
+			Description:     task.Description,
+			Status:          task.Status,
+			Flux:            task.Flux,
+			Every:           task.Every,
+			Cron:            task.Cron,
+			LatestCompleted: latestCompleted,
+			LastRunStatus:   task.LastRunStatus,
+			LastRunError:    task.LastRunError,
+		},
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/dashboards/%s/cells/%s/task", dashboardID, cellID),
+			"cell": fmt.Sprintf("/api/v2/dashboards/%s/cells/%s", dashboardID, cellID),
+			"task": fmt.Sprintf("/api/v2/tasks/%s", task.ID),
+			"runs": fmt.Sprintf("/api/v2/tasks/%s/runs", task.ID),
+		},
+	}
+}
+
+// handleGetDashboardCellTask returns the task populating the bucket of a dashboard cell,
+// with links to the task and its runs.
+func (h *DashboardHandler) handleGetDashboardCellTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetDashboardCellViewRequest(ctx, r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	dashboard, err := h.dashboardService.FindDashboardByID(ctx, req.dashboardID)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var cell *influxdb.Cell
+	for _, c := range dashboard.Cells {
+		if c.ID == req.cellID {
+			cell = c
+			break
+		}
+	}
+	if cell == nil {
+		h.api.Err(w, r, &errors.Error{
+			Code: errors.ENotFound,
+			Msg:  influxdb.ErrCellNotFound,
+		})
+		return
+	}
+	if cell.TaskID == nil {
+		h.api.Err(w, r, &errors.Error{
+			Code: errors.ENotFound,
+			Msg:  "cell is not linked to a task",
+		})
+		return
+	}
+
+	task, err := h.taskService.FindTaskByID(ctx, *cell.TaskID)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.log.Debug("Dashboard cell task retrieved", zap.String("dashboardID", req.dashboardID.String()), zap.String("cellID", req.cellID.String()), zap.String("taskID", cell.TaskID.String()))
+
+	h.api.Respond(w, r, http.StatusOK, newDashboardCellTaskResponse(req.dashboardID, req.cellID, task))
+}
+
 func (h *DashboardHandler) lookupOrgByDashboardID(ctx context.Context, id platform.ID) (platform.ID, error) {
 	d, err := h.dashboardService.FindDashboardByID(ctx, id)
 	if err != nil {
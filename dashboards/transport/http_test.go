@@ -23,6 +23,7 @@ import (
 	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/label"
 	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
 	"github.com/influxdata/influxdb/v2/tenant"
 	itesting "github.com/influxdata/influxdb/v2/testing"
 	"github.com/yudai/gojsondiff"
@@ -38,6 +39,7 @@ func newDashboardHandler(log *zap.Logger, opts ...option) *DashboardHandler {
 		orgService:       mock.NewOrganizationService(),
 		labelService:     mock.NewLabelService(),
 		urmService:       mock.NewUserResourceMappingService(),
+		taskService:      mock.NewTaskService(),
 	}
 
 	for _, opt := range opts {
@@ -50,6 +52,7 @@ func newDashboardHandler(log *zap.Logger, opts ...option) *DashboardHandler {
 		deps.labelService,
 		deps.userService,
 		deps.orgService,
+		deps.taskService,
 		tenant.NewURMHandler(
 			log.With(zap.String("handler", "urm")),
 			influxdb.DashboardsResourceType,
@@ -1648,6 +1651,9 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
 		y      int32
 		w      int32
 		h      int32
+		taskID string
+		// unlinkTask unlinks the cell from its task.
+		unlinkTask bool
 	}
 	type wants struct {
 		statusCode  int
@@ -1655,6 +1661,39 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
 		body        string
 	}
 
+	orgID := dashboardstesting.MustIDBase16("020f755c3c083000")
+	linkDashboardService := func(taskID *platform.ID) *mock.DashboardService {
+		return &mock.DashboardService{
+			FindDashboardByIDF: func(ctx context.Context, id platform.ID) (*influxdb.Dashboard, error) {
+				return &influxdb.Dashboard{ID: id, OrganizationID: orgID}, nil
+			},
+			UpdateDashboardCellF: func(ctx context.Context, id, cellID platform.ID, upd influxdb.CellUpdate) (*influxdb.Cell, error) {
+				cell := &influxdb.Cell{
+					ID:     dashboardstesting.MustIDBase16("020f755c3c082000"),
+					TaskID: taskID,
+				}
+
+				if err := upd.Apply(cell); err != nil {
+					return nil, err
+				}
+
+				return cell, nil
+			},
+		}
+	}
+	taskService := &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id platform.ID) (*taskmodel.Task, error) {
+			switch id.String() {
+			case "020f755c3c082001":
+				return &taskmodel.Task{ID: id, OrganizationID: orgID}, nil
+			case "020f755c3c082002":
+				return &taskmodel.Task{ID: id, OrganizationID: dashboardstesting.MustIDBase16("020f755c3c083001")}, nil
+			}
+			return nil, taskmodel.ErrTaskNotFound
+		},
+	}
+	linkedTaskID := dashboardstesting.MustIDBase16("020f755c3c082001")
+
 	tests := []struct {
 		name   string
 		fields fields
@@ -1699,6 +1738,84 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
     "view": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000/view"
   }
 }
+`,
+			},
+		},
+		{
+			name:   "link a dashboard cell to a task",
+			fields: fields{linkDashboardService(nil)},
+			args: args{
+				id:     "020f755c3c082000",
+				cellID: "020f755c3c082000",
+				taskID: "020f755c3c082001",
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "id": "020f755c3c082000",
+  "x": 0,
+  "y": 0,
+  "w": 0,
+  "h": 0,
+  "taskID": "020f755c3c082001",
+  "links": {
+    "self": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000",
+    "view": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000/view",
+    "task": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000/task"
+  }
+}
+`,
+			},
+		},
+		{
+			name:   "link a dashboard cell to a task of another organization",
+			fields: fields{linkDashboardService(nil)},
+			args: args{
+				id:     "020f755c3c082000",
+				cellID: "020f755c3c082000",
+				taskID: "020f755c3c082002",
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			name:   "link a dashboard cell to a missing task",
+			fields: fields{linkDashboardService(nil)},
+			args: args{
+				id:     "020f755c3c082000",
+				cellID: "020f755c3c082000",
+				taskID: "020f755c3c082003",
+			},
+			wants: wants{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			name:   "unlink a dashboard cell from its task",
+			fields: fields{linkDashboardService(&linkedTaskID)},
+			args: args{
+				id:         "020f755c3c082000",
+				cellID:     "020f755c3c082000",
+				unlinkTask: true,
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "id": "020f755c3c082000",
+  "x": 0,
+  "y": 0,
+  "w": 0,
+  "h": 0,
+  "links": {
+    "self": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000",
+    "view": "/api/v2/dashboards/020f755c3c082000/cells/020f755c3c082000/view"
+  }
+}
 `,
 			},
 		},
@@ -1709,6 +1826,7 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
 			h := newDashboardHandler(
 				zaptest.NewLogger(t),
 				withDashboardService(tt.fields.DashboardService),
+				withTaskService(taskService),
 			)
 
 			upd := influxdb.CellUpdate{}
@@ -1724,6 +1842,13 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
 			if tt.args.h != 0 {
 				upd.H = &tt.args.h
 			}
+			if tt.args.taskID != "" {
+				taskID := dashboardstesting.MustIDBase16(tt.args.taskID)
+				upd.TaskID = &taskID
+			}
+			if tt.args.unlinkTask {
+				upd.TaskID = new(platform.ID)
+			}
 
 			b, err := json.Marshal(upd)
 			if err != nil {
@@ -1765,6 +1890,148 @@ func TestService_handlePatchDashboardCell(t *testing.T) {
 	}
 }
 
+func TestService_handleGetDashboardCellTask(t *testing.T) {
+	type fields struct {
+		DashboardService influxdb.DashboardService
+	}
+	type args struct {
+		id     string
+		cellID string
+	}
+	type wants struct {
+		statusCode  int
+		contentType string
+		body        string
+	}
+
+	taskID := dashboardstesting.MustIDBase16("020f755c3c082001")
+	taskService := &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id platform.ID) (*taskmodel.Task, error) {
+			return &taskmodel.Task{
+				ID:             id,
+				OrganizationID: dashboardstesting.MustIDBase16("020f755c3c083000"),
+				OwnerID:        dashboardstesting.MustIDBase16("020f755c3c084000"),
+				Name:           "downsample",
+				Status:         "active",
+			}, nil
+		},
+	}
+	dashboardService := &mock.DashboardService{
+		FindDashboardByIDF: func(ctx context.Context, id platform.ID) (*influxdb.Dashboard, error) {
+			return &influxdb.Dashboard{
+				ID: id,
+				Cells: []*influxdb.Cell{
+					{ID: dashboardstesting.MustIDBase16("da7aba5e5d81e550"), TaskID: &taskID},
+					{ID: dashboardstesting.MustIDBase16("da7aba5e5d81e551")},
+				},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "get the task of a dashboard cell",
+			fields: fields{dashboardService},
+			args: args{
+				id:     "020f755c3c082000",
+				cellID: "da7aba5e5d81e550",
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "task": {
+    "id": "020f755c3c082001",
+    "orgID": "020f755c3c083000",
+    "org": "",
+    "ownerID": "020f755c3c084000",
+    "name": "downsample",
+    "status": "active",
+    "flux": ""
+  },
+  "links": {
+    "self": "/api/v2/dashboards/020f755c3c082000/cells/da7aba5e5d81e550/task",
+    "cell": "/api/v2/dashboards/020f755c3c082000/cells/da7aba5e5d81e550",
+    "task": "/api/v2/tasks/020f755c3c082001",
+    "runs": "/api/v2/tasks/020f755c3c082001/runs"
+  }
+}
+`,
+			},
+		},
+		{
+			name:   "cell not linked to a task",
+			fields: fields{dashboardService},
+			args: args{
+				id:     "020f755c3c082000",
+				cellID: "da7aba5e5d81e551",
+			},
+			wants: wants{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			name:   "cell not found",
+			fields: fields{dashboardService},
+			args: args{
+				id:     "020f755c3c082000",
+				cellID: "da7aba5e5d81e552",
+			},
+			wants: wants{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newDashboardHandler(
+				zaptest.NewLogger(t),
+				withDashboardService(tt.fields.DashboardService),
+				withTaskService(taskService),
+			)
+
+			r := httptest.NewRequest("GET", "http://any.url", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.args.id)
+			rctx.URLParams.Add("cellID", tt.args.cellID)
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				chi.RouteCtxKey,
+				rctx),
+			)
+			w := httptest.NewRecorder()
+
+			h.handleGetDashboardCellTask(w, r)
+
+			res := w.Result()
+			content := res.Header.Get("Content-Type")
+			body, _ := io.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. handleGetDashboardCellTask() = %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if tt.wants.contentType != "" && content != tt.wants.contentType {
+				t.Errorf("%q. handleGetDashboardCellTask() = %v, want %v", tt.name, content, tt.wants.contentType)
+			}
+			if tt.wants.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+					t.Errorf("%q, handleGetDashboardCellTask(). error unmarshalling json %v", tt.name, err)
+				} else if !eq {
+					t.Errorf("%q. handleGetDashboardCellTask() = ***%s***", tt.name, diff)
+				}
+			}
+		})
+	}
+}
+
 func Test_dashboardCellIDPath(t *testing.T) {
 	t.Parallel()
 	dashboard, err := platform.IDFromString("deadbeefdeadbeef")
@@ -1983,6 +2250,7 @@ type dashboardDependencies struct {
 	orgService       influxdb.OrganizationService
 	labelService     influxdb.LabelService
 	urmService       influxdb.UserResourceMappingService
+	taskService      taskmodel.TaskService
 }
 
 type option func(*dashboardDependencies)
@@ -1998,3 +2266,9 @@ func withLabelService(svc influxdb.LabelService) option {
 		d.labelService = svc
 	}
 }
+
+func withTaskService(svc taskmodel.TaskService) option {
+	return func(d *dashboardDependencies) {
+		d.taskService = svc
+	}
+}
@@ -0,0 +1,38 @@
+package gather
+
+import (
+	"sync"
+	"time"
+)
+
+// periodic calls a function every interval, from start until stop.
+// It is shared by the services writing or checking the metrics of the server.
+type periodic struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// start calls fn with the current time every interval until stop is called.
+func (p *periodic) start(interval time.Duration, fn func(now time.Time)) {
+	p.done = make(chan struct{})
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case now := <-ticker.C:
+				fn(now)
+			}
+		}
+	}()
+}
+
+// stop stops calling the function and waits for the current call to return.
+func (p *periodic) stop() {
+	close(p.done)
+	p.wg.Wait()
+}
@@ -83,51 +83,7 @@ func (p *prometheusScraper) parse(r io.Reader, header http.Header, target influx
 
 	// read metrics
 	for name, family := range metricFamilies {
-		for _, m := range family.Metric {
-			// reading tags
-			tags := makeLabels(m)
-			// reading fields
-			var fields map[string]interface{}
-			switch family.GetType() {
-			case dto.MetricType_SUMMARY:
-				// summary metric
-				fields = makeQuantiles(m)
-				fields["count"] = float64(m.GetSummary().GetSampleCount())
-
-				ss := float64(m.GetSummary().GetSampleSum())
-				if !math.IsNaN(ss) {
-					fields["sum"] = ss
-				}
-			case dto.MetricType_HISTOGRAM:
-				// histogram metric
-				fields = makeBuckets(m)
-				fields["count"] = float64(m.GetHistogram().GetSampleCount())
-
-				ss := float64(m.GetHistogram().GetSampleSum())
-				if !math.IsNaN(ss) {
-					fields["sum"] = ss
-				}
-			default:
-				// standard metric
-				fields = getNameAndValue(m)
-			}
-			if len(fields) == 0 {
-				continue
-			}
-			tm := now
-			if m.TimestampMs != nil && *m.TimestampMs > 0 {
-				tm = time.Unix(0, *m.TimestampMs*1000000)
-			}
-			me := Metrics{
-				Timestamp: tm,
-				Tags:      tags,
-				Fields:    fields,
-				Name:      name,
-				Type:      family.GetType(),
-			}
-			ms = append(ms, me)
-		}
-
+		ms = append(ms, familyMetrics(name, family, now)...)
 	}
 
 	collected = MetricsCollection{
@@ -139,6 +95,57 @@ func (p *prometheusScraper) parse(r io.Reader, header http.Header, target influx
 	return collected, nil
 }
 
+// familyMetrics converts the metrics of a family to Metrics, timestamped
+// with now unless they have their own timestamp.
+func familyMetrics(name string, family *dto.MetricFamily, now time.Time) []Metrics {
+	ms := make([]Metrics, 0, len(family.Metric))
+	for _, m := range family.Metric {
+		// reading tags
+		tags := makeLabels(m)
+		// reading fields
+		var fields map[string]interface{}
+		switch family.GetType() {
+		case dto.MetricType_SUMMARY:
+			// summary metric
+			fields = makeQuantiles(m)
+			fields["count"] = float64(m.GetSummary().GetSampleCount())
+
+			ss := float64(m.GetSummary().GetSampleSum())
+			if !math.IsNaN(ss) {
+				fields["sum"] = ss
+			}
+		case dto.MetricType_HISTOGRAM:
+			// histogram metric
+			fields = makeBuckets(m)
+			fields["count"] = float64(m.GetHistogram().GetSampleCount())
+
+			ss := float64(m.GetHistogram().GetSampleSum())
+			if !math.IsNaN(ss) {
+				fields["sum"] = ss
+			}
+		default:
+			// standard metric
+			fields = getNameAndValue(m)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		tm := now
+		if m.TimestampMs != nil && *m.TimestampMs > 0 {
+			tm = time.Unix(0, *m.TimestampMs*1000000)
+		}
+		me := Metrics{
+			Timestamp: tm,
+			Tags:      tags,
+			Fields:    fields,
+			Name:      name,
+			Type:      family.GetType(),
+		}
+		ms = append(ms, me)
+	}
+	return ms
+}
+
 // Get labels from metric
 func makeLabels(m *dto.Metric) map[string]string {
 	result := map[string]string{}
@@ -0,0 +1,224 @@
+package gather
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// resourceAlertMeasurement is the measurement of the alerts written into the _monitoring bucket.
+const resourceAlertMeasurement = "resource_alerts"
+
+// ResourceCheck is a resource of the server watched by a ResourceAlerter.
+type ResourceCheck struct {
+	// Name identifies the resource in the alerts.
+	Name string
+	// Threshold is the usage above which the resource raises an alert.
+	Threshold float64
+	// Usage returns the current usage of the resource.
+	Usage func() (float64, error)
+}
+
+// GaugeUsage returns the sum of the values of the gauge name across all its labels.
+// A gauge vector has no metric family until one of its labels is set, so a gauge
+// that is not found has a usage of 0.
+func GaugeUsage(gatherer prometheus.Gatherer, name string) func() (float64, error) {
+	return func() (float64, error) {
+		families, err := gatherer.Gather()
+		if err != nil {
+			return 0, err
+		}
+		var v float64
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+			for _, m := range family.GetMetric() {
+				v += m.GetGauge().GetValue()
+			}
+		}
+		return v, nil
+	}
+}
+
+// FileSizeUsage returns the size of the file at path in bytes.
+func FileSizeUsage(path string) func() (float64, error) {
+	return func() (float64, error) {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		return float64(fi.Size()), nil
+	}
+}
+
+// ResourceAlert is raised when the usage of a resource goes above its threshold,
+// and resolved once it is back below.
+type ResourceAlert struct {
+	Resource  string    `json:"resource"`
+	Usage     float64   `json:"usage"`
+	Threshold float64   `json:"threshold"`
+	Resolved  bool      `json:"resolved"`
+	Time      time.Time `json:"time"`
+}
+
+// ResourceAlerter periodically checks the usage of the resources of the server, such as
+// the query queue or the size of the bolt file, and alerts the operators before their hard
+// limits are hit. The alerts are logged, written into the _monitoring bucket of an organization
+// when OrgID is valid, and sent to Notify when it is set.
+type ResourceAlerter struct {
+	Checks []ResourceCheck
+	// Interval is between each check of the resources.
+	Interval time.Duration
+	// OrgID is the organization the alerts are written for.
+	OrgID platform.ID
+	// Notify is called with each alert raised or resolved.
+	Notify func(ctx context.Context, alert ResourceAlert) error
+
+	log     *zap.Logger
+	buckets influxdb.BucketService
+	writer  storage.PointsWriter
+
+	// alerting holds the names of the resources currently above their threshold.
+	alerting map[string]bool
+
+	periodic periodic
+}
+
+// NewResourceAlerter creates a ResourceAlerter running the checks every interval.
+func NewResourceAlerter(
+	log *zap.Logger,
+	buckets influxdb.BucketService,
+	writer storage.PointsWriter,
+	checks []ResourceCheck,
+	interval time.Duration,
+) *ResourceAlerter {
+	if interval == 0 {
+		interval = 60 * time.Second
+	}
+	return &ResourceAlerter{
+		Checks:   checks,
+		Interval: interval,
+		log:      log,
+		buckets:  buckets,
+		writer:   writer,
+		alerting: make(map[string]bool),
+	}
+}
+
+// Open starts checking the resources every interval.
+func (a *ResourceAlerter) Open() {
+	a.periodic.start(a.Interval, func(now time.Time) {
+		a.check(context.Background(), now)
+	})
+}
+
+// Close stops checking the resources.
+func (a *ResourceAlerter) Close() {
+	a.periodic.stop()
+}
+
+// check raises the alerts of the resources that went above their threshold
+// and resolves the ones of the resources back below it.
+func (a *ResourceAlerter) check(ctx context.Context, now time.Time) {
+	for _, c := range a.Checks {
+		usage, err := c.Usage()
+		if err != nil {
+			a.log.Error("Unable to check resource usage", zap.String("resource", c.Name), zap.Error(err))
+			continue
+		}
+
+		above := usage > c.Threshold
+		if above == a.alerting[c.Name] {
+			continue
+		}
+		a.alerting[c.Name] = above
+
+		alert := ResourceAlert{
+			Resource:  c.Name,
+			Usage:     usage,
+			Threshold: c.Threshold,
+			Resolved:  !above,
+			Time:      now,
+		}
+		fields := []zap.Field{
+			zap.String("resource", c.Name),
+			zap.Float64("usage", usage),
+			zap.Float64("threshold", c.Threshold),
+		}
+		if above {
+			a.log.Warn("Resource usage is above its threshold", fields...)
+		} else {
+			a.log.Info("Resource usage is back below its threshold", fields...)
+		}
+
+		if a.OrgID.Valid() {
+			if err := a.write(ctx, alert); err != nil {
+				a.log.Error("Unable to write resource alert", zap.String("resource", c.Name), zap.Error(err))
+			}
+		}
+		if a.Notify != nil {
+			if err := a.Notify(ctx, alert); err != nil {
+				a.log.Error("Unable to notify resource alert", zap.String("resource", c.Name), zap.Error(err))
+			}
+		}
+	}
+}
+
+// write writes alert into the _monitoring bucket of the organization.
+func (a *ResourceAlerter) write(ctx context.Context, alert ResourceAlert) error {
+	bucket, err := a.buckets.FindBucketByName(ctx, a.OrgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return err
+	}
+
+	level := "crit"
+	if alert.Resolved {
+		level = "ok"
+	}
+	p, err := models.NewPoint(
+		resourceAlertMeasurement,
+		models.NewTags(map[string]string{"resource": alert.Resource, "level": level}),
+		models.Fields{"usage": alert.Usage, "threshold": alert.Threshold},
+		alert.Time,
+	)
+	if err != nil {
+		return err
+	}
+	return a.writer.WritePoints(ctx, a.OrgID, bucket.ID, []models.Point{p})
+}
+
+// WebhookNotifier returns a ResourceAlerter Notify function posting the alerts as JSON to url.
+func WebhookNotifier(client *http.Client, url string) func(ctx context.Context, alert ResourceAlert) error {
+	return func(ctx context.Context, alert ResourceAlert) error {
+		body, err := json.Marshal(alert)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			return fmt.Errorf("webhook responded with status %s", resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,84 @@
+package gather
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestResourceAlerter_Check(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	queueing := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "qc_queueing_active"}, []string{"org"})
+	reg.MustRegister(queueing)
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, orgID platform.ID, name string) (*influxdb.Bucket, error) {
+		require.Equal(t, influxdb.MonitoringSystemBucketName, name)
+		return &influxdb.Bucket{ID: 2, OrgID: orgID, Name: name}, nil
+	}
+	var written []string
+	writer := &mock.PointsWriter{}
+	writer.WritePointsFn = func(ctx context.Context, orgID platform.ID, bucketID platform.ID, points []models.Point) error {
+		require.Equal(t, platform.ID(1), orgID)
+		require.Equal(t, platform.ID(2), bucketID)
+		for _, p := range points {
+			written = append(written, p.String())
+		}
+		return nil
+	}
+
+	a := NewResourceAlerter(zaptest.NewLogger(t), buckets, writer, []ResourceCheck{
+		{Name: "query_queue", Threshold: 5, Usage: GaugeUsage(reg, "qc_queueing_active")},
+	}, time.Minute)
+	a.OrgID = 1
+	var notified []ResourceAlert
+	a.Notify = func(ctx context.Context, alert ResourceAlert) error {
+		notified = append(notified, alert)
+		return nil
+	}
+
+	// A gauge vector without labels set is not gathered, its usage is 0.
+	a.check(context.Background(), time.Unix(0, 5))
+
+	// The usage is summed across labels, an alert is raised once above the threshold.
+	queueing.WithLabelValues("a").Set(3)
+	a.check(context.Background(), time.Unix(0, 10))
+	queueing.WithLabelValues("b").Set(4)
+	a.check(context.Background(), time.Unix(0, 20))
+	a.check(context.Background(), time.Unix(0, 30))
+	queueing.WithLabelValues("b").Set(0)
+	a.check(context.Background(), time.Unix(0, 40))
+
+	require.Equal(t, []string{
+		"resource_alerts,level=crit,resource=query_queue threshold=5,usage=7 20",
+		"resource_alerts,level=ok,resource=query_queue threshold=5,usage=3 40",
+	}, written)
+	require.Equal(t, []ResourceAlert{
+		{Resource: "query_queue", Usage: 7, Threshold: 5, Time: time.Unix(0, 20)},
+		{Resource: "query_queue", Usage: 3, Threshold: 5, Resolved: true, Time: time.Unix(0, 40)},
+	}, notified)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got ResourceAlert
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer ts.Close()
+
+	alert := ResourceAlert{Resource: "bolt_file", Usage: 2048, Threshold: 1024, Time: time.Unix(10, 0).UTC()}
+	require.NoError(t, WebhookNotifier(ts.Client(), ts.URL)(context.Background(), alert))
+	require.Equal(t, alert, got)
+}
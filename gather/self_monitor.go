@@ -0,0 +1,114 @@
+package gather
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// DefaultSelfMonitorPrefixes are the prefixes of the metrics of the query controller,
+// the task scheduler and executor, and the HTTP API written by a SelfMonitor.
+var DefaultSelfMonitorPrefixes = []string{"qc_", "task_", "http_api_"}
+
+// SelfMonitor periodically writes the metrics of the server into the _monitoring
+// system bucket of an organization, so the health of the server can be queried
+// without scraping it.
+type SelfMonitor struct {
+	OrgID platform.ID
+	// Interval is between each write of the metrics.
+	Interval time.Duration
+	// Prefixes are the prefixes of the names of the metrics written.
+	// All the metrics are written when it is empty.
+	Prefixes []string
+
+	log      *zap.Logger
+	gatherer prometheus.Gatherer
+	buckets  influxdb.BucketService
+	writer   storage.PointsWriter
+
+	periodic periodic
+}
+
+// NewSelfMonitor creates a SelfMonitor writing the metrics of gatherer
+// into the _monitoring bucket of the organization orgID.
+func NewSelfMonitor(
+	log *zap.Logger,
+	gatherer prometheus.Gatherer,
+	buckets influxdb.BucketService,
+	writer storage.PointsWriter,
+	orgID platform.ID,
+	interval time.Duration,
+) *SelfMonitor {
+	if interval == 0 {
+		interval = 60 * time.Second
+	}
+	return &SelfMonitor{
+		OrgID:    orgID,
+		Interval: interval,
+		Prefixes: DefaultSelfMonitorPrefixes,
+		log:      log,
+		gatherer: gatherer,
+		buckets:  buckets,
+		writer:   writer,
+	}
+}
+
+// Open starts writing the metrics every interval.
+func (m *SelfMonitor) Open() {
+	m.periodic.start(m.Interval, func(now time.Time) {
+		if err := m.write(context.Background(), now); err != nil {
+			m.log.Error("Unable to write server metrics", zap.Error(err))
+		}
+	})
+}
+
+// Close stops writing the metrics.
+func (m *SelfMonitor) Close() {
+	m.periodic.stop()
+}
+
+// write gathers the metrics and writes them timestamped with now.
+func (m *SelfMonitor) write(ctx context.Context, now time.Time) error {
+	bucket, err := m.buckets.FindBucketByName(ctx, m.OrgID, influxdb.MonitoringSystemBucketName)
+	if err != nil {
+		return err
+	}
+
+	families, err := m.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+	var ms MetricsSlice
+	for _, family := range families {
+		if m.match(family.GetName()) {
+			ms = append(ms, familyMetrics(family.GetName(), family, now)...)
+		}
+	}
+	if len(ms) == 0 {
+		return nil
+	}
+
+	ps, err := ms.Points()
+	if err != nil {
+		return err
+	}
+	return m.writer.WritePoints(ctx, m.OrgID, bucket.ID, ps)
+}
+
+func (m *SelfMonitor) match(name string) bool {
+	if len(m.Prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range m.Prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
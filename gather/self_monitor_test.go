@@ -0,0 +1,55 @@
+package gather
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSelfMonitor_Write(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	queries := prometheus.NewCounter(prometheus.CounterOpts{Name: "qc_requests_total"})
+	queries.Add(3)
+	runs := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "task_executor_total_runs_active"}, []string{"task"})
+	runs.WithLabelValues("a").Set(2)
+	reg.MustRegister(queries, runs, prometheus.NewGauge(prometheus.GaugeOpts{Name: "go_goroutines"}))
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByNameFn = func(ctx context.Context, orgID platform.ID, name string) (*influxdb.Bucket, error) {
+		require.Equal(t, platform.ID(1), orgID)
+		require.Equal(t, influxdb.MonitoringSystemBucketName, name)
+		return &influxdb.Bucket{ID: 2, OrgID: orgID, Name: name}, nil
+	}
+
+	var written []string
+	writer := &mock.PointsWriter{}
+	writer.WritePointsFn = func(ctx context.Context, orgID platform.ID, bucketID platform.ID, points []models.Point) error {
+		require.Equal(t, platform.ID(1), orgID)
+		require.Equal(t, platform.ID(2), bucketID)
+		for _, p := range points {
+			written = append(written, p.String())
+		}
+		return nil
+	}
+
+	m := NewSelfMonitor(zaptest.NewLogger(t), reg, buckets, writer, 1, time.Minute)
+	require.NoError(t, m.write(context.Background(), time.Unix(0, 10)))
+	require.ElementsMatch(t, []string{
+		"qc_requests_total counter=3 10",
+		"task_executor_total_runs_active,task=a gauge=2 10",
+	}, written)
+
+	// All the metrics are written without prefixes.
+	written = nil
+	m.Prefixes = nil
+	require.NoError(t, m.write(context.Background(), time.Unix(0, 10)))
+	require.Len(t, written, 3)
+}
@@ -37,6 +37,10 @@ type APIBackend struct {
 	UIDisabled     bool   // if true requests for the UI will return 404
 	Logger         *zap.Logger
 	FluxLogEnabled bool
+	// QueryResults keeps the results of the queries so they can be downloaded again.
+	QueryResults *QueryResultStore
+	// QuerySigner signs the URLs running a query without a token.
+	QuerySigner *QuerySigner
 	errors.HTTPErrorHandler
 	SessionRenewDisabled bool
 	// MaxBatchSizeBytes is the maximum number of bytes which can be written
@@ -184,6 +188,8 @@ func NewAPIHandler(b *APIBackend, opts ...APIHandlerOptFn) *APIHandler {
 	taskBackend.TaskService = authorizer.NewTaskService(taskLogger, b.TaskService)
 	taskHandler := NewTaskHandler(b.Logger, taskBackend)
 	h.Mount(prefixTasks, taskHandler)
+	h.Mount(prefixDownsamplingTasks, taskHandler)
+	h.Mount(prefixDeletedTasks, taskHandler)
 
 	telegrafBackend := NewTelegrafBackend(b.Logger.With(zap.String("handler", "telegraf")), b)
 	telegrafBackend.TelegrafService = authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService)
@@ -8,20 +8,19 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/influxdata/flux/iocounter"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/influxql"
 	"github.com/influxdata/influxdb/v2/kit/platform/errors"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	iql "github.com/influxdata/influxql"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
-const (
-	traceIDHeader = "Trace-Id"
-)
-
 func (h *InfluxqlHandler) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		h.Metrics.Requests,
@@ -35,7 +34,7 @@ func (h *InfluxqlHandler) handleInfluxqldQuery(w http.ResponseWriter, r *http.Re
 	defer span.Finish()
 
 	if id, _, found := tracing.InfoFromSpan(span); found {
-		w.Header().Set(traceIDHeader, id)
+		w.Header().Set(kithttp.TraceIDHeader, id)
 	}
 
 	ctx := r.Context()
@@ -144,22 +143,50 @@ func (h *InfluxqlHandler) handleInfluxqldQuery(w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	// Proxies can guard against queries reading too much data with
+	// a default time range and a lower bucket limit.
+	var defaultRange time.Duration
+	if s := r.FormValue("default_range"); s != "" {
+		if defaultRange, err = iql.ParseDuration(s); err != nil || defaultRange <= 0 {
+			h.HandleHTTPError(ctx, &errors.Error{
+				Code: errors.EInvalid,
+				Msg:  "default_range must be a positive duration",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+	var maxBuckets int
+	if s := r.FormValue("max_buckets"); s != "" {
+		if maxBuckets, err = strconv.Atoi(s); err != nil || maxBuckets <= 0 {
+			h.HandleHTTPError(ctx, &errors.Error{
+				Code: errors.EInvalid,
+				Msg:  "max_buckets must be a positive integer",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
 	formatString := r.Header.Get("Accept")
 	encodingFormat := influxql.EncodingFormatFromMimeType(formatString)
 	w.Header().Set("Content-Type", encodingFormat.ContentType())
 
 	req := &influxql.QueryRequest{
-		DB:             r.FormValue("db"),
-		RP:             r.FormValue("rp"),
-		Epoch:          r.FormValue("epoch"),
-		EncodingFormat: encodingFormat,
-		OrganizationID: o.ID,
-		Query:          query,
-		Params:         params,
-		Source:         r.Header.Get("User-Agent"),
-		Authorization:  auth,
-		Chunked:        chunked,
-		ChunkSize:      chunkSize,
+		DB:               r.FormValue("db"),
+		RP:               r.FormValue("rp"),
+		Epoch:            r.FormValue("epoch"),
+		EncodingFormat:   encodingFormat,
+		OrganizationID:   o.ID,
+		Query:            query,
+		Params:           params,
+		Source:           r.Header.Get("User-Agent"),
+		Authorization:    auth,
+		Chunked:          chunked,
+		ChunkSize:        chunkSize,
+		SortTags:         r.FormValue("sort_tags") == "true",
+		DefaultTimeRange: defaultRange,
+		MaxBuckets:       maxBuckets,
 	}
 
 	var respSize int64
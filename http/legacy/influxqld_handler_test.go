@@ -3,10 +3,12 @@ package legacy
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	platform "github.com/influxdata/influxdb/v2"
@@ -223,6 +225,56 @@ func TestInfluxQLdHandler_HandleQuery(t *testing.T) {
 				"Content-Type": {"application/json"},
 			},
 		},
+		{
+			name:    "query limits",
+			context: pcontext.SetAuthorizer(ctx, &platform.Authorization{Status: platform.Active}),
+			fields: fields{
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{}, nil
+					},
+				},
+				ProxyQueryService: &imock.ProxyQueryService{
+					QueryF: func(ctx context.Context, w io.Writer, req *influxql.QueryRequest) (influxql.Statistics, error) {
+						if req.DefaultTimeRange != 7*24*time.Hour || req.MaxBuckets != 100 {
+							return influxql.Statistics{}, fmt.Errorf("unexpected limits: %s, %d", req.DefaultTimeRange, req.MaxBuckets)
+						}
+						_, err := io.WriteString(w, "good")
+						return influxql.Statistics{}, err
+					},
+				},
+			},
+			args: args{
+				r: httptest.NewRequest("POST", "/query?default_range=7d&max_buckets=100", nil).WithContext(ctx),
+				w: httptest.NewRecorder(),
+			},
+			wantBody: []byte("good"),
+			wantCode: http.StatusOK,
+			wantHeader: http.Header{
+				"Content-Type": {"application/json"},
+			},
+		},
+		{
+			name:    "invalid max buckets",
+			context: pcontext.SetAuthorizer(ctx, &platform.Authorization{Status: platform.Active}),
+			fields: fields{
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{}, nil
+					},
+				},
+			},
+			args: args{
+				r: httptest.NewRequest("POST", "/query?max_buckets=0", nil).WithContext(ctx),
+				w: httptest.NewRecorder(),
+			},
+			wantCode: http.StatusBadRequest,
+			wantHeader: http.Header{
+				"Content-Type":          {"application/json; charset=utf-8"},
+				"X-Platform-Error-Code": {"invalid"},
+			},
+			wantBody: []byte(`{"code":"invalid","message":"max_buckets must be a positive integer"}`),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
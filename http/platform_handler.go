@@ -34,6 +34,9 @@ func NewPlatformHandler(b *APIBackend, opts ...APIHandlerOptFn) *PlatformHandler
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/swagger.json")
+	if b.QuerySigner != nil {
+		h.RegisterNoAuthRoute("GET", prefixSignedQuery)
+	}
 
 	assetHandler := static.NewAssetHandler(b.AssetsPath)
 	if b.UIDisabled {
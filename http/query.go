@@ -8,6 +8,8 @@ import (
 	"io"
 	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -50,6 +52,11 @@ type QueryRequest struct {
 	// To obtain a QueryRequest with no result but runtime errors,
 	// add the header `Prefer: return-no-content-with-error` to the HTTP request.
 	PreferNoContentWithError bool
+	// PreferTable specifies that the results are rendered as aligned text
+	// tables instead of annotated CSV, to be read in a terminal.
+	// To obtain them, add the header `Accept: text/plain` to the HTTP request,
+	// with text/plain as the single most preferred media type.
+	PreferTable bool
 }
 
 // QueryDialect is the formatting options for the query response.
@@ -59,6 +66,8 @@ type QueryDialect struct {
 	CommentPrefix  string   `json:"commentPrefix"`
 	DateTimeFormat string   `json:"dateTimeFormat"`
 	Annotations    []string `json:"annotations"`
+	// LineTerminator ends each line of the response, either "\r\n" (the default) or "\n".
+	LineTerminator string `json:"lineTerminator,omitempty"`
 }
 
 // WithDefaults adds default values to the request.
@@ -116,6 +125,12 @@ func (r QueryRequest) Validate() error {
 		return fmt.Errorf(`unknown dialect date time format: %s`, r.Dialect.DateTimeFormat)
 	}
 
+	switch r.Dialect.LineTerminator {
+	case "", "\r\n", "\n":
+	default:
+		return fmt.Errorf(`invalid dialect line terminator: must be "\r\n" or "\n"`)
+	}
+
 	return nil
 }
 
@@ -214,6 +229,8 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 	var dialect flux.Dialect
 	if r.PreferNoContent {
 		dialect = &query.NoContentDialect{}
+	} else if r.PreferTable {
+		dialect = query.NewTableDialect()
 	} else {
 		// TODO(nathanielc): Use commentPrefix and dateTimeFormat
 		// once they are supported.
@@ -226,6 +243,10 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 			dialect = &query.NoContentWithErrorDialect{
 				ResultEncoderConfig: encConfig,
 			}
+		} else if r.Dialect.LineTerminator == "\n" {
+			dialect = &query.LFDialect{
+				ResultEncoderConfig: encConfig,
+			}
 		} else {
 			dialect = &csv.Dialect{
 				ResultEncoderConfig: encConfig,
@@ -267,10 +288,20 @@ func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error
 		qr.Dialect.CommentPrefix = "#"
 		qr.Dialect.DateTimeFormat = "RFC3339"
 		qr.Dialect.Annotations = d.ResultEncoderConfig.Annotations
+	case *query.LFDialect:
+		var header = !d.ResultEncoderConfig.NoHeader
+		qr.Dialect.Header = &header
+		qr.Dialect.Delimiter = string(d.ResultEncoderConfig.Delimiter)
+		qr.Dialect.CommentPrefix = "#"
+		qr.Dialect.DateTimeFormat = "RFC3339"
+		qr.Dialect.Annotations = d.ResultEncoderConfig.Annotations
+		qr.Dialect.LineTerminator = "\n"
 	case *query.NoContentDialect:
 		qr.PreferNoContent = true
 	case *query.NoContentWithErrorDialect:
 		qr.PreferNoContentWithError = true
+	case *query.TableDialect:
+		qr.PreferTable = true
 	default:
 		return nil, fmt.Errorf("unsupported dialect %T", d)
 	}
@@ -314,6 +345,8 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc influxdb.Organ
 		req.PreferNoContentWithError = true
 	}
 
+	req.PreferTable = acceptsPlainText(r)
+
 	req = req.WithDefaults()
 	if err := req.Validate(); err != nil {
 		return nil, body.bytesRead, err
@@ -323,6 +356,39 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc influxdb.Organ
 	return &req, body.bytesRead, err
 }
 
+// acceptsPlainText reports whether the client of r prefers a text/plain response.
+// text/plain must be the media type of the highest quality in the Accept header,
+// with no other type of the same quality, so clients that merely accept it among
+// other types, such as "application/json, text/plain, */*", still get CSV.
+func acceptsPlainText(r *http.Request) bool {
+	plain, best := 0.0, 0.0
+	ties := 0
+	for _, v := range r.Header.Values("Accept") {
+		for _, accept := range strings.Split(v, ",") {
+			mt, params, err := mime.ParseMediaType(accept)
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if s, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(s, 64); err != nil {
+					continue
+				}
+			}
+			if mt == "text/plain" && q > plain {
+				plain = q
+			}
+			switch {
+			case q > best:
+				best, ties = q, 1
+			case q == best:
+				ties++
+			}
+		}
+	}
+	return plain > 0 && plain == best && ties == 1
+}
+
 type countReader struct {
 	bytesRead int
 	io.Reader
@@ -8,11 +8,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/NYTimes/gziphandler"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/complete"
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/iocounter"
 	"github.com/influxdata/flux/lang"
@@ -35,8 +37,10 @@ import (
 )
 
 const (
-	prefixQuery   = "/api/v2/query"
-	traceIDHeader = "Trace-Id"
+	prefixQuery = "/api/v2/query"
+	// queryStatisticsTrailer is the response trailer holding the statistics of a query
+	// as JSON. It is only sent to the clients accepting trailers with "TE: trailers".
+	queryStatisticsTrailer = "Influx-Query-Statistics"
 )
 
 // FluxBackend is all services and associated parameters required to construct
@@ -52,20 +56,33 @@ type FluxBackend struct {
 	ProxyQueryService   query.ProxyQueryService
 	FluxLanguageService fluxlang.FluxLanguageService
 	Flagger             feature.Flagger
+
+	// QueryResults keeps the results of the queries so they can be downloaded again.
+	// It is nil when the results are not kept.
+	QueryResults *QueryResultStore
+	// QuerySigner signs the URLs running a query without a token.
+	// It is nil when signed URLs are disabled.
+	QuerySigner          *QuerySigner
+	AuthorizationService influxdb.AuthorizationService
+	UserService          influxdb.UserService
 }
 
 // NewFluxBackend returns a new instance of FluxBackend.
 func NewFluxBackend(log *zap.Logger, b *APIBackend) *FluxBackend {
 	return &FluxBackend{
-		HTTPErrorHandler:    b.HTTPErrorHandler,
-		log:                 log,
-		FluxLogEnabled:      b.FluxLogEnabled,
-		QueryEventRecorder:  b.QueryEventRecorder,
-		AlgoWProxy:          b.AlgoWProxy,
-		ProxyQueryService:   b.FluxService,
-		OrganizationService: b.OrganizationService,
-		FluxLanguageService: b.FluxLanguageService,
-		Flagger:             b.Flagger,
+		HTTPErrorHandler:     b.HTTPErrorHandler,
+		log:                  log,
+		FluxLogEnabled:       b.FluxLogEnabled,
+		QueryEventRecorder:   b.QueryEventRecorder,
+		AlgoWProxy:           b.AlgoWProxy,
+		ProxyQueryService:    b.FluxService,
+		OrganizationService:  b.OrganizationService,
+		FluxLanguageService:  b.FluxLanguageService,
+		Flagger:              b.Flagger,
+		QueryResults:         b.QueryResults,
+		QuerySigner:          b.QuerySigner,
+		AuthorizationService: b.AuthorizationService,
+		UserService:          b.UserService,
 	}
 }
 
@@ -89,6 +106,12 @@ type FluxHandler struct {
 	EventRecorder metric.EventRecorder
 
 	Flagger feature.Flagger
+
+	QueryResults *QueryResultStore
+
+	QuerySigner          *QuerySigner
+	AuthorizationService influxdb.AuthorizationService
+	UserService          influxdb.UserService
 }
 
 // Prefix provides the route prefix.
@@ -105,11 +128,15 @@ func NewFluxHandler(log *zap.Logger, b *FluxBackend) *FluxHandler {
 		log:              log,
 		FluxLogEnabled:   b.FluxLogEnabled,
 
-		ProxyQueryService:   b.ProxyQueryService,
-		OrganizationService: b.OrganizationService,
-		EventRecorder:       b.QueryEventRecorder,
-		FluxLanguageService: b.FluxLanguageService,
-		Flagger:             b.Flagger,
+		ProxyQueryService:    b.ProxyQueryService,
+		OrganizationService:  b.OrganizationService,
+		EventRecorder:        b.QueryEventRecorder,
+		FluxLanguageService:  b.FluxLanguageService,
+		Flagger:              b.Flagger,
+		QueryResults:         b.QueryResults,
+		QuerySigner:          b.QuerySigner,
+		AuthorizationService: b.AuthorizationService,
+		UserService:          b.UserService,
 	}
 
 	// query reponses can optionally be gzip encoded
@@ -119,6 +146,13 @@ func NewFluxHandler(log *zap.Logger, b *FluxBackend) *FluxHandler {
 	h.Handler("POST", "/api/v2/query/analyze", withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.postQueryAnalyze)))
 	h.Handler("GET", "/api/v2/query/suggestions", withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.getFluxSuggestions)))
 	h.Handler("GET", "/api/v2/query/suggestions/:name", withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.getFluxSuggestion)))
+	if h.QueryResults != nil {
+		h.Handler("GET", "/api/v2/query/results/:id", gziphandler.GzipHandler(http.HandlerFunc(h.getQueryResult)))
+	}
+	if h.QuerySigner != nil {
+		h.Handler("POST", "/api/v2/query/sign", http.HandlerFunc(h.postSignQuery))
+		h.Handler("GET", prefixSignedQuery, gziphandler.GzipHandler(http.HandlerFunc(h.getSignedQuery)))
+	}
 	return h
 }
 
@@ -130,7 +164,7 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := h.log.With(logger.TraceFields(ctx)...)
 	if id, _, found := tracing.InfoFromContext(ctx); found {
-		w.Header().Set(traceIDHeader, id)
+		w.Header().Set(kithttp.TraceIDHeader, id)
 	}
 
 	// TODO(desa): I really don't like how we're recording the usage metrics here
@@ -193,8 +227,25 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	hd.SetHeaders(w)
+	sendStats := acceptsTrailers(r)
+	if sendStats {
+		w.Header().Set("Trailer", queryStatisticsTrailer)
+	}
+
+	// Keep the result of the query so the client can download it again if it gets
+	// disconnected, when the client asks for it. The query is then not canceled
+	// when the client disconnects.
+	var out io.Writer = w
+	if h.QueryResults != nil && strings.EqualFold(r.Header.Get(queryResumableHeader), "true") {
+		if res, ok := h.QueryResults.create(a.GetUserID(), w.Header().Get("Content-Type")); ok {
+			defer func() { res.finish(h.QueryResults.now()) }()
+			w.Header().Set(queryResultIDHeader, res.id.String())
+			out = &resumableWriter{client: w, result: res}
+			ctx = detachedContext{Context: ctx}
+		}
+	}
 
-	cw := iocounter.Writer{Writer: w}
+	cw := iocounter.Writer{Writer: out}
 	stats, err := h.ProxyQueryService.Query(ctx, &cw, req)
 	if err != nil {
 		if cw.Count() == 0 {
@@ -209,6 +260,14 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 		)
 	}
 
+	if sendStats {
+		// The profiles of the transformations are left out to keep the trailer small.
+		stats.Profiles = nil
+		if b, err := json.Marshal(stats); err == nil {
+			w.Header().Set(queryStatisticsTrailer, string(b))
+		}
+	}
+
 	// Detailed logging for flux queries if enabled
 	if h.FluxLogEnabled {
 		h.logFluxQuery(cw.Count(), stats, req.Request.Compiler, err)
@@ -216,6 +275,18 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// acceptsTrailers reports whether the client of r accepts trailers in the response.
+func acceptsTrailers(r *http.Request) bool {
+	for _, v := range r.Header.Values("TE") {
+		for _, te := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(te), "trailers") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (h *FluxHandler) logFluxQuery(n int64, stats flux.Statistics, compiler flux.Compiler, err error) {
 	var q string
 	c, ok := compiler.(lang.FluxCompiler)
@@ -318,6 +389,42 @@ type fluxParams map[string]string
 type suggestionResponse struct {
 	Name   string     `json:"name"`
 	Params fluxParams `json:"params"`
+	// Required lists the parameters that must be passed to the function,
+	// the pipe parameter excluded.
+	Required []string `json:"required,omitempty"`
+	// Signature is the type of the function, as documented by Flux.
+	Signature string `json:"signature,omitempty"`
+}
+
+// newSuggestionResponse describes the parameters and the signature of the Flux function name.
+func newSuggestionResponse(completer complete.Completer, name string) (suggestionResponse, error) {
+	suggestion, err := completer.FunctionSuggestion(name)
+	if err != nil {
+		return suggestionResponse{}, err
+	}
+	v, err := completer.Value(name)
+	if err != nil {
+		return suggestionResponse{}, err
+	}
+
+	ft := v.Type()
+	args, err := ft.SortedArguments()
+	if err != nil {
+		return suggestionResponse{}, err
+	}
+	var required []string
+	for _, arg := range args {
+		if !arg.Optional() && !arg.Pipe() {
+			required = append(required, string(arg.Name()))
+		}
+	}
+
+	return suggestionResponse{
+		Name:      name,
+		Params:    suggestion.Params,
+		Required:  required,
+		Signature: ft.CanonicalString(),
+	}, nil
 }
 
 // suggestionsResponse provides a list of available Flux functions
@@ -335,7 +442,7 @@ func (h *FluxHandler) getFluxSuggestions(w http.ResponseWriter, r *http.Request)
 	names := completer.FunctionNames()
 	var functions []suggestionResponse
 	for _, name := range names {
-		suggestion, err := completer.FunctionSuggestion(name)
+		suggestion, err := newSuggestionResponse(completer, name)
 		if err != nil {
 			h.HandleHTTPError(ctx, err, w)
 			return
@@ -349,11 +456,9 @@ func (h *FluxHandler) getFluxSuggestions(w http.ResponseWriter, r *http.Request)
 
 			filteredParams[key] = value
 		}
+		suggestion.Params = filteredParams
 
-		functions = append(functions, suggestionResponse{
-			Name:   name,
-			Params: filteredParams,
-		})
+		functions = append(functions, suggestion)
 	}
 	res := suggestionsResponse{Functions: functions}
 
@@ -372,13 +477,12 @@ func (h *FluxHandler) getFluxSuggestion(w http.ResponseWriter, r *http.Request)
 	name := httprouter.ParamsFromContext(ctx).ByName("name")
 	completer := h.FluxLanguageService.Completer()
 
-	suggestion, err := completer.FunctionSuggestion(name)
+	res, err := newSuggestionResponse(completer, name)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
-	res := suggestionResponse{Name: name, Params: suggestion.Params}
 	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
 		logEncodingError(h.log, r, err)
 		return
@@ -546,6 +650,19 @@ func (s FluxQueryService) Check(ctx context.Context) check.Response {
 	return QueryHealthCheck(s.Addr, s.InsecureSkipVerify)
 }
 
+var _ query.AsyncQueryService = (*FluxAsyncQueryService)(nil)
+
+// FluxAsyncQueryService implements query.AsyncQueryService by making HTTP requests to the /api/v2/query API endpoint.
+// The results are decoded from the CSV response as they are streamed back from the server.
+type FluxAsyncQueryService struct {
+	FluxQueryService
+}
+
+// Query runs a flux query against a influx server and returns a query whose results are decoded from the response.
+func (s *FluxAsyncQueryService) Query(ctx context.Context, r *query.Request) (flux.Query, error) {
+	return query.AsyncQueryServiceBridge{QueryService: &s.FluxQueryService}.Query(ctx, r)
+}
+
 // GetQueryResponse runs a flux query with common parameters and returns the response from the query service.
 func GetQueryResponse(qr *QueryRequest, addr *url.URL, org, token string, headers ...string) (*http.Response, error) {
 	if len(headers)%2 != 0 {
@@ -16,8 +16,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/complete"
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 	"github.com/influxdata/influxdb/v2"
 	icontext "github.com/influxdata/influxdb/v2/context"
 	"github.com/influxdata/influxdb/v2/http/metric"
@@ -697,3 +700,129 @@ func benchmarkQuery(b *testing.B, disableCompression bool) {
 
 	}
 }
+
+// completerLanguageService is a FluxLanguageService that completes the names in scope.
+type completerLanguageService struct {
+	fluxlang.FluxLanguageService
+	scope values.Scope
+}
+
+func (s completerLanguageService) Completer() complete.Completer {
+	return complete.NewCompleter(s.scope)
+}
+
+func TestFluxHandler_getFluxSuggestion(t *testing.T) {
+	scope := values.NewScope()
+	ft := semantic.NewFunctionType(semantic.BasicInt, []semantic.ArgumentType{
+		{Name: []byte("n"), Type: semantic.BasicInt},
+		{Name: []byte("column"), Type: semantic.BasicString},
+	})
+	scope.Set("limit", values.NewFunction("limit", ft, nil, false))
+	scope.Set("x", values.NewInt(1))
+
+	h := NewFluxHandler(zaptest.NewLogger(t), &FluxBackend{
+		HTTPErrorHandler:    kithttp.NewErrorHandler(zaptest.NewLogger(t)),
+		FluxLanguageService: completerLanguageService{scope: scope},
+	})
+
+	get := func(path string, v interface{}) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+		}
+		if err := json.NewDecoder(w.Body).Decode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got suggestionResponse
+	get("/api/v2/query/suggestions/limit", &got)
+	want := suggestionResponse{
+		Name:      "limit",
+		Params:    fluxParams{"n": "int", "column": "string"},
+		Required:  []string{"column", "n"},
+		Signature: "(column: string, n: int) => int",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected suggestion -want/+got:\n%s", diff)
+	}
+
+	// The list of suggestions only includes functions.
+	var list suggestionsResponse
+	get("/api/v2/query/suggestions", &list)
+	if diff := cmp.Diff(suggestionsResponse{Functions: []suggestionResponse{want}}, list); diff != "" {
+		t.Fatalf("unexpected suggestions -want/+got:\n%s", diff)
+	}
+}
+
+func TestFluxHandler_PostQuery_StatisticsTrailer(t *testing.T) {
+	orgService := &influxmock.OrganizationService{
+		FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+			return &influxdb.Organization{ID: platform.ID(1), Name: platform.ID(1).String()}, nil
+		},
+	}
+	queryService := &mock.ProxyQueryService{
+		QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+			_, _ = w.Write([]byte("#datatype,string,long\n,result,table\n,_result,0\n"))
+			return flux.Statistics{
+				TotalDuration: time.Second,
+				Concurrency:   2,
+				Profiles:      []flux.TransportProfile{{NodeType: "from"}},
+			}, nil
+		},
+	}
+	h := NewFluxHandler(zaptest.NewLogger(t), &FluxBackend{
+		HTTPErrorHandler:    kithttp.NewErrorHandler(zaptest.NewLogger(t)),
+		log:                 zaptest.NewLogger(t),
+		QueryEventRecorder:  noopEventRecorder{},
+		OrganizationService: orgService,
+		ProxyQueryService:   queryService,
+		FluxLanguageService: fluxlang.DefaultService,
+		Flagger:             feature.DefaultFlagger(),
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a := &influxdb.Authorization{ID: 1, OrgID: 1, Permissions: influxdb.OperPermissions()}
+		h.ServeHTTP(w, r.WithContext(icontext.SetAuthorizer(r.Context(), a)))
+	}))
+	defer ts.Close()
+
+	post := func(te string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/v2/query?orgID=0000000000000001", strings.NewReader("buckets()"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/vnd.flux")
+		if te != "" {
+			req.Header.Set("TE", te)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The trailers are only read with the body.
+		if _, err := io.ReadAll(res.Body); err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status code %s", res.Status)
+		}
+		return res
+	}
+
+	res := post("gzip, trailers")
+	var stats flux.Statistics
+	if err := json.Unmarshal([]byte(res.Trailer.Get(queryStatisticsTrailer)), &stats); err != nil {
+		t.Fatalf("unable to decode statistics trailer %q: %v", res.Trailer.Get(queryStatisticsTrailer), err)
+	}
+	if stats.TotalDuration != time.Second || stats.Concurrency != 2 || stats.Profiles != nil {
+		t.Fatalf("unexpected statistics: %+v", stats)
+	}
+
+	// The statistics are not sent to the clients that don't accept trailers.
+	if res := post(""); res.Trailer.Get(queryStatisticsTrailer) != "" {
+		t.Fatalf("unexpected statistics trailer: %q", res.Trailer.Get(queryStatisticsTrailer))
+	}
+}
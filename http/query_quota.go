@@ -0,0 +1,134 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kit/platform/errors"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/query/control"
+	"go.uber.org/zap"
+)
+
+const prefixQueryQuotas = "/api/v2/quotas/query"
+
+// QueryQuotaService manages the quotas overriding the query controller defaults for an organization.
+// It is implemented by *control.Controller.
+type QueryQuotaService interface {
+	OrgQuotas() map[platform.ID]control.OrgQuota
+	SetOrgQuota(orgID platform.ID, quota control.OrgQuota) error
+	DeleteOrgQuota(orgID platform.ID)
+}
+
+// QueryQuotaHandler lets operators read and change the query quotas of organizations at runtime.
+// The changes are not persisted: on restart, the quotas are the ones given by the
+// query-org-concurrency-overrides and query-org-memory-bytes-overrides options of influxd.
+type QueryQuotaHandler struct {
+	chi.Router
+
+	log *zap.Logger
+	api *kithttp.API
+	svc QueryQuotaService
+}
+
+// NewQueryQuotaHandler creates a handler for the query quotas of svc.
+func NewQueryQuotaHandler(log *zap.Logger, svc QueryQuotaService) *QueryQuotaHandler {
+	h := &QueryQuotaHandler{
+		log: log,
+		api: kithttp.NewAPI(kithttp.WithLog(log)),
+		svc: svc,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+		h.mwAuthorize,
+	)
+
+	r.Get("/", h.handleGetQuotas)
+	r.Route("/{orgID}", func(r chi.Router) {
+		r.Put("/", h.handlePutQuota)
+		r.Delete("/", h.handleDeleteQuota)
+	})
+	h.Router = r
+	return h
+}
+
+func (h *QueryQuotaHandler) Prefix() string {
+	return prefixQueryQuotas
+}
+
+type queryQuotasResponse struct {
+	Quotas map[platform.ID]control.OrgQuota `json:"quotas"`
+}
+
+func (h *QueryQuotaHandler) handleGetQuotas(w http.ResponseWriter, r *http.Request) {
+	h.api.Respond(w, r, http.StatusOK, queryQuotasResponse{Quotas: h.svc.OrgQuotas()})
+}
+
+func (h *QueryQuotaHandler) handlePutQuota(w http.ResponseWriter, r *http.Request) {
+	orgID, err := decodeQueryQuotaOrgID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	var quota control.OrgQuota
+	if err := h.api.DecodeJSON(r.Body, &quota); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.svc.SetOrgQuota(orgID, quota); err != nil {
+		h.api.Err(w, r, &errors.Error{
+			Code: errors.EInvalid,
+			Err:  err,
+		})
+		return
+	}
+	h.api.Respond(w, r, http.StatusOK, quota)
+}
+
+func (h *QueryQuotaHandler) handleDeleteQuota(w http.ResponseWriter, r *http.Request) {
+	orgID, err := decodeQueryQuotaOrgID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.svc.DeleteOrgQuota(orgID)
+	h.api.Respond(w, r, http.StatusNoContent, nil)
+}
+
+func decodeQueryQuotaOrgID(r *http.Request) (platform.ID, error) {
+	orgID, err := platform.IDFromString(chi.URLParam(r, "orgID"))
+	if err != nil {
+		return 0, &errors.Error{
+			Code: errors.EInvalid,
+			Msg:  "invalid organization id",
+			Err:  err,
+		}
+	}
+	return *orgID, nil
+}
+
+func (h *QueryQuotaHandler) mwAuthorize(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if err := authorizer.IsAllowedAll(r.Context(), influxdb.OperPermissions()); err != nil {
+			h.api.Err(w, r, &errors.Error{
+				Code: errors.EUnauthorized,
+				Msg:  fmt.Sprintf("access to %s requires operator permissions", h.Prefix()),
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	influxdbcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/query/control"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestQueryQuotaHandler(t *testing.T) {
+	ctrl, err := control.New(control.Config{
+		InitialMemoryBytesQuotaPerQuery: 1024,
+		MemoryBytesQuotaPerQuery:        4096,
+	}, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ctrl.Shutdown(context.Background()))
+	}()
+
+	h := NewQueryQuotaHandler(zaptest.NewLogger(t), ctrl)
+	do := func(method, path string, body interface{}) *http.Response {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		r, err := http.NewRequest(method, path, &buf)
+		require.NoError(t, err)
+		ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, influxdb.OperPermissions()))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r.WithContext(ctx))
+		return rr.Result()
+	}
+
+	quota := control.OrgQuota{MemoryBytesQuotaPerQuery: 1 << 20, ConcurrencyQuota: 2}
+	rs := do(http.MethodPut, "/000000000000000a", quota)
+	require.Equal(t, http.StatusOK, rs.StatusCode)
+	require.Equal(t, map[platform.ID]control.OrgQuota{10: quota}, ctrl.OrgQuotas())
+
+	rs = do(http.MethodGet, "/", nil)
+	require.Equal(t, http.StatusOK, rs.StatusCode)
+	var got queryQuotasResponse
+	require.NoError(t, json.NewDecoder(rs.Body).Decode(&got))
+	require.Equal(t, map[platform.ID]control.OrgQuota{10: quota}, got.Quotas)
+
+	// the memory quota of an organization cannot be lower than the initial memory of a query
+	rs = do(http.MethodPut, "/000000000000000a", control.OrgQuota{MemoryBytesQuotaPerQuery: 512})
+	require.Equal(t, http.StatusBadRequest, rs.StatusCode)
+	require.Equal(t, map[platform.ID]control.OrgQuota{10: quota}, ctrl.OrgQuotas())
+
+	rs = do(http.MethodPut, "/not-an-id", quota)
+	require.Equal(t, http.StatusBadRequest, rs.StatusCode)
+
+	rs = do(http.MethodDelete, "/000000000000000a", nil)
+	require.Equal(t, http.StatusNoContent, rs.StatusCode)
+	require.Empty(t, ctrl.OrgQuotas())
+}
+
+func TestQueryQuotaHandler_Authorization(t *testing.T) {
+	h := NewQueryQuotaHandler(zaptest.NewLogger(t), nil)
+
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), mock.NewMockAuthorizer(false, influxdb.ReadAllPermissions()))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r.WithContext(ctx))
+
+	require.Equal(t, http.StatusUnauthorized, rr.Result().StatusCode)
+}
@@ -0,0 +1,380 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/httprouter"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	errors2 "github.com/influxdata/influxdb/v2/kit/platform/errors"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/snowflake"
+)
+
+const (
+	// queryResumableHeader is the request header with which a client opts in
+	// to keep the result of its query, so it can download it again after it got
+	// disconnected. The query then keeps running when its client disconnects.
+	queryResumableHeader = "Influx-Query-Resumable"
+
+	// queryResultIDHeader is the response header holding the ID used to download
+	// the results of a query again after the client got disconnected.
+	queryResultIDHeader = "Influx-Query-Result-Id"
+)
+
+var (
+	errQueryResultTooLarge = &errors2.Error{
+		Code: errors2.ETooLarge,
+		Msg:  "query result is too large to be kept",
+	}
+
+	errQueryResultsFull = &errors2.Error{
+		Code: errors2.ETooLarge,
+		Msg:  "too many query results are kept to keep this one",
+	}
+)
+
+// QueryResultStore keeps the encoded response of the queries for a while after they complete,
+// so a client that got disconnected while downloading it can reconnect and resume the download.
+// A query keeps running when its client disconnects as long as its result can be kept.
+//
+// When the store is full, the oldest completed results are evicted to make room for
+// new ones. The results of the queries still running are never evicted: a new result
+// that does not fit is only sent to its client, as if the store was not used.
+type QueryResultStore struct {
+	// Retention is how long the result of a query is kept after the query completes.
+	Retention time.Duration
+	// MaxBytes is the maximum size of a result that is kept.
+	// The larger results are only sent to the client, as if the store was not used.
+	MaxBytes int64
+	// MaxTotalBytes is the maximum size of all the results kept, 0 for no limit.
+	MaxTotalBytes int64
+	// MaxResults is the maximum number of results kept, 0 for no limit.
+	MaxResults int
+
+	idGenerator platform.IDGenerator
+	now         func() time.Time
+
+	mu         sync.Mutex
+	results    map[platform.ID]*queryResult
+	totalBytes int64
+}
+
+// NewQueryResultStore creates a store keeping the results of up to maxBytes for retention,
+// and up to maxResults results of maxTotalBytes altogether.
+func NewQueryResultStore(retention time.Duration, maxBytes, maxTotalBytes int64, maxResults int) *QueryResultStore {
+	return &QueryResultStore{
+		Retention:     retention,
+		MaxBytes:      maxBytes,
+		MaxTotalBytes: maxTotalBytes,
+		MaxResults:    maxResults,
+		idGenerator:   snowflake.NewIDGenerator(),
+		now:           time.Now,
+		results:       make(map[platform.ID]*queryResult),
+	}
+}
+
+// create adds a new result for a query submitted by userID.
+// It returns false when the store is full of the results of running queries.
+func (s *QueryResultStore) create(userID platform.ID, contentType string) (*queryResult, bool) {
+	res := &queryResult{
+		id:          s.idGenerator.ID(),
+		userID:      userID,
+		contentType: contentType,
+		store:       s,
+	}
+	res.cond = sync.NewCond(&res.mu)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	if s.MaxResults > 0 {
+		s.evict(func() bool { return len(s.results) >= s.MaxResults })
+		if len(s.results) >= s.MaxResults {
+			return nil, false
+		}
+	}
+	s.results[res.id] = res
+	return res, true
+}
+
+// find returns the result with the given ID if it is still kept.
+func (s *QueryResultStore) find(id platform.ID) (*queryResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	res, ok := s.results[id]
+	return res, ok
+}
+
+// reserve accounts for n more bytes of res, evicting the oldest completed results
+// if all the results would not fit in the store anymore. When res cannot grow,
+// it is removed from the store and the reason is returned.
+func (s *QueryResultStore) reserve(res *queryResult, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.results[res.id]; !ok {
+		return errQueryResultsFull
+	}
+	if res.size+int64(n) > s.MaxBytes {
+		s.remove(res)
+		return errQueryResultTooLarge
+	}
+	if s.MaxTotalBytes > 0 {
+		s.evict(func() bool { return s.totalBytes+int64(n) > s.MaxTotalBytes })
+		if s.totalBytes+int64(n) > s.MaxTotalBytes {
+			s.remove(res)
+			return errQueryResultsFull
+		}
+	}
+	res.size += int64(n)
+	s.totalBytes += int64(n)
+	return nil
+}
+
+// expire removes the results that were completed longer than the retention ago
+// and the results that could not be kept. The store mutex must be held.
+func (s *QueryResultStore) expire() {
+	now := s.now()
+	for _, res := range s.results {
+		if res.expired(now, s.Retention) {
+			s.remove(res)
+		}
+	}
+}
+
+// evict removes the oldest completed results while full reports that the store
+// is full. The store mutex must be held.
+func (s *QueryResultStore) evict(full func() bool) {
+	if !full() {
+		return
+	}
+
+	var completed []*queryResult
+	doneAt := make(map[platform.ID]time.Time)
+	for _, res := range s.results {
+		if t, ok := res.completedAt(); ok {
+			completed = append(completed, res)
+			doneAt[res.id] = t
+		}
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		return doneAt[completed[i].id].Before(doneAt[completed[j].id])
+	})
+
+	for _, res := range completed {
+		if !full() {
+			return
+		}
+		s.remove(res)
+	}
+}
+
+// remove drops res from the store. The clients downloading it can still finish
+// their download. The store mutex must be held.
+func (s *QueryResultStore) remove(res *queryResult) {
+	if _, ok := s.results[res.id]; !ok {
+		return
+	}
+	delete(s.results, res.id)
+	s.totalBytes -= res.size
+}
+
+// queryResult is the encoded response of a single query.
+type queryResult struct {
+	id          platform.ID
+	userID      platform.ID
+	contentType string
+
+	store *QueryResultStore
+	// size is the number of bytes reserved in the store, guarded by the store mutex.
+	size int64
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    []byte
+	err    error
+	done   bool
+	doneAt time.Time
+}
+
+// Write appends p to the result. It returns an error once the result
+// cannot be kept anymore, after which nothing is kept.
+func (r *queryResult) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	err := r.err
+	r.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.store.reserve(r, len(p)); err != nil {
+		r.mu.Lock()
+		r.err, r.buf = err, nil
+		r.cond.Broadcast()
+		r.mu.Unlock()
+		return 0, err
+	}
+
+	r.mu.Lock()
+	r.buf = append(r.buf, p...)
+	r.cond.Broadcast()
+	r.mu.Unlock()
+	return len(p), nil
+}
+
+// finish marks the result as complete.
+func (r *queryResult) finish(now time.Time) {
+	r.mu.Lock()
+	r.done, r.doneAt = true, now
+	r.cond.Broadcast()
+	r.mu.Unlock()
+}
+
+// completedAt returns when the result was completed, if it is complete and kept.
+func (r *queryResult) completedAt() (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.doneAt, r.done && r.err == nil
+}
+
+// expired reports whether the result was completed longer than retention
+// before now, or could not be kept.
+func (r *queryResult) expired(now time.Time, retention time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err != nil || (r.done && now.Sub(r.doneAt) > retention)
+}
+
+// writeTo writes the result from offset to w, waiting for the query to
+// write more until it is complete or ctx is done.
+func (r *queryResult) writeTo(ctx context.Context, w io.Writer, offset int64) error {
+	// Wake up the waiting loop when the client goes away.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.mu.Lock()
+			r.cond.Broadcast()
+			r.mu.Unlock()
+		case <-stop:
+		}
+	}()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		if r.err != nil {
+			return r.err
+		}
+		if offset < int64(len(r.buf)) {
+			p := r.buf[offset:]
+			r.mu.Unlock()
+			n, err := w.Write(p)
+			r.mu.Lock()
+			if err != nil {
+				return err
+			}
+			offset += int64(n)
+			continue
+		}
+		if r.done {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r.cond.Wait()
+	}
+}
+
+// resumableWriter writes the response of a query to the client and to the result
+// kept for it. It keeps writing to the result after the client is gone and
+// only fails once neither the client nor the result can be written.
+type resumableWriter struct {
+	client    io.Writer
+	clientErr error
+	result    *queryResult
+}
+
+func (w *resumableWriter) Write(p []byte) (int, error) {
+	if w.clientErr == nil {
+		_, w.clientErr = w.client.Write(p)
+	}
+	if _, err := w.result.Write(p); err != nil && w.clientErr != nil {
+		return 0, w.clientErr
+	}
+	return len(p), nil
+}
+
+// detachedContext keeps the values of its parent but is never canceled,
+// so a query can outlive the request that submitted it.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// getQueryResult resumes the download of the response of a query.
+// The offset query parameter is the number of bytes of the response already
+// received, counted before any content encoding such as gzip is applied.
+func (h *FluxHandler) getQueryResult(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	id, err := platform.IDFromString(httprouter.ParamsFromContext(ctx).ByName("id"))
+	if err != nil {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.EInvalid,
+			Msg:  "invalid query result id",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	var offset int64
+	if s := r.URL.Query().Get("offset"); s != "" {
+		if offset, err = strconv.ParseInt(s, 10, 64); err != nil || offset < 0 {
+			h.HandleHTTPError(ctx, &errors2.Error{
+				Code: errors2.EInvalid,
+				Msg:  "offset must be a non-negative integer",
+			}, w)
+			return
+		}
+	}
+
+	// Only the user who submitted the query can download its result.
+	res, ok := h.QueryResults.find(*id)
+	if !ok || res.userID != a.GetUserID() {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.ENotFound,
+			Msg:  "query result not found",
+		}, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", res.contentType)
+	w.Header().Set(queryResultIDHeader, res.id.String())
+	cw := kithttp.NewStatusResponseWriter(w)
+	if err := res.writeTo(ctx, cw, offset); err != nil && cw.ResponseBytes() == 0 {
+		h.HandleHTTPError(ctx, err, w)
+	}
+}
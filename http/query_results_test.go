@@ -0,0 +1,236 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/feature"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	querymock "github.com/influxdata/influxdb/v2/query/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// disconnectingWriter fails every write after the first n bytes, like a client that went away.
+type disconnectingWriter struct {
+	n       int
+	written []byte
+}
+
+func (w *disconnectingWriter) Write(p []byte) (int, error) {
+	if len(w.written)+len(p) > w.n {
+		return 0, errors.New("client disconnected")
+	}
+	w.written = append(w.written, p...)
+	return len(p), nil
+}
+
+func TestQueryResultStore_Resume(t *testing.T) {
+	store := NewQueryResultStore(time.Minute, 1024, 0, 0)
+	h := NewFluxHandler(zaptest.NewLogger(t), &FluxBackend{
+		HTTPErrorHandler: kithttp.NewErrorHandler(zaptest.NewLogger(t)),
+		QueryResults:     store,
+	})
+
+	userID := platform.ID(1)
+	res, ok := store.create(userID, "text/csv; charset=utf-8")
+	require.True(t, ok)
+
+	// The client goes away after the first row, the rest of the result is still kept.
+	client := &disconnectingWriter{n: 6}
+	w := &resumableWriter{client: client, result: res}
+	for _, row := range []string{"a,b,c\n", "1,2,3\n", "4,5,6\n"} {
+		n, err := w.Write([]byte(row))
+		require.NoError(t, err)
+		require.Equal(t, len(row), n)
+	}
+	res.finish(store.now())
+	require.Equal(t, "a,b,c\n", string(client.written))
+
+	get := func(path string, userID platform.ID) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		a := &mock.Authorizer{UserID: userID}
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), a))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		return rr
+	}
+
+	rr := get("/api/v2/query/results/"+res.id.String()+"?offset=6", userID)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "1,2,3\n4,5,6\n", rr.Body.String())
+	require.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+
+	rr = get("/api/v2/query/results/"+res.id.String(), userID)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "a,b,c\n1,2,3\n4,5,6\n", rr.Body.String())
+
+	// Only the user who submitted the query can download its result.
+	rr = get("/api/v2/query/results/"+res.id.String(), platform.ID(2))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = get("/api/v2/query/results/"+res.id.String()+"?offset=-1", userID)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// The result is removed once the retention has passed.
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	rr = get("/api/v2/query/results/"+res.id.String(), userID)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestQueryResultStore_WaitForResult(t *testing.T) {
+	store := NewQueryResultStore(time.Minute, 1024, 0, 0)
+	res, ok := store.create(1, "text/csv")
+	require.True(t, ok)
+
+	done := make(chan struct{})
+	var got disconnectingWriter
+	got.n = 1024
+	go func() {
+		defer close(done)
+		require.NoError(t, res.writeTo(context.Background(), &got, 0))
+	}()
+
+	_, err := res.Write([]byte("a,b\n"))
+	require.NoError(t, err)
+	_, err = res.Write([]byte("1,2\n"))
+	require.NoError(t, err)
+	res.finish(store.now())
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the result to be written")
+	}
+	require.Equal(t, "a,b\n1,2\n", string(got.written))
+}
+
+func TestQueryResultStore_TooLarge(t *testing.T) {
+	store := NewQueryResultStore(time.Minute, 8, 0, 0)
+	res, ok := store.create(1, "text/csv")
+	require.True(t, ok)
+
+	// The client still receives a result too large to be kept.
+	client := &disconnectingWriter{n: 12}
+	w := &resumableWriter{client: client, result: res}
+	for _, row := range []string{"a,b\n", "1,2\n", "3,4\n"} {
+		_, err := w.Write([]byte(row))
+		require.NoError(t, err)
+	}
+	require.Equal(t, "a,b\n1,2\n3,4\n", string(client.written))
+
+	_, ok = store.find(res.id)
+	require.False(t, ok)
+
+	// Once the client is gone too, the query is stopped.
+	_, err := w.Write([]byte("5,6\n"))
+	require.Error(t, err)
+}
+
+func TestQueryResultStore_Eviction(t *testing.T) {
+	store := NewQueryResultStore(time.Minute, 8, 12, 2)
+
+	write := func(res *queryResult, p string) error {
+		_, err := res.Write([]byte(p))
+		return err
+	}
+
+	first, ok := store.create(1, "text/csv")
+	require.True(t, ok)
+	require.NoError(t, write(first, "a,b\n1,2\n"))
+	first.finish(store.now())
+
+	second, ok := store.create(1, "text/csv")
+	require.True(t, ok)
+	require.NoError(t, write(second, "a,b\n"))
+
+	// The oldest completed result makes room for the bytes of a new one.
+	require.NoError(t, write(second, "1,2\n"))
+	_, ok = store.find(first.id)
+	require.False(t, ok)
+	_, ok = store.find(second.id)
+	require.True(t, ok)
+
+	// The results of running queries are never evicted, so a new result
+	// is not kept when the store is full of them.
+	third, ok := store.create(1, "text/csv")
+	require.True(t, ok)
+	_, ok = store.create(1, "text/csv")
+	require.False(t, ok)
+
+	require.NoError(t, write(third, "a,b\n"))
+	require.Equal(t, errQueryResultsFull, write(third, "1,2\n"))
+	_, ok = store.find(third.id)
+	require.False(t, ok)
+
+	// A completed result is evicted to keep a new one.
+	second.finish(store.now())
+	fourth, ok := store.create(1, "text/csv")
+	require.True(t, ok)
+	_, ok = store.find(second.id)
+	require.False(t, ok)
+	require.NoError(t, write(fourth, "a,b\n1,2\n"))
+}
+
+func TestFluxHandler_ResumableOptIn(t *testing.T) {
+	var detached bool
+	queryService := &querymock.ProxyQueryService{
+		QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+			detached = ctx.Done() == nil
+			_, _ = w.Write([]byte("#datatype,string,long\n,result,table\n,_result,0\n"))
+			return flux.Statistics{}, nil
+		},
+	}
+	h := NewFluxHandler(zaptest.NewLogger(t), &FluxBackend{
+		HTTPErrorHandler: kithttp.NewErrorHandler(zaptest.NewLogger(t)),
+		log:              zaptest.NewLogger(t),
+		OrganizationService: &mock.OrganizationService{
+			FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return &influxdb.Organization{ID: platform.ID(1), Name: platform.ID(1).String()}, nil
+			},
+		},
+		QueryEventRecorder:  noopEventRecorder{},
+		ProxyQueryService:   queryService,
+		FluxLanguageService: fluxlang.DefaultService,
+		Flagger:             feature.DefaultFlagger(),
+		QueryResults:        NewQueryResultStore(time.Minute, 1024, 0, 0),
+	})
+
+	post := func(resumable string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v2/query?orgID=0000000000000001", strings.NewReader("buckets()"))
+		r.Header.Set("Content-Type", "application/vnd.flux")
+		if resumable != "" {
+			r.Header.Set(queryResumableHeader, resumable)
+		}
+		a := &influxdb.Authorization{ID: 1, UserID: 2, OrgID: 1, Permissions: influxdb.OperPermissions()}
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), a))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		return rr
+	}
+
+	// The results are only kept, and the queries detached from their client,
+	// when the client asks for it.
+	rr := post("")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Empty(t, rr.Header().Get(queryResultIDHeader))
+	require.False(t, detached)
+
+	rr = post("true")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.NotEmpty(t, rr.Header().Get(queryResultIDHeader))
+	require.True(t, detached)
+}
@@ -0,0 +1,275 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	errors2 "github.com/influxdata/influxdb/v2/kit/platform/errors"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+)
+
+const (
+	prefixSignedQuery = "/api/v2/query/signed"
+
+	signedQueryOrgID   = "orgID"
+	signedQueryAuthID  = "authorizationID"
+	signedQueryQuery   = "query"
+	signedQueryExpires = "expires"
+	signedQuerySig     = "signature"
+)
+
+var (
+	errSignedQueryInvalid = &errors2.Error{
+		Code: errors2.EUnauthorized,
+		Msg:  "signed query URL is invalid",
+	}
+	errSignedQueryExpired = &errors2.Error{
+		Code: errors2.EUnauthorized,
+		Msg:  "signed query URL has expired",
+	}
+)
+
+// QuerySigner signs URLs that run a Flux query in an organization with the permissions
+// of the token that requested them, so the results can be fetched without a token until
+// the URL expires. Deactivating or deleting the token, or deactivating its user, revokes
+// all of its URLs.
+//
+// Unless influxd is given a signing key with query-signing-key-path, it generates a
+// new one every time it starts, so the URLs signed before a restart are no longer valid after it.
+type QuerySigner struct {
+	// MaxExpiry is the longest time a signed URL is valid for.
+	MaxExpiry time.Duration
+
+	key []byte
+	now func() time.Time
+}
+
+// NewQuerySigner creates a signer of URLs valid for up to maxExpiry, signed with key.
+func NewQuerySigner(key []byte, maxExpiry time.Duration) *QuerySigner {
+	return &QuerySigner{
+		MaxExpiry: maxExpiry,
+		key:       key,
+		now:       time.Now,
+	}
+}
+
+// signedQuery is the query a signed URL runs.
+type signedQuery struct {
+	OrgID   platform.ID
+	AuthID  platform.ID
+	Query   string
+	Expires time.Time
+}
+
+func (s *QuerySigner) signature(q signedQuery) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(strings.Join([]string{
+		q.OrgID.String(),
+		q.AuthID.String(),
+		strconv.FormatInt(q.Expires.Unix(), 10),
+		q.Query,
+	}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// sign returns the path and parameters of the URL running q.
+func (s *QuerySigner) sign(q signedQuery) string {
+	v := url.Values{}
+	v.Set(signedQueryOrgID, q.OrgID.String())
+	v.Set(signedQueryAuthID, q.AuthID.String())
+	v.Set(signedQueryQuery, q.Query)
+	v.Set(signedQueryExpires, strconv.FormatInt(q.Expires.Unix(), 10))
+	v.Set(signedQuerySig, s.signature(q))
+	return prefixSignedQuery + "?" + v.Encode()
+}
+
+// verify returns the query of the signed URL parameters v,
+// unless they were altered or the URL has expired.
+func (s *QuerySigner) verify(v url.Values) (signedQuery, error) {
+	orgID, err := platform.IDFromString(v.Get(signedQueryOrgID))
+	if err != nil {
+		return signedQuery{}, errSignedQueryInvalid
+	}
+	authID, err := platform.IDFromString(v.Get(signedQueryAuthID))
+	if err != nil {
+		return signedQuery{}, errSignedQueryInvalid
+	}
+	expires, err := strconv.ParseInt(v.Get(signedQueryExpires), 10, 64)
+	if err != nil {
+		return signedQuery{}, errSignedQueryInvalid
+	}
+
+	q := signedQuery{
+		OrgID:   *orgID,
+		AuthID:  *authID,
+		Query:   v.Get(signedQueryQuery),
+		Expires: time.Unix(expires, 0),
+	}
+	if !hmac.Equal([]byte(s.signature(q)), []byte(v.Get(signedQuerySig))) {
+		return signedQuery{}, errSignedQueryInvalid
+	}
+	if !s.now().Before(q.Expires) {
+		return signedQuery{}, errSignedQueryExpired
+	}
+	return q, nil
+}
+
+type signQueryRequest struct {
+	Query string `json:"query"`
+	// ExpiresIn is how long the URL is valid for, such as 1h or 7d.
+	// It defaults to the maximum expiry of the signer.
+	ExpiresIn string `json:"expiresIn"`
+}
+
+type signQueryResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// Note reminds that the URL does not survive a change of the signing key.
+	Note string `json:"note"`
+}
+
+const signQueryNote = "the URL is valid until expiresAt or until the signing key of the server changes, whichever comes first"
+
+// postSignQuery creates a signed URL running a query in the organization of the request.
+func (h *FluxHandler) postSignQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Err:  err,
+		}, w)
+		return
+	}
+	// The URLs are tied to a token so they can be revoked.
+	auth, ok := a.(*influxdb.Authorization)
+	if !ok {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.EInvalid,
+			Msg:  "signed query URLs can only be created with a token",
+		}, w)
+		return
+	}
+
+	var req signQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.EInvalid,
+			Msg:  "invalid json",
+			Err:  err,
+		}, w)
+		return
+	}
+	if req.Query == "" {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.EInvalid,
+			Msg:  "query is required",
+		}, w)
+		return
+	}
+
+	expiresIn := h.QuerySigner.MaxExpiry
+	if req.ExpiresIn != "" {
+		if expiresIn, err = ParseDuration(req.ExpiresIn); err != nil || expiresIn <= 0 || expiresIn > h.QuerySigner.MaxExpiry {
+			h.HandleHTTPError(ctx, &errors2.Error{
+				Code: errors2.EInvalid,
+				Msg:  "expiresIn must be a positive duration of at most " + h.QuerySigner.MaxExpiry.String(),
+			}, w)
+			return
+		}
+	}
+
+	org, err := queryOrganization(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	q := signedQuery{
+		OrgID:   org.ID,
+		AuthID:  auth.ID,
+		Query:   req.Query,
+		Expires: h.QuerySigner.now().Add(expiresIn).Truncate(time.Second),
+	}
+	res := signQueryResponse{
+		URL:       h.QuerySigner.sign(q),
+		ExpiresAt: q.Expires.UTC(),
+		Note:      signQueryNote,
+	}
+	if err := encodeResponse(ctx, w, http.StatusCreated, res); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// getSignedQuery runs the query of a signed URL. It does not require a token.
+func (h *FluxHandler) getSignedQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	q, err := h.QuerySigner.verify(r.URL.Query())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	auth, err := h.AuthorizationService.FindAuthorizationByID(ctx, q.AuthID)
+	if err != nil || !auth.IsActive() {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.EUnauthorized,
+			Msg:  "the token of the signed query URL is no longer valid",
+		}, w)
+		return
+	}
+
+	// The URLs of a user are revoked with the user, as their tokens are.
+	if auth.GetUserID().Valid() {
+		if u, err := h.UserService.FindUserByID(ctx, auth.GetUserID()); err != nil || u.Status == influxdb.Inactive {
+			InactiveUserError(ctx, h, w)
+			return
+		}
+	}
+
+	qr := QueryRequest{
+		Query:       q.Query,
+		Org:         &influxdb.Organization{ID: q.OrgID},
+		PreferTable: acceptsPlainText(r),
+	}.WithDefaults()
+	req, err := qr.ProxyRequest()
+	if err != nil {
+		h.HandleHTTPError(ctx, &errors2.Error{
+			Code: errors2.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+	req.Request.Source = r.Header.Get("User-Agent")
+	req.Request.Authorization = auth
+
+	ctx = pcontext.SetAuthorizer(ctx, auth)
+	if hd, ok := req.Dialect.(HTTPDialect); ok {
+		hd.SetHeaders(w)
+	}
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil && cw.Count() == 0 {
+		h.HandleHTTPError(ctx, err, w)
+	}
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/v2"
+	pcontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	influxmock "github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/query"
+	"github.com/influxdata/influxdb/v2/query/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestFluxHandler_SignedQuery(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	signer := NewQuerySigner([]byte("secret"), 24*time.Hour)
+	signer.now = func() time.Time { return now }
+
+	auth := &influxdb.Authorization{ID: 2, OrgID: 1, UserID: 5, Status: influxdb.Active}
+	user := &influxdb.User{ID: 5, Name: "user", Status: influxdb.Active}
+	var queries []*query.ProxyRequest
+	h := NewFluxHandler(zaptest.NewLogger(t), &FluxBackend{
+		HTTPErrorHandler: kithttp.NewErrorHandler(zaptest.NewLogger(t)),
+		OrganizationService: &influxmock.OrganizationService{
+			FindOrganizationF: func(ctx context.Context, filter influxdb.OrganizationFilter) (*influxdb.Organization, error) {
+				return &influxdb.Organization{ID: 1, Name: "org"}, nil
+			},
+		},
+		ProxyQueryService: &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				queries = append(queries, req)
+				_, err := w.Write([]byte("#datatype,string,long\n,result,table\n,_result,0\n"))
+				return flux.Statistics{}, err
+			},
+		},
+		AuthorizationService: &influxmock.AuthorizationService{
+			FindAuthorizationByIDFn: func(ctx context.Context, id platform.ID) (*influxdb.Authorization, error) {
+				return auth, nil
+			},
+		},
+		UserService: &influxmock.UserService{
+			FindUserByIDFn: func(ctx context.Context, id platform.ID) (*influxdb.User, error) {
+				return user, nil
+			},
+		},
+		QuerySigner: signer,
+	})
+
+	sign := func(a influxdb.Authorizer, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v2/query/sign?org=org", strings.NewReader(body))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), a))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		return rr
+	}
+	get := func(url string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		return rr
+	}
+
+	rr := sign(auth, `{"query": "buckets()", "expiresIn": "1h"}`)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var res signQueryResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&res))
+	require.Equal(t, now.Add(time.Hour), res.ExpiresAt)
+	require.True(t, strings.HasPrefix(res.URL, prefixSignedQuery+"?"))
+	require.Equal(t, signQueryNote, res.Note)
+
+	// The query runs with the token that signed the URL.
+	rr = get(res.URL)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Len(t, queries, 1)
+	require.Equal(t, platform.ID(1), queries[0].Request.OrganizationID)
+	require.Equal(t, auth, queries[0].Request.Authorization)
+	require.Equal(t, "buckets()", queries[0].Request.Compiler.(lang.FluxCompiler).Query)
+
+	// The query cannot be changed.
+	rr = get(strings.Replace(res.URL, "buckets", "yield", 1))
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// The URL is revoked with its token.
+	auth.Status = influxdb.Inactive
+	rr = get(res.URL)
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	auth.Status = influxdb.Active
+
+	// The URL is revoked with the user of its token.
+	user.Status = influxdb.Inactive
+	rr = get(res.URL)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	user.Status = influxdb.Active
+
+	// The URL expires.
+	now = now.Add(time.Hour)
+	rr = get(res.URL)
+	require.Equal(t, http.StatusUnauthorized, rr.Code)
+	require.Len(t, queries, 1)
+
+	// The URLs cannot be valid for longer than the maximum expiry.
+	rr = sign(auth, `{"query": "buckets()", "expiresIn": "2d"}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// The URLs of sessions could not be revoked.
+	rr = sign(&influxdb.Session{ID: 3, UserID: 4}, `{"query": "buckets()"}`)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -157,6 +157,19 @@ func TestQueryRequest_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid line terminator",
+			fields: fields{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					LineTerminator: "\r",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid query",
 			fields: fields{
@@ -168,6 +181,18 @@ func TestQueryRequest_Validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid query with line terminator",
+			fields: fields{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					LineTerminator: "\n",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -188,13 +213,14 @@ func TestQueryRequest_Validate(t *testing.T) {
 
 func TestQueryRequest_proxyRequest(t *testing.T) {
 	type fields struct {
-		Extern  json.RawMessage
-		AST     json.RawMessage
-		Query   string
-		Type    string
-		Dialect QueryDialect
-		Now     time.Time
-		org     *platform.Organization
+		Extern      json.RawMessage
+		AST         json.RawMessage
+		Query       string
+		Type        string
+		Dialect     QueryDialect
+		Now         time.Time
+		PreferTable bool
+		org         *platform.Organization
 	}
 	tests := []struct {
 		name    string
@@ -237,6 +263,54 @@ func TestQueryRequest_proxyRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid query with line terminator",
+			fields: fields{
+				Query: "howdy",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ";",
+					DateTimeFormat: "RFC3339",
+					LineTerminator: "\n",
+				},
+				org: &platform.Organization{},
+			},
+			now: func() time.Time { return time.Unix(1, 1) },
+			want: &query.ProxyRequest{
+				Request: query.Request{
+					Compiler: lang.FluxCompiler{
+						Now:   time.Unix(1, 1),
+						Query: `howdy`,
+					},
+				},
+				Dialect: &query.LFDialect{
+					ResultEncoderConfig: csv.ResultEncoderConfig{
+						NoHeader:  false,
+						Delimiter: ';',
+					},
+				},
+			},
+		},
+		{
+			name: "valid query with table",
+			fields: fields{
+				Query:       "howdy",
+				Type:        "flux",
+				Dialect:     QueryDialect{Delimiter: ",", DateTimeFormat: "RFC3339"},
+				PreferTable: true,
+				org:         &platform.Organization{},
+			},
+			now: func() time.Time { return time.Unix(1, 1) },
+			want: &query.ProxyRequest{
+				Request: query.Request{
+					Compiler: lang.FluxCompiler{
+						Now:   time.Unix(1, 1),
+						Query: `howdy`,
+					},
+				},
+				Dialect: query.NewTableDialect(),
+			},
+		},
 		{
 			name: "valid AST",
 			fields: fields{
@@ -343,13 +417,14 @@ func TestQueryRequest_proxyRequest(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := QueryRequest{
-				Extern:  tt.fields.Extern,
-				AST:     tt.fields.AST,
-				Query:   tt.fields.Query,
-				Type:    tt.fields.Type,
-				Dialect: tt.fields.Dialect,
-				Now:     tt.fields.Now,
-				Org:     tt.fields.org,
+				Extern:      tt.fields.Extern,
+				AST:         tt.fields.AST,
+				Query:       tt.fields.Query,
+				Type:        tt.fields.Type,
+				Dialect:     tt.fields.Dialect,
+				Now:         tt.fields.Now,
+				PreferTable: tt.fields.PreferTable,
+				Org:         tt.fields.org,
 			}
 			got, err := r.proxyRequest(tt.now)
 			if (err != nil) != tt.wantErr {
@@ -437,6 +512,36 @@ func Test_decodeQueryRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid query request accepting text",
+			args: args{
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query": "from()"}`))
+					r.Header.Set("Accept", "text/plain; charset=utf-8, */*;q=0.1")
+					return r
+				}(),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform2.ID { s, _ := platform2.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+			},
+			want: &QueryRequest{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Header:         func(x bool) *bool { return &x }(true),
+				},
+				Org: &platform.Organization{
+					ID: func() platform2.ID { s, _ := platform2.IDFromString("deadbeefdeadbeef"); return *s }(),
+				},
+				PreferTable: true,
+			},
+		},
 		{
 			name: "error decoding json",
 			args: args{
@@ -609,6 +714,7 @@ func Test_decodeProxyQueryRequest(t *testing.T) {
 }
 
 func TestProxyRequestToQueryRequest_Compilers(t *testing.T) {
+	header := true
 	tests := []struct {
 		name string
 		pr   query.ProxyRequest
@@ -650,6 +756,50 @@ func TestProxyRequestToQueryRequest_Compilers(t *testing.T) {
 				Now:             time.Unix(45, 45),
 			},
 		},
+		{
+			name: "line terminator copied",
+			pr: query.ProxyRequest{
+				Dialect: &query.LFDialect{
+					ResultEncoderConfig: csv.ResultEncoderConfig{Delimiter: ','},
+				},
+				Request: query.Request{
+					Compiler: lang.FluxCompiler{
+						Query: `howdy`,
+						Now:   time.Unix(45, 45),
+					},
+				},
+			},
+			want: QueryRequest{
+				Type:  "flux",
+				Query: `howdy`,
+				Now:   time.Unix(45, 45),
+				Dialect: QueryDialect{
+					Header:         &header,
+					Delimiter:      ",",
+					CommentPrefix:  "#",
+					DateTimeFormat: "RFC3339",
+					LineTerminator: "\n",
+				},
+			},
+		},
+		{
+			name: "table copied",
+			pr: query.ProxyRequest{
+				Dialect: query.NewTableDialect(),
+				Request: query.Request{
+					Compiler: lang.FluxCompiler{
+						Query: `howdy`,
+						Now:   time.Unix(45, 45),
+					},
+				},
+			},
+			want: QueryRequest{
+				Type:        "flux",
+				Query:       `howdy`,
+				Now:         time.Unix(45, 45),
+				PreferTable: true,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -663,3 +813,29 @@ func TestProxyRequestToQueryRequest_Compilers(t *testing.T) {
 		})
 	}
 }
+
+func TestAcceptsPlainText(t *testing.T) {
+	for _, tt := range []struct {
+		accept string
+		want   bool
+	}{
+		{accept: "", want: false},
+		{accept: "text/plain", want: true},
+		{accept: "text/plain; charset=utf-8, */*;q=0.1", want: true},
+		{accept: "application/csv;q=0.5, text/plain;q=0.8", want: true},
+		// Clients that accept text/plain among other types still get CSV.
+		{accept: "application/json, text/plain, */*", want: false},
+		{accept: "text/plain;q=0.5, */*", want: false},
+		{accept: "text/plain;q=0", want: false},
+	} {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			if got := acceptsPlainText(r); got != tt.want {
+				t.Errorf("acceptsPlainText(%q) = %v, want %v", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
@@ -9,10 +9,12 @@ import (
 	"net/url"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/httprouter"
 	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/authorizer"
 	pcontext "github.com/influxdata/influxdb/v2/context"
 	"github.com/influxdata/influxdb/v2/kit/platform"
 	errors2 "github.com/influxdata/influxdb/v2/kit/platform/errors"
@@ -85,6 +87,15 @@ const (
 	tasksIDRunsIDRetryPath = "/api/v2/tasks/:id/runs/:rid/retry"
 	tasksIDLabelsPath      = "/api/v2/tasks/:id/labels"
 	tasksIDLabelsIDPath    = "/api/v2/tasks/:id/labels/:lid"
+
+	tasksIDRevisionsPath           = "/api/v2/tasks/:id/revisions"
+	tasksIDRevisionsIDRollbackPath = "/api/v2/tasks/:id/revisions/:revision/rollback"
+
+	prefixDownsamplingTasks = "/api/v2/downsampling-tasks"
+
+	prefixDeletedTasks        = "/api/v2/deleted-tasks"
+	deletedTasksIDPath        = "/api/v2/deleted-tasks/:id"
+	deletedTasksIDRestorePath = "/api/v2/deleted-tasks/:id/restore"
 )
 
 // NewTaskHandler returns a new instance of TaskHandler.
@@ -105,6 +116,7 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 
 	h.HandlerFunc("GET", prefixTasks, h.handleGetTasks)
 	h.Handler("POST", prefixTasks, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handlePostTask)))
+	h.HandlerFunc("POST", prefixDownsamplingTasks, h.handlePostDownsamplingTask)
 
 	h.HandlerFunc("GET", tasksIDPath, h.handleGetTask)
 	h.Handler("PATCH", tasksIDPath, withFeatureProxy(b.AlgoWProxy, http.HandlerFunc(h.handleUpdateTask)))
@@ -113,6 +125,13 @@ func NewTaskHandler(log *zap.Logger, b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("GET", tasksIDLogsPath, h.handleGetLogs)
 	h.HandlerFunc("GET", tasksIDRunsIDLogsPath, h.handleGetLogs)
 
+	h.HandlerFunc("GET", tasksIDRevisionsPath, h.handleGetTaskRevisions)
+	h.HandlerFunc("POST", tasksIDRevisionsIDRollbackPath, h.handleRollbackTask)
+
+	h.HandlerFunc("GET", prefixDeletedTasks, h.handleGetDeletedTasks)
+	h.HandlerFunc("GET", deletedTasksIDPath, h.handleGetDeletedTask)
+	h.HandlerFunc("POST", deletedTasksIDRestorePath, h.handleRestoreTask)
+
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
 		log:                        b.log.With(zap.String("handler", "member")),
@@ -170,12 +189,15 @@ type Task struct {
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
 	Offset          string                 `json:"offset,omitempty"`
+	CatchUp         string                 `json:"catchUp,omitempty"`
 	LatestCompleted string                 `json:"latestCompleted,omitempty"`
 	LastRunStatus   string                 `json:"lastRunStatus,omitempty"`
 	LastRunError    string                 `json:"lastRunError,omitempty"`
 	CreatedAt       string                 `json:"createdAt,omitempty"`
 	UpdatedAt       string                 `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	Tags            map[string]string      `json:"tags,omitempty"`
+	Version         int64                  `json:"version,omitempty"`
 }
 
 type taskResponse struct {
@@ -215,12 +237,15 @@ func NewFrontEndTask(t taskmodel.Task) Task {
 		Every:           t.Every,
 		Cron:            t.Cron,
 		Offset:          offset,
+		CatchUp:         t.CatchUp,
 		LatestCompleted: latestCompleted,
 		LastRunStatus:   t.LastRunStatus,
 		LastRunError:    t.LastRunError,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Metadata:        t.Metadata,
+		Tags:            t.Tags,
+		Version:         t.Version,
 	}
 }
 
@@ -263,12 +288,15 @@ func convertTask(t Task) *taskmodel.Task {
 		Every:           t.Every,
 		Cron:            t.Cron,
 		Offset:          offset,
+		CatchUp:         t.CatchUp,
 		LatestCompleted: latestCompleted,
 		LastRunStatus:   t.LastRunStatus,
 		LastRunError:    t.LastRunError,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
 		Metadata:        t.Metadata,
+		Tags:            t.Tags,
+		Version:         t.Version,
 	}
 }
 
@@ -395,6 +423,7 @@ type httpRun struct {
 	StartedAt    *time.Time      `json:"startedAt,omitempty"`
 	FinishedAt   *time.Time      `json:"finishedAt,omitempty"`
 	RequestedAt  *time.Time      `json:"requestedAt,omitempty"`
+	Manual       bool            `json:"manual,omitempty"`
 	Log          []taskmodel.Log `json:"log,omitempty"`
 }
 
@@ -403,6 +432,7 @@ func newRunResponse(r taskmodel.Run) runResponse {
 		ID:           r.ID,
 		TaskID:       r.TaskID,
 		Status:       r.Status,
+		Manual:       r.Manual,
 		Log:          r.Log,
 		ScheduledFor: &r.ScheduledFor,
 	}
@@ -433,6 +463,7 @@ func convertRun(r httpRun) *taskmodel.Run {
 		ID:     r.ID,
 		TaskID: r.TaskID,
 		Status: r.Status,
+		Manual: r.Manual,
 		Log:    r.Log,
 	}
 
@@ -589,6 +620,21 @@ func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs influxdb.O
 		req.filter.Name = &name
 	}
 
+	// tags are given as key=value pairs
+	for _, tag := range qp["tag"] {
+		k, v, ok := strings.Cut(tag, "=")
+		if !ok || k == "" {
+			return nil, &errors2.Error{
+				Code: errors2.EInvalid,
+				Msg:  fmt.Sprintf("%q is not a valid tag, expected key=value", tag),
+			}
+		}
+		if req.filter.Tags == nil {
+			req.filter.Tags = make(map[string]string)
+		}
+		req.filter.Tags[k] = v
+	}
+
 	return req, nil
 }
 
@@ -614,7 +660,13 @@ func (h *TaskHandler) handlePostTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !req.TaskCreate.OrganizationID.Valid() {
+	h.createTask(w, r, req.TaskCreate)
+}
+
+// createTask creates the task once its organization is identified and writes it to the response.
+func (h *TaskHandler) createTask(w http.ResponseWriter, r *http.Request, tc taskmodel.TaskCreate) {
+	ctx := r.Context()
+	if !tc.OrganizationID.Valid() {
 		err := &errors2.Error{
 			Code: errors2.EInvalid,
 			Msg:  "invalid organization id",
@@ -623,7 +675,7 @@ func (h *TaskHandler) handlePostTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.TaskService.CreateTask(ctx, req.TaskCreate)
+	task, err := h.TaskService.CreateTask(ctx, tc)
 	if err != nil {
 		if e, ok := err.(AuthzError); ok {
 			h.log.Error("Failed authentication", zap.Errors("error messages", []error{err, e.AuthzError()}))
@@ -676,6 +728,84 @@ func decodePostTaskRequest(ctx context.Context, r *http.Request) (*postTaskReque
 	}, nil
 }
 
+// handlePostDownsamplingTask creates a task aggregating the data of a bucket into another
+// bucket from a few parameters, so that users do not have to write its script.
+func (h *TaskHandler) handlePostDownsamplingTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	d, err := decodePostDownsamplingTaskRequest(ctx, r)
+	if err != nil {
+		err = &errors2.Error{
+			Err:  err,
+			Code: errors2.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tc, err := d.TaskCreate()
+	if err != nil {
+		err = &errors2.Error{
+			Err:  err,
+			Code: errors2.EInvalid,
+			Msg:  "failed to generate downsampling task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.populateTaskCreateOrg(ctx, &tc); err != nil {
+		err = &errors2.Error{
+			Err: err,
+			Msg: "could not identify organization",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// Check that the task can be created before looking up its buckets,
+	// so the buckets of an organization are not disclosed to anyone else.
+	if _, _, err := authorizer.AuthorizeCreate(ctx, influxdb.TasksResourceType, tc.OrganizationID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// Report a missing bucket now rather than on the first run of the task.
+	// Only the buckets the user can read are found.
+	buckets := authorizer.NewBucketService(h.BucketService)
+	for _, name := range []string{d.SourceBucket, d.DestinationBucket} {
+		if _, err := buckets.FindBucketByName(ctx, tc.OrganizationID, name); err != nil {
+			err = &errors2.Error{
+				Err:  err,
+				Code: errors2.ErrorCode(err),
+				Msg:  fmt.Sprintf("could not find bucket %q", name),
+			}
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	h.createTask(w, r, tc)
+}
+
+func decodePostDownsamplingTaskRequest(ctx context.Context, r *http.Request) (*taskmodel.DownsampleTaskCreate, error) {
+	var d taskmodel.DownsampleTaskCreate
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.OwnerID = auth.GetUserID()
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
 func (h *TaskHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	req, err := decodeGetTaskRequest(ctx, r)
@@ -870,6 +1000,315 @@ func decodeDeleteTaskRequest(ctx context.Context, r *http.Request) (*deleteTaskR
 	}, nil
 }
 
+type taskRevisionResponse struct {
+	Links     map[string]string `json:"links"`
+	Revision  int64             `json:"revision"`
+	Flux      string            `json:"flux"`
+	CreatedAt string            `json:"createdAt"`
+}
+
+type taskRevisionsResponse struct {
+	Links     map[string]string      `json:"links"`
+	Revisions []taskRevisionResponse `json:"revisions"`
+}
+
+func newTaskRevisionsResponse(taskID platform.ID, revs []*taskmodel.TaskRevision) taskRevisionsResponse {
+	rs := taskRevisionsResponse{
+		Links: map[string]string{
+			"self": path.Join(taskIDPath(taskID), "revisions"),
+			"task": taskIDPath(taskID),
+		},
+		Revisions: make([]taskRevisionResponse, len(revs)),
+	}
+	for i, rev := range revs {
+		rs.Revisions[i] = taskRevisionResponse{
+			Links: map[string]string{
+				"rollback": path.Join(taskIDPath(taskID), "revisions", strconv.FormatInt(rev.Revision, 10), "rollback"),
+			},
+			Revision:  rev.Revision,
+			Flux:      rev.Flux,
+			CreatedAt: rev.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return rs
+}
+
+// revisions returns the task service as a service for the script history of tasks.
+func (h *TaskHandler) revisions() (taskmodel.TaskRevisionService, error) {
+	revisions, ok := h.TaskService.(taskmodel.TaskRevisionService)
+	if !ok {
+		return nil, taskmodel.ErrTaskRevisionsNotSupported
+	}
+	return revisions, nil
+}
+
+func (h *TaskHandler) handleGetTaskRevisions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetTaskRequest(ctx, r)
+	if err != nil {
+		err = &errors2.Error{
+			Err:  err,
+			Code: errors2.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	revisions, err := h.revisions()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	revs, err := revisions.FindTaskRevisions(ctx, req.TaskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskRevisionsResponse(req.TaskID, revs)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type rollbackTaskRequest struct {
+	TaskID   platform.ID
+	Revision int64
+}
+
+func decodeRollbackTaskRequest(ctx context.Context, r *http.Request) (*rollbackTaskRequest, error) {
+	tr, err := decodeGetTaskRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	rev, err := strconv.ParseInt(params.ByName("revision"), 10, 64)
+	if err != nil || rev < 0 {
+		return nil, &errors2.Error{
+			Code: errors2.EInvalid,
+			Msg:  "revision must be a non-negative integer",
+		}
+	}
+
+	return &rollbackTaskRequest{
+		TaskID:   tr.TaskID,
+		Revision: rev,
+	}, nil
+}
+
+func (h *TaskHandler) handleRollbackTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeRollbackTaskRequest(ctx, r)
+	if err != nil {
+		err = &errors2.Error{
+			Err:  err,
+			Code: errors2.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	revisions, err := h.revisions()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := revisions.RollbackTask(ctx, req.TaskID, req.Revision)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID, ResourceType: influxdb.TasksResourceType})
+	if err != nil {
+		err = &errors2.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Task rolled back", zap.String("taskID", fmt.Sprint(task.ID)), zap.Int64("revision", req.Revision))
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+type deletedTaskResponse struct {
+	Links     map[string]string `json:"links"`
+	DeletedAt string            `json:"deletedAt"`
+	Task      Task              `json:"task"`
+}
+
+func newDeletedTaskResponse(t taskmodel.DeletedTask) deletedTaskResponse {
+	return deletedTaskResponse{
+		Links: map[string]string{
+			"self":    path.Join(prefixDeletedTasks, t.Task.ID.String()),
+			"restore": path.Join(prefixDeletedTasks, t.Task.ID.String(), "restore"),
+		},
+		DeletedAt: t.DeletedAt.Format(time.RFC3339),
+		Task:      NewFrontEndTask(*t.Task),
+	}
+}
+
+type deletedTasksResponse struct {
+	Links map[string]string     `json:"links"`
+	Tasks []deletedTaskResponse `json:"tasks"`
+}
+
+// trash returns the task service as a service for deleted tasks.
+func (h *TaskHandler) trash() (taskmodel.TaskTrashService, error) {
+	trash, ok := h.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+	return trash, nil
+}
+
+func (h *TaskHandler) handleGetDeletedTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orgID, err := decodeGetDeletedTasksRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		err = &errors2.Error{
+			Err:  err,
+			Code: errors2.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	trash, err := h.trash()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tasks, err := trash.FindDeletedTasks(ctx, orgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs := deletedTasksResponse{
+		Links: map[string]string{
+			"self": prefixDeletedTasks + "?orgID=" + orgID.String(),
+		},
+		Tasks: make([]deletedTaskResponse, len(tasks)),
+	}
+	for i := range tasks {
+		rs.Tasks[i] = newDeletedTaskResponse(*tasks[i])
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, rs); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+// decodeGetDeletedTasksRequest returns the organization whose deleted tasks are listed,
+// given either by ID or by name.
+func decodeGetDeletedTasksRequest(ctx context.Context, r *http.Request, orgs influxdb.OrganizationService) (platform.ID, error) {
+	qp := r.URL.Query()
+	if oid := qp.Get("orgID"); oid != "" {
+		orgID, err := platform.IDFromString(oid)
+		if err != nil {
+			return 0, err
+		}
+		return *orgID, nil
+	}
+
+	if orgName := qp.Get("org"); orgName != "" {
+		o, err := orgs.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &orgName})
+		if err != nil {
+			return 0, err
+		}
+		return o.ID, nil
+	}
+
+	return 0, &errors2.Error{
+		Code: errors2.EInvalid,
+		Msg:  "either org or orgID must be provided",
+	}
+}
+
+func (h *TaskHandler) handleGetDeletedTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetTaskRequest(ctx, r)
+	if err != nil {
+		err = &errors2.Error{
+			Err:  err,
+			Code: errors2.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	trash, err := h.trash()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := trash.FindDeletedTaskByID(ctx, req.TaskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDeletedTaskResponse(*task)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handleRestoreTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req, err := decodeGetTaskRequest(ctx, r)
+	if err != nil {
+		err = &errors2.Error{
+			Err:  err,
+			Code: errors2.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	trash, err := h.trash()
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := trash.RestoreTask(ctx, req.TaskID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: task.ID, ResourceType: influxdb.TasksResourceType})
+	if err != nil {
+		err = &errors2.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.log.Debug("Task restored", zap.String("taskID", fmt.Sprint(task.ID)))
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskResponse(*task, labels)); err != nil {
+		logEncodingError(h.log, r, err)
+		return
+	}
+}
+
 func (h *TaskHandler) handleGetLogs(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -1578,6 +2017,114 @@ func (t TaskService) DeleteTask(ctx context.Context, id platform.ID) error {
 		Do(ctx)
 }
 
+// FindTaskRevisions returns the previous scripts of a task, oldest first.
+func (t TaskService) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*taskmodel.TaskRevision, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var rs taskRevisionsResponse
+	err := t.Client.
+		Get(taskIDPath(taskID), "revisions").
+		DecodeJSON(&rs).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	revs := make([]*taskmodel.TaskRevision, len(rs.Revisions))
+	for i, r := range rs.Revisions {
+		createdAt, _ := time.Parse(time.RFC3339, r.CreatedAt)
+		revs[i] = &taskmodel.TaskRevision{
+			TaskID:    taskID,
+			Revision:  r.Revision,
+			Flux:      r.Flux,
+			CreatedAt: createdAt,
+		}
+	}
+	return revs, nil
+}
+
+// RollbackTask restores the script of a task to the given revision.
+func (t TaskService) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*taskmodel.Task, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var tr taskResponse
+	err := t.Client.
+		Post(nil, taskIDPath(taskID), "revisions", strconv.FormatInt(revision, 10), "rollback").
+		DecodeJSON(&tr).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTask(tr.Task), nil
+}
+
+// FindDeletedTasks returns the deleted tasks of an organization.
+func (t TaskService) FindDeletedTasks(ctx context.Context, orgID platform.ID) ([]*taskmodel.DeletedTask, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var rs deletedTasksResponse
+	err := t.Client.
+		Get(prefixDeletedTasks).
+		QueryParams([2]string{"orgID", orgID.String()}).
+		DecodeJSON(&rs).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*taskmodel.DeletedTask, len(rs.Tasks))
+	for i := range rs.Tasks {
+		tasks[i] = convertDeletedTask(rs.Tasks[i])
+	}
+	return tasks, nil
+}
+
+// FindDeletedTaskByID returns a single deleted task.
+func (t TaskService) FindDeletedTaskByID(ctx context.Context, id platform.ID) (*taskmodel.DeletedTask, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var tr deletedTaskResponse
+	err := t.Client.
+		Get(prefixDeletedTasks, id.String()).
+		DecodeJSON(&tr).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertDeletedTask(tr), nil
+}
+
+// RestoreTask returns a deleted task to the set of tasks.
+func (t TaskService) RestoreTask(ctx context.Context, id platform.ID) (*taskmodel.Task, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var tr taskResponse
+	err := t.Client.
+		Post(nil, prefixDeletedTasks, id.String(), "restore").
+		DecodeJSON(&tr).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTask(tr.Task), nil
+}
+
+func convertDeletedTask(t deletedTaskResponse) *taskmodel.DeletedTask {
+	deletedAt, _ := time.Parse(time.RFC3339, t.DeletedAt)
+	return &taskmodel.DeletedTask{
+		Task:      convertTask(t.Task),
+		DeletedAt: deletedAt,
+	}
+}
+
 // FindLogs returns logs for a run.
 func (t TaskService) FindLogs(ctx context.Context, filter taskmodel.LogFilter) ([]*taskmodel.Log, int, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
@@ -1314,6 +1314,102 @@ func TestTaskHandler_CreateTaskWithOrgName(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_handlePostDownsamplingTask(t *testing.T) {
+	i := influxdbtesting.NewTestInmemStore(t)
+
+	ts := tenant.NewService(tenant.NewStore(i))
+	ctx := context.Background()
+
+	u := &influxdb.User{Name: "u"}
+	if err := ts.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: "o"}
+	if err := ts.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"raw", "rollup"} {
+		if err := ts.CreateBucket(ctx, &influxdb.Bucket{OrgID: o.ID, Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var created taskmodel.TaskCreate
+	taskSvc := &mock.TaskService{
+		CreateTaskFn: func(_ context.Context, tc taskmodel.TaskCreate) (*taskmodel.Task, error) {
+			created = tc
+			return &taskmodel.Task{ID: 9, OrganizationID: tc.OrganizationID, OwnerID: tc.OwnerID, Name: "x", Flux: tc.Flux}, nil
+		},
+	}
+
+	lStore, _ := label.NewStore(i)
+	h := NewTaskHandler(zaptest.NewLogger(t), &TaskBackend{
+		log:              zaptest.NewLogger(t),
+		HTTPErrorHandler: kithttp.NewErrorHandler(zaptest.NewLogger(t)),
+
+		TaskService:                taskSvc,
+		OrganizationService:        ts,
+		UserResourceMappingService: ts,
+		LabelService:               label.NewService(lStore),
+		UserService:                ts,
+		BucketService:              ts,
+	})
+
+	auth := &influxdb.Authorization{UserID: u.ID, Status: influxdb.Active, Permissions: influxdb.OwnerPermissions(o.ID)}
+	post := func(d taskmodel.DownsampleTaskCreate) *http.Response {
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest("POST", "http://localhost:8086/api/v2/downsampling-tasks", bytes.NewReader(b))
+		r = r.WithContext(pcontext.SetAuthorizer(ctx, auth))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	d := taskmodel.DownsampleTaskCreate{
+		Organization:      o.Name,
+		SourceBucket:      "raw",
+		DestinationBucket: "rollup",
+		Aggregate:         "mean",
+		Every:             "1h",
+	}
+	res := post(d)
+	if res.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(res.Body)
+		t.Fatalf("expected status created, got %v: %s", res.StatusCode, body)
+	}
+	want, err := d.TaskCreate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Flux != want.Flux {
+		t.Fatalf("unexpected script:\n got: %s\nwant: %s", created.Flux, want.Flux)
+	}
+	if created.OrganizationID != o.ID || created.OwnerID != u.ID {
+		t.Fatalf("unexpected org or owner: %s, %s", created.OrganizationID, created.OwnerID)
+	}
+
+	// The buckets must exist when the task is created.
+	d.DestinationBucket = "missing"
+	if res := post(d); res.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status not found, got %v", res.StatusCode)
+	}
+
+	d.Aggregate = "unknown"
+	if res := post(d); res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status bad request, got %v", res.StatusCode)
+	}
+
+	// The buckets are not looked up for users who cannot create the task.
+	d.Aggregate = "mean"
+	auth = &influxdb.Authorization{UserID: u.ID, Status: influxdb.Active}
+	if res := post(d); res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected status unauthorized, got %v", res.StatusCode)
+	}
+}
+
 func TestTaskHandler_Sessions(t *testing.T) {
 	t.Skip("rework these")
 	// Common setup to get a working base for using tasks.
@@ -1762,3 +1858,176 @@ func TestTaskHandler_Sessions(t *testing.T) {
 		}
 	})
 }
+
+type trashTaskService struct {
+	*mock.TaskService
+	deleted  map[platform.ID]*taskmodel.DeletedTask
+	restored platform.ID
+}
+
+func (s *trashTaskService) FindDeletedTasks(_ context.Context, orgID platform.ID) ([]*taskmodel.DeletedTask, error) {
+	var tasks []*taskmodel.DeletedTask
+	for _, t := range s.deleted {
+		if t.Task.OrganizationID == orgID {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *trashTaskService) FindDeletedTaskByID(_ context.Context, id platform.ID) (*taskmodel.DeletedTask, error) {
+	t, ok := s.deleted[id]
+	if !ok {
+		return nil, taskmodel.ErrDeletedTaskNotFound
+	}
+	return t, nil
+}
+
+func (s *trashTaskService) RestoreTask(ctx context.Context, id platform.ID) (*taskmodel.Task, error) {
+	t, err := s.FindDeletedTaskByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.restored = id
+	delete(s.deleted, id)
+	return t.Task, nil
+}
+
+func (s *trashTaskService) PurgeDeletedTasks(context.Context, time.Duration) ([]*taskmodel.DeletedTask, error) {
+	return nil, nil
+}
+
+func TestTaskHandler_DeletedTasks(t *testing.T) {
+	deletedAt := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	svc := &trashTaskService{
+		TaskService: &mock.TaskService{},
+		deleted: map[platform.ID]*taskmodel.DeletedTask{
+			1: {Task: &taskmodel.Task{ID: 1, OrganizationID: 10, Name: "a", Status: "active"}, DeletedAt: deletedAt},
+			2: {Task: &taskmodel.Task{ID: 2, OrganizationID: 20, Name: "b", Status: "active"}, DeletedAt: deletedAt},
+		},
+	}
+
+	backend := NewMockTaskBackend(t)
+	backend.HTTPErrorHandler = kithttp.NewErrorHandler(zaptest.NewLogger(t))
+	backend.TaskService = svc
+	server := httptest.NewServer(NewTaskHandler(zaptest.NewLogger(t), backend))
+	defer server.Close()
+
+	client := TaskService{Client: mustNewHTTPClient(t, server.URL, "")}
+	ctx := context.Background()
+
+	tasks, err := client.FindDeletedTasks(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Task.ID != 1 || !tasks[0].DeletedAt.Equal(deletedAt) {
+		t.Fatalf("unexpected deleted tasks: %+v", tasks)
+	}
+
+	task, err := client.FindDeletedTaskByID(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Task.Name != "b" {
+		t.Fatalf("unexpected deleted task: %+v", task.Task)
+	}
+
+	restored, err := client.RestoreTask(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.ID != 2 || svc.restored != 2 {
+		t.Fatalf("unexpected restored task: %+v", restored)
+	}
+
+	if _, err := client.RestoreTask(ctx, 2); errors2.ErrorCode(err) != errors2.ENotFound {
+		t.Fatalf("expected not found restoring a task twice, got %v", err)
+	}
+
+	// Deleted tasks are listed for a single organization.
+	res, err := http.Get(server.URL + prefixDeletedTasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status bad request without an org, got %v", res.StatusCode)
+	}
+}
+
+type revisionTaskService struct {
+	*mock.TaskService
+	revisions  []*taskmodel.TaskRevision
+	rolledBack int64
+}
+
+func (s *revisionTaskService) FindTaskRevisions(_ context.Context, taskID platform.ID) ([]*taskmodel.TaskRevision, error) {
+	if taskID != 1 {
+		return nil, taskmodel.ErrTaskNotFound
+	}
+	return s.revisions, nil
+}
+
+func (s *revisionTaskService) RollbackTask(_ context.Context, taskID platform.ID, revision int64) (*taskmodel.Task, error) {
+	for _, rev := range s.revisions {
+		if rev.Revision == revision {
+			s.rolledBack = revision
+			return &taskmodel.Task{ID: taskID, OrganizationID: 10, Name: "a", Status: "active", Flux: rev.Flux}, nil
+		}
+	}
+	return nil, taskmodel.ErrTaskRevisionNotFound
+}
+
+func TestTaskHandler_Revisions(t *testing.T) {
+	createdAt := time.Date(2022, 3, 4, 5, 6, 7, 0, time.UTC)
+	svc := &revisionTaskService{
+		TaskService: &mock.TaskService{},
+		revisions: []*taskmodel.TaskRevision{
+			{TaskID: 1, Revision: 0, Flux: `option task = {name: "a", every: 1m}`, CreatedAt: createdAt},
+			{TaskID: 1, Revision: 1, Flux: `option task = {name: "a", every: 5m}`, CreatedAt: createdAt.Add(time.Hour)},
+		},
+		rolledBack: -1,
+	}
+
+	backend := NewMockTaskBackend(t)
+	backend.HTTPErrorHandler = kithttp.NewErrorHandler(zaptest.NewLogger(t))
+	backend.TaskService = svc
+	server := httptest.NewServer(NewTaskHandler(zaptest.NewLogger(t), backend))
+	defer server.Close()
+
+	client := TaskService{Client: mustNewHTTPClient(t, server.URL, "")}
+	ctx := context.Background()
+
+	revs, err := client.FindTaskRevisions(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 2 || revs[1].Revision != 1 || revs[1].Flux != svc.revisions[1].Flux || !revs[1].CreatedAt.Equal(createdAt.Add(time.Hour)) {
+		t.Fatalf("unexpected task revisions: %+v", revs)
+	}
+
+	if _, err := client.FindTaskRevisions(ctx, 2); errors2.ErrorCode(err) != errors2.ENotFound {
+		t.Fatalf("expected not found listing the revisions of a missing task, got %v", err)
+	}
+
+	task, err := client.RollbackTask(ctx, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.ID != 1 || svc.rolledBack != 0 {
+		t.Fatalf("unexpected rolled back task: %+v", task)
+	}
+
+	if _, err := client.RollbackTask(ctx, 1, 5); errors2.ErrorCode(err) != errors2.ENotFound {
+		t.Fatalf("expected not found rolling back to a missing revision, got %v", err)
+	}
+
+	res, err := http.Post(server.URL+"/api/v2/tasks/0000000000000001/revisions/latest/rollback", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status bad request for an invalid revision, got %v", res.StatusCode)
+	}
+}
@@ -30,36 +30,116 @@ For raw aggregate iterators, you can use the reduceSliceIterators which pass
 in a slice of all points to the function and return a point. For more complex
 iterator types, you may need to create your own iterators by hand.
 
-Once your iterator is complete, you'll need to add it to the NewCallIterator()
-function if it is to be available to IteratorCreators and add it to the select()
-function to allow it to be included during planning.
+Once your iterator is complete, map/reduce-style iterators are made available
+by registering them with RegisterAggregate(). Raw aggregate iterators need to be
+added to the select() function to be included during planning.
 */
 
+// CallIteratorFunc creates an iterator computing a call over the points of input.
+type CallIteratorFunc func(input Iterator, opt IteratorOptions) (Iterator, error)
+
+// Aggregate is a map/reduce-style aggregate function. The aggregate is computed
+// for the points of every shard by the storage engine and the partial results
+// of the shards are then combined by the query engine.
+type Aggregate struct {
+	// New creates the iterator computing the aggregate.
+	New CallIteratorFunc
+
+	// Merge creates the iterator combining the partial results of every shard.
+	// New is used to combine the results when Merge is nil.
+	Merge CallIteratorFunc
+
+	// Type returns the type of the values returned by the aggregate for
+	// arguments of the given types. The type is determined by the default
+	// rules of the query engine when Type is nil.
+	Type func(args []influxql.DataType) influxql.DataType
+
+	// Selector is true for aggregates returning one of their input points.
+	Selector bool
+
+	// Internal is true for aggregates that only merge the partial results of
+	// another aggregate and cannot be called by a query.
+	Internal bool
+}
+
+// aggregates is a lookup of the map/reduce-style aggregates by function name.
+var aggregates = make(map[string]Aggregate)
+
+// RegisterAggregate registers a map/reduce-style aggregate function by name.
+func RegisterAggregate(name string, agg Aggregate) {
+	if _, ok := aggregates[name]; ok {
+		panic("aggregate already registered: " + name)
+	}
+	aggregates[name] = agg
+}
+
+// RegisteredAggregates returns the names of the registered aggregate functions.
+func RegisteredAggregates() []string {
+	a := make([]string, 0, len(aggregates))
+	for k := range aggregates {
+		a = append(a, k)
+	}
+	sort.Strings(a)
+	return a
+}
+
+func init() {
+	sameType := func(args []influxql.DataType) influxql.DataType {
+		// TODO(jsternberg): Verify the input type.
+		return args[0]
+	}
+
+	RegisterAggregate("count", Aggregate{
+		New: newCountIterator,
+		// Sum the counted points of each shard.
+		Merge: newSumIterator,
+		Type:  func([]influxql.DataType) influxql.DataType { return influxql.Integer },
+	})
+	RegisterAggregate("min", Aggregate{New: newMinIterator, Type: sameType, Selector: true})
+	RegisterAggregate("max", Aggregate{New: newMaxIterator, Type: sameType, Selector: true})
+	RegisterAggregate("sum", Aggregate{New: newSumIterator, Type: sameType})
+	RegisterAggregate("first", Aggregate{New: newFirstIterator, Type: sameType, Selector: true})
+	RegisterAggregate("last", Aggregate{New: newLastIterator, Type: sameType, Selector: true})
+	RegisterAggregate("mean", Aggregate{
+		New:  newMeanIterator,
+		Type: func([]influxql.DataType) influxql.DataType { return influxql.Float },
+	})
+	RegisterAggregate("distinct", Aggregate{
+		New: NewDistinctIterator,
+		// Combine the distinct values of each shard.
+		Merge: newDistinctMergeIterator,
+		Type:  sameType,
+	})
+	RegisterAggregate("sum_hll", Aggregate{
+		New: NewSumHllIterator,
+		// Merge the counted points of each shard.
+		Merge: NewMergeHllIterator,
+	})
+	RegisterAggregate("merge_hll", Aggregate{New: NewMergeHllIterator, Internal: true})
+}
+
 // NewCallIterator returns a new iterator for a Call.
 func NewCallIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
 	name := opt.Expr.(*influxql.Call).Name
-	switch name {
-	case "count":
-		return newCountIterator(input, opt)
-	case "min":
-		return newMinIterator(input, opt)
-	case "max":
-		return newMaxIterator(input, opt)
-	case "sum":
-		return newSumIterator(input, opt)
-	case "first":
-		return newFirstIterator(input, opt)
-	case "last":
-		return newLastIterator(input, opt)
-	case "mean":
-		return newMeanIterator(input, opt)
-	case "sum_hll":
-		return NewSumHllIterator(input, opt)
-	case "merge_hll":
-		return NewMergeHllIterator(input, opt)
-	default:
+	agg, ok := aggregates[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported function call: %s", name)
+	}
+	return agg.New(input, opt)
+}
+
+// newMergeCallIterator returns an iterator combining the partial results
+// of a Call computed for each shard.
+func newMergeCallIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
+	name := opt.Expr.(*influxql.Call).Name
+	agg, ok := aggregates[name]
+	if !ok {
 		return nil, fmt.Errorf("unsupported function call: %s", name)
 	}
+	if agg.Merge != nil {
+		return agg.Merge(input, opt)
+	}
+	return agg.New(input, opt)
 }
 
 // newCountIterator returns an iterator for operating on a count() call.
@@ -514,6 +594,46 @@ func NewDistinctIterator(input Iterator, opt IteratorOptions) (Iterator, error)
 	}
 }
 
+// newDistinctMergeIterator returns an iterator combining the distinct points
+// computed for each shard. Every value keeps its earliest point, or its latest
+// point for descending queries, as if distinct() had read the points in order.
+func newDistinctMergeIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, FloatPointEmitter) {
+			fn := newFloatDistinctMergeReducer(opt.Ascending)
+			return fn, fn
+		}
+		return newFloatReduceFloatIterator(input, opt, createFn), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, IntegerPointEmitter) {
+			fn := newIntegerDistinctMergeReducer(opt.Ascending)
+			return fn, fn
+		}
+		return newIntegerReduceIntegerIterator(input, opt, createFn), nil
+	case UnsignedIterator:
+		createFn := func() (UnsignedPointAggregator, UnsignedPointEmitter) {
+			fn := newUnsignedDistinctMergeReducer(opt.Ascending)
+			return fn, fn
+		}
+		return newUnsignedReduceUnsignedIterator(input, opt, createFn), nil
+	case StringIterator:
+		createFn := func() (StringPointAggregator, StringPointEmitter) {
+			fn := newStringDistinctMergeReducer(opt.Ascending)
+			return fn, fn
+		}
+		return newStringReduceStringIterator(input, opt, createFn), nil
+	case BooleanIterator:
+		createFn := func() (BooleanPointAggregator, BooleanPointEmitter) {
+			fn := newBooleanDistinctMergeReducer(opt.Ascending)
+			return fn, fn
+		}
+		return newBooleanReduceBooleanIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported distinct iterator type: %T", input)
+	}
+}
+
 // newMeanIterator returns an iterator for operating on a mean() call.
 func newMeanIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
 	switch input := input.(type) {
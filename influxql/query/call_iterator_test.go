@@ -184,6 +184,45 @@ func TestCallIterator_Count_Boolean(t *testing.T) {
 	}
 }
 
+// Ensure that the buffers reused between windows do not leak aggregates
+// from one interval into the next.
+func TestCallIterator_Count_ManyIntervals(t *testing.T) {
+	var points []query.FloatPoint
+	for i := 0; i < 1000; i++ {
+		host := "hostA"
+		if i%2 == 1 {
+			host = "hostB"
+		}
+		points = append(points, query.FloatPoint{Name: "cpu", Time: int64(i), Value: float64(i), Tags: ParseTags("host=" + host)})
+	}
+
+	itr, err := query.NewCallIterator(
+		&FloatIterator{Points: points},
+		query.IteratorOptions{
+			Expr:      MustParseExpr(`count("value")`),
+			Interval:  query.Interval{Duration: 10 * time.Nanosecond},
+			Ordered:   true,
+			Ascending: true,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Iterators([]query.Iterator{itr}).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	exp := make([][]query.Point, 0, 100)
+	for i := 0; i < 100; i++ {
+		exp = append(exp, []query.Point{&query.IntegerPoint{Name: "cpu", Time: int64(i * 10), Value: 10, Aggregated: 10}})
+	}
+	if diff := cmp.Diff(a, exp); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
 // Ensure that a float iterator can be created for a min() call.
 func TestCallIterator_Min_Float(t *testing.T) {
 	itr, _ := query.NewCallIterator(
@@ -1081,6 +1120,158 @@ func TestNewCallIterator_UnsupportedExprName(t *testing.T) {
 	}
 }
 
+// Ensure that the distinct values of several shards are combined keeping the first point of each value.
+func TestCallIterator_Distinct_Merge(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		ascending bool
+		points    [][]query.FloatPoint
+		exp       []query.Point
+	}{
+		{
+			name:      "ascending",
+			ascending: true,
+			points: [][]query.FloatPoint{
+				{{Name: "cpu", Time: 5, Value: 1}, {Name: "cpu", Time: 6, Value: 2}},
+				{{Name: "cpu", Time: 2, Value: 1}, {Name: "cpu", Time: 3, Value: 3}},
+			},
+			exp: []query.Point{
+				&query.FloatPoint{Name: "cpu", Time: 2, Value: 1},
+				&query.FloatPoint{Name: "cpu", Time: 3, Value: 3},
+				&query.FloatPoint{Name: "cpu", Time: 6, Value: 2},
+			},
+		},
+		{
+			name: "descending",
+			points: [][]query.FloatPoint{
+				{{Name: "cpu", Time: 6, Value: 2}, {Name: "cpu", Time: 2, Value: 1}},
+				{{Name: "cpu", Time: 5, Value: 1}, {Name: "cpu", Time: 3, Value: 3}},
+			},
+			exp: []query.Point{
+				&query.FloatPoint{Name: "cpu", Time: 3, Value: 3},
+				&query.FloatPoint{Name: "cpu", Time: 5, Value: 1},
+				&query.FloatPoint{Name: "cpu", Time: 6, Value: 2},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := query.IteratorOptions{
+				Expr:      MustParseExpr(`distinct("value")`),
+				Interval:  query.Interval{Duration: 10 * time.Nanosecond},
+				StartTime: influxql.MinTime,
+				EndTime:   influxql.MaxTime,
+				Ascending: tt.ascending,
+			}
+
+			var shards []query.Iterator
+			for _, points := range tt.points {
+				itr, err := query.NewCallIterator(&FloatIterator{Points: points}, opt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				shards = append(shards, itr)
+			}
+
+			itr, err := query.Iterators(shards).Merge(opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if a, err := (Iterators{itr}).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(len(a), len(tt.exp)); diff != "" {
+				t.Fatalf("unexpected number of points:\n%s", diff)
+			} else {
+				got := make([]query.Point, len(a))
+				for i := range a {
+					got[i] = a[i][0]
+				}
+				if diff := cmp.Diff(got, tt.exp); diff != "" {
+					t.Fatalf("unexpected points:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+// Ensure that an aggregate registered by name can be compiled and creates its iterator.
+func TestRegisterAggregate(t *testing.T) {
+	query.RegisterAggregate("test_count", query.Aggregate{
+		New: func(input query.Iterator, opt query.IteratorOptions) (query.Iterator, error) {
+			call := opt.Expr.(*influxql.Call)
+			opt.Expr = &influxql.Call{Name: "count", Args: call.Args}
+			return query.NewCallIterator(input, opt)
+		},
+		Type: func([]influxql.DataType) influxql.DataType { return influxql.Integer },
+	})
+	t.Cleanup(func() { query.UnregisterAggregate("test_count") })
+
+	stmt, err := influxql.ParseStatement(`SELECT test_count(value) FROM cpu`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := query.Compile(stmt.(*influxql.SelectStatement), query.CompileOptions{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	typ, err := query.CallTypeMapper{}.CallType("test_count", []influxql.DataType{influxql.Float})
+	if err != nil {
+		t.Fatal(err)
+	} else if typ != influxql.Integer {
+		t.Fatalf("unexpected type: %s", typ)
+	}
+
+	itr, err := query.NewCallIterator(
+		&FloatIterator{Points: []query.FloatPoint{
+			{Name: "cpu", Time: 0, Value: 15},
+			{Name: "cpu", Time: 1, Value: 10},
+		}},
+		query.IteratorOptions{
+			Expr:      MustParseExpr(`test_count("value")`),
+			Interval:  query.Interval{Duration: 5 * time.Nanosecond},
+			Ordered:   true,
+			Ascending: true,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a, err := Iterators([]query.Iterator{itr}).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]query.Point{
+		{&query.IntegerPoint{Name: "cpu", Time: 0, Value: 2, Aggregated: 2}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected registering an aggregate twice to panic")
+		}
+	}()
+	query.RegisterAggregate("count", query.Aggregate{})
+}
+
+func TestNewCallIterator_MergeHll(t *testing.T) {
+	// merge_hll() is used to combine the sum_hll() results of every shard,
+	// but it cannot be called by a query.
+	stmt, err := influxql.ParseStatement(`SELECT merge_hll(value) FROM cpu`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := query.Compile(stmt.(*influxql.SelectStatement), query.CompileOptions{}); err == nil || err.Error() != "undefined function merge_hll()" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := query.NewCallIterator(
+		&StringIterator{},
+		query.IteratorOptions{Expr: MustParseExpr(`merge_hll("value")`)},
+	); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
 func BenchmarkCountIterator_1K(b *testing.B)   { benchmarkCountIterator(b, 1000) }
 func BenchmarkCountIterator_100K(b *testing.B) { benchmarkCountIterator(b, 100000) }
 func BenchmarkCountIterator_1M(b *testing.B)   { benchmarkCountIterator(b, 1000000) }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -14,6 +15,10 @@ import (
 // CompileOptions are the customization options for the compiler.
 type CompileOptions struct {
 	Now time.Time
+
+	// DefaultTimeRange limits the statements without a lower time bound to
+	// the time range ending at Now. Zero reads from the minimum time.
+	DefaultTimeRange time.Duration
 }
 
 // Statement is a compiled query statement.
@@ -167,7 +172,11 @@ func (c *compiledStatement) preprocess(stmt *influxql.SelectStatement) error {
 
 	// Resolve the min and max times now that we know if there is an interval or not.
 	if c.TimeRange.Min.IsZero() {
-		c.TimeRange.Min = time.Unix(0, influxql.MinTime).UTC()
+		if c.Options.DefaultTimeRange > 0 {
+			c.TimeRange.Min = c.Options.Now.Add(-c.Options.DefaultTimeRange)
+		} else {
+			c.TimeRange.Min = time.Unix(0, influxql.MinTime).UTC()
+		}
 	}
 	if c.TimeRange.Max.IsZero() {
 		// If the interval is non-zero, then we have an aggregate query and
@@ -183,6 +192,10 @@ func (c *compiledStatement) preprocess(stmt *influxql.SelectStatement) error {
 }
 
 func (c *compiledStatement) compile(stmt *influxql.SelectStatement) error {
+	// A measurement listed more than once in the FROM clause is only read once
+	// so its points are not returned twice.
+	stmt.Sources = uniqueSources(stmt.Sources)
+
 	if err := c.compileFields(stmt); err != nil {
 		return err
 	}
@@ -206,6 +219,24 @@ func (c *compiledStatement) compile(stmt *influxql.SelectStatement) error {
 	return nil
 }
 
+// uniqueSources returns the sources without the measurements that are
+// already in the list. Subqueries are always kept.
+func uniqueSources(sources influxql.Sources) influxql.Sources {
+	seen := make(map[string]struct{}, len(sources))
+	unique := sources[:0:0]
+	for _, source := range sources {
+		if m, ok := source.(*influxql.Measurement); ok {
+			key := m.String()
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		unique = append(unique, source)
+	}
+	return unique
+}
+
 func (c *compiledStatement) compileFields(stmt *influxql.SelectStatement) error {
 	valuer := MathValuer{}
 
@@ -393,13 +424,18 @@ func (c *compiledField) compileFunction(expr *influxql.Call) error {
 	// Validate the function call and mark down some meta properties
 	// related to the function for query validation.
 	switch expr.Name {
-	case "max", "min", "first", "last":
-		// top/bottom are not included here since they are not typical functions.
-	case "count", "sum", "mean", "median", "mode", "stddev", "spread", "sum_hll":
+	case "median", "mode", "stddev", "spread":
 		// These functions are not considered selectors.
 		c.global.OnlySelectors = false
 	default:
-		return fmt.Errorf("undefined function %s()", expr.Name)
+		// top/bottom are not included here since they are not typical functions.
+		agg, ok := aggregates[expr.Name]
+		if !ok || agg.Internal {
+			return fmt.Errorf("undefined function %s()", expr.Name)
+		}
+		if !agg.Selector {
+			c.global.OnlySelectors = false
+		}
 	}
 
 	if exp, got := 1, len(expr.Args); exp != got {
@@ -424,12 +460,18 @@ func (c *compiledField) compilePercentile(args []influxql.Expr) error {
 		return fmt.Errorf("invalid number of arguments for percentile, expected %d, got %d", exp, got)
 	}
 
-	switch args[1].(type) {
+	var percentile float64
+	switch arg1 := args[1].(type) {
 	case *influxql.IntegerLiteral:
+		percentile = float64(arg1.Val)
 	case *influxql.NumberLiteral:
+		percentile = arg1.Val
 	default:
 		return fmt.Errorf("expected float argument in percentile()")
 	}
+	if percentile < 0 || percentile > 100 {
+		return fmt.Errorf("percentile must be between 0 and 100, got %s", args[1])
+	}
 	return c.compileSymbol("percentile", args[0])
 }
 
@@ -878,6 +920,10 @@ func (c *compiledField) compileTopBottom(call *influxql.Call) error {
 			if !ok {
 				return fmt.Errorf("only fields or tags are allowed in %s(), found %s", call.Name, v)
 			}
+			// The selected points always keep their time, it cannot be used to distinguish them.
+			if strings.EqualFold(ref.Val, "time") {
+				return fmt.Errorf("time cannot be used as a field or tag in %s()", call.Name)
+			}
 
 			// Add a field for each of the listed dimensions when not writing the results.
 			if !c.global.HasTarget {
@@ -1149,6 +1195,9 @@ func (c *compiledStatement) Prepare(ctx context.Context, shardMapper ShardMapper
 			} else {
 				timeRange.Min = time.Unix(0, last-int64(interval)*int64(sopt.MaxBucketsN-1))
 			}
+			if sopt.Trace != nil {
+				sopt.Trace.Tracef("shard start time limited to %d buckets of %s: %s", sopt.MaxBucketsN, interval, timeRange.Min.UTC().Format(time.RFC3339Nano))
+			}
 		}
 	}
 
@@ -1171,6 +1220,14 @@ func (c *compiledStatement) Prepare(ctx context.Context, shardMapper ShardMapper
 		}
 	}
 
+	if sopt.Trace != nil {
+		if !c.Interval.IsZero() && c.ExtraIntervals > 0 {
+			sopt.Trace.Tracef("shard time range extended by %d extra intervals of %s", c.ExtraIntervals, c.Interval.Duration)
+		}
+		sopt.Trace.Tracef("map shards: sources=%s time range=[%s, %s]", c.stmt.Sources,
+			timeRange.Min.UTC().Format(time.RFC3339Nano), timeRange.Max.UTC().Format(time.RFC3339Nano))
+	}
+
 	// Create an iterator creator based on the shards in the cluster.
 	shards, err := shardMapper.MapShards(ctx, c.stmt.Sources, timeRange, sopt)
 	if err != nil {
@@ -1221,11 +1278,22 @@ func (c *compiledStatement) Prepare(ctx context.Context, shardMapper ShardMapper
 		}
 	}
 
+	var ic interface {
+		IteratorCreator
+		io.Closer
+	} = shards
+	if sopt.Trace != nil {
+		sopt.Trace.Tracef("rewritten fields: %s", stmt.Fields)
+		sopt.Trace.Tracef("iterator options: start=%d end=%d interval=%s offset=%s dimensions=[%s] ascending=%t",
+			opt.StartTime, opt.EndTime, opt.Interval.Duration, opt.Interval.Offset, strings.Join(opt.Dimensions, ", "), opt.Ascending)
+		ic = &traceIteratorCreator{ic: shards, trace: sopt.Trace}
+	}
+
 	columns := stmt.ColumnNames()
 	return &preparedStatement{
 		stmt:      stmt,
 		opt:       opt,
-		ic:        shards,
+		ic:        ic,
 		columns:   columns,
 		maxPointN: sopt.MaxPointN,
 		now:       c.Options.Now,
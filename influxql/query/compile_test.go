@@ -168,6 +168,8 @@ func TestCompile_Failures(t *testing.T) {
 		{s: `SELECT top(value) FROM cpu`, err: `invalid number of arguments for top, expected at least 2, got 1`},
 		{s: `SELECT top('unexpected', 5) FROM cpu`, err: `expected first argument to be a field in top(), found 'unexpected'`},
 		{s: `SELECT top(value, 'unexpected', 5) FROM cpu`, err: `only fields or tags are allowed in top(), found 'unexpected'`},
+		{s: `SELECT top(value, time, 2) FROM cpu`, err: `time cannot be used as a field or tag in top()`},
+		{s: `SELECT bottom(value, host, TIME, 2) FROM cpu`, err: `time cannot be used as a field or tag in bottom()`},
 		{s: `SELECT top(value, 2.5) FROM cpu`, err: `expected integer as last argument in top(), found 2.500`},
 		{s: `SELECT top(value, -1) FROM cpu`, err: `limit (-1) in top function must be at least 1`},
 		{s: `SELECT top(value, 3) FROM cpu LIMIT 2`, err: `limit (3) in top function can not be larger than the LIMIT (2) in the select statement`},
@@ -224,6 +226,8 @@ func TestCompile_Failures(t *testing.T) {
 		{s: `SELECT percentile() FROM myseries`, err: `invalid number of arguments for percentile, expected 2, got 0`},
 		{s: `SELECT percentile(field1) FROM myseries`, err: `invalid number of arguments for percentile, expected 2, got 1`},
 		{s: `SELECT percentile(field1, foo) FROM myseries`, err: `expected float argument in percentile()`},
+		{s: `SELECT percentile(field1, 101) FROM myseries`, err: `percentile must be between 0 and 100, got 101`},
+		{s: `SELECT percentile(field1, -0.5) FROM myseries`, err: `percentile must be between 0 and 100, got -0.500`},
 		{s: `SELECT percentile(max(field1), 75) FROM myseries`, err: `expected field argument in percentile()`},
 		{s: `SELECT field1 FROM foo group by time(1s)`, err: `GROUP BY requires at least one aggregate function`},
 		{s: `SELECT field1 FROM foo fill(none)`, err: `fill(none) must be used with a function`},
@@ -439,3 +443,50 @@ func TestPrepare_MapShardsTimeRange(t *testing.T) {
 		})
 	}
 }
+
+func TestPrepare_InvalidArgumentTypes(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		err string
+	}{
+		{s: `SELECT mean(s) FROM cpu`, err: `invalid argument type for the first argument in mean(): string`},
+		{s: `SELECT sum(b) FROM cpu`, err: `invalid argument type for the first argument in sum(): boolean`},
+		{s: `SELECT derivative(s) FROM cpu`, err: `invalid argument type for the first argument in derivative(): string`},
+		{s: `SELECT difference(last(s)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(10s)`, err: `invalid argument type for the first argument in difference(): string`},
+		{s: `SELECT abs(s) FROM cpu`, err: `invalid argument type for the first argument in abs(): string`},
+		{s: `SELECT count(s), max(b), sum(value) FROM cpu`},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			stmt, err := influxql.ParseStatement(tt.s)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			c, err := query.Compile(stmt.(*influxql.SelectStatement), query.CompileOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			shardMapper := ShardMapper{
+				MapShardsFn: func(_ context.Context, _ influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+					return &ShardGroup{
+						Fields: map[string]influxql.DataType{
+							"value": influxql.Float,
+							"s":     influxql.String,
+							"b":     influxql.Boolean,
+						},
+					}
+				},
+			}
+
+			_, err = c.Prepare(context.Background(), &shardMapper, query.SelectOptions{})
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			} else if err == nil || err.Error() != tt.err {
+				t.Fatalf("unexpected error: got=%v want=%s", err, tt.err)
+			}
+		})
+	}
+}
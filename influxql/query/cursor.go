@@ -404,6 +404,45 @@ func (cur *filterCursor) Scan(row *Row) bool {
 	return false
 }
 
+// pointLimitCheckInterval is the number of rows scanned between two checks of
+// the points read by a pointLimitCursor, as collecting the statistics of the
+// iterators is not free.
+const pointLimitCheckInterval = 1000
+
+// pointLimitCursor stops a query once its iterators have read more than limit points.
+// The points are counted every pointLimitCheckInterval rows and at the end of the cursor.
+type pointLimitCursor struct {
+	Cursor
+	limit int
+	rows  int
+	err   error
+}
+
+func newPointLimitCursor(cur Cursor, limit int) *pointLimitCursor {
+	return &pointLimitCursor{Cursor: cur, limit: limit}
+}
+
+func (cur *pointLimitCursor) Scan(row *Row) bool {
+	if cur.err != nil {
+		return false
+	}
+	ok := cur.Cursor.Scan(row)
+	if cur.rows++; !ok || cur.rows%pointLimitCheckInterval == 0 {
+		if n := cur.Cursor.Stats().PointN; n > cur.limit {
+			cur.err = ErrMaxSelectPointsLimitExceeded(n, cur.limit)
+			return false
+		}
+	}
+	return ok
+}
+
+func (cur *pointLimitCursor) Err() error {
+	if cur.err != nil {
+		return cur.err
+	}
+	return cur.Cursor.Err()
+}
+
 type nullCursor struct {
 	columns []influxql.VarRef
 }
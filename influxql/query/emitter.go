@@ -9,6 +9,10 @@ type Emitter struct {
 	cur       Cursor
 	chunkSize int
 
+	// limit is the number of points of the next emitted row.
+	// It grows up to chunkSize when the emitter is adaptive.
+	limit int
+
 	series  Series
 	row     *models.Row
 	columns []string
@@ -23,10 +27,23 @@ func NewEmitter(cur Cursor, chunkSize int) *Emitter {
 	return &Emitter{
 		cur:       cur,
 		chunkSize: chunkSize,
+		limit:     chunkSize,
 		columns:   columns,
 	}
 }
 
+// NewAdaptiveEmitter returns an Emitter whose first row holds at most
+// initialChunkSize points. The size of each following row doubles until
+// it reaches chunkSize, so the first points of a large series are returned
+// quickly and the following ones in fewer, larger rows.
+func NewAdaptiveEmitter(cur Cursor, initialChunkSize, chunkSize int) *Emitter {
+	e := NewEmitter(cur, chunkSize)
+	if initialChunkSize > 0 && initialChunkSize < chunkSize {
+		e.limit = initialChunkSize
+	}
+	return e
+}
+
 // Close closes the underlying iterators.
 func (e *Emitter) Close() error {
 	return e.cur.Close()
@@ -54,10 +71,11 @@ func (e *Emitter) Emit() (*models.Row, bool, error) {
 		if e.row == nil {
 			e.createRow(row.Series, row.Values)
 		} else if e.series.SameSeries(row.Series) {
-			if e.chunkSize > 0 && len(e.row.Values) >= e.chunkSize {
+			if e.limit > 0 && len(e.row.Values) >= e.limit {
 				r := e.row
 				r.Partial = true
 				e.createRow(row.Series, row.Values)
+				e.grow()
 				return r, true, nil
 			}
 			e.row.Values = append(e.row.Values, row.Values)
@@ -69,6 +87,16 @@ func (e *Emitter) Emit() (*models.Row, bool, error) {
 	}
 }
 
+// grow doubles the size of the next emitted rows, up to the chunk size.
+func (e *Emitter) grow() {
+	if e.limit < e.chunkSize {
+		e.limit *= 2
+		if e.limit > e.chunkSize {
+			e.limit = e.chunkSize
+		}
+	}
+}
+
 // createRow creates a new row attached to the emitter.
 func (e *Emitter) createRow(series Series, values []interface{}) {
 	e.series = series
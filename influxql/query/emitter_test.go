@@ -0,0 +1,150 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2/influxql/query"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxql"
+)
+
+func TestEmitter_Adaptive(t *testing.T) {
+	var rows []query.Row
+	for i := 0; i < 20; i++ {
+		rows = append(rows, query.Row{Time: int64(i), Series: query.Series{Name: "cpu"}, Values: []interface{}{int64(i), int64(i)}})
+	}
+	rows = append(rows, query.Row{Time: 0, Series: query.Series{Name: "mem"}, Values: []interface{}{int64(0), int64(0)}})
+
+	cur := query.RowCursor(rows, []influxql.VarRef{{Val: "time", Type: influxql.Time}, {Val: "value", Type: influxql.Integer}})
+	em := query.NewAdaptiveEmitter(cur, 2, 6)
+	defer em.Close()
+
+	type emitted struct {
+		Name    string
+		Size    int
+		Partial bool
+	}
+	var got []emitted
+	for {
+		row, _, err := em.Emit()
+		if err != nil {
+			t.Fatal(err)
+		} else if row == nil {
+			break
+		}
+		got = append(got, emitted{Name: row.Name, Size: len(row.Values), Partial: row.Partial})
+	}
+
+	// The size of the rows doubles up to the chunk size and keeps growing across series.
+	if diff := cmp.Diff([]emitted{
+		{Name: "cpu", Size: 2, Partial: true},
+		{Name: "cpu", Size: 4, Partial: true},
+		{Name: "cpu", Size: 6, Partial: true},
+		{Name: "cpu", Size: 6, Partial: true},
+		{Name: "cpu", Size: 2},
+		{Name: "mem", Size: 1},
+	}, got); diff != "" {
+		t.Fatalf("unexpected rows:\n%s", diff)
+	}
+}
+
+func TestEmitter_Adaptive_Unlimited(t *testing.T) {
+	var rows []query.Row
+	for i := 0; i < 10; i++ {
+		rows = append(rows, query.Row{Time: int64(i), Series: query.Series{Name: "cpu"}, Values: []interface{}{int64(i)}})
+	}
+
+	// Without a chunk size every point of a series is returned in a single row.
+	cur := query.RowCursor(rows, []influxql.VarRef{{Val: "time", Type: influxql.Time}})
+	em := query.NewAdaptiveEmitter(cur, 2, 0)
+	defer em.Close()
+
+	row, _, err := em.Emit()
+	if err != nil {
+		t.Fatal(err)
+	} else if row == nil || len(row.Values) != 10 || row.Partial {
+		t.Fatalf("unexpected row: %v", row)
+	}
+}
+
+func TestEmitter_MultipleShards(t *testing.T) {
+	// Both shards hold points of the same series, with one window overlapping the two shards.
+	shards := [][]query.FloatPoint{
+		{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 15 * Second, Value: 2},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 5 * Second, Value: 10},
+		},
+		{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 18 * Second, Value: 3},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 25 * Second, Value: 4},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 25 * Second, Value: 20},
+		},
+	}
+	shardMapper := ShardMapper{
+		MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields:     map[string]influxql.DataType{"value": influxql.Float},
+				Dimensions: []string{"host"},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					itrs := make([]query.Iterator, len(shards))
+					for i, points := range shards {
+						itr, err := query.NewCallIterator(&FloatIterator{Points: points}, opt)
+						if err != nil {
+							return nil, err
+						}
+						itrs[i] = itr
+					}
+					return query.Iterators(itrs).Merge(opt)
+				},
+			}
+		},
+	}
+
+	stmt := MustParseSelectStatement(`SELECT sum(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:30Z' GROUP BY time(10s), host fill(none)`)
+	cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	em := query.NewEmitter(cur, 0)
+	defer em.Close()
+
+	var got []*models.Row
+	for {
+		row, _, err := em.Emit()
+		if err != nil {
+			t.Fatal(err)
+		} else if row == nil {
+			break
+		}
+		got = append(got, row)
+	}
+
+	// A single row is emitted for each tagset, with the values of both shards combined.
+	if diff := cmp.Diff([]*models.Row{
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "A"},
+			Columns: []string{"time", "sum"},
+			Values: [][]interface{}{
+				{time.Unix(0, 0).UTC(), float64(1)},
+				{time.Unix(10, 0).UTC(), float64(5)},
+				{time.Unix(20, 0).UTC(), float64(4)},
+			},
+		},
+		{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "B"},
+			Columns: []string{"time", "sum"},
+			Values: [][]interface{}{
+				{time.Unix(0, 0).UTC(), float64(10)},
+				{time.Unix(20, 0).UTC(), float64(20)},
+			},
+		},
+	}, got); diff != "" {
+		t.Fatalf("unexpected rows:\n%s", diff)
+	}
+}
@@ -19,16 +19,34 @@ type ExecutionContext struct {
 
 	// Options used to start this query.
 	ExecutionOptions
+
+	// statistics counts the rows and values sent to the results when set.
+	statistics *iql.Statistics
 }
 
 // Send sends a Result to the Results channel and will exit if the query has
 // been interrupted or aborted.
 func (ectx *ExecutionContext) Send(ctx context.Context, result *Result) error {
 	result.StatementID = ectx.statementID
+
+	// Count the result before sending it since the receiver may modify it.
+	var rows, values int
+	if ectx.statistics != nil {
+		rows = len(result.Series)
+		for _, row := range result.Series {
+			values += len(row.Values)
+		}
+	}
+
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	case ectx.Results <- result:
 	}
+
+	if ectx.statistics != nil {
+		ectx.statistics.RowCount += rows
+		ectx.statistics.ValueCount += values
+	}
 	return nil
 }
@@ -118,9 +118,22 @@ type ExecutionOptions struct {
 	// The requested maximum number of points to return in each result.
 	ChunkSize int
 
+	// InitialChunkSize is the maximum number of points of the first result
+	// of a statement. The size of the next results doubles until it reaches
+	// ChunkSize. Zero returns ChunkSize points in every result.
+	InitialChunkSize int
+
 	// If this query is being executed in a read-only context.
 	ReadOnly bool
 
+	// DefaultTimeRange limits the statements without a lower time bound
+	// to the time range ending now. Zero reads from the minimum time.
+	DefaultTimeRange time.Duration
+
+	// MaxBucketsN lowers the maximum number of buckets of a statement
+	// configured for the server. Zero keeps the server limit.
+	MaxBucketsN int
+
 	// Node to execute on.
 	NodeID uint64
 
@@ -204,7 +217,7 @@ func (e *Executor) executeQuery(ctx context.Context, query *influxql.Query, opt
 		span.Finish()
 	}()
 
-	defer e.recover(query, results)
+	defer e.recover(ctx, query, results)
 
 	gatherer := new(iql.StatisticsGatherer)
 
@@ -214,7 +227,7 @@ func (e *Executor) executeQuery(ctx context.Context, query *influxql.Query, opt
 		e.Metrics.ExecutingDuration.WithLabelValues(statusLabel).Observe(dur.Seconds())
 	}(time.Now())
 
-	ectx := &ExecutionContext{StatisticsGatherer: gatherer, ExecutionOptions: opt}
+	ectx := &ExecutionContext{StatisticsGatherer: gatherer, ExecutionOptions: opt, statistics: statistics}
 
 	// Setup the execution context that will be used when executing statements.
 	ectx.Results = results
@@ -282,12 +295,14 @@ LOOP:
 		statistics.StatementCount += 1
 
 		// Log each normalized statement.
+		logger := e.log.With(zap.Int("statement_id", i))
 		if !ectx.Quiet {
-			e.log.Info("Executing query", zap.Stringer("query", stmt))
+			logger.Debug("Executing statement", zap.Stringer("query", stmt))
 			span.LogFields(log.String("normalized_query", stmt.String()))
 		}
 
 		gatherer.Reset()
+		rowCount, valueCount := statistics.RowCount, statistics.ValueCount
 		stmtStart := time.Now()
 		// Send any other statements to the underlying statement executor.
 		err = tracing.LogError(span, e.StatementExecutor.ExecuteStatement(ctx, stmt, ectx))
@@ -296,6 +311,15 @@ LOOP:
 		stmtStats.ExecuteDuration = stmtDur - stmtStats.PlanDuration
 		statistics.Add(stmtStats)
 
+		if !ectx.Quiet {
+			logger.Debug("Executed statement",
+				zap.Duration("plan_duration", stmtStats.PlanDuration),
+				zap.Duration("execute_duration", stmtStats.ExecuteDuration),
+				zap.Int("row_count", statistics.RowCount-rowCount),
+				zap.Int("value_count", statistics.ValueCount-valueCount),
+				zap.Error(err))
+		}
+
 		// Send an error for this result if it failed for some reason.
 		if err != nil {
 			statusLabel = control.LabelNotExecuted
@@ -340,12 +364,17 @@ func init() {
 	}
 }
 
-func (e *Executor) recover(query *influxql.Query, results chan *Result) {
+func (e *Executor) recover(ctx context.Context, query *influxql.Query, results chan *Result) {
 	if err := recover(); err != nil {
 		e.log.Error(fmt.Sprintf("%s [panic:%s] %s", query.String(), err, debug.Stack()))
-		results <- &Result{
+
+		// Do not wait for a reader that went away, the results would never be closed.
+		select {
+		case results <- &Result{
 			StatementID: -1,
 			Err:         fmt.Errorf("%s [panic:%s]", query.String(), err),
+		}:
+		case <-ctx.Done():
 		}
 
 		if willCrash {
@@ -11,9 +11,13 @@ import (
 	"github.com/influxdata/influxdb/v2/influxql/control"
 	"github.com/influxdata/influxdb/v2/influxql/query"
 	"github.com/influxdata/influxdb/v2/influxql/query/mocks"
+	"github.com/influxdata/influxdb/v2/models"
 	"github.com/influxdata/influxql"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 var errUnexpected = errors.New("unexpected error")
@@ -113,6 +117,33 @@ func TestQueryExecutor_Panic(t *testing.T) {
 	}
 }
 
+func TestQueryExecutor_Panic_Abort(t *testing.T) {
+	q, err := influxql.ParseQuery(`SELECT count(value) FROM cpu`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{})
+	e := NewQueryExecutor(t)
+	e.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(ctx context.Context, stmt influxql.Statement, ectx *query.ExecutionContext) error {
+			close(started)
+			panic("test error")
+		},
+	}
+
+	// The reader went away, the panic must not wait for it to read the error.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, _ := e.ExecuteQuery(ctx, q, query.ExecutionOptions{})
+	<-started
+	time.Sleep(50 * time.Millisecond)
+
+	if result, ok := <-results; ok {
+		t.Fatalf("expected the results to be closed, got %v", result)
+	}
+}
+
 func TestQueryExecutor_InvalidSource(t *testing.T) {
 	e := NewQueryExecutor(t)
 	e.StatementExecutor = &StatementExecutor{
@@ -192,6 +223,72 @@ func TestExecutor_ExecuteQuery_Statistics(t *testing.T) {
 	assert.Equal(t, 2, stats.StatementCount)
 }
 
+// This test verifies the rows and values sent for every statement are counted.
+func TestExecutor_ExecuteQuery_ResultStatistics(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	stmt := influxql.MustParseStatement("SELECT f0 FROM m0")
+	q := &influxql.Query{Statements: influxql.Statements{stmt, stmt}}
+
+	se := mocks.NewMockStatementExecutor(ctl)
+	se.EXPECT().ExecuteStatement(gomock.Any(), stmt, gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, statement influxql.Statement, ectx *query.ExecutionContext) error {
+			return ectx.Send(ctx, &query.Result{Series: models.Rows{
+				{Name: "m0", Columns: []string{"time", "f0"}, Values: [][]interface{}{{int64(0), 1.0}, {int64(1), 2.0}}},
+				{Name: "m0", Tags: map[string]string{"t0": "a"}, Columns: []string{"time", "f0"}, Values: [][]interface{}{{int64(0), 3.0}}},
+			}})
+		})
+
+	e := NewQueryExecutor(t)
+	e.StatementExecutor = se
+
+	ctx := context.Background()
+	results, stats := e.ExecuteQuery(ctx, q, query.ExecutionOptions{Quiet: true})
+	discardOutput(results)
+	assert.Equal(t, 4, stats.RowCount)
+	assert.Equal(t, 6, stats.ValueCount)
+}
+
+func TestExecutor_ExecuteQuery_Logging(t *testing.T) {
+	stmt := influxql.MustParseStatement("SELECT f0 FROM m0")
+	q := &influxql.Query{Statements: influxql.Statements{stmt}}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	e := query.NewExecutor(zap.New(core), control.NewControllerMetrics([]string{}))
+	e.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(ctx context.Context, stmt influxql.Statement, ectx *query.ExecutionContext) error {
+			return ectx.Send(ctx, &query.Result{Series: models.Rows{
+				{Name: "m0", Columns: []string{"time", "f0"}, Values: [][]interface{}{{int64(0), 1.0}, {int64(1), 2.0}}},
+			}})
+		},
+	}
+
+	results, _ := e.ExecuteQuery(context.Background(), q, query.ExecutionOptions{})
+	discardOutput(results)
+
+	entries := logs.All()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+		assert.Equal(t, "Executing statement", entries[0].Message)
+		assert.Equal(t, "SELECT f0 FROM m0", entries[0].ContextMap()["query"])
+
+		assert.Equal(t, zapcore.DebugLevel, entries[1].Level)
+		assert.Equal(t, "Executed statement", entries[1].Message)
+		fields := entries[1].ContextMap()
+		assert.Equal(t, int64(0), fields["statement_id"])
+		assert.Equal(t, int64(1), fields["row_count"])
+		assert.Equal(t, int64(2), fields["value_count"])
+	}
+
+	// Quiet queries are not logged.
+	logs.TakeAll()
+	results, _ = e.ExecuteQuery(context.Background(), q, query.ExecutionOptions{Quiet: true})
+	discardOutput(results)
+	assert.Empty(t, logs.All())
+}
+
 func discardOutput(results <-chan *query.Result) {
 	for range results {
 		// Read all results and discard.
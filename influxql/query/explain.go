@@ -3,43 +3,57 @@ package query
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/influxdata/influxql"
 )
 
 func (p *preparedStatement) Explain(ctx context.Context) (string, error) {
-	// Determine the cost of all iterators created as part of this plan.
-	ic := &explainIteratorCreator{ic: p.ic}
-	p.ic = ic
-	cur, err := p.Select(ctx)
-	p.ic = ic.ic
-
+	nodes, err := p.plan(ctx)
 	if err != nil {
 		return "", err
 	}
-	cur.Close()
 
 	var buf bytes.Buffer
-	for i, node := range ic.nodes {
+	for i, node := range nodes {
 		if i > 0 {
 			buf.WriteString("\n")
 		}
 
 		expr := "<nil>"
-		if node.Expr != nil {
-			expr = node.Expr.String()
+		if node.Opt.Expr != nil {
+			expr = node.Opt.Expr.String()
 		}
 		fmt.Fprintf(&buf, "EXPRESSION: %s\n", expr)
-		if len(node.Aux) != 0 {
-			refs := make([]string, len(node.Aux))
-			for i, ref := range node.Aux {
+		if len(node.Opt.Aux) != 0 {
+			refs := make([]string, len(node.Opt.Aux))
+			for i, ref := range node.Opt.Aux {
 				refs[i] = ref.String()
 			}
 			fmt.Fprintf(&buf, "AUXILIARY FIELDS: %s\n", strings.Join(refs, ", "))
 		}
+		if node.Measurement != nil {
+			fmt.Fprintf(&buf, "MEASUREMENT: %s\n", node.Measurement)
+		}
+		if len(node.Opt.GroupBy) != 0 {
+			dims := make([]string, 0, len(node.Opt.GroupBy))
+			for dim := range node.Opt.GroupBy {
+				dims = append(dims, dim)
+			}
+			sort.Strings(dims)
+			fmt.Fprintf(&buf, "TAG SET: %s\n", strings.Join(dims, ", "))
+		}
+		fmt.Fprintf(&buf, "TIME RANGE: %s - %s\n", formatExplainTime(node.Opt.StartTime), formatExplainTime(node.Opt.EndTime))
+		if !node.Opt.Interval.IsZero() {
+			fmt.Fprintf(&buf, "INTERVAL: %s\n", influxql.FormatDuration(node.Opt.Interval.Duration))
+		}
 		fmt.Fprintf(&buf, "NUMBER OF SHARDS: %d\n", node.Cost.NumShards)
 		fmt.Fprintf(&buf, "NUMBER OF SERIES: %d\n", node.Cost.NumSeries)
 		fmt.Fprintf(&buf, "CACHED VALUES: %d\n", node.Cost.CachedValues)
@@ -47,13 +61,186 @@ func (p *preparedStatement) Explain(ctx context.Context) (string, error) {
 		fmt.Fprintf(&buf, "NUMBER OF BLOCKS: %d\n", node.Cost.BlocksRead)
 		fmt.Fprintf(&buf, "SIZE OF BLOCKS: %d\n", node.Cost.BlockSize)
 	}
+
+	hash, err := newPlan(nodes).Hash()
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&buf, "\nPLAN HASH: %s\n", hash)
 	return buf.String(), nil
 }
 
+// formatExplainTime formats a time of the plan, leaving out the unbounded times.
+func formatExplainTime(t int64) string {
+	switch t {
+	case influxql.MinTime:
+		return "-inf"
+	case influxql.MaxTime:
+		return "+inf"
+	default:
+		return time.Unix(0, t).UTC().Format(time.RFC3339Nano)
+	}
+}
+
+func (p *preparedStatement) Plan(ctx context.Context) (Plan, error) {
+	nodes, err := p.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newPlan(nodes), nil
+}
+
+// plan returns the iterators the statement creates, without reading them.
+func (p *preparedStatement) plan(ctx context.Context) ([]planNode, error) {
+	// Determine the cost of all iterators created as part of this plan.
+	ic := &explainIteratorCreator{ic: p.ic}
+	p.ic = ic
+	cur, err := p.Select(ctx)
+	p.ic = ic.ic
+
+	if err != nil {
+		return nil, err
+	}
+	cur.Close()
+	return ic.nodes, nil
+}
+
+// Plan is the list of iterators created to execute a statement, in the order they are created.
+// Its JSON encoding only depends on the statement and the time it is prepared at.
+type Plan []PlanNode
+
+func newPlan(nodes []planNode) Plan {
+	plan := make(Plan, len(nodes))
+	for i, node := range nodes {
+		plan[i] = newPlanNode(node.Measurement, node.Opt)
+	}
+	return plan
+}
+
+// Hash returns the hex encoded SHA-256 checksum of the shape of the plan.
+// The absolute times of the nodes are replaced by the length of their time range,
+// so the same query run at different times, such as a query relative to now(),
+// has the same hash.
+func (p Plan) Hash() (string, error) {
+	shape := make([]planNodeShape, len(p))
+	for i, node := range p {
+		shape[i] = planNodeShape{PlanNode: node, Range: -1}
+		if node.StartTime != influxql.MinTime && node.EndTime != influxql.MaxTime {
+			shape[i].Range = node.EndTime - node.StartTime
+		}
+		shape[i].PlanNode.StartTime, shape[i].PlanNode.EndTime = 0, 0
+	}
+
+	b, err := json.Marshal(shape)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// planNodeShape is a plan node without its absolute times. Range is the length
+// of the time range of the node, or -1 if the range is unbounded.
+type planNodeShape struct {
+	PlanNode
+	Range int64 `json:"range"`
+}
+
+// PlanNode describes an iterator created by a plan. The options that do not change
+// the values read by the iterator, such as its concurrency, are left out.
+type PlanNode struct {
+	Measurement string   `json:"measurement"`
+	Expr        string   `json:"expr,omitempty"`
+	Aux         []string `json:"aux,omitempty"`
+	Sources     []string `json:"sources,omitempty"`
+
+	Interval   Interval `json:"interval"`
+	Dimensions []string `json:"dimensions,omitempty"`
+	GroupBy    []string `json:"groupBy,omitempty"`
+	Location   string   `json:"location,omitempty"`
+
+	Fill      string      `json:"fill"`
+	FillValue interface{} `json:"fillValue,omitempty"`
+
+	Condition string `json:"condition,omitempty"`
+	StartTime int64  `json:"startTime"`
+	EndTime   int64  `json:"endTime"`
+	Ascending bool   `json:"ascending"`
+
+	Limit   int `json:"limit,omitempty"`
+	Offset  int `json:"offset,omitempty"`
+	SLimit  int `json:"slimit,omitempty"`
+	SOffset int `json:"soffset,omitempty"`
+
+	StripName bool `json:"stripName,omitempty"`
+	Dedupe    bool `json:"dedupe,omitempty"`
+	Ordered   bool `json:"ordered,omitempty"`
+}
+
+func newPlanNode(m *influxql.Measurement, opt IteratorOptions) PlanNode {
+	node := PlanNode{
+		Interval:   opt.Interval,
+		Dimensions: opt.Dimensions,
+		Fill:       fillOptionString(opt.Fill),
+		FillValue:  opt.FillValue,
+		StartTime:  opt.StartTime,
+		EndTime:    opt.EndTime,
+		Ascending:  opt.Ascending,
+		Limit:      opt.Limit,
+		Offset:     opt.Offset,
+		SLimit:     opt.SLimit,
+		SOffset:    opt.SOffset,
+		StripName:  opt.StripName,
+		Dedupe:     opt.Dedupe,
+		Ordered:    opt.Ordered,
+	}
+	if m != nil {
+		node.Measurement = m.String()
+	}
+	if opt.Expr != nil {
+		node.Expr = opt.Expr.String()
+	}
+	for _, ref := range opt.Aux {
+		node.Aux = append(node.Aux, ref.String())
+	}
+	for _, src := range opt.Sources {
+		node.Sources = append(node.Sources, src.String())
+	}
+	for dim := range opt.GroupBy {
+		node.GroupBy = append(node.GroupBy, dim)
+	}
+	sort.Strings(node.GroupBy)
+	if opt.Location != nil {
+		node.Location = opt.Location.String()
+	}
+	if opt.Condition != nil {
+		node.Condition = opt.Condition.String()
+	}
+	return node
+}
+
+// fillOptionString returns the name of the fill option as written in a query.
+func fillOptionString(fill influxql.FillOption) string {
+	switch fill {
+	case influxql.NullFill:
+		return "null"
+	case influxql.NoFill:
+		return "none"
+	case influxql.NumberFill:
+		return "number"
+	case influxql.PreviousFill:
+		return "previous"
+	case influxql.LinearFill:
+		return "linear"
+	default:
+		return fmt.Sprintf("fill(%d)", fill)
+	}
+}
+
 type planNode struct {
-	Expr influxql.Expr
-	Aux  []influxql.VarRef
-	Cost IteratorCost
+	Measurement *influxql.Measurement
+	Opt         IteratorOptions
+	Cost        IteratorCost
 }
 
 type explainIteratorCreator struct {
@@ -70,9 +257,9 @@ func (e *explainIteratorCreator) CreateIterator(ctx context.Context, m *influxql
 		return nil, err
 	}
 	e.nodes = append(e.nodes, planNode{
-		Expr: opt.Expr,
-		Aux:  opt.Aux,
-		Cost: cost,
+		Measurement: m,
+		Opt:         opt,
+		Cost:        cost,
 	})
 	return &nilFloatIterator{}, nil
 }
@@ -0,0 +1,7 @@
+package query
+
+// UnregisterAggregate removes an aggregate registered by a test,
+// so that the test can register it again when it is run again.
+func UnregisterAggregate(name string) {
+	delete(aggregates, name)
+}
@@ -411,6 +411,11 @@ func (r *FloatSumHllReducer) Emit() []StringPoint {
 // FloatDistinctReducer returns the distinct points in a series.
 type FloatDistinctReducer struct {
 	m map[float64]FloatPoint
+
+	// merge keeps the first point of every value in the order of ascending
+	// instead of the first point aggregated.
+	merge     bool
+	ascending bool
 }
 
 // NewFloatDistinctReducer creates a new FloatDistinctReducer.
@@ -418,9 +423,18 @@ func NewFloatDistinctReducer() *FloatDistinctReducer {
 	return &FloatDistinctReducer{m: make(map[float64]FloatPoint)}
 }
 
+// newFloatDistinctMergeReducer creates a FloatDistinctReducer for
+// combining distinct points which are not aggregated in time order.
+func newFloatDistinctMergeReducer(ascending bool) *FloatDistinctReducer {
+	r := NewFloatDistinctReducer()
+	r.merge, r.ascending = true, ascending
+	return r
+}
+
 // AggregateFloat aggregates a point into the reducer.
 func (r *FloatDistinctReducer) AggregateFloat(p *FloatPoint) {
-	if _, ok := r.m[p.Value]; !ok {
+	prev, ok := r.m[p.Value]
+	if !ok || r.merge && (r.ascending && p.Time < prev.Time || !r.ascending && p.Time > prev.Time) {
 		r.m[p.Value] = *p
 	}
 }
@@ -908,6 +922,11 @@ func (r *IntegerSumHllReducer) Emit() []StringPoint {
 // IntegerDistinctReducer returns the distinct points in a series.
 type IntegerDistinctReducer struct {
 	m map[int64]IntegerPoint
+
+	// merge keeps the first point of every value in the order of ascending
+	// instead of the first point aggregated.
+	merge     bool
+	ascending bool
 }
 
 // NewIntegerDistinctReducer creates a new IntegerDistinctReducer.
@@ -915,9 +934,18 @@ func NewIntegerDistinctReducer() *IntegerDistinctReducer {
 	return &IntegerDistinctReducer{m: make(map[int64]IntegerPoint)}
 }
 
+// newIntegerDistinctMergeReducer creates a IntegerDistinctReducer for
+// combining distinct points which are not aggregated in time order.
+func newIntegerDistinctMergeReducer(ascending bool) *IntegerDistinctReducer {
+	r := NewIntegerDistinctReducer()
+	r.merge, r.ascending = true, ascending
+	return r
+}
+
 // AggregateInteger aggregates a point into the reducer.
 func (r *IntegerDistinctReducer) AggregateInteger(p *IntegerPoint) {
-	if _, ok := r.m[p.Value]; !ok {
+	prev, ok := r.m[p.Value]
+	if !ok || r.merge && (r.ascending && p.Time < prev.Time || !r.ascending && p.Time > prev.Time) {
 		r.m[p.Value] = *p
 	}
 }
@@ -1405,6 +1433,11 @@ func (r *UnsignedSumHllReducer) Emit() []StringPoint {
 // UnsignedDistinctReducer returns the distinct points in a series.
 type UnsignedDistinctReducer struct {
 	m map[uint64]UnsignedPoint
+
+	// merge keeps the first point of every value in the order of ascending
+	// instead of the first point aggregated.
+	merge     bool
+	ascending bool
 }
 
 // NewUnsignedDistinctReducer creates a new UnsignedDistinctReducer.
@@ -1412,9 +1445,18 @@ func NewUnsignedDistinctReducer() *UnsignedDistinctReducer {
 	return &UnsignedDistinctReducer{m: make(map[uint64]UnsignedPoint)}
 }
 
+// newUnsignedDistinctMergeReducer creates a UnsignedDistinctReducer for
+// combining distinct points which are not aggregated in time order.
+func newUnsignedDistinctMergeReducer(ascending bool) *UnsignedDistinctReducer {
+	r := NewUnsignedDistinctReducer()
+	r.merge, r.ascending = true, ascending
+	return r
+}
+
 // AggregateUnsigned aggregates a point into the reducer.
 func (r *UnsignedDistinctReducer) AggregateUnsigned(p *UnsignedPoint) {
-	if _, ok := r.m[p.Value]; !ok {
+	prev, ok := r.m[p.Value]
+	if !ok || r.merge && (r.ascending && p.Time < prev.Time || !r.ascending && p.Time > prev.Time) {
 		r.m[p.Value] = *p
 	}
 }
@@ -1900,6 +1942,11 @@ func (r *StringSumHllReducer) Emit() []StringPoint {
 // StringDistinctReducer returns the distinct points in a series.
 type StringDistinctReducer struct {
 	m map[string]StringPoint
+
+	// merge keeps the first point of every value in the order of ascending
+	// instead of the first point aggregated.
+	merge     bool
+	ascending bool
 }
 
 // NewStringDistinctReducer creates a new StringDistinctReducer.
@@ -1907,9 +1954,18 @@ func NewStringDistinctReducer() *StringDistinctReducer {
 	return &StringDistinctReducer{m: make(map[string]StringPoint)}
 }
 
+// newStringDistinctMergeReducer creates a StringDistinctReducer for
+// combining distinct points which are not aggregated in time order.
+func newStringDistinctMergeReducer(ascending bool) *StringDistinctReducer {
+	r := NewStringDistinctReducer()
+	r.merge, r.ascending = true, ascending
+	return r
+}
+
 // AggregateString aggregates a point into the reducer.
 func (r *StringDistinctReducer) AggregateString(p *StringPoint) {
-	if _, ok := r.m[p.Value]; !ok {
+	prev, ok := r.m[p.Value]
+	if !ok || r.merge && (r.ascending && p.Time < prev.Time || !r.ascending && p.Time > prev.Time) {
 		r.m[p.Value] = *p
 	}
 }
@@ -2397,6 +2453,11 @@ func (r *BooleanSumHllReducer) Emit() []StringPoint {
 // BooleanDistinctReducer returns the distinct points in a series.
 type BooleanDistinctReducer struct {
 	m map[bool]BooleanPoint
+
+	// merge keeps the first point of every value in the order of ascending
+	// instead of the first point aggregated.
+	merge     bool
+	ascending bool
 }
 
 // NewBooleanDistinctReducer creates a new BooleanDistinctReducer.
@@ -2404,9 +2465,18 @@ func NewBooleanDistinctReducer() *BooleanDistinctReducer {
 	return &BooleanDistinctReducer{m: make(map[bool]BooleanPoint)}
 }
 
+// newBooleanDistinctMergeReducer creates a BooleanDistinctReducer for
+// combining distinct points which are not aggregated in time order.
+func newBooleanDistinctMergeReducer(ascending bool) *BooleanDistinctReducer {
+	r := NewBooleanDistinctReducer()
+	r.merge, r.ascending = true, ascending
+	return r
+}
+
 // AggregateBoolean aggregates a point into the reducer.
 func (r *BooleanDistinctReducer) AggregateBoolean(p *BooleanPoint) {
-	if _, ok := r.m[p.Value]; !ok {
+	prev, ok := r.m[p.Value]
+	if !ok || r.merge && (r.ascending && p.Time < prev.Time || !r.ascending && p.Time > prev.Time) {
 		r.m[p.Value] = *p
 	}
 }
@@ -50,14 +50,8 @@ func (CallTypeMapper) MapType(measurement *influxql.Measurement, field string) i
 func (CallTypeMapper) CallType(name string, args []influxql.DataType) (influxql.DataType, error) {
 	// If the function is not implemented by the embedded field mapper, then
 	// see if we implement the function and return the type here.
-	switch name {
-	case "mean":
-		return influxql.Float, nil
-	case "count":
-		return influxql.Integer, nil
-	case "min", "max", "sum", "first", "last":
-		// TODO(jsternberg): Verify the input type.
-		return args[0], nil
+	if agg, ok := aggregates[name]; ok && agg.Type != nil {
+		return agg.Type(args), nil
 	}
 	return influxql.Unknown, nil
 }
@@ -73,6 +67,9 @@ func (FunctionTypeMapper) MapType(measurement *influxql.Measurement, field strin
 }
 
 func (m FunctionTypeMapper) CallType(name string, args []influxql.DataType) (influxql.DataType, error) {
+	if err := validateNumericArg(name, args); err != nil {
+		return influxql.Unknown, err
+	}
 	if typ, err := m.CallTypeMapper.CallType(name, args); typ != influxql.Unknown || err != nil {
 		return typ, err
 	}
@@ -95,11 +92,45 @@ func (m FunctionTypeMapper) CallType(name string, args []influxql.DataType) (inf
 	case "elapsed":
 		return influxql.Integer, nil
 	default:
+		// Let the math functions validate their own arguments.
+		if typ, err := (MathTypeMapper{}).CallType(name, args); typ != influxql.Unknown || err != nil {
+			return typ, err
+		}
 		// TODO(jsternberg): Do not use default for this.
 		return args[0], nil
 	}
 }
 
+// validateNumericArg returns an error if a function that can only be computed
+// from numbers is called with a string or boolean as its first argument.
+// Without this, the query would only fail once the iterators are created.
+func validateNumericArg(name string, args []influxql.DataType) error {
+	switch name {
+	case "mean", "median", "sum", "spread", "stddev", "integral", "percentile",
+		"top", "bottom",
+		"derivative", "non_negative_derivative",
+		"difference", "non_negative_difference",
+		"moving_average", "cumulative_sum",
+		"exponential_moving_average",
+		"double_exponential_moving_average",
+		"triple_exponential_moving_average",
+		"relative_strength_index",
+		"triple_exponential_derivative",
+		"kaufmans_efficiency_ratio",
+		"kaufmans_adaptive_moving_average",
+		"chande_momentum_oscillator",
+		"holt_winters", "holt_winters_with_fit":
+		if len(args) == 0 {
+			return nil
+		}
+		switch args[0] {
+		case influxql.String, influxql.Boolean:
+			return fmt.Errorf("invalid argument type for the first argument in %s(): %s", name, args[0])
+		}
+	}
+	return nil
+}
+
 // FloatMeanReducer calculates the mean of the aggregated points.
 type FloatMeanReducer struct {
 	sum   float64
@@ -377,7 +408,9 @@ func (r *IntegerDerivativeReducer) Emit() []FloatPoint {
 
 	// Calculate the derivative of successive points by dividing the
 	// difference of each value by the elapsed time normalized to the interval.
-	diff := float64(r.curr.Value - r.prev.Value)
+	// The values are converted before subtracting so the difference
+	// of values far apart does not overflow.
+	diff := float64(r.curr.Value) - float64(r.prev.Value)
 	elapsed := r.curr.Time - r.prev.Time
 	if !r.ascending {
 		elapsed = -elapsed
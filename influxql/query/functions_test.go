@@ -424,6 +424,41 @@ func TestHoltWinters_MaxTime(t *testing.T) {
 //
 // The idea here is that 30 iterations should be enough to hit every possible
 // sequence at least once.
+func TestIntegerDerivativeReducer_Overflow(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		prev, curr    int64
+		isNonNegative bool
+		want          []query.FloatPoint
+	}{
+		{
+			name: "increasing",
+			prev: math.MinInt64,
+			curr: math.MaxInt64,
+			want: []query.FloatPoint{{Time: 2, Value: float64(math.MaxInt64) - float64(math.MinInt64)}},
+		},
+		{
+			name: "decreasing",
+			prev: math.MaxInt64,
+			curr: math.MinInt64,
+			want: []query.FloatPoint{{Time: 2, Value: float64(math.MinInt64) - float64(math.MaxInt64)}},
+		},
+		{
+			name:          "decreasing non-negative",
+			prev:          math.MaxInt64,
+			curr:          math.MinInt64,
+			isNonNegative: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := query.NewIntegerDerivativeReducer(query.Interval{Duration: 1}, tt.isNonNegative, true)
+			r.AggregateInteger(&query.IntegerPoint{Time: 1, Value: tt.prev})
+			r.AggregateInteger(&query.IntegerPoint{Time: 2, Value: tt.curr})
+			tassert.Equal(t, tt.want, r.Emit())
+		})
+	}
+}
+
 func TestSample_AllSamplesSeen(t *testing.T) {
 	ps := []query.FloatPoint{
 		{Time: 1, Value: 1},
@@ -653,6 +653,10 @@ type floatLimitIterator struct {
 	opt   IteratorOptions
 	n     int
 
+	// single is set when the input produces a single group so the
+	// input does not have to be read once the limit has been reached.
+	single bool
+
 	prev struct {
 		name string
 		tags Tags
@@ -676,6 +680,11 @@ func (itr *floatLimitIterator) Close() error { return itr.input.Close() }
 // Next returns the next point from the iterator.
 func (itr *floatLimitIterator) Next() (*FloatPoint, error) {
 	for {
+		// Stop reading when no other group can follow.
+		if itr.single && itr.opt.Limit > 0 && (itr.n-itr.opt.Offset) >= itr.opt.Limit {
+			return nil, nil
+		}
+
 		p, err := itr.input.Next()
 		if p == nil || err != nil {
 			return nil, err
@@ -1002,6 +1011,11 @@ type floatReduceFloatIterator struct {
 	opt      IteratorOptions
 	points   []FloatPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*floatReduceFloatPoint
+	keys []string
 }
 
 func newFloatReduceFloatIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, FloatPointEmitter)) *floatReduceFloatIterator {
@@ -1071,7 +1085,10 @@ func (itr *floatReduceFloatIterator) reduce() ([]FloatPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*floatReduceFloatPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*floatReduceFloatPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -1115,7 +1132,7 @@ func (itr *floatReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		rp.Aggregator.AggregateFloat(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -1151,6 +1168,13 @@ func (itr *floatReduceFloatIterator) reduce() ([]FloatPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = floatPointsByTime(a)
@@ -1290,6 +1314,11 @@ type floatReduceIntegerIterator struct {
 	opt      IteratorOptions
 	points   []IntegerPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*floatReduceIntegerPoint
+	keys []string
 }
 
 func newFloatReduceIntegerIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, IntegerPointEmitter)) *floatReduceIntegerIterator {
@@ -1359,7 +1388,10 @@ func (itr *floatReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*floatReduceIntegerPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*floatReduceIntegerPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -1403,7 +1435,7 @@ func (itr *floatReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		rp.Aggregator.AggregateFloat(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -1439,6 +1471,13 @@ func (itr *floatReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = integerPointsByTime(a)
@@ -1578,6 +1617,11 @@ type floatReduceUnsignedIterator struct {
 	opt      IteratorOptions
 	points   []UnsignedPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*floatReduceUnsignedPoint
+	keys []string
 }
 
 func newFloatReduceUnsignedIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, UnsignedPointEmitter)) *floatReduceUnsignedIterator {
@@ -1647,7 +1691,10 @@ func (itr *floatReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*floatReduceUnsignedPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*floatReduceUnsignedPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -1691,7 +1738,7 @@ func (itr *floatReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		rp.Aggregator.AggregateFloat(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -1727,6 +1774,13 @@ func (itr *floatReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = unsignedPointsByTime(a)
@@ -1866,6 +1920,11 @@ type floatReduceStringIterator struct {
 	opt      IteratorOptions
 	points   []StringPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*floatReduceStringPoint
+	keys []string
 }
 
 func newFloatReduceStringIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, StringPointEmitter)) *floatReduceStringIterator {
@@ -1935,7 +1994,10 @@ func (itr *floatReduceStringIterator) reduce() ([]StringPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*floatReduceStringPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*floatReduceStringPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -1979,7 +2041,7 @@ func (itr *floatReduceStringIterator) reduce() ([]StringPoint, error) {
 		rp.Aggregator.AggregateFloat(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -2015,6 +2077,13 @@ func (itr *floatReduceStringIterator) reduce() ([]StringPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = stringPointsByTime(a)
@@ -2154,6 +2223,11 @@ type floatReduceBooleanIterator struct {
 	opt      IteratorOptions
 	points   []BooleanPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*floatReduceBooleanPoint
+	keys []string
 }
 
 func newFloatReduceBooleanIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, BooleanPointEmitter)) *floatReduceBooleanIterator {
@@ -2223,7 +2297,10 @@ func (itr *floatReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*floatReduceBooleanPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*floatReduceBooleanPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -2267,7 +2344,7 @@ func (itr *floatReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		rp.Aggregator.AggregateFloat(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -2303,6 +2380,13 @@ func (itr *floatReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = booleanPointsByTime(a)
@@ -3317,6 +3401,10 @@ type integerLimitIterator struct {
 	opt   IteratorOptions
 	n     int
 
+	// single is set when the input produces a single group so the
+	// input does not have to be read once the limit has been reached.
+	single bool
+
 	prev struct {
 		name string
 		tags Tags
@@ -3340,6 +3428,11 @@ func (itr *integerLimitIterator) Close() error { return itr.input.Close() }
 // Next returns the next point from the iterator.
 func (itr *integerLimitIterator) Next() (*IntegerPoint, error) {
 	for {
+		// Stop reading when no other group can follow.
+		if itr.single && itr.opt.Limit > 0 && (itr.n-itr.opt.Offset) >= itr.opt.Limit {
+			return nil, nil
+		}
+
 		p, err := itr.input.Next()
 		if p == nil || err != nil {
 			return nil, err
@@ -3666,6 +3759,11 @@ type integerReduceFloatIterator struct {
 	opt      IteratorOptions
 	points   []FloatPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*integerReduceFloatPoint
+	keys []string
 }
 
 func newIntegerReduceFloatIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, FloatPointEmitter)) *integerReduceFloatIterator {
@@ -3735,7 +3833,10 @@ func (itr *integerReduceFloatIterator) reduce() ([]FloatPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*integerReduceFloatPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*integerReduceFloatPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -3779,7 +3880,7 @@ func (itr *integerReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		rp.Aggregator.AggregateInteger(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -3815,6 +3916,13 @@ func (itr *integerReduceFloatIterator) reduce() ([]FloatPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = floatPointsByTime(a)
@@ -3954,6 +4062,11 @@ type integerReduceIntegerIterator struct {
 	opt      IteratorOptions
 	points   []IntegerPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*integerReduceIntegerPoint
+	keys []string
 }
 
 func newIntegerReduceIntegerIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, IntegerPointEmitter)) *integerReduceIntegerIterator {
@@ -4023,7 +4136,10 @@ func (itr *integerReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*integerReduceIntegerPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*integerReduceIntegerPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -4067,7 +4183,7 @@ func (itr *integerReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		rp.Aggregator.AggregateInteger(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -4103,6 +4219,13 @@ func (itr *integerReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = integerPointsByTime(a)
@@ -4242,6 +4365,11 @@ type integerReduceUnsignedIterator struct {
 	opt      IteratorOptions
 	points   []UnsignedPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*integerReduceUnsignedPoint
+	keys []string
 }
 
 func newIntegerReduceUnsignedIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, UnsignedPointEmitter)) *integerReduceUnsignedIterator {
@@ -4311,7 +4439,10 @@ func (itr *integerReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*integerReduceUnsignedPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*integerReduceUnsignedPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -4355,7 +4486,7 @@ func (itr *integerReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		rp.Aggregator.AggregateInteger(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -4391,6 +4522,13 @@ func (itr *integerReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = unsignedPointsByTime(a)
@@ -4530,6 +4668,11 @@ type integerReduceStringIterator struct {
 	opt      IteratorOptions
 	points   []StringPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*integerReduceStringPoint
+	keys []string
 }
 
 func newIntegerReduceStringIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, StringPointEmitter)) *integerReduceStringIterator {
@@ -4599,7 +4742,10 @@ func (itr *integerReduceStringIterator) reduce() ([]StringPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*integerReduceStringPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*integerReduceStringPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -4643,7 +4789,7 @@ func (itr *integerReduceStringIterator) reduce() ([]StringPoint, error) {
 		rp.Aggregator.AggregateInteger(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -4679,6 +4825,13 @@ func (itr *integerReduceStringIterator) reduce() ([]StringPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = stringPointsByTime(a)
@@ -4818,6 +4971,11 @@ type integerReduceBooleanIterator struct {
 	opt      IteratorOptions
 	points   []BooleanPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*integerReduceBooleanPoint
+	keys []string
 }
 
 func newIntegerReduceBooleanIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, BooleanPointEmitter)) *integerReduceBooleanIterator {
@@ -4887,7 +5045,10 @@ func (itr *integerReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*integerReduceBooleanPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*integerReduceBooleanPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -4931,7 +5092,7 @@ func (itr *integerReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		rp.Aggregator.AggregateInteger(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -4967,6 +5128,13 @@ func (itr *integerReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = booleanPointsByTime(a)
@@ -5981,6 +6149,10 @@ type unsignedLimitIterator struct {
 	opt   IteratorOptions
 	n     int
 
+	// single is set when the input produces a single group so the
+	// input does not have to be read once the limit has been reached.
+	single bool
+
 	prev struct {
 		name string
 		tags Tags
@@ -6004,6 +6176,11 @@ func (itr *unsignedLimitIterator) Close() error { return itr.input.Close() }
 // Next returns the next point from the iterator.
 func (itr *unsignedLimitIterator) Next() (*UnsignedPoint, error) {
 	for {
+		// Stop reading when no other group can follow.
+		if itr.single && itr.opt.Limit > 0 && (itr.n-itr.opt.Offset) >= itr.opt.Limit {
+			return nil, nil
+		}
+
 		p, err := itr.input.Next()
 		if p == nil || err != nil {
 			return nil, err
@@ -6330,6 +6507,11 @@ type unsignedReduceFloatIterator struct {
 	opt      IteratorOptions
 	points   []FloatPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*unsignedReduceFloatPoint
+	keys []string
 }
 
 func newUnsignedReduceFloatIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, FloatPointEmitter)) *unsignedReduceFloatIterator {
@@ -6399,7 +6581,10 @@ func (itr *unsignedReduceFloatIterator) reduce() ([]FloatPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*unsignedReduceFloatPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*unsignedReduceFloatPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -6443,7 +6628,7 @@ func (itr *unsignedReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -6479,6 +6664,13 @@ func (itr *unsignedReduceFloatIterator) reduce() ([]FloatPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = floatPointsByTime(a)
@@ -6618,6 +6810,11 @@ type unsignedReduceIntegerIterator struct {
 	opt      IteratorOptions
 	points   []IntegerPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*unsignedReduceIntegerPoint
+	keys []string
 }
 
 func newUnsignedReduceIntegerIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, IntegerPointEmitter)) *unsignedReduceIntegerIterator {
@@ -6687,7 +6884,10 @@ func (itr *unsignedReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*unsignedReduceIntegerPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*unsignedReduceIntegerPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -6731,7 +6931,7 @@ func (itr *unsignedReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -6767,6 +6967,13 @@ func (itr *unsignedReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = integerPointsByTime(a)
@@ -6906,6 +7113,11 @@ type unsignedReduceUnsignedIterator struct {
 	opt      IteratorOptions
 	points   []UnsignedPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*unsignedReduceUnsignedPoint
+	keys []string
 }
 
 func newUnsignedReduceUnsignedIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, UnsignedPointEmitter)) *unsignedReduceUnsignedIterator {
@@ -6975,7 +7187,10 @@ func (itr *unsignedReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*unsignedReduceUnsignedPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*unsignedReduceUnsignedPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -7019,7 +7234,7 @@ func (itr *unsignedReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -7055,6 +7270,13 @@ func (itr *unsignedReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = unsignedPointsByTime(a)
@@ -7194,6 +7416,11 @@ type unsignedReduceStringIterator struct {
 	opt      IteratorOptions
 	points   []StringPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*unsignedReduceStringPoint
+	keys []string
 }
 
 func newUnsignedReduceStringIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, StringPointEmitter)) *unsignedReduceStringIterator {
@@ -7263,7 +7490,10 @@ func (itr *unsignedReduceStringIterator) reduce() ([]StringPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*unsignedReduceStringPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*unsignedReduceStringPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -7307,7 +7537,7 @@ func (itr *unsignedReduceStringIterator) reduce() ([]StringPoint, error) {
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -7343,6 +7573,13 @@ func (itr *unsignedReduceStringIterator) reduce() ([]StringPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = stringPointsByTime(a)
@@ -7482,6 +7719,11 @@ type unsignedReduceBooleanIterator struct {
 	opt      IteratorOptions
 	points   []BooleanPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*unsignedReduceBooleanPoint
+	keys []string
 }
 
 func newUnsignedReduceBooleanIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, BooleanPointEmitter)) *unsignedReduceBooleanIterator {
@@ -7551,7 +7793,10 @@ func (itr *unsignedReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*unsignedReduceBooleanPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*unsignedReduceBooleanPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -7595,7 +7840,7 @@ func (itr *unsignedReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -7631,6 +7876,13 @@ func (itr *unsignedReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = booleanPointsByTime(a)
@@ -8645,6 +8897,10 @@ type stringLimitIterator struct {
 	opt   IteratorOptions
 	n     int
 
+	// single is set when the input produces a single group so the
+	// input does not have to be read once the limit has been reached.
+	single bool
+
 	prev struct {
 		name string
 		tags Tags
@@ -8668,6 +8924,11 @@ func (itr *stringLimitIterator) Close() error { return itr.input.Close() }
 // Next returns the next point from the iterator.
 func (itr *stringLimitIterator) Next() (*StringPoint, error) {
 	for {
+		// Stop reading when no other group can follow.
+		if itr.single && itr.opt.Limit > 0 && (itr.n-itr.opt.Offset) >= itr.opt.Limit {
+			return nil, nil
+		}
+
 		p, err := itr.input.Next()
 		if p == nil || err != nil {
 			return nil, err
@@ -8980,6 +9241,11 @@ type stringReduceFloatIterator struct {
 	opt      IteratorOptions
 	points   []FloatPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*stringReduceFloatPoint
+	keys []string
 }
 
 func newStringReduceFloatIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, FloatPointEmitter)) *stringReduceFloatIterator {
@@ -9049,7 +9315,10 @@ func (itr *stringReduceFloatIterator) reduce() ([]FloatPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*stringReduceFloatPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*stringReduceFloatPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -9093,7 +9362,7 @@ func (itr *stringReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		rp.Aggregator.AggregateString(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -9129,6 +9398,13 @@ func (itr *stringReduceFloatIterator) reduce() ([]FloatPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = floatPointsByTime(a)
@@ -9268,6 +9544,11 @@ type stringReduceIntegerIterator struct {
 	opt      IteratorOptions
 	points   []IntegerPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*stringReduceIntegerPoint
+	keys []string
 }
 
 func newStringReduceIntegerIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, IntegerPointEmitter)) *stringReduceIntegerIterator {
@@ -9337,7 +9618,10 @@ func (itr *stringReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*stringReduceIntegerPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*stringReduceIntegerPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -9381,7 +9665,7 @@ func (itr *stringReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		rp.Aggregator.AggregateString(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -9417,6 +9701,13 @@ func (itr *stringReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = integerPointsByTime(a)
@@ -9556,6 +9847,11 @@ type stringReduceUnsignedIterator struct {
 	opt      IteratorOptions
 	points   []UnsignedPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*stringReduceUnsignedPoint
+	keys []string
 }
 
 func newStringReduceUnsignedIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, UnsignedPointEmitter)) *stringReduceUnsignedIterator {
@@ -9625,7 +9921,10 @@ func (itr *stringReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*stringReduceUnsignedPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*stringReduceUnsignedPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -9669,7 +9968,7 @@ func (itr *stringReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		rp.Aggregator.AggregateString(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -9705,6 +10004,13 @@ func (itr *stringReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = unsignedPointsByTime(a)
@@ -9844,6 +10150,11 @@ type stringReduceStringIterator struct {
 	opt      IteratorOptions
 	points   []StringPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*stringReduceStringPoint
+	keys []string
 }
 
 func newStringReduceStringIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, StringPointEmitter)) *stringReduceStringIterator {
@@ -9913,7 +10224,10 @@ func (itr *stringReduceStringIterator) reduce() ([]StringPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*stringReduceStringPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*stringReduceStringPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -9957,7 +10271,7 @@ func (itr *stringReduceStringIterator) reduce() ([]StringPoint, error) {
 		rp.Aggregator.AggregateString(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -9993,6 +10307,13 @@ func (itr *stringReduceStringIterator) reduce() ([]StringPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = stringPointsByTime(a)
@@ -10132,6 +10453,11 @@ type stringReduceBooleanIterator struct {
 	opt      IteratorOptions
 	points   []BooleanPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*stringReduceBooleanPoint
+	keys []string
 }
 
 func newStringReduceBooleanIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, BooleanPointEmitter)) *stringReduceBooleanIterator {
@@ -10201,7 +10527,10 @@ func (itr *stringReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*stringReduceBooleanPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*stringReduceBooleanPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -10245,7 +10574,7 @@ func (itr *stringReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		rp.Aggregator.AggregateString(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -10281,6 +10610,13 @@ func (itr *stringReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = booleanPointsByTime(a)
@@ -11295,6 +11631,10 @@ type booleanLimitIterator struct {
 	opt   IteratorOptions
 	n     int
 
+	// single is set when the input produces a single group so the
+	// input does not have to be read once the limit has been reached.
+	single bool
+
 	prev struct {
 		name string
 		tags Tags
@@ -11318,6 +11658,11 @@ func (itr *booleanLimitIterator) Close() error { return itr.input.Close() }
 // Next returns the next point from the iterator.
 func (itr *booleanLimitIterator) Next() (*BooleanPoint, error) {
 	for {
+		// Stop reading when no other group can follow.
+		if itr.single && itr.opt.Limit > 0 && (itr.n-itr.opt.Offset) >= itr.opt.Limit {
+			return nil, nil
+		}
+
 		p, err := itr.input.Next()
 		if p == nil || err != nil {
 			return nil, err
@@ -11630,6 +11975,11 @@ type booleanReduceFloatIterator struct {
 	opt      IteratorOptions
 	points   []FloatPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*booleanReduceFloatPoint
+	keys []string
 }
 
 func newBooleanReduceFloatIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, FloatPointEmitter)) *booleanReduceFloatIterator {
@@ -11699,7 +12049,10 @@ func (itr *booleanReduceFloatIterator) reduce() ([]FloatPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*booleanReduceFloatPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*booleanReduceFloatPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -11743,7 +12096,7 @@ func (itr *booleanReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		rp.Aggregator.AggregateBoolean(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -11779,6 +12132,13 @@ func (itr *booleanReduceFloatIterator) reduce() ([]FloatPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = floatPointsByTime(a)
@@ -11918,6 +12278,11 @@ type booleanReduceIntegerIterator struct {
 	opt      IteratorOptions
 	points   []IntegerPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*booleanReduceIntegerPoint
+	keys []string
 }
 
 func newBooleanReduceIntegerIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, IntegerPointEmitter)) *booleanReduceIntegerIterator {
@@ -11987,7 +12352,10 @@ func (itr *booleanReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*booleanReduceIntegerPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*booleanReduceIntegerPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -12031,7 +12399,7 @@ func (itr *booleanReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		rp.Aggregator.AggregateBoolean(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -12067,6 +12435,13 @@ func (itr *booleanReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = integerPointsByTime(a)
@@ -12206,6 +12581,11 @@ type booleanReduceUnsignedIterator struct {
 	opt      IteratorOptions
 	points   []UnsignedPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*booleanReduceUnsignedPoint
+	keys []string
 }
 
 func newBooleanReduceUnsignedIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, UnsignedPointEmitter)) *booleanReduceUnsignedIterator {
@@ -12275,7 +12655,10 @@ func (itr *booleanReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*booleanReduceUnsignedPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*booleanReduceUnsignedPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -12319,7 +12702,7 @@ func (itr *booleanReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		rp.Aggregator.AggregateBoolean(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -12355,6 +12738,13 @@ func (itr *booleanReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = unsignedPointsByTime(a)
@@ -12494,6 +12884,11 @@ type booleanReduceStringIterator struct {
 	opt      IteratorOptions
 	points   []StringPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*booleanReduceStringPoint
+	keys []string
 }
 
 func newBooleanReduceStringIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, StringPointEmitter)) *booleanReduceStringIterator {
@@ -12563,7 +12958,10 @@ func (itr *booleanReduceStringIterator) reduce() ([]StringPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*booleanReduceStringPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*booleanReduceStringPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -12607,7 +13005,7 @@ func (itr *booleanReduceStringIterator) reduce() ([]StringPoint, error) {
 		rp.Aggregator.AggregateBoolean(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -12643,6 +13041,13 @@ func (itr *booleanReduceStringIterator) reduce() ([]StringPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = stringPointsByTime(a)
@@ -12782,6 +13187,11 @@ type booleanReduceBooleanIterator struct {
 	opt      IteratorOptions
 	points   []BooleanPoint
 	keepTags bool
+
+	// m and keys are reused by every window to avoid reallocating
+	// them for each interval of a query with many small intervals.
+	m    map[string]*booleanReduceBooleanPoint
+	keys []string
 }
 
 func newBooleanReduceBooleanIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, BooleanPointEmitter)) *booleanReduceBooleanIterator {
@@ -12851,7 +13261,10 @@ func (itr *booleanReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 	}
 
 	// Create points by tags.
-	m := make(map[string]*booleanReduceBooleanPoint)
+	if itr.m == nil {
+		itr.m = make(map[string]*booleanReduceBooleanPoint)
+	}
+	m := itr.m
 	for {
 		// Read next point.
 		curr, err := itr.input.NextInWindow(startTime, endTime)
@@ -12895,7 +13308,7 @@ func (itr *booleanReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		rp.Aggregator.AggregateBoolean(curr)
 	}
 
-	keys := make([]string, 0, len(m))
+	keys := itr.keys[:0]
 	for k := range m {
 		keys = append(keys, k)
 	}
@@ -12931,6 +13344,13 @@ func (itr *booleanReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 			a = append(a, points[i])
 		}
 	}
+
+	// Release the aggregators of this window but keep the buffers for the next one.
+	for _, k := range keys {
+		delete(m, k)
+	}
+	itr.keys = keys
+
 	// Points may be out of order. Perform a stable sort by time if requested.
 	if !sortedByTime && itr.opt.Ordered {
 		var sorted sort.Interface = booleanPointsByTime(a)
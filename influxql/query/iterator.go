@@ -121,7 +121,7 @@ func (a Iterators) coerce() interface{} {
 // A sorted merge iterator or a merge iterator can be used based on opt.
 func (a Iterators) Merge(opt IteratorOptions) (Iterator, error) {
 	// Check if this is a call expression.
-	call, ok := opt.Expr.(*influxql.Call)
+	_, ok := opt.Expr.(*influxql.Call)
 
 	// Merge into a single iterator.
 	if !ok && opt.MergeSorted() {
@@ -147,21 +147,7 @@ func (a Iterators) Merge(opt IteratorOptions) (Iterator, error) {
 		return itr, nil
 	}
 
-	// When merging the count() function, use sum() to sum the counted points.
-	if call.Name == "count" {
-		opt.Expr = &influxql.Call{
-			Name: "sum",
-			Args: call.Args,
-		}
-	}
-	// When merging the sum_hll() function, use merge_hll() to sum the counted points.
-	if call.Name == "sum_hll" {
-		opt.Expr = &influxql.Call{
-			Name: "merge_hll",
-			Args: call.Args,
-		}
-	}
-	return NewCallIterator(itr, opt)
+	return newMergeCallIterator(itr, opt)
 }
 
 // NewMergeIterator returns an iterator to merge itrs into one.
@@ -259,6 +245,12 @@ func NewSortedMergeIterator(inputs []Iterator, opt IteratorOptions) Iterator {
 	}
 }
 
+// NewReadAheadIterator returns an iterator that reads points from input in a
+// separate goroutine, buffering them until they are requested with Next.
+func NewReadAheadIterator(input Iterator) Iterator {
+	return newParallelIterator(input)
+}
+
 // newParallelIterator returns an iterator that runs in a separate goroutine.
 func newParallelIterator(input Iterator) Iterator {
 	if input == nil {
@@ -299,6 +291,36 @@ func NewLimitIterator(input Iterator, opt IteratorOptions) Iterator {
 	}
 }
 
+// newSingleGroupLimitIterator returns a limit iterator for an input that produces a
+// single group. It stops reading the input as soon as the limit has been reached
+// instead of reading and discarding the rest of the points.
+func newSingleGroupLimitIterator(input Iterator, opt IteratorOptions) Iterator {
+	switch input := input.(type) {
+	case FloatIterator:
+		itr := newFloatLimitIterator(input, opt)
+		itr.single = true
+		return itr
+	case IntegerIterator:
+		itr := newIntegerLimitIterator(input, opt)
+		itr.single = true
+		return itr
+	case UnsignedIterator:
+		itr := newUnsignedLimitIterator(input, opt)
+		itr.single = true
+		return itr
+	case StringIterator:
+		itr := newStringLimitIterator(input, opt)
+		itr.single = true
+		return itr
+	case BooleanIterator:
+		itr := newBooleanLimitIterator(input, opt)
+		itr.single = true
+		return itr
+	default:
+		panic(fmt.Sprintf("unsupported limit iterator type: %T", input))
+	}
+}
+
 // NewFilterIterator returns an iterator that filters the points based on the
 // condition. This iterator is not nearly as efficient as filtering points
 // within the query engine and is only used when filtering subqueries.
@@ -610,6 +632,13 @@ type IteratorOptions struct {
 	// Limits on the creation of iterators.
 	MaxSeriesN int
 
+	// Read ahead on each shard iterator in a separate goroutine.
+	ReadAhead bool
+
+	// Maximum number of shards to create iterators for at once.
+	// If zero or one, the shards are processed one after another.
+	ShardConcurrency int
+
 	// If this channel is set and is closed, the iterator should try to exit
 	// and close as soon as possible.
 	InterruptCh <-chan struct{}
@@ -683,6 +712,8 @@ func newIteratorOptionsStmt(stmt *influxql.SelectStatement, sopt SelectOptions)
 	opt.Limit, opt.Offset = stmt.Limit, stmt.Offset
 	opt.SLimit, opt.SOffset = stmt.SLimit, stmt.SOffset
 	opt.MaxSeriesN = sopt.MaxSeriesN
+	opt.ReadAhead = sopt.ReadAhead
+	opt.ShardConcurrency = sopt.MaxShardConcurrency
 	opt.OrgID = sopt.OrgID
 
 	return opt, nil
@@ -690,8 +721,10 @@ func newIteratorOptionsStmt(stmt *influxql.SelectStatement, sopt SelectOptions)
 
 func newIteratorOptionsSubstatement(ctx context.Context, stmt *influxql.SelectStatement, opt IteratorOptions) (IteratorOptions, error) {
 	subOpt, err := newIteratorOptionsStmt(stmt, SelectOptions{
-		OrgID:      opt.OrgID,
-		MaxSeriesN: opt.MaxSeriesN,
+		OrgID:               opt.OrgID,
+		MaxSeriesN:          opt.MaxSeriesN,
+		ReadAhead:           opt.ReadAhead,
+		MaxShardConcurrency: opt.ShardConcurrency,
 	})
 	if err != nil {
 		return IteratorOptions{}, err
@@ -1004,7 +1037,9 @@ func encodeIteratorOptions(opt *IteratorOptions) *internal.IteratorOptions {
 	}
 
 	// Fill value can only be a number. Set it if available.
-	if v, ok := opt.FillValue.(float64); ok {
+	// Integer fill values are encoded as a float and cast back
+	// to the type of the filled iterator when the fill is applied.
+	if v, ok := castToFloat(opt.FillValue); ok {
 		pb.FillValue = proto.Float64(v)
 	}
 
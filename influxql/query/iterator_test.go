@@ -324,6 +324,52 @@ func TestMergeIterator_Coerce_Float(t *testing.T) {
 	}
 }
 
+// Ensure that merging read ahead iterators returns the same points as merging the inputs directly.
+func TestMergeIterator_ReadAhead(t *testing.T) {
+	inputs := []*FloatIterator{
+		{Points: []query.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 12, Value: 3},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 1, Value: 2},
+		}},
+		{Points: []query.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 20, Value: 7},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 11, Value: 5},
+		}},
+		{Points: []query.FloatPoint{}},
+	}
+
+	itrs := make([]query.Iterator, len(inputs))
+	for i, input := range inputs {
+		itrs[i] = query.NewReadAheadIterator(input)
+	}
+
+	itr := query.NewMergeIterator(itrs, query.IteratorOptions{
+		Interval: query.Interval{
+			Duration: 10 * time.Nanosecond,
+		},
+		Dimensions: []string{"host"},
+		Ascending:  true,
+	})
+	if a, err := Iterators([]query.Iterator{itr}).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !deep.Equal(a, [][]query.Point{
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0, Value: 1}},
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 12, Value: 3}},
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 20, Value: 7}},
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 1, Value: 2}},
+		{&query.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 11, Value: 5}},
+	}) {
+		t.Errorf("unexpected points: %s", spew.Sdump(a))
+	}
+
+	for i, input := range inputs {
+		if !input.Closed {
+			t.Errorf("iterator %d not closed", i)
+		}
+	}
+}
+
 // Ensure that a set of iterators can be merged together, sorted by name/tag.
 func TestSortedMergeIterator_Float(t *testing.T) {
 	inputs := []*FloatIterator{
@@ -1518,6 +1564,30 @@ func TestIteratorOptions_MarshalBinary(t *testing.T) {
 	}
 }
 
+// Ensure an integer fill value is not dropped when the options are marshaled.
+func TestIteratorOptions_MarshalBinary_IntegerFillValue(t *testing.T) {
+	for _, fillValue := range []interface{}{int64(100), uint64(100)} {
+		opt := &query.IteratorOptions{
+			Fill:      influxql.NumberFill,
+			FillValue: fillValue,
+		}
+
+		buf, err := opt.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var other query.IteratorOptions
+		if err := other.UnmarshalBinary(buf); err != nil {
+			t.Fatal(err)
+		} else if other.Fill != influxql.NumberFill {
+			t.Fatalf("unexpected fill option: %v", other.Fill)
+		} else if other.FillValue != float64(100) {
+			t.Fatalf("unexpected fill value for %T: %#v", fillValue, other.FillValue)
+		}
+	}
+}
+
 // Ensure iterator can be encoded and decoded over a byte stream.
 func TestIterator_EncodeDecode(t *testing.T) {
 	var buf bytes.Buffer
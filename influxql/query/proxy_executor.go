@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"io"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,11 +12,16 @@ import (
 	"github.com/influxdata/influxdb/v2/kit/platform/errors"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
 	influxlogger "github.com/influxdata/influxdb/v2/logger"
+	"github.com/influxdata/influxdb/v2/models"
 	"github.com/influxdata/influxql"
 	"github.com/opentracing/opentracing-go/log"
 	"go.uber.org/zap"
 )
 
+// unchunkedInitialChunkSize is the number of points of the first result
+// of a statement when the response is not chunked.
+const unchunkedInitialChunkSize = 100
+
 type ProxyExecutor struct {
 	log      *zap.Logger
 	executor *Executor
@@ -34,7 +40,7 @@ func (s *ProxyExecutor) Query(ctx context.Context, w io.Writer, req *iql.QueryRe
 	defer span.Finish()
 
 	logger := s.log.With(influxlogger.TraceFields(ctx)...)
-	logger.Info("executing new query", zap.String("query", req.Query))
+	logger.Debug("executing new query", zap.String("query", req.Query))
 
 	p := influxql.NewParser(strings.NewReader(req.Query))
 	p.SetParams(req.Params)
@@ -50,19 +56,31 @@ func (s *ProxyExecutor) Query(ctx context.Context, w io.Writer, req *iql.QueryRe
 	span.LogFields(log.String("query", q.String()))
 
 	opts := ExecutionOptions{
-		OrgID:           req.OrganizationID,
-		Database:        req.DB,
-		RetentionPolicy: req.RP,
-		ChunkSize:       req.ChunkSize,
-		ReadOnly:        true,
-		Authorizer:      OpenAuthorizer,
+		OrgID:            req.OrganizationID,
+		Database:         req.DB,
+		RetentionPolicy:  req.RP,
+		ChunkSize:        req.ChunkSize,
+		ReadOnly:         true,
+		Authorizer:       OpenAuthorizer,
+		DefaultTimeRange: req.DefaultTimeRange,
+		MaxBucketsN:      req.MaxBuckets,
+	}
+	if !req.Chunked {
+		// The results of a statement are combined into a single response, start
+		// with small results so the response can be written sooner.
+		opts.InitialChunkSize = unchunkedInitialChunkSize
 	}
 
 	epoch := req.Epoch
 	rw := NewResponseWriter(req.EncodingFormat)
 
 	results, stats := s.executor.ExecuteQuery(ctx, q, opts)
-	if req.Chunked {
+	if req.EncodingFormat == iql.EncodingFormatJSON && !req.SortTags {
+		enc := NewJSONStreamEncoder(w)
+		enc.Epoch = epoch
+		enc.Chunked = req.Chunked
+		err = enc.Encode(ctx, results)
+	} else if req.Chunked {
 		for r := range results {
 			// Ignore nil results.
 			if r == nil {
@@ -81,6 +99,11 @@ func (s *ProxyExecutor) Query(ctx context.Context, w io.Writer, req *iql.QueryRe
 		}
 	} else {
 		resp := Response{Results: GatherResults(results, epoch)}
+		if req.SortTags {
+			for _, r := range resp.Results {
+				sort.Stable(models.LexicalRows(r.Series))
+			}
+		}
 		err = rw.WriteResponse(ctx, w, resp)
 	}
 
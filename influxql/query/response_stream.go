@@ -0,0 +1,256 @@
+package query
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/influxdata/influxdb/v2/kit/tracing"
+	"github.com/influxdata/influxdb/v2/models"
+)
+
+// JSONStreamEncoder writes results into the JSON shape of a 1.x query response
+// as they arrive, instead of buffering every result in memory first.
+//
+// When Chunked is false the results of a statement are combined the same way
+// GatherResults combines them, and the whole query is written as a single
+// response. When a statement fails after some of its series have been written,
+// the error is added to the statement next to the series that were written.
+//
+// When Chunked is true every result is written as its own response.
+type JSONStreamEncoder struct {
+	// Epoch converts result timestamps to the given precision when set.
+	Epoch string
+
+	// Chunked writes every result as a separate response.
+	Chunked bool
+
+	w   *bufio.Writer
+	err error
+
+	started  bool
+	stmtOpen bool
+	stmt     *Result
+
+	seriesOpen bool
+	row        *models.Row
+	rowComma   bool
+	valuesOpen bool
+}
+
+// NewJSONStreamEncoder returns a JSONStreamEncoder that writes to w.
+func NewJSONStreamEncoder(w io.Writer) *JSONStreamEncoder {
+	return &JSONStreamEncoder{w: bufio.NewWriter(w)}
+}
+
+// Encode writes every result read from results until the channel is closed.
+func (e *JSONStreamEncoder) Encode(ctx context.Context, results <-chan *Result) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if e.Chunked {
+		return e.encodeChunked(ctx, results)
+	}
+
+	for r := range results {
+		// Ignore nil results.
+		if r == nil {
+			continue
+		}
+
+		// if requested, convert result timestamps to epoch
+		if e.Epoch != "" {
+			convertToEpoch(r, e.Epoch)
+		}
+
+		e.writeResult(r)
+		if e.err != nil {
+			return e.err
+		}
+	}
+
+	if !e.started {
+		e.writeString("{}")
+	} else {
+		e.closeStatement()
+		e.writeString("]}")
+	}
+	e.writeString("\n")
+
+	return e.flush()
+}
+
+func (e *JSONStreamEncoder) encodeChunked(ctx context.Context, results <-chan *Result) error {
+	rw := &jsonFormatter{}
+	for r := range results {
+		// Ignore nil results.
+		if r == nil {
+			continue
+		}
+
+		// if requested, convert result timestamps to epoch
+		if e.Epoch != "" {
+			convertToEpoch(r, e.Epoch)
+		}
+
+		if err := rw.WriteResponse(ctx, e.w, Response{Results: []*Result{r}}); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+func (e *JSONStreamEncoder) writeResult(r *Result) {
+	if !e.started {
+		e.writeString(`{"results":[`)
+		e.started = true
+	}
+
+	if e.stmtOpen && e.stmt.StatementID != r.StatementID {
+		e.closeStatement()
+		e.writeString(",")
+	}
+
+	if !e.stmtOpen {
+		e.stmtOpen = true
+		e.stmt = &Result{StatementID: r.StatementID}
+		e.writeString(`{"statement_id":`)
+		e.writeJSON(r.StatementID)
+	}
+
+	if r.Err != nil {
+		e.stmt.Err = r.Err
+		return
+	}
+
+	for _, row := range r.Series {
+		e.writeRow(row)
+	}
+	e.stmt.Messages = append(e.stmt.Messages, r.Messages...)
+	e.stmt.Partial = r.Partial
+}
+
+// writeRow writes the values of row, appending them to the previous row
+// if both are for the same series.
+func (e *JSONStreamEncoder) writeRow(row *models.Row) {
+	if e.row != nil && e.row.SameSeries(row) {
+		e.writeValues(row.Values)
+		e.row.Partial = row.Partial
+		return
+	}
+
+	if !e.seriesOpen {
+		e.writeString(`,"series":[`)
+		e.seriesOpen = true
+	} else {
+		e.closeRow()
+		e.writeString(",")
+	}
+
+	// Write the fields of the row leading up to its values and leave the object open.
+	b, err := json.Marshal(&models.Row{Name: row.Name, Tags: row.Tags, Columns: row.Columns})
+	if err != nil {
+		e.err = err
+		return
+	}
+	e.write(b[:len(b)-1])
+
+	e.row = &models.Row{Name: row.Name, Tags: row.Tags, Partial: row.Partial}
+	e.rowComma = len(b) > 2
+	e.writeValues(row.Values)
+}
+
+func (e *JSONStreamEncoder) writeValues(values [][]interface{}) {
+	for _, v := range values {
+		if !e.valuesOpen {
+			if e.rowComma {
+				e.writeString(",")
+			}
+			e.writeString(`"values":[`)
+			e.valuesOpen = true
+			e.rowComma = true
+		} else {
+			e.writeString(",")
+		}
+		e.writeJSON(v)
+	}
+}
+
+func (e *JSONStreamEncoder) closeRow() {
+	if e.row == nil {
+		return
+	}
+
+	if e.valuesOpen {
+		e.writeString("]")
+		e.valuesOpen = false
+	}
+	if e.row.Partial {
+		if e.rowComma {
+			e.writeString(",")
+		}
+		e.writeString(`"partial":true`)
+	}
+	e.writeString("}")
+	e.row = nil
+}
+
+func (e *JSONStreamEncoder) closeStatement() {
+	if !e.stmtOpen {
+		return
+	}
+
+	if e.seriesOpen {
+		e.closeRow()
+		e.writeString("]")
+		e.seriesOpen = false
+	}
+	if len(e.stmt.Messages) > 0 {
+		e.writeString(`,"messages":`)
+		e.writeJSON(e.stmt.Messages)
+	}
+	if e.stmt.Partial {
+		e.writeString(`,"partial":true`)
+	}
+	if e.stmt.Err != nil {
+		e.writeString(`,"error":`)
+		e.writeJSON(e.stmt.Err.Error())
+	}
+	e.writeString("}")
+	e.stmtOpen = false
+	e.stmt = nil
+}
+
+func (e *JSONStreamEncoder) writeJSON(v interface{}) {
+	if e.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		e.err = err
+		return
+	}
+	e.write(b)
+}
+
+func (e *JSONStreamEncoder) writeString(s string) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.WriteString(s)
+}
+
+func (e *JSONStreamEncoder) write(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+func (e *JSONStreamEncoder) flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
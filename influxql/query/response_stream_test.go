@@ -0,0 +1,121 @@
+package query_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/influxql/query"
+	"github.com/influxdata/influxdb/v2/models"
+)
+
+func TestJSONStreamEncoder(t *testing.T) {
+	ts := time.Unix(0, 0).UTC()
+
+	for _, tt := range []struct {
+		name    string
+		results func() []*query.Result
+	}{
+		{
+			name:    "no results",
+			results: func() []*query.Result { return nil },
+		},
+		{
+			name: "empty series",
+			results: func() []*query.Result {
+				return []*query.Result{{Series: make(models.Rows, 0)}}
+			},
+		},
+		{
+			name: "merged partial rows",
+			results: func() []*query.Result {
+				return []*query.Result{
+					{Series: models.Rows{{Name: "cpu", Tags: map[string]string{"host": "a"}, Columns: []string{"time", "value"}, Values: [][]interface{}{{ts, 1.0}}, Partial: true}}, Partial: true},
+					{Series: models.Rows{{Name: "cpu", Tags: map[string]string{"host": "a"}, Columns: []string{"time", "value"}, Values: [][]interface{}{{ts, 2.0}}}}},
+					{Series: models.Rows{{Name: "cpu", Tags: map[string]string{"host": "b"}, Columns: []string{"time", "value"}, Values: [][]interface{}{{ts, 3.0}}}}},
+					{Series: models.Rows{{Name: "mem", Columns: []string{"time", "value"}, Values: [][]interface{}{{ts, 4.0}}}}},
+				}
+			},
+		},
+		{
+			name: "multiple statements",
+			results: func() []*query.Result {
+				return []*query.Result{
+					{StatementID: 0, Series: models.Rows{{Name: "databases", Columns: []string{"name"}, Values: [][]interface{}{{"db0"}, {"db1"}}}}},
+					{StatementID: 1, Series: models.Rows{{Columns: []string{"name"}}}},
+					{StatementID: 2, Messages: []*query.Message{{Level: query.WarningLevel, Text: "deprecated"}}},
+					{StatementID: 3, Err: errors.New("expected error")},
+				}
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// The results are modified by the encoders, give each its own copy.
+			exp, err := json.Marshal(query.Response{Results: query.GatherResults(resultsChan(tt.results()), "")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := query.NewJSONStreamEncoder(&buf).Encode(context.Background(), resultsChan(tt.results())); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := buf.String(), string(exp)+"\n"; got != want {
+				t.Fatalf("unexpected output:\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestJSONStreamEncoder_ErrorAfterSeries(t *testing.T) {
+	results := []*query.Result{
+		{Series: models.Rows{{Name: "cpu", Columns: []string{"time", "value"}, Values: [][]interface{}{{int64(0), 1.0}}}}},
+		{Err: errors.New("max-select-point limit exceeded")},
+		{StatementID: 1, Series: models.Rows{{Name: "mem", Columns: []string{"time", "value"}, Values: [][]interface{}{{int64(0), 2.0}}}}},
+	}
+
+	var buf bytes.Buffer
+	if err := query.NewJSONStreamEncoder(&buf).Encode(context.Background(), resultsChan(results)); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[[0,1]]}],"error":"max-select-point limit exceeded"},` +
+		`{"statement_id":1,"series":[{"name":"mem","columns":["time","value"],"values":[[0,2]]}]}]}` + "\n"
+	if got := buf.String(); got != exp {
+		t.Fatalf("unexpected output:\n got: %s\nwant: %s", got, exp)
+	}
+}
+
+func TestJSONStreamEncoder_Chunked(t *testing.T) {
+	results := []*query.Result{
+		{Series: models.Rows{{Name: "cpu", Columns: []string{"time", "value"}, Values: [][]interface{}{{time.Unix(1, 0).UTC(), 1.0}}, Partial: true}}, Partial: true},
+		{Series: models.Rows{{Name: "cpu", Columns: []string{"time", "value"}, Values: [][]interface{}{{time.Unix(2, 0).UTC(), 2.0}}}}},
+	}
+
+	var buf bytes.Buffer
+	enc := query.NewJSONStreamEncoder(&buf)
+	enc.Chunked = true
+	enc.Epoch = "s"
+	if err := enc.Encode(context.Background(), resultsChan(results)); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[[1,1]],"partial":true}],"partial":true}]}` + "\n" +
+		`{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[[2,2]]}]}]}` + "\n"
+	if got := buf.String(); got != exp {
+		t.Fatalf("unexpected output:\n got: %s\nwant: %s", got, exp)
+	}
+}
+
+func resultsChan(results []*query.Result) <-chan *query.Result {
+	ch := make(chan *query.Result, len(results))
+	for _, r := range results {
+		ch <- r
+	}
+	close(ch)
+	return ch
+}
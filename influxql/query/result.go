@@ -43,6 +43,74 @@ func (t *TagSet) Reverse() {
 	}
 }
 
+// ReduceSeriesFilter evaluates the parts of a series filter which only depend
+// on the tags of the series, so the remaining expression can be evaluated
+// against the fields of each point while iterating over the series.
+//
+// If the filter can never match a point in the series, false is returned and
+// the series can be skipped entirely. If the filter always matches, a nil
+// expression is returned.
+func ReduceSeriesFilter(filter influxql.Expr, tags map[string]string) (influxql.Expr, bool) {
+	if filter == nil {
+		return nil, true
+	}
+
+	expr := influxql.RewriteExpr(influxql.CloneExpr(filter), func(expr influxql.Expr) influxql.Expr {
+		if ref, ok := expr.(*influxql.VarRef); ok && ref.Type == influxql.Tag {
+			if v, ok := tags[ref.Val]; ok {
+				return &influxql.StringLiteral{Val: v}
+			}
+		}
+		return expr
+	})
+	expr = reduceBooleanExpr(influxql.Reduce(expr, nil))
+
+	if lit, ok := expr.(*influxql.BooleanLiteral); ok {
+		return nil, lit.Val
+	}
+	return expr, true
+}
+
+// reduceBooleanExpr removes boolean literals from AND and OR expressions and
+// evaluates regular expression comparisons against string literals.
+func reduceBooleanExpr(expr influxql.Expr) influxql.Expr {
+	switch expr := expr.(type) {
+	case *influxql.ParenExpr:
+		inner := reduceBooleanExpr(expr.Expr)
+		if _, ok := inner.(*influxql.BooleanLiteral); ok {
+			return inner
+		}
+		return &influxql.ParenExpr{Expr: inner}
+	case *influxql.BinaryExpr:
+		switch expr.Op {
+		case influxql.AND, influxql.OR:
+			lhs, rhs := reduceBooleanExpr(expr.LHS), reduceBooleanExpr(expr.RHS)
+			for _, pair := range [2][2]influxql.Expr{{lhs, rhs}, {rhs, lhs}} {
+				lit, ok := pair[0].(*influxql.BooleanLiteral)
+				if !ok {
+					continue
+				}
+				if lit.Val == (expr.Op == influxql.OR) {
+					return lit
+				}
+				return pair[1]
+			}
+			return &influxql.BinaryExpr{Op: expr.Op, LHS: lhs, RHS: rhs}
+		case influxql.EQREGEX, influxql.NEQREGEX:
+			lhs, ok := expr.LHS.(*influxql.StringLiteral)
+			if !ok {
+				return expr
+			}
+			re, ok := expr.RHS.(*influxql.RegexLiteral)
+			if !ok || re.Val == nil {
+				return expr
+			}
+			return &influxql.BooleanLiteral{Val: re.Val.MatchString(lhs.Val) == (expr.Op == influxql.EQREGEX)}
+		}
+	}
+	return expr
+}
+
 // LimitTagSets returns a tag set list with SLIMIT and SOFFSET applied.
 func LimitTagSets(a []*TagSet, slimit, soffset int) []*TagSet {
 	// Ignore if no limit or offset is specified.
@@ -55,8 +123,9 @@ func LimitTagSets(a []*TagSet, slimit, soffset int) []*TagSet {
 		return nil
 	}
 
-	// Clamp limit to the max number of tag sets.
-	if soffset+slimit > len(a) {
+	// Clamp limit to the max number of tag sets. SOFFSET
+	// without SLIMIT keeps all the remaining tag sets.
+	if slimit == 0 || soffset+slimit > len(a) {
 		slimit = len(a) - soffset
 	}
 	return a[soffset : soffset+slimit]
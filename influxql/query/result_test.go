@@ -0,0 +1,79 @@
+package query_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/influxql/query"
+)
+
+func TestReduceSeriesFilter(t *testing.T) {
+	tags := map[string]string{"host": "server01", "region": "uswest"}
+
+	for _, tt := range []struct {
+		filter string
+		exp    string
+		match  bool
+	}{
+		{filter: `value > 1`, exp: `value > 1`, match: true},
+		{filter: `host::tag = 'server01'`, match: true},
+		{filter: `host::tag = 'server02'`, match: false},
+		{filter: `host::tag = 'server01' AND value > 1`, exp: `value > 1`, match: true},
+		{filter: `host::tag = 'server02' AND value > 1`, match: false},
+		{filter: `host::tag = 'server02' OR value > 1`, exp: `value > 1`, match: true},
+		{filter: `(region::tag =~ /^us/ OR value > 1) AND value < 10`, exp: `value < 10`, match: true},
+		{filter: `region::tag !~ /^us/ AND value < 10`, match: false},
+		{filter: `dc::tag = 'a' AND value < 10`, exp: `dc::tag = 'a' AND value < 10`, match: true},
+		{filter: `host::field = 'server02'`, exp: `host::field = 'server02'`, match: true},
+	} {
+		t.Run(tt.filter, func(t *testing.T) {
+			expr, match := query.ReduceSeriesFilter(MustParseExpr(tt.filter), tags)
+			if match != tt.match {
+				t.Fatalf("unexpected match: got=%v exp=%v", match, tt.match)
+			}
+
+			var got string
+			if expr != nil {
+				got = expr.String()
+			}
+			if got != tt.exp {
+				t.Fatalf("unexpected expression: got=%q exp=%q", got, tt.exp)
+			}
+		})
+	}
+
+	if expr, match := query.ReduceSeriesFilter(nil, tags); expr != nil || !match {
+		t.Fatalf("unexpected result for nil filter: %v %v", expr, match)
+	}
+}
+
+func TestLimitTagSets(t *testing.T) {
+	tagSets := []*query.TagSet{{Key: []byte("a")}, {Key: []byte("b")}, {Key: []byte("c")}}
+
+	for _, tt := range []struct {
+		name            string
+		slimit, soffset int
+		exp             []string
+	}{
+		{name: "NoLimit", exp: []string{"a", "b", "c"}},
+		{name: "SLimit", slimit: 2, exp: []string{"a", "b"}},
+		{name: "SLimitSOffset", slimit: 1, soffset: 1, exp: []string{"b"}},
+		{name: "SLimitPastEnd", slimit: 5, soffset: 1, exp: []string{"b", "c"}},
+		{name: "SOffset", soffset: 1, exp: []string{"b", "c"}},
+		{name: "SOffsetAtEnd", soffset: 3, exp: []string{}},
+		{name: "SOffsetPastEnd", soffset: 4},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			for _, ts := range query.LimitTagSets(tagSets, tt.slimit, tt.soffset) {
+				keys = append(keys, string(ts.Key))
+			}
+			if len(keys) == 0 && len(tt.exp) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(keys, tt.exp) {
+				t.Fatalf("unexpected tag sets: got %v, want %v", keys, tt.exp)
+			}
+		})
+	}
+}
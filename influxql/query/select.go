@@ -33,15 +33,29 @@ type SelectOptions struct {
 	MaxSeriesN int
 
 	// Maximum number of points to read from the query.
-	// This requires the passed in context to have a Monitor that is
-	// created using WithMonitor.
+	// The query fails once its iterators have read more points.
 	MaxPointN int
 
 	// Maximum number of buckets for a statement.
 	MaxBucketsN int
 
+	// DefaultTimeRange limits the statements without a lower time bound
+	// to the time range ending now. Zero reads from the minimum time.
+	DefaultTimeRange time.Duration
+
+	// Read ahead on each shard in a separate goroutine while the
+	// previously read points are being processed.
+	ReadAhead bool
+
+	// Maximum number of shards to create iterators for at once.
+	// The iterators are still merged in shard order so the output does not change.
+	MaxShardConcurrency int
+
 	// StatisticsGatherer gathers metrics about the execution of the query.
 	StatisticsGatherer *iql.StatisticsGatherer
+
+	// Trace records the decisions made while planning the statement when set.
+	Trace *PlanTrace
 }
 
 // ShardMapper retrieves and maps shards into an IteratorCreator that can later be
@@ -103,6 +117,10 @@ type PreparedStatement interface {
 	// Explain outputs the explain plan for this statement.
 	Explain(ctx context.Context) (string, error)
 
+	// Plan returns the iterators this statement creates, in a form that can be
+	// serialized deterministically.
+	Plan(ctx context.Context) (Plan, error)
+
 	// Close closes the resources associated with this prepared statement.
 	// This must be called as the mapped shards may hold open resources such
 	// as network connections.
@@ -112,7 +130,7 @@ type PreparedStatement interface {
 // Prepare will compile the statement with the default compile options and
 // then prepare the query.
 func Prepare(ctx context.Context, stmt *influxql.SelectStatement, shardMapper ShardMapper, opt SelectOptions) (PreparedStatement, error) {
-	c, err := Compile(stmt, CompileOptions{})
+	c, err := Compile(stmt, CompileOptions{DefaultTimeRange: opt.DefaultTimeRange})
 	if err != nil {
 		return nil, err
 	}
@@ -160,6 +178,9 @@ func (p *preparedStatement) Select(ctx context.Context) (Cursor, error) {
 		return nil, err
 	}
 
+	if p.maxPointN > 0 {
+		cur = newPointLimitCursor(cur, p.maxPointN)
+	}
 	return cur, nil
 }
 
@@ -247,12 +268,8 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 	opt.Limit, opt.Offset = 0, 0
 	switch expr.Name {
 	case "distinct":
-		opt.Ordered = true
-		input, err := buildExprIterator(ctx, expr.Args[0].(*influxql.VarRef), b.ic, b.sources, opt, b.selector, false)
-		if err != nil {
-			return nil, err
-		}
-		input, err = NewDistinctIterator(input, opt)
+		// The distinct values are collected for every shard and combined afterwards.
+		input, err := b.callIterator(ctx, expr, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -541,8 +558,6 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 					return newCountIterator(input, opt)
 				}
 			}
-			fallthrough
-		case "min", "max", "sum", "first", "last", "mean", "sum_hll", "merge_hll":
 			return b.callIterator(ctx, expr, opt)
 		case "median":
 			opt.Ordered = true
@@ -585,6 +600,9 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 			}
 			return newPercentileIterator(input, opt, percentile)
 		default:
+			if _, ok := aggregates[expr.Name]; ok {
+				return b.callIterator(ctx, expr, opt)
+			}
 			return nil, fmt.Errorf("unsupported call: %s", expr.Name)
 		}
 	}()
@@ -620,7 +638,9 @@ func (b *exprIteratorBuilder) callIterator(ctx context.Context, expr *influxql.C
 				// Identify the name of the field we are using.
 				arg0 := expr.Args[0].(*influxql.VarRef)
 
-				opt.Ordered = false
+				// distinct() keeps the earliest point of each value, so it reads
+				// the points of the subquery in order.
+				opt.Ordered = expr.Name == "distinct"
 				input, err := buildExprIterator(ctx, arg0, b.ic, []influxql.Source{source}, opt, b.selector, false)
 				if err != nil {
 					return err
@@ -853,7 +873,7 @@ func buildAuxIterator(ctx context.Context, ic IteratorCreator, sources influxql.
 	}
 	// Apply limit & offset.
 	if opt.Limit > 0 || opt.Offset > 0 {
-		input = NewLimitIterator(input, opt)
+		input = newLimitIterator(input, sources, opt)
 	}
 	return input, nil
 }
@@ -866,11 +886,23 @@ func buildFieldIterator(ctx context.Context, expr influxql.Expr, ic IteratorCrea
 
 	// Apply limit & offset.
 	if opt.Limit > 0 || opt.Offset > 0 {
-		input = NewLimitIterator(input, opt)
+		input = newLimitIterator(input, sources, opt)
 	}
 	return input, nil
 }
 
+// newLimitIterator applies the limit and offset to the points read from sources.
+// When the points form a single group, because there is no grouping by tag and a
+// single measurement is read, the sources stop being read once the limit is reached.
+func newLimitIterator(input Iterator, sources influxql.Sources, opt IteratorOptions) Iterator {
+	if len(opt.Dimensions) == 0 && len(sources) == 1 {
+		if m, ok := sources[0].(*influxql.Measurement); ok && m.Regex == nil {
+			return newSingleGroupLimitIterator(input, opt)
+		}
+	}
+	return NewLimitIterator(input, opt)
+}
+
 type valueMapper struct {
 	// An index that maps a node's string output to its symbol so that all
 	// nodes with the same signature are mapped the same.
@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -233,6 +234,56 @@ func TestSelect(t *testing.T) {
 				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=B")}, Values: []interface{}{false}},
 			},
 		},
+		{
+			name: "Count_Distinct_Float",
+			q:    `SELECT count(distinct(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`,
+			typ:  influxql.Float,
+			itrs: []query.Iterator{
+				// The same values are read from several shards and must only be counted once.
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 1 * Second, Value: 19},
+				}},
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
+				}},
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 2 * Second, Value: 20},
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: 19},
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 10 * Second, Value: 2},
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 11 * Second, Value: 2},
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 12 * Second, Value: 3},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A")}, Values: []interface{}{int64(2)}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A")}, Values: []interface{}{int64(2)}},
+				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=B")}, Values: []interface{}{int64(1)}},
+			},
+		},
+		{
+			name: "Count_Distinct_String",
+			q:    `SELECT count(distinct(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s) fill(none)`,
+			typ:  influxql.String,
+			itrs: []query.Iterator{
+				&StringIterator{Points: []query.StringPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: "a"},
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 1 * Second, Value: "b"},
+				}},
+				&StringIterator{Points: []query.StringPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: "a"},
+					{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 15 * Second, Value: "c"},
+				}},
+				&StringIterator{Points: []query.StringPoint{
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: "b"},
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 12 * Second, Value: "c"},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{int64(2)}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{int64(1)}},
+			},
+		},
 		{
 			name: "Mean_Float",
 			q:    `SELECT mean(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`,
@@ -334,14 +385,14 @@ func TestSelect(t *testing.T) {
 			q:    `SELECT mean(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`,
 			typ:  influxql.String,
 			itrs: []query.Iterator{&StringIterator{}},
-			err:  `unsupported mean iterator type: *query_test.StringIterator`,
+			err:  `invalid argument type for the first argument in mean(): string`,
 		},
 		{
 			name: "Mean_Boolean",
 			q:    `SELECT mean(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`,
 			typ:  influxql.Boolean,
 			itrs: []query.Iterator{&BooleanIterator{}},
-			err:  `unsupported mean iterator type: *query_test.BooleanIterator`,
+			err:  `invalid argument type for the first argument in mean(): boolean`,
 		},
 		{
 			name: "Median_Float",
@@ -441,14 +492,14 @@ func TestSelect(t *testing.T) {
 			q:    `SELECT median(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`,
 			typ:  influxql.String,
 			itrs: []query.Iterator{&StringIterator{}},
-			err:  `unsupported median iterator type: *query_test.StringIterator`,
+			err:  `invalid argument type for the first argument in median(): string`,
 		},
 		{
 			name: "Median_Boolean",
 			q:    `SELECT median(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`,
 			typ:  influxql.Boolean,
 			itrs: []query.Iterator{&BooleanIterator{}},
-			err:  `unsupported median iterator type: *query_test.BooleanIterator`,
+			err:  `invalid argument type for the first argument in median(): boolean`,
 		},
 		{
 			name: "Mode_Float",
@@ -2692,6 +2743,27 @@ func TestSelect(t *testing.T) {
 				{Time: 12 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(11)}},
 			},
 		},
+		{
+			name: "MovingAverage_Mean",
+			q:    `SELECT moving_average(mean(value), 2) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z' GROUP BY time(4s)`,
+			typ:  influxql.Float,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Time: 0 * Second, Value: 20},
+					{Name: "cpu", Time: 2 * Second, Value: 10},
+					{Name: "cpu", Time: 4 * Second, Value: 19},
+					{Name: "cpu", Time: 6 * Second, Value: 3},
+					{Name: "cpu", Time: 8 * Second, Value: 8},
+					{Name: "cpu", Time: 12 * Second, Value: 6},
+					{Name: "cpu", Time: 14 * Second, Value: 2},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 4 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(13)}},
+				{Time: 8 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(9.5)}},
+				{Time: 12 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(6)}},
+			},
+		},
 		{
 			name: "CumulativeSum_Float",
 			q:    `SELECT cumulative_sum(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z'`,
@@ -2749,6 +2821,28 @@ func TestSelect(t *testing.T) {
 				{Time: 12 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{uint64(52)}},
 			},
 		},
+		{
+			name: "CumulativeSum_Mean",
+			q:    `SELECT cumulative_sum(mean(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z' GROUP BY time(4s)`,
+			typ:  influxql.Float,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Time: 0 * Second, Value: 20},
+					{Name: "cpu", Time: 2 * Second, Value: 10},
+					{Name: "cpu", Time: 4 * Second, Value: 19},
+					{Name: "cpu", Time: 6 * Second, Value: 3},
+					{Name: "cpu", Time: 8 * Second, Value: 8},
+					{Name: "cpu", Time: 12 * Second, Value: 6},
+					{Name: "cpu", Time: 14 * Second, Value: 2},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(15)}},
+				{Time: 4 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(26)}},
+				{Time: 8 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(34)}},
+				{Time: 12 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(38)}},
+			},
+		},
 		{
 			name: "CumulativeSum_Duplicate_Float",
 			q:    `SELECT cumulative_sum(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z'`,
@@ -2861,6 +2955,34 @@ func TestSelect(t *testing.T) {
 				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=B")}, Values: []interface{}{float64(20), float64(5)}},
 			},
 		},
+		{
+			name: "GroupByWildcard",
+			q:    `SELECT sum(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), * fill(none)`,
+			typ:  influxql.Float,
+			expr: `sum(value::float)`,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: 3},
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 31 * Second, Value: 100},
+				}},
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: 19},
+					{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 10 * Second, Value: 2},
+				}},
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A,region=east")}, Values: []interface{}{float64(19)}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A,region=east")}, Values: []interface{}{float64(2)}},
+				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A,region=west")}, Values: []interface{}{float64(20)}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A,region=west")}, Values: []interface{}{float64(3)}},
+				{Time: 30 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A,region=west")}, Values: []interface{}{float64(100)}},
+				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=B,region=west")}, Values: []interface{}{float64(10)}},
+			},
+		},
 		{
 			name: "GroupByOffset",
 			q:    `SELECT mean(value) FROM cpu WHERE time >= now() - 2m AND time < now() GROUP BY time(1m, now())`,
@@ -2882,6 +3004,60 @@ func TestSelect(t *testing.T) {
 			},
 			now: mustParseTime("1970-01-01T00:02:30Z"),
 		},
+		{
+			name: "Raw_Descending_Limit",
+			q:    `SELECT value FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' ORDER BY time DESC LIMIT 2`,
+			typ:  influxql.Float,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Time: 30 * Second, Aux: []interface{}{float64(4)}},
+					{Name: "cpu", Time: 20 * Second, Aux: []interface{}{float64(3)}},
+					{Name: "cpu", Time: 10 * Second, Aux: []interface{}{float64(2)}},
+					{Name: "cpu", Time: 0 * Second, Aux: []interface{}{float64(1)}},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 30 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(4)}},
+				{Time: 20 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(3)}},
+			},
+		},
+		{
+			name: "Max_GroupByTime_Descending_Fill",
+			q:    `SELECT max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:40Z' GROUP BY time(10s) fill(previous) ORDER BY time DESC`,
+			typ:  influxql.Float,
+			expr: `max(value::float)`,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Time: 35 * Second, Value: 5},
+					{Name: "cpu", Time: 31 * Second, Value: 7},
+					{Name: "cpu", Time: 12 * Second, Value: 3},
+					{Name: "cpu", Time: 1 * Second, Value: 1},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 30 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(7)}},
+				{Time: 20 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(7)}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(3)}},
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(1)}},
+			},
+		},
+		{
+			name: "Max_GroupByTime_Descending_Linear_Limit",
+			q:    `SELECT max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:40Z' GROUP BY time(10s) fill(linear) ORDER BY time DESC LIMIT 3`,
+			typ:  influxql.Float,
+			expr: `max(value::float)`,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Time: 31 * Second, Value: 7},
+					{Name: "cpu", Time: 1 * Second, Value: 1},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 30 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(7)}},
+				{Time: 20 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(5)}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(3)}},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.onlyArch != "" && runtime.GOARCH != tt.onlyArch {
@@ -3028,6 +3204,346 @@ func TestSelect_Raw(t *testing.T) {
 	}
 }
 
+// Ensure a SELECT with aggregates of different fields reads every field with its own
+// iterator and combines the aggregates into one row per interval.
+func TestSelect_MultipleAggregates(t *testing.T) {
+	shardMapper := ShardMapper{
+		MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"a": influxql.Float,
+					"b": influxql.Integer,
+				},
+				Dimensions: []string{"host"},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					var input query.Iterator
+					switch field := opt.Expr.(*influxql.Call).Args[0].(*influxql.VarRef).Val; field {
+					case "a":
+						input = &FloatIterator{Points: []query.FloatPoint{
+							{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 1},
+							{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 3},
+							{Name: "cpu", Tags: ParseTags("host=A"), Time: 20 * Second, Value: 10},
+						}}
+					case "b":
+						input = &IntegerIterator{Points: []query.IntegerPoint{
+							{Name: "cpu", Tags: ParseTags("host=A"), Time: 1 * Second, Value: 7},
+							{Name: "cpu", Tags: ParseTags("host=A"), Time: 2 * Second, Value: 9},
+							{Name: "cpu", Tags: ParseTags("host=A"), Time: 12 * Second, Value: 4},
+						}}
+					default:
+						t.Fatalf("unexpected field: %s", field)
+					}
+					return query.NewCallIterator(input, opt)
+				},
+			}
+		},
+	}
+
+	stmt := MustParseSelectStatement(`SELECT mean(a), max(b) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:30Z' GROUP BY time(10s), host`)
+	stmt.OmitTime = true
+	cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	a, err := ReadCursor(cur)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff := cmp.Diff([]query.Row{
+		{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A")}, Values: []interface{}{float64(2), int64(9)}},
+		{Time: 10 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A")}, Values: []interface{}{nil, int64(4)}},
+		{Time: 20 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A")}, Values: []interface{}{float64(10), nil}},
+	}, a); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+// Ensure the planning decisions of a SELECT are recorded when a trace is set.
+func TestSelect_Trace(t *testing.T) {
+	shardMapper := ShardMapper{
+		MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"value": influxql.Float,
+				},
+				Dimensions: []string{"host"},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					return &FloatIterator{}, nil
+				},
+			}
+		},
+	}
+
+	var trace query.PlanTrace
+	stmt := MustParseSelectStatement(`SELECT max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(10s), *`)
+	cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{Trace: &trace})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := ReadCursor(cur); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	exp := []string{
+		"map shards: sources=cpu time range=[1970-01-01T00:00:00Z, 1970-01-01T00:00:59.999999999Z]",
+		"rewritten fields: max(value::float)",
+		"iterator options: start=0 end=59999999999 interval=10s offset=0s dimensions=[host] ascending=true",
+		"create iterator: source=cpu expr=max(value::float) aux=0 dimensions=[host] interval=10s",
+	}
+	if diff := cmp.Diff(exp, trace.Events()); diff != "" {
+		t.Fatalf("unexpected trace:\n%s", diff)
+	}
+}
+
+func TestPreparedStatement_Plan(t *testing.T) {
+	shardMapper := ShardMapper{
+		MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"value": influxql.Float,
+				},
+				Dimensions: []string{"host", "region", "zone"},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					return &FloatIterator{}, nil
+				},
+			}
+		},
+	}
+
+	plan := func(s string) query.Plan {
+		t.Helper()
+		p, err := query.Prepare(context.Background(), MustParseSelectStatement(s), &shardMapper, query.SelectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer p.Close()
+		plan, err := p.Plan(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return plan
+	}
+
+	const q = `SELECT max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' AND host = 'a' GROUP BY time(10s), * fill(none)`
+	got := plan(q)
+	if diff := cmp.Diff(query.Plan{{
+		Measurement: "cpu",
+		Expr:        "max(value::float)",
+		Interval:    query.Interval{Duration: 10 * time.Second},
+		Dimensions:  []string{"host", "region", "zone"},
+		GroupBy:     []string{"host", "region", "zone"},
+		Fill:        "none",
+		Condition:   "host::tag = 'a'",
+		StartTime:   0,
+		EndTime:     59999999999,
+		Ascending:   true,
+		Ordered:     true,
+	}}, got); diff != "" {
+		t.Fatalf("unexpected plan:\n%s", diff)
+	}
+
+	// The hash of the plan does not depend on the order of the group by dimensions.
+	hash, err := got.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if h, err := plan(q).Hash(); err != nil || h != hash {
+			t.Fatalf("unexpected plan hash: got %s, want %s (%v)", h, hash, err)
+		}
+	}
+	// Nor on the absolute times of the query, only on the length of its time range.
+	if h, err := plan(`SELECT max(value) FROM cpu WHERE time >= '1970-01-01T01:00:00Z' AND time < '1970-01-01T01:01:00Z' AND host = 'a' GROUP BY time(10s), * fill(none)`).Hash(); err != nil || h != hash {
+		t.Fatalf("unexpected plan hash: got %s, want %s (%v)", h, hash, err)
+	}
+	if h, _ := plan(`SELECT max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:02:00Z' AND host = 'a' GROUP BY time(10s), * fill(none)`).Hash(); h == hash {
+		t.Fatal("expected plans of different time ranges to have different hashes")
+	}
+	if h, _ := plan(`SELECT min(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' AND host = 'a' GROUP BY time(10s), * fill(none)`).Hash(); h == hash {
+		t.Fatal("expected plans of different queries to have different hashes")
+	}
+}
+
+func TestPreparedStatement_Explain(t *testing.T) {
+	shardMapper := ShardMapper{
+		MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"value": influxql.Float,
+				},
+				Dimensions: []string{"host", "region"},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					return &FloatIterator{}, nil
+				},
+			}
+		},
+	}
+
+	stmt := MustParseSelectStatement(`SELECT max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(10s), *`)
+	p, err := query.Prepare(context.Background(), stmt, &shardMapper, query.SelectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	plan, err := p.Explain(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(plan), "\n")
+	if got := lines[len(lines)-1]; !strings.HasPrefix(got, "PLAN HASH: ") {
+		t.Fatalf("unexpected last line: %s", got)
+	}
+
+	exp := []string{
+		"EXPRESSION: max(value::float)",
+		"MEASUREMENT: cpu",
+		"TAG SET: host, region",
+		"TIME RANGE: 1970-01-01T00:00:00Z - 1970-01-01T00:00:59.999999999Z",
+		"INTERVAL: 10s",
+		"NUMBER OF SHARDS: 0",
+		"NUMBER OF SERIES: 0",
+		"CACHED VALUES: 0",
+		"NUMBER OF FILES: 0",
+		"NUMBER OF BLOCKS: 0",
+		"SIZE OF BLOCKS: 0",
+		"",
+	}
+	if diff := cmp.Diff(exp, lines[:len(lines)-1]); diff != "" {
+		t.Fatalf("unexpected plan:\n%s", diff)
+	}
+}
+
+// Ensure a select from several measurements returns a series for each of them
+// and reads a measurement listed more than once only once.
+func TestSelect_MultipleMeasurements(t *testing.T) {
+	for _, tt := range []struct {
+		q    string
+		want []string
+	}{
+		{q: `SELECT value FROM cpu, mem`, want: []string{"cpu", "mem"}},
+		{q: `SELECT value FROM cpu, mem, cpu`, want: []string{"cpu", "mem"}},
+		{q: `SELECT max(value) FROM mem, cpu, mem WHERE time >= 0s AND time < 20s GROUP BY time(10s)`, want: []string{"mem", "cpu"}},
+	} {
+		t.Run(tt.q, func(t *testing.T) {
+			var created []string
+			shardMapper := ShardMapper{
+				MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+					return &ShardGroup{
+						Fields: map[string]influxql.DataType{
+							"value": influxql.Float,
+						},
+						CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+							created = append(created, m.Name)
+							p := query.FloatPoint{Name: m.Name, Time: 0 * Second, Value: 1}
+							if len(opt.Aux) > 0 {
+								p.Aux = []interface{}{float64(1)}
+							}
+							return &FloatIterator{Points: []query.FloatPoint{p}}, nil
+						},
+					}
+				},
+			}
+
+			cur, err := query.Select(context.Background(), MustParseSelectStatement(tt.q), &shardMapper, query.SelectOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			rows, err := ReadCursor(cur)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(tt.want, created); diff != "" {
+				t.Fatalf("unexpected iterators:\n%s", diff)
+			}
+
+			var names []string
+			for _, row := range rows {
+				if row.Values[1] != nil && (len(names) == 0 || names[len(names)-1] != row.Series.Name) {
+					names = append(names, row.Series.Name)
+				}
+			}
+			if diff := cmp.Diff([]string{"cpu", "mem"}, names); diff != "" {
+				t.Fatalf("unexpected series:\n%s", diff)
+			}
+		})
+	}
+}
+
+// Ensure the points past the limit are not read when they cannot belong to another group.
+func TestSelect_Limit_StopReading(t *testing.T) {
+	for _, tt := range []struct {
+		q      string
+		rows   int
+		unread bool
+	}{
+		{q: `SELECT value FROM cpu LIMIT 2`, rows: 2, unread: true},
+		{q: `SELECT value FROM cpu LIMIT 2 OFFSET 3`, rows: 2, unread: true},
+		{q: `SELECT max(value) FROM cpu WHERE time >= 0s AND time < 100s GROUP BY time(10s) LIMIT 2`, rows: 2, unread: true},
+		{q: `SELECT max(value) FROM cpu WHERE time >= 0s AND time < 100s GROUP BY time(10s), host LIMIT 2`, rows: 4, unread: false},
+		{q: `SELECT value FROM cpu, mem LIMIT 2`, rows: 4, unread: false},
+		{q: `SELECT value FROM cpu ORDER BY time DESC LIMIT 2`, rows: 2, unread: true},
+		{q: `SELECT max(value) FROM cpu WHERE time >= 0s AND time < 100s GROUP BY time(10s) ORDER BY time DESC LIMIT 2`, rows: 2, unread: true},
+	} {
+		t.Run(tt.q, func(t *testing.T) {
+			var inputs []*FloatIterator
+			shardMapper := ShardMapper{
+				MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+					return &ShardGroup{
+						Fields: map[string]influxql.DataType{
+							"value": influxql.Float,
+						},
+						Dimensions: []string{"host"},
+						CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+							// ten points every 10s, grouped by host when requested
+							itr := &FloatIterator{}
+							for i := 0; i < 10; i++ {
+								p := query.FloatPoint{Name: m.Name, Time: int64(i) * 10 * Second, Value: float64(i)}
+								if len(opt.Dimensions) > 0 {
+									p.Tags = ParseTags("host=" + []string{"A", "B"}[i/5])
+									p.Time = int64(i%5) * 10 * Second
+								}
+								if len(opt.Aux) > 0 {
+									p.Aux = []interface{}{float64(i)}
+								}
+								itr.Points = append(itr.Points, p)
+							}
+							if !opt.Ascending {
+								for i, j := 0, len(itr.Points)-1; i < j; i, j = i+1, j-1 {
+									itr.Points[i], itr.Points[j] = itr.Points[j], itr.Points[i]
+								}
+							}
+							inputs = append(inputs, itr)
+							return itr, nil
+						},
+					}
+				},
+			}
+
+			cur, err := query.Select(context.Background(), MustParseSelectStatement(tt.q), &shardMapper, query.SelectOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			rows, err := ReadCursor(cur)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(rows) != tt.rows {
+				t.Fatalf("unexpected number of rows: got %d, want %d", len(rows), tt.rows)
+			}
+
+			unread := 0
+			for _, input := range inputs {
+				unread += len(input.Points)
+			}
+			if got := unread > 0; got != tt.unread {
+				t.Fatalf("unexpected unread points: %d", unread)
+			}
+		})
+	}
+}
+
 // Ensure a SELECT binary expr queries can be executed as floats.
 func TestSelect_BinaryExpr(t *testing.T) {
 	shardMapper := ShardMapper{
@@ -4079,6 +4595,222 @@ func TestSelect_BinaryExpr_NilValues(t *testing.T) {
 	}
 }
 
+func TestSelect_MaxPointN(t *testing.T) {
+	shardMapper := ShardMapper{
+		MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"value": influxql.Float,
+				},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					return &countingFloatIterator{FloatIterator: FloatIterator{Points: []query.FloatPoint{
+						{Name: "cpu", Time: 0 * Second, Value: 1},
+						{Name: "cpu", Time: 5 * Second, Value: 2},
+						{Name: "cpu", Time: 9 * Second, Value: 3},
+					}}}, nil
+				},
+			}
+		},
+	}
+
+	for _, tt := range []struct {
+		name string
+		stmt string
+		err  string
+	}{
+		{
+			name: "Raw",
+			stmt: `SELECT value FROM cpu`,
+			err:  `max-select-point limit exceeed: (3/2)`,
+		},
+		{
+			name: "Aggregate",
+			stmt: `SELECT sum(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:10Z' GROUP BY time(5s)`,
+			err:  `max-select-point limit exceeed: (3/2)`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := MustParseSelectStatement(tt.stmt)
+			cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{MaxPointN: 2})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if _, err := ReadCursor(cur); err == nil || err.Error() != tt.err {
+				t.Fatalf("unexpected error: got %v, want %s", err, tt.err)
+			}
+
+			// The limit is not reached when the query reads fewer points.
+			cur, err = query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{MaxPointN: 3})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if _, err := ReadCursor(cur); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+// Ensure the points of a long query are counted while it is read, not only at its end.
+func TestSelect_MaxPointN_Interval(t *testing.T) {
+	points := make([]query.FloatPoint, 2500)
+	for i := range points {
+		points[i] = query.FloatPoint{Name: "cpu", Time: int64(i) * Second, Value: float64(i)}
+	}
+	shardMapper := ShardMapper{
+		MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"value": influxql.Float,
+				},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					return &countingFloatIterator{FloatIterator: FloatIterator{Points: points}}, nil
+				},
+			}
+		},
+	}
+
+	cur, err := query.Select(context.Background(), MustParseSelectStatement(`SELECT value FROM cpu`), &shardMapper, query.SelectOptions{MaxPointN: 1500})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer cur.Close()
+
+	var n int
+	for {
+		var row query.Row
+		if !cur.Scan(&row) {
+			break
+		}
+		n++
+	}
+	if cur.Err() == nil {
+		t.Fatal("expected the max-select-point limit to be exceeded")
+	}
+	if n >= len(points) {
+		t.Fatalf("expected the query to stop before its end, read %d rows", n)
+	}
+}
+
+// countingFloatIterator counts the points it returns in its stats, like the storage iterators.
+type countingFloatIterator struct {
+	FloatIterator
+	stats query.IteratorStats
+}
+
+func (itr *countingFloatIterator) Stats() query.IteratorStats { return itr.stats }
+
+func (itr *countingFloatIterator) Next() (*query.FloatPoint, error) {
+	p, err := itr.FloatIterator.Next()
+	if p != nil {
+		itr.stats.PointN++
+	}
+	return p, err
+}
+
+// Ensure arithmetic between aggregates is evaluated on the values of each interval.
+func TestSelect_BinaryExpr_Aggregates(t *testing.T) {
+	shardMapper := ShardMapper{
+		MapShardsFn: func(_ context.Context, sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"f": influxql.Float,
+					"i": influxql.Integer,
+					"u": influxql.Unsigned,
+				},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					var itr query.Iterator
+					switch ref := influxql.ExprNames(opt.Expr)[0]; ref.Type {
+					case influxql.Float:
+						itr = &FloatIterator{Points: []query.FloatPoint{
+							{Name: "cpu", Time: 0 * Second, Value: 1.5},
+							{Name: "cpu", Time: 2 * Second, Value: 2.5},
+							{Name: "cpu", Time: 5 * Second, Value: 4},
+						}}
+					case influxql.Integer:
+						itr = &IntegerIterator{Points: []query.IntegerPoint{
+							{Name: "cpu", Time: 0 * Second, Value: 3},
+							{Name: "cpu", Time: 2 * Second, Value: 6},
+							{Name: "cpu", Time: 5 * Second, Value: 10},
+						}}
+					case influxql.Unsigned:
+						itr = &UnsignedIterator{Points: []query.UnsignedPoint{
+							{Name: "cpu", Time: 0 * Second, Value: 3},
+						}}
+					default:
+						t.Fatalf("unexpected field: %s", ref)
+					}
+					return query.NewCallIterator(itr, opt)
+				},
+			}
+		},
+	}
+
+	for _, test := range []struct {
+		Name      string
+		Statement string
+		Rows      []query.Row
+		Err       string
+	}{
+		{
+			Name:      "Integer_Minus_Integer",
+			Statement: `SELECT max(i) - min(i) FROM cpu`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{int64(7)}},
+			},
+		},
+		{
+			Name:      "Integer_Minus_Float",
+			Statement: `SELECT max(i) - min(f) FROM cpu`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{8.5}},
+			},
+		},
+		{
+			Name:      "Integer_Division",
+			Statement: `SELECT sum(i) / count(i) FROM cpu`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(19) / 3}},
+			},
+		},
+		{
+			Name:      "GroupByTime",
+			Statement: `SELECT max(i) - min(f) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:10Z' GROUP BY time(5s)`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{4.5}},
+				{Time: 5 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(6)}},
+			},
+		},
+		{
+			Name:      "Integer_Plus_Unsigned",
+			Statement: `SELECT max(i) + max(u) FROM cpu`,
+			Err:       `type error: max(i::integer) + max(u::unsigned): cannot use + between an integer and unsigned, an explicit cast is required`,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			stmt := MustParseSelectStatement(test.Statement)
+			stmt.OmitTime = true
+			cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{})
+			if err != nil {
+				if test.Err == "" {
+					t.Fatalf("unexpected error: %s", err)
+				} else if have, want := err.Error(), test.Err; have != want {
+					t.Fatalf("unexpected error: %s != %s", have, want)
+				}
+				return
+			} else if test.Err != "" {
+				t.Fatalf("expected error: %s", test.Err)
+			}
+
+			if a, err := ReadCursor(cur); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(test.Rows, a); diff != "" {
+				t.Errorf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
 type ShardMapper struct {
 	MapShardsFn func(ctx context.Context, sources influxql.Sources, t influxql.TimeRange) query.ShardGroup
 }
@@ -363,6 +363,82 @@ func TestSubquery(t *testing.T) {
 				{Time: mustParseTime("2019-06-25T22:36:15.144253616Z").UnixNano(), Series: query.Series{Name: "testing"}, Values: []interface{}{float64(2), "a"}},
 			},
 		},
+		{
+			Name:      "AggregateOfAliasedAggregate",
+			Statement: `SELECT mean(v) FROM (SELECT max(value) AS v FROM cpu GROUP BY time(5s)) WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s)`,
+			Fields:    map[string]influxql.DataType{"value": influxql.Float},
+			MapShardsFn: func(t *testing.T, tr influxql.TimeRange) CreateIteratorFn {
+				return func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) query.Iterator {
+					if got, want := opt.Expr.String(), "max(value::float)"; got != want {
+						t.Errorf("unexpected expression: got=%s want=%s", got, want)
+					}
+					if got, want := opt.Interval.Duration, 5*time.Second; got != want {
+						t.Errorf("unexpected interval: got=%s want=%s", got, want)
+					}
+
+					itr, err := query.NewCallIterator(&FloatIterator{Points: []query.FloatPoint{
+						{Name: "cpu", Time: 0 * Second, Value: 2},
+						{Name: "cpu", Time: 1 * Second, Value: 4},
+						{Name: "cpu", Time: 6 * Second, Value: 8},
+						{Name: "cpu", Time: 12 * Second, Value: 1},
+						{Name: "cpu", Time: 16 * Second, Value: 3},
+					}}, opt)
+					if err != nil {
+						panic(err)
+					}
+					return itr
+				}
+			},
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(6)}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(2)}},
+			},
+		},
+		{
+			Name:      "NestedSubqueries",
+			Statement: `SELECT max(m) FROM (SELECT mean(v) AS m FROM (SELECT value * 2.0 AS v FROM cpu) GROUP BY time(5s)) WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:10Z'`,
+			Fields:    map[string]influxql.DataType{"value": influxql.Float},
+			MapShardsFn: func(t *testing.T, tr influxql.TimeRange) CreateIteratorFn {
+				if got, want := tr.MaxTimeNano(), 10*Second-1; got != want {
+					t.Errorf("unexpected max time: got=%d want=%d", got, want)
+				}
+				return func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) query.Iterator {
+					if got, want := opt.Aux, []influxql.VarRef{{Val: "value", Type: influxql.Float}}; !cmp.Equal(got, want) {
+						t.Errorf("unexpected auxiliary fields:\n%s", cmp.Diff(want, got))
+					}
+					return &FloatIterator{Points: []query.FloatPoint{
+						{Name: "cpu", Time: 0 * Second, Aux: []interface{}{1.0}},
+						{Name: "cpu", Time: 2 * Second, Aux: []interface{}{2.0}},
+						{Name: "cpu", Time: 5 * Second, Aux: []interface{}{4.0}},
+						{Name: "cpu", Time: 7 * Second, Aux: []interface{}{6.0}},
+					}}
+				}
+			},
+			Rows: []query.Row{
+				{Time: 5 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(10)}},
+			},
+		},
+		{
+			Name:      "Distinct",
+			Statement: `SELECT distinct(v) FROM (SELECT value AS v FROM cpu) WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:10Z'`,
+			Fields:    map[string]influxql.DataType{"value": influxql.Float},
+			MapShardsFn: func(t *testing.T, tr influxql.TimeRange) CreateIteratorFn {
+				return func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) query.Iterator {
+					if !opt.Ordered {
+						t.Error("expected the points of the subquery to be read in order")
+					}
+					return &FloatIterator{Points: []query.FloatPoint{
+						{Name: "cpu", Time: 0 * Second, Aux: []interface{}{2.0}},
+						{Name: "cpu", Time: 2 * Second, Aux: []interface{}{1.0}},
+						{Name: "cpu", Time: 5 * Second, Aux: []interface{}{2.0}},
+					}}
+				}
+			},
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(2)}},
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(1)}},
+			},
+		},
 	} {
 		t.Run(test.Name, func(t *testing.T) {
 			shardMapper := ShardMapper{
@@ -0,0 +1,76 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/influxql"
+)
+
+// PlanTrace records the decisions made while a select statement is planned,
+// such as the time range used to map shards and the iterators that were created.
+// It is used to find out why a query read the shards and series it did.
+//
+// A nil PlanTrace discards every decision. Callers still check for nil before
+// formatting a decision so that planning costs nothing when tracing is off.
+type PlanTrace struct {
+	mu     sync.Mutex
+	events []string
+}
+
+// Tracef records a single planning decision.
+func (t *PlanTrace) Tracef(format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.events = append(t.events, fmt.Sprintf(format, args...))
+	t.mu.Unlock()
+}
+
+// Events returns the decisions recorded so far in the order they were made.
+func (t *PlanTrace) Events() []string {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	events := make([]string, len(t.events))
+	copy(events, t.events)
+	return events
+}
+
+// String returns the recorded decisions, one per line.
+func (t *PlanTrace) String() string {
+	return strings.Join(t.Events(), "\n")
+}
+
+// traceIteratorCreator records every iterator created by the wrapped iterator creator.
+type traceIteratorCreator struct {
+	ic interface {
+		IteratorCreator
+		io.Closer
+	}
+	trace *PlanTrace
+}
+
+func (t *traceIteratorCreator) CreateIterator(ctx context.Context, m *influxql.Measurement, opt IteratorOptions) (Iterator, error) {
+	expr := "<nil>"
+	if opt.Expr != nil {
+		expr = opt.Expr.String()
+	}
+	t.trace.Tracef("create iterator: source=%s expr=%s aux=%d dimensions=[%s] interval=%s",
+		m.String(), expr, len(opt.Aux), strings.Join(opt.Dimensions, ", "), opt.Interval.Duration)
+	return t.ic.CreateIterator(ctx, m, opt)
+}
+
+func (t *traceIteratorCreator) IteratorCost(ctx context.Context, m *influxql.Measurement, opt IteratorOptions) (IteratorCost, error) {
+	return t.ic.IteratorCost(ctx, m, opt)
+}
+
+func (t *traceIteratorCreator) Close() error {
+	return t.ic.Close()
+}
@@ -2,6 +2,7 @@ package influxql
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/platform"
@@ -73,6 +74,15 @@ type QueryRequest struct {
 	Query          string                  `json:"query"`        // Query contains the InfluxQL.
 	Params         map[string]interface{}  `json:"params,omitempty"`
 	Source         string                  `json:"source"` // Source represents the ultimate source of the request.
+	// SortTags orders the series of each result by name and tag keys and values in lexical order.
+	// It only applies to responses that are not chunked.
+	SortTags bool `json:"sort_tags,omitempty"`
+	// DefaultTimeRange limits the statements without a lower time bound
+	// to the time range ending now. Zero reads from the minimum time.
+	DefaultTimeRange time.Duration `json:"default_time_range,omitempty"`
+	// MaxBuckets lowers the maximum number of buckets of a statement
+	// configured for the server. Zero keeps the server limit.
+	MaxBuckets int `json:"max_buckets,omitempty"`
 }
 
 // The HTTP query requests represented the body expected by the QueryHandler
@@ -15,6 +15,8 @@ type Statistics struct {
 	StatementCount  int           `json:"statement_count"`  // StatementCount is the number of InfluxQL statements executed
 	ScannedValues   int           `json:"scanned_values"`   // ScannedValues is the number of values scanned from storage
 	ScannedBytes    int           `json:"scanned_bytes"`    // ScannedBytes is the number of bytes scanned from storage
+	RowCount        int           `json:"row_count"`        // RowCount is the number of series rows returned to the caller
+	ValueCount      int           `json:"value_count"`      // ValueCount is the number of values returned to the caller, one per point or interval
 }
 
 // Adding returns the sum of s and other.
@@ -25,6 +27,8 @@ func (s Statistics) Adding(other Statistics) Statistics {
 		StatementCount:  s.StatementCount + other.StatementCount,
 		ScannedValues:   s.ScannedValues + other.ScannedValues,
 		ScannedBytes:    s.ScannedBytes + other.ScannedBytes,
+		RowCount:        s.RowCount + other.RowCount,
+		ValueCount:      s.ValueCount + other.ValueCount,
 	}
 }
 
@@ -35,6 +39,8 @@ func (s *Statistics) Add(other Statistics) {
 	s.StatementCount += other.StatementCount
 	s.ScannedValues += other.ScannedValues
 	s.ScannedBytes += other.ScannedBytes
+	s.RowCount += other.RowCount
+	s.ValueCount += other.ValueCount
 }
 
 func (s *Statistics) LogToSpan(span opentracing.Span) {
@@ -47,6 +53,8 @@ func (s *Statistics) LogToSpan(span opentracing.Span) {
 		log.Int("stats_statement_count", s.StatementCount),
 		log.Int("stats_scanned_values", s.ScannedValues),
 		log.Int("stats_scanned_bytes", s.ScannedBytes),
+		log.Int("stats_row_count", s.RowCount),
+		log.Int("stats_value_count", s.ValueCount),
 	)
 }
 
@@ -29,12 +29,12 @@ type TSDBStoreMock struct {
 	DiskSizeFn                func() (int64, error)
 	ExpandSourcesFn           func(sources influxql.Sources) (influxql.Sources, error)
 	ImportShardFn             func(id uint64, r io.Reader) error
-	MeasurementsCardinalityFn func(database string) (int64, error)
+	MeasurementsCardinalityFn func(ctx context.Context, database string) (int64, error)
 	MeasurementNamesFn        func(ctx context.Context, auth query.Authorizer, database string, cond influxql.Expr) ([][]byte, error)
 	OpenFn                    func() error
 	PathFn                    func() string
 	RestoreShardFn            func(id uint64, r io.Reader) error
-	SeriesCardinalityFn       func(database string) (int64, error)
+	SeriesCardinalityFn       func(ctx context.Context, database string) (int64, error)
 	SetShardEnabledFn         func(shardID uint64, enabled bool) error
 	ShardFn                   func(id uint64) *tsdb.Shard
 	ShardGroupFn              func(ids []uint64) tsdb.ShardGroup
@@ -94,8 +94,8 @@ func (s *TSDBStoreMock) ImportShard(id uint64, r io.Reader) error {
 func (s *TSDBStoreMock) MeasurementNames(ctx context.Context, auth query.Authorizer, database string, cond influxql.Expr) ([][]byte, error) {
 	return s.MeasurementNamesFn(ctx, auth, database, cond)
 }
-func (s *TSDBStoreMock) MeasurementsCardinality(database string) (int64, error) {
-	return s.MeasurementsCardinalityFn(database)
+func (s *TSDBStoreMock) MeasurementsCardinality(ctx context.Context, database string) (int64, error) {
+	return s.MeasurementsCardinalityFn(ctx, database)
 }
 func (s *TSDBStoreMock) Open() error {
 	return s.OpenFn()
@@ -106,8 +106,8 @@ func (s *TSDBStoreMock) Path() string {
 func (s *TSDBStoreMock) RestoreShard(id uint64, r io.Reader) error {
 	return s.RestoreShardFn(id, r)
 }
-func (s *TSDBStoreMock) SeriesCardinality(database string) (int64, error) {
-	return s.SeriesCardinalityFn(database)
+func (s *TSDBStoreMock) SeriesCardinality(ctx context.Context, database string) (int64, error) {
+	return s.SeriesCardinalityFn(ctx, database)
 }
 func (s *TSDBStoreMock) SetShardEnabled(shardID uint64, enabled bool) error {
 	return s.SetShardEnabledFn(shardID, enabled)
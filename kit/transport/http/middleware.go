@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -95,12 +96,27 @@ func SkipOptions(next http.Handler) http.Handler {
 	return http.HandlerFunc(fn)
 }
 
+const (
+	// TraceIDHeader is the response header holding the ID of the trace of the request.
+	TraceIDHeader = "Trace-Id"
+	// TraceSampledHeader is the response header telling if the trace of the request was sampled.
+	TraceSampledHeader = "Trace-Sampled"
+)
+
+// Trace starts a span for each request, continuing the trace propagated in its headers.
+// The ID of the trace is returned in the response headers, so a client that asked for
+// the request to be traced can find the trace of a slow request.
 func Trace(name string) Middleware {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			span, r := tracing.ExtractFromHTTPRequest(r, name)
 			defer span.Finish()
 
+			if traceID, sampled, ok := tracing.InfoFromSpan(span); ok {
+				w.Header().Set(TraceIDHeader, traceID)
+				w.Header().Set(TraceSampledHeader, strconv.FormatBool(sampled))
+			}
+
 			span.LogKV("user_agent", UserAgent(r))
 			for k, v := range r.Header {
 				if len(v) == 0 {
@@ -9,10 +9,13 @@ import (
 	"github.com/influxdata/influxdb/v2/kit/platform"
 	"github.com/influxdata/influxdb/v2/kit/prom"
 	"github.com/influxdata/influxdb/v2/kit/prom/promtest"
+	"github.com/influxdata/influxdb/v2/kit/tracing"
 	"github.com/influxdata/influxdb/v2/pkg/testttp"
+	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-client-go"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -231,3 +234,32 @@ func TestCors(t *testing.T) {
 		})
 	}
 }
+
+func TestTrace(t *testing.T) {
+	oldTracer := opentracing.GlobalTracer()
+	defer opentracing.SetGlobalTracer(oldTracer)
+	tracer, closer := jaeger.NewTracer(t.Name(), jaeger.NewConstSampler(false), jaeger.NewInMemoryReporter())
+	defer closer.Close()
+	opentracing.SetGlobalTracer(tracer)
+
+	var traceID string
+	h := Trace("test")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _, ok := tracing.InfoFromContext(r.Context())
+		require.True(t, ok)
+		traceID = id
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, traceID, rr.Header().Get(TraceIDHeader))
+	require.Equal(t, "false", rr.Header().Get(TraceSampledHeader))
+
+	// A client asks for its request to be traced with the jaeger debug header.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(jaeger.JaegerDebugHeader, "slow-query")
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+	require.Equal(t, traceID, rr.Header().Get(TraceIDHeader))
+	require.Equal(t, "true", rr.Header().Get(TraceSampledHeader))
+}
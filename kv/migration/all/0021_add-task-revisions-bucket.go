@@ -0,0 +1,10 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var taskRevisionsBucket = []byte("taskRevisionsv1")
+
+var Migration0021_AddTaskRevisionsBucket = migration.CreateBuckets(
+	"create task revisions bucket",
+	taskRevisionsBucket,
+)
@@ -0,0 +1,10 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var taskTagIndexBucket = []byte("taskTagIndexv1")
+
+var Migration0022_AddTaskTagIndexBucket = migration.CreateBuckets(
+	"create task tag index bucket",
+	taskTagIndexBucket,
+)
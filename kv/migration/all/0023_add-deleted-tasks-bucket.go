@@ -0,0 +1,10 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var deletedTaskBucket = []byte("deletedTasksv1")
+
+var Migration0023_AddDeletedTasksBucket = migration.CreateBuckets(
+	"create deleted tasks bucket",
+	deletedTaskBucket,
+)
@@ -0,0 +1,10 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var preferenceBucket = []byte("preferencesv1")
+
+var Migration0024_AddPreferencesBucket = migration.CreateBuckets(
+	"create preferences bucket",
+	preferenceBucket,
+)
@@ -0,0 +1,10 @@
+package all
+
+import "github.com/influxdata/influxdb/v2/kv/migration"
+
+var taskOrgRunsBucket = []byte("taskOrgRunsv1")
+
+var Migration0025_AddTaskOrgRunsBucket = migration.CreateBuckets(
+	"create task org runs bucket",
+	taskOrgRunsBucket,
+)
@@ -47,5 +47,15 @@ var Migrations = [...]migration.Spec{
 	Migration0019_AddRemotesReplicationsToTokens,
 	// add_remotes_replications_metrics_buckets
 	Migration0020_Add_remotes_replications_metrics_buckets,
+	// add task revisions bucket
+	Migration0021_AddTaskRevisionsBucket,
+	// add task tag index bucket
+	Migration0022_AddTaskTagIndexBucket,
+	// add deleted tasks bucket
+	Migration0023_AddDeletedTasksBucket,
+	// add preferences bucket
+	Migration0024_AddPreferencesBucket,
+	// add task org runs bucket
+	Migration0025_AddTaskOrgRunsBucket,
 	// {{ do_not_edit . }}
 }
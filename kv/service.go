@@ -1,6 +1,8 @@
 package kv
 
 import (
+	"crypto/cipher"
+
 	"github.com/benbjohnson/clock"
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/platform"
@@ -9,6 +11,7 @@ import (
 	"github.com/influxdata/influxdb/v2/resource"
 	"github.com/influxdata/influxdb/v2/resource/noop"
 	"github.com/influxdata/influxdb/v2/snowflake"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
 	"go.uber.org/zap"
 )
 
@@ -69,6 +72,15 @@ func NewService(log *zap.Logger, kv Store, orgs influxdb.OrganizationService, co
 type ServiceConfig struct {
 	Clock               clock.Clock
 	FluxLanguageService fluxlang.FluxLanguageService
+
+	// TaskQuota limits the tasks of every organization.
+	TaskQuota taskmodel.OrgQuota
+	// TaskQuotaOverrides replaces TaskQuota for specific organizations.
+	TaskQuotaOverrides map[platform.ID]taskmodel.OrgQuota
+
+	// TaskCipher encrypts the names and scripts of tasks at rest when set.
+	// See NewTaskCipher.
+	TaskCipher cipher.AEAD
 }
 
 // WithResourceLogger sets the resource audit logger for the service.
@@ -20,11 +20,17 @@ import (
 // taskBucket:
 //   <taskID>: task data storage
 // taskRunBucket:
-//   <taskID>/<runID>: run data storage
+//   <taskID>/<runID>: run data storage, run IDs are time ordered so the runs of a task sort chronologically
 //   <taskID>/manualRuns: list of runs to run manually
 //   <taskID>/latestCompleted: run data for the latest completed run of a task
 // taskIndexBucket
 //   <orgID>/<taskID>: index for tasks by org
+// taskTagIndexBucket
+//   <orgID><tagKey>\x00<tagValue>\x00<taskID>: index for tasks by tag within an org
+// deletedTaskBucket
+//   <taskID>: tombstone of a deleted task, see task_trash.go
+// taskOrgRunsBucket
+//   <orgID>: number of runs of the tasks of an org that are scheduled or running
 
 // We may want to add a <taskName>/<taskID> index to allow us to look up tasks by task name.
 
@@ -32,8 +38,14 @@ var (
 	taskBucket      = []byte("tasksv1")
 	taskRunBucket   = []byte("taskRunsv1")
 	taskIndexBucket = []byte("taskIndexsv1")
+
+	taskOrgRunsBucket = []byte("taskOrgRunsv1")
 )
 
+// MaxRunLogs is the number of log lines kept for a single run. Once a run has
+// that many lines, the oldest lines are dropped to make room for new ones.
+const MaxRunLogs = 100
+
 var _ taskmodel.TaskService = (*Service)(nil)
 
 type matchableTask interface {
@@ -43,6 +55,7 @@ type matchableTask interface {
 	GetType() string
 	GetName() string
 	GetStatus() string
+	GetTags() map[string]string
 	ToInfluxDB() *taskmodel.Task
 }
 
@@ -59,10 +72,13 @@ type basicKvTask struct {
 	LastRunStatus   string            `json:"lastRunStatus,omitempty"`
 	LastRunError    string            `json:"lastRunError,omitempty"`
 	Offset          influxdb.Duration `json:"offset,omitempty"`
+	CatchUp         string            `json:"catchUp,omitempty"`
 	LatestCompleted time.Time         `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time         `json:"latestScheduled,omitempty"`
 	LatestSuccess   time.Time         `json:"latestSuccess,omitempty"`
 	LatestFailure   time.Time         `json:"latestFailure,omitempty"`
+	Tags            map[string]string `json:"tags,omitempty"`
+	Version         int64             `json:"version,omitempty"`
 }
 
 func (kv basicKvTask) GetID() platform.ID {
@@ -89,6 +105,10 @@ func (kv basicKvTask) GetStatus() string {
 	return kv.Status
 }
 
+func (kv basicKvTask) GetTags() map[string]string {
+	return kv.Tags
+}
+
 func (kv basicKvTask) ToInfluxDB() *taskmodel.Task {
 	return &taskmodel.Task{
 		ID:              kv.ID,
@@ -103,10 +123,13 @@ func (kv basicKvTask) ToInfluxDB() *taskmodel.Task {
 		LastRunStatus:   kv.LastRunStatus,
 		LastRunError:    kv.LastRunError,
 		Offset:          kv.Offset.Duration,
+		CatchUp:         kv.CatchUp,
 		LatestCompleted: kv.LatestCompleted,
 		LatestScheduled: kv.LatestScheduled,
 		LatestSuccess:   kv.LatestSuccess,
 		LatestFailure:   kv.LatestFailure,
+		Tags:            kv.Tags,
+		Version:         kv.Version,
 	}
 }
 
@@ -173,7 +196,7 @@ func (s *Service) findTaskByID(ctx context.Context, tx Tx, id platform.ID, basic
 	} else {
 		t = &kvTask{}
 	}
-	if err := json.Unmarshal(v, t); err != nil {
+	if err := s.unmarshalTask(v, t); err != nil {
 		return nil, taskmodel.ErrInternalTaskServiceError(err)
 	}
 
@@ -234,6 +257,11 @@ func (s *Service) findTasks(ctx context.Context, tx Tx, filter taskmodel.TaskFil
 		}
 	}
 
+	// tags are the most selective filter, use the index of the org when given.
+	if len(filter.Tags) > 0 && filter.OrganizationID != nil {
+		return s.findTasksByTags(ctx, tx, *filter.OrganizationID, filter)
+	}
+
 	// filter by user id.
 	if filter.User != nil {
 		return s.findTasksByUser(ctx, tx, filter)
@@ -281,7 +309,7 @@ func (s *Service) findTasksByUser(ctx context.Context, tx Tx, filter taskmodel.T
 		} else {
 			task = &kvTask{}
 		}
-		if err := json.Unmarshal(v, task); err != nil {
+		if err := s.unmarshalTask(v, task); err != nil {
 			return nil, 0, taskmodel.ErrInternalTaskServiceError(err)
 		}
 
@@ -385,7 +413,7 @@ type taskMatchFn func(matchableTask) bool
 // a task matches the filter. Will return nil if
 // the filter should match all tasks.
 func newTaskMatchFn(f taskmodel.TaskFilter) taskMatchFn {
-	if f.Type == nil && f.Name == nil && f.Status == nil && f.User == nil {
+	if f.Type == nil && f.Name == nil && f.Status == nil && f.User == nil && len(f.Tags) == 0 {
 		return nil
 	}
 
@@ -414,6 +442,13 @@ func newTaskMatchFn(f taskmodel.TaskFilter) taskMatchFn {
 		if f.User != nil && t.GetOwnerID() != *f.User {
 			return false
 		}
+		if tags := t.GetTags(); len(f.Tags) > 0 {
+			for k, v := range f.Tags {
+				if tv, ok := tags[k]; !ok || tv != v {
+					return false
+				}
+			}
+		}
 
 		return true
 	}
@@ -460,7 +495,7 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter taskmodel.Task
 		} else {
 			task = &kvTask{}
 		}
-		if err := json.Unmarshal(v, task); err != nil {
+		if err := s.unmarshalTask(v, task); err != nil {
 			return nil, 0, taskmodel.ErrInternalTaskServiceError(err)
 		}
 
@@ -524,6 +559,16 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 		return nil, taskmodel.ErrTaskOptionParse(err)
 	}
 
+	if quota := s.orgQuota(org.ID); quota.MaxTasks > 0 {
+		ids, err := s.orgTaskIDs(ctx, tx, org.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) >= quota.MaxTasks {
+			return nil, taskmodel.ErrOrgQuotaExceeded(org.ID, "tasks", quota.MaxTasks)
+		}
+	}
+
 	if tc.Status == "" {
 		tc.Status = string(taskmodel.TaskActive)
 	}
@@ -536,15 +581,18 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 		Organization:    org.Name,
 		OwnerID:         tc.OwnerID,
 		Metadata:        tc.Metadata,
+		Tags:            tc.Tags,
 		Name:            opts.Name,
 		Description:     tc.Description,
 		Status:          tc.Status,
 		Flux:            tc.Flux,
 		Every:           opts.Every.String(),
 		Cron:            opts.Cron,
+		CatchUp:         tc.CatchUp,
 		CreatedAt:       createdAt,
 		LatestCompleted: createdAt,
 		LatestScheduled: createdAt,
+		Version:         1,
 	}
 
 	if opts.Offset != nil {
@@ -566,7 +614,7 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
 	}
 
-	taskBytes, err := json.Marshal(task)
+	taskBytes, err := s.marshalTask(task)
 	if err != nil {
 		return nil, taskmodel.ErrInternalTaskServiceError(err)
 	}
@@ -593,6 +641,11 @@ func (s *Service) createTask(ctx context.Context, tx Tx, org *influxdb.Organizat
 		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	// write the tag index
+	if err := s.putTaskTags(ctx, tx, task.OrganizationID, task.ID, task.Tags); err != nil {
+		return nil, err
+	}
+
 	uid, _ := icontext.GetUserID(ctx)
 	if err := s.audit.Log(resource.Change{
 		Type:           resource.Create,
@@ -635,7 +688,21 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id platform.ID, upd tas
 	}
 	task := t.ToInfluxDB()
 
+	// reject updates based on a stale read of the task
+	if upd.Version != nil && *upd.Version != task.Version {
+		return nil, taskmodel.ErrTaskConflict
+	}
+
 	updatedAt := s.clock.Now().UTC()
+	prev := &taskmodel.TaskRevision{
+		TaskID:    task.ID,
+		Revision:  task.Version,
+		Flux:      task.Flux,
+		CreatedAt: task.UpdatedAt,
+	}
+	if prev.CreatedAt.IsZero() {
+		prev.CreatedAt = task.CreatedAt
+	}
 
 	// update the flux script
 	if !upd.Options.IsZero() || upd.Flux != nil {
@@ -685,6 +752,25 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id platform.ID, upd tas
 		task.UpdatedAt = updatedAt
 	}
 
+	if upd.Tags != nil {
+		if err := s.deleteTaskTags(ctx, tx, task.OrganizationID, task.ID, task.Tags); err != nil {
+			return nil, err
+		}
+		if err := s.putTaskTags(ctx, tx, task.OrganizationID, task.ID, *upd.Tags); err != nil {
+			return nil, err
+		}
+		task.Tags = *upd.Tags
+		if len(task.Tags) == 0 {
+			task.Tags = nil
+		}
+		task.UpdatedAt = updatedAt
+	}
+
+	if upd.CatchUp != nil {
+		task.CatchUp = *upd.CatchUp
+		task.UpdatedAt = updatedAt
+	}
+
 	if upd.LatestCompleted != nil {
 		// make sure we only update latest completed one way
 		tlc := task.LatestCompleted
@@ -731,6 +817,18 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id platform.ID, upd tas
 		}
 	}
 
+	// any user facing modification bumps the version
+	if task.UpdatedAt.Equal(updatedAt) {
+		task.Version++
+	}
+
+	// keep the replaced script so the change can be rolled back
+	if task.Flux != prev.Flux {
+		if err := s.putTaskRevision(ctx, tx, prev); err != nil {
+			return nil, err
+		}
+	}
+
 	// save the updated task
 	bucket, err := tx.Bucket(taskBucket)
 	if err != nil {
@@ -741,7 +839,7 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id platform.ID, upd tas
 		return nil, err
 	}
 
-	taskBytes, err := json.Marshal(task)
+	taskBytes, err := s.marshalTask(task)
 	if err != nil {
 		return nil, taskmodel.ErrInternalTaskServiceError(err)
 	}
@@ -767,7 +865,8 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id platform.ID, upd tas
 	return task, nil
 }
 
-// DeleteTask removes a task by ID and purges all associated data and scheduled runs.
+// DeleteTask removes a task by ID along with its scheduled runs.
+// The task is kept in the trash so it can be restored until it is purged.
 func (s *Service) DeleteTask(ctx context.Context, id platform.ID) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
 		err := s.deleteTask(ctx, tx, id)
@@ -784,11 +883,6 @@ func (s *Service) DeleteTask(ctx context.Context, id platform.ID) error {
 }
 
 func (s *Service) deleteTask(ctx context.Context, tx Tx, id platform.ID) error {
-	taskBucket, err := tx.Bucket(taskBucket)
-	if err != nil {
-		return taskmodel.ErrUnexpectedTaskBucketErr(err)
-	}
-
 	runBucket, err := tx.Bucket(taskRunBucket)
 	if err != nil {
 		return taskmodel.ErrUnexpectedTaskBucketErr(err)
@@ -815,6 +909,11 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id platform.ID) error {
 		return taskmodel.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	// remove the tag index
+	if err := s.deleteTaskTags(ctx, tx, task.GetOrgID(), task.GetID(), task.GetTags()); err != nil {
+		return err
+	}
+
 	// remove latest completed
 	lastCompletedKey, err := taskLatestCompletedKey(task.GetID())
 	if err != nil {
@@ -826,11 +925,15 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id platform.ID) error {
 	}
 
 	// remove the runs
-	runs, _, err := s.findRuns(ctx, tx, taskmodel.RunFilter{Task: task.GetID()})
+	runs, err := s.currentlyRunning(ctx, tx, task.GetID())
 	if err != nil {
 		return err
 	}
 
+	if err := s.addOrgRunCount(ctx, tx, task.GetOrgID(), -len(runs)); err != nil {
+		return err
+	}
+
 	for _, run := range runs {
 		key, err := taskRunKey(task.GetID(), run.ID)
 		if err != nil {
@@ -841,16 +944,11 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id platform.ID) error {
 			return taskmodel.ErrUnexpectedTaskBucketErr(err)
 		}
 	}
-	// remove the task
-	key, err := taskKey(task.GetID())
-	if err != nil {
+	// move the task to the trash, its script history is kept until it is purged
+	if err := s.trashTask(ctx, tx, task.GetID()); err != nil {
 		return err
 	}
 
-	if err := taskBucket.Delete(key); err != nil {
-		return taskmodel.ErrUnexpectedTaskBucketErr(err)
-	}
-
 	uid, _ := icontext.GetUserID(ctx)
 	return s.audit.Log(resource.Change{
 		Type:           resource.Delete,
@@ -1100,11 +1198,21 @@ func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID platform.ID
 		return nil, err
 	}
 
+	task, err := s.findTaskByID(ctx, tx, taskID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOrgConcurrency(ctx, tx, task.GetOrgID()); err != nil {
+		return nil, err
+	}
+
 	r.ID = s.IDGenerator.ID()
 	r.Status = taskmodel.RunScheduled.String()
 	r.StartedAt = time.Time{}
 	r.FinishedAt = time.Time{}
 	r.RequestedAt = time.Time{}
+	r.Manual = true
 
 	// add a clean copy of the run to the manual runs
 	bucket, err := tx.Bucket(taskRunBucket)
@@ -1164,6 +1272,15 @@ func (s *Service) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor
 }
 
 func (s *Service) forceRun(ctx context.Context, tx Tx, taskID platform.ID, scheduledFor int64) (*taskmodel.Run, error) {
+	task, err := s.findTaskByID(ctx, tx, taskID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOrgConcurrency(ctx, tx, task.GetOrgID()); err != nil {
+		return nil, err
+	}
+
 	// create a run
 	t := time.Unix(scheduledFor, 0).UTC()
 	r := &taskmodel.Run{
@@ -1172,6 +1289,7 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID platform.ID, sched
 		Status:       taskmodel.RunScheduled.String(),
 		RequestedAt:  time.Now().UTC(),
 		ScheduledFor: t,
+		Manual:       true,
 		Log:          []taskmodel.Log{},
 	}
 
@@ -1225,10 +1343,58 @@ func (s *Service) CreateRun(ctx context.Context, taskID platform.ID, scheduledFo
 	})
 	return r, err
 }
+
+// CreateRuns creates many runs in a single transaction.
+// Either all of the runs are created or, if any of them fails, none of them.
+func (s *Service) CreateRuns(ctx context.Context, creates []taskmodel.RunCreate) ([]*taskmodel.Run, error) {
+	var runs []*taskmodel.Run
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		runs = make([]*taskmodel.Run, 0, len(creates))
+		for _, c := range creates {
+			run, err := s.createRun(ctx, tx, c.TaskID, c.ScheduledFor, c.RunAt)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
 func (s *Service) createRun(ctx context.Context, tx Tx, taskID platform.ID, scheduledFor time.Time, runAt time.Time) (*taskmodel.Run, error) {
-	id := s.IDGenerator.ID()
 	t := time.Unix(scheduledFor.Unix(), 0).UTC()
 
+	// A scheduler that retries or restarts can ask for the same run again,
+	// return the run that was already created for the scheduled time.
+	existing, err := s.scheduledRun(ctx, tx, taskID, t)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	task, err := s.findTaskByID(ctx, tx, taskID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// The run may also have finished already, in which case it is not run twice.
+	// Nothing is left to run up to the time of the latest completed run.
+	if !t.After(task.ToInfluxDB().LatestCompleted) {
+		return nil, taskmodel.ErrTaskRunAlreadyCompleted
+	}
+
+	if err := s.checkOrgConcurrency(ctx, tx, task.GetOrgID()); err != nil {
+		return nil, err
+	}
+
+	id := s.IDGenerator.ID()
+
 	run := taskmodel.Run{
 		ID:           id,
 		TaskID:       taskID,
@@ -1252,6 +1418,11 @@ func (s *Service) createRun(ctx context.Context, tx Tx, taskID platform.ID, sche
 	if err != nil {
 		return nil, err
 	}
+
+	if err := s.addOrgRunCount(ctx, tx, task.GetOrgID(), 1); err != nil {
+		return nil, err
+	}
+
 	if err := b.Put(runKey, runBytes); err != nil {
 		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
 	}
@@ -1259,6 +1430,168 @@ func (s *Service) createRun(ctx context.Context, tx Tx, taskID platform.ID, sche
 	return &run, nil
 }
 
+// scheduledRun returns the run of a task that the schedule created for scheduledFor
+// and that has not finished yet, or nil if there is none.
+func (s *Service) scheduledRun(ctx context.Context, tx Tx, taskID platform.ID, scheduledFor time.Time) (*taskmodel.Run, error) {
+	runs, err := s.currentlyRunning(ctx, tx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range runs {
+		if !r.Manual && r.ScheduledFor.Equal(scheduledFor) {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// orgQuota returns the task quota of an organization.
+func (s *Service) orgQuota(orgID platform.ID) taskmodel.OrgQuota {
+	if quota, ok := s.Config.TaskQuotaOverrides[orgID]; ok {
+		return quota
+	}
+	return s.Config.TaskQuota
+}
+
+// checkOrgConcurrency returns an error if the organization cannot have
+// another run scheduled or running.
+func (s *Service) checkOrgConcurrency(ctx context.Context, tx Tx, orgID platform.ID) error {
+	quota := s.orgQuota(orgID)
+	if quota.MaxConcurrency <= 0 {
+		return nil
+	}
+
+	n, err := s.orgRunCount(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	if n >= quota.MaxConcurrency {
+		return taskmodel.ErrOrgQuotaExceeded(orgID, "concurrent runs", quota.MaxConcurrency)
+	}
+	return nil
+}
+
+// orgRunCount returns the number of runs of the tasks of an organization that
+// are scheduled or running. The number is kept up to date as runs are created
+// and finished, the runs are only counted for organizations that have no
+// number stored yet, such as the ones with runs created by an older version.
+func (s *Service) orgRunCount(ctx context.Context, tx Tx, orgID platform.ID) (int, error) {
+	b, err := tx.Bucket(taskOrgRunsBucket)
+	if err != nil {
+		return 0, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := orgID.Encode()
+	if err != nil {
+		return 0, taskmodel.ErrInvalidTaskID
+	}
+
+	v, err := b.Get(key)
+	if err == nil {
+		var n int
+		if err := json.Unmarshal(v, &n); err != nil {
+			return 0, taskmodel.ErrInternalTaskServiceError(err)
+		}
+		return n, nil
+	}
+	if !IsNotFound(err) {
+		return 0, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	ids, err := s.orgTaskIDs(ctx, tx, orgID)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	for _, id := range ids {
+		runs, err := s.currentlyRunning(ctx, tx, id)
+		if err != nil {
+			return 0, err
+		}
+		n += len(runs)
+	}
+	return n, nil
+}
+
+// addOrgRunCount adds delta to the number of runs of the tasks of an organization
+// that are scheduled or running. It must be called before the runs are stored or removed.
+//
+// The number is only kept for organizations with a concurrency quota. For the others,
+// the number stored before is dropped, so the runs are counted again once a quota is set.
+func (s *Service) addOrgRunCount(ctx context.Context, tx Tx, orgID platform.ID, delta int) error {
+	b, err := tx.Bucket(taskOrgRunsBucket)
+	if err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := orgID.Encode()
+	if err != nil {
+		return taskmodel.ErrInvalidTaskID
+	}
+
+	if s.orgQuota(orgID).MaxConcurrency <= 0 {
+		if _, err := b.Get(key); err != nil {
+			if IsNotFound(err) {
+				return nil
+			}
+			return taskmodel.ErrUnexpectedTaskBucketErr(err)
+		}
+		if err := b.Delete(key); err != nil {
+			return taskmodel.ErrUnexpectedTaskBucketErr(err)
+		}
+		return nil
+	}
+
+	n, err := s.orgRunCount(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	if n += delta; n < 0 {
+		n = 0
+	}
+
+	v, err := json.Marshal(n)
+	if err != nil {
+		return taskmodel.ErrInternalTaskServiceError(err)
+	}
+
+	if err := b.Put(key, v); err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
+// orgTaskIDs returns the IDs of the tasks of an organization.
+func (s *Service) orgTaskIDs(ctx context.Context, tx Tx, orgID platform.ID) ([]platform.ID, error) {
+	indexBucket, err := tx.Bucket(taskIndexBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	prefix, err := orgID.Encode()
+	if err != nil {
+		return nil, taskmodel.ErrInvalidTaskID
+	}
+
+	c, err := indexBucket.ForwardCursor(prefix, WithCursorPrefix(prefix))
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+	defer c.Close()
+
+	var ids []platform.ID
+	for k, v := c.Next(); k != nil; k, v = c.Next() {
+		id, err := platform.IDFromString(string(v))
+		if err != nil {
+			return nil, taskmodel.ErrInvalidTaskID
+		}
+		ids = append(ids, *id)
+	}
+	return ids, c.Err()
+}
+
 func (s *Service) CurrentlyRunning(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error) {
 	var runs []*taskmodel.Run
 	err := s.kv.View(ctx, func(tx Tx) error {
@@ -1424,6 +1757,19 @@ func (s *Service) startManualRun(ctx context.Context, tx Tx, taskID, runID platf
 		return nil, err
 	}
 
+	task, err := s.findTaskByID(ctx, tx, taskID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOrgConcurrency(ctx, tx, task.GetOrgID()); err != nil {
+		return nil, err
+	}
+
+	if err := s.addOrgRunCount(ctx, tx, task.GetOrgID(), 1); err != nil {
+		return nil, err
+	}
+
 	if err := b.Put(runKey, mRunBytes); err != nil {
 		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
 	}
@@ -1445,6 +1791,27 @@ func (s *Service) FinishRun(ctx context.Context, taskID, runID platform.ID) (*ta
 	return run, err
 }
 
+// FinishRuns finishes many runs in a single transaction.
+// Either all of the runs are finished or, if any of them fails, none of them.
+func (s *Service) FinishRuns(ctx context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error) {
+	var runs []*taskmodel.Run
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		runs = make([]*taskmodel.Run, 0, len(refs))
+		for _, ref := range refs {
+			run, err := s.finishRun(ctx, tx, ref.TaskID, ref.RunID)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
 func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID platform.ID) (*taskmodel.Run, error) {
 	// get the run
 	r, err := s.findRunByID(ctx, tx, taskID, runID)
@@ -1463,7 +1830,7 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID platform.I
 		latestSuccess = &scheduled
 	}
 
-	_, err = s.updateTask(ctx, tx, taskID, taskmodel.TaskUpdate{
+	task, err := s.updateTask(ctx, tx, taskID, taskmodel.TaskUpdate{
 		LatestCompleted: &scheduled,
 		LatestSuccess:   latestSuccess,
 		LatestFailure:   latestFailure,
@@ -1485,6 +1852,10 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID platform.I
 		return nil, err
 	}
 
+	if err := s.addOrgRunCount(ctx, tx, task.OrganizationID, -1); err != nil {
+		return nil, err
+	}
+
 	// remove run
 	bucket, err := tx.Bucket(taskRunBucket)
 	if err != nil {
@@ -1572,6 +1943,9 @@ func (s *Service) addRunLog(ctx context.Context, tx Tx, taskID, runID platform.I
 	// update log
 	l := taskmodel.Log{RunID: runID, Time: when.Format(time.RFC3339Nano), Message: log}
 	run.Log = append(run.Log, l)
+	if len(run.Log) > MaxRunLogs {
+		run.Log = append(run.Log[:0], run.Log[len(run.Log)-MaxRunLogs:]...)
+	}
 	// save run
 	b, err := tx.Bucket(taskRunBucket)
 	if err != nil {
@@ -1595,6 +1969,97 @@ func (s *Service) addRunLog(ctx context.Context, tx Tx, taskID, runID platform.I
 	return nil
 }
 
+// ExpireRuns fails and finishes every run that has been started for longer than olderThan.
+// This cleans up runs left behind by a process that stopped before it could finish them,
+// so they no longer count against the concurrency limit of their task.
+func (s *Service) ExpireRuns(ctx context.Context, olderThan time.Duration) ([]*taskmodel.Run, error) {
+	var runs []*taskmodel.Run
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		rs, err := s.expireRuns(ctx, tx, s.clock.Now().UTC().Add(-olderThan))
+		if err != nil {
+			return err
+		}
+		runs = rs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func (s *Service) expireRuns(ctx context.Context, tx Tx, before time.Time) ([]*taskmodel.Run, error) {
+	bucket, err := tx.Bucket(taskRunBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	c, err := bucket.ForwardCursor(nil)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// free cursor resources
+	defer c.Close()
+
+	// collect the runs before modifying the bucket the cursor is walking
+	var stale []*taskmodel.Run
+	for k, v := c.Next(); k != nil; k, v = c.Next() {
+		if strings.HasSuffix(string(k), "manualRuns") || strings.HasSuffix(string(k), "latestCompleted") {
+			continue
+		}
+
+		r := &taskmodel.Run{}
+		if err := json.Unmarshal(v, r); err != nil {
+			return nil, taskmodel.ErrInternalTaskServiceError(err)
+		}
+
+		// runs that are still queued have not been claimed by an executor yet
+		if !r.StartedAt.IsZero() && r.StartedAt.Before(before) {
+			stale = append(stale, r)
+		}
+	}
+	if err := c.Err(); err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	now := s.clock.Now().UTC()
+	runs := make([]*taskmodel.Run, 0, len(stale))
+	for _, r := range stale {
+		if err := s.addRunLog(ctx, tx, r.TaskID, r.ID, now, "Run expired before it finished"); err != nil {
+			return nil, err
+		}
+		if err := s.updateRunState(ctx, tx, r.TaskID, r.ID, now, taskmodel.RunFail); err != nil {
+			return nil, err
+		}
+		// finish with the same line as the executor, so that the expiry is
+		// reported as the last run error
+		if err := s.addRunLog(ctx, tx, r.TaskID, r.ID, now, fmt.Sprintf("Completed(%s)", taskmodel.RunFail)); err != nil {
+			return nil, err
+		}
+
+		run, err := s.finishRun(ctx, tx, r.TaskID, r.ID)
+		if err == taskmodel.ErrTaskNotFound {
+			// the task is gone, drop the run with it
+			key, err := taskRunKey(r.TaskID, r.ID)
+			if err != nil {
+				return nil, err
+			}
+			if err := bucket.Delete(key); err != nil {
+				return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
 func taskKey(taskID platform.ID) ([]byte, error) {
 	encodedID, err := taskID.Encode()
 	if err != nil {
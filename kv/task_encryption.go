@@ -0,0 +1,129 @@
+package kv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+)
+
+// encryptedTaskValuePrefix marks a task name or script that is stored encrypted.
+// Values without the prefix were written without a task cipher and are read as is,
+// so the encryption can be turned on for an existing store.
+const encryptedTaskValuePrefix = "aesgcm:"
+
+// NewTaskCipher returns an AES-GCM cipher to encrypt the names and scripts of tasks at rest.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256.
+func NewTaskCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// marshalTask encodes a task for storage, encrypting its name and script
+// when the service has a task cipher.
+func (s *Service) marshalTask(task *taskmodel.Task) ([]byte, error) {
+	if s.Config.TaskCipher == nil {
+		return json.Marshal(task)
+	}
+
+	var err error
+	enc := *task
+	if enc.Name, err = s.encryptTaskValue(task.Name); err != nil {
+		return nil, err
+	}
+	if enc.Flux, err = s.encryptTaskValue(task.Flux); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&enc)
+}
+
+// unmarshalTask decodes a stored task into t and decrypts its name and script.
+func (s *Service) unmarshalTask(v []byte, t matchableTask) error {
+	if err := json.Unmarshal(v, t); err != nil {
+		return err
+	}
+
+	var err error
+	switch t := t.(type) {
+	case *basicKvTask:
+		t.Name, err = s.decryptTaskValue(t.Name)
+	case *kvTask:
+		if t.Name, err = s.decryptTaskValue(t.Name); err != nil {
+			return err
+		}
+		t.Flux, err = s.decryptTaskValue(t.Flux)
+	}
+	return err
+}
+
+// marshalTaskRevision encodes a task revision for storage, encrypting its script
+// when the service has a task cipher.
+func (s *Service) marshalTaskRevision(rev *taskmodel.TaskRevision) ([]byte, error) {
+	if s.Config.TaskCipher == nil {
+		return json.Marshal(rev)
+	}
+
+	var err error
+	enc := *rev
+	if enc.Flux, err = s.encryptTaskValue(rev.Flux); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&enc)
+}
+
+// unmarshalTaskRevision decodes a stored task revision into rev and decrypts its script.
+func (s *Service) unmarshalTaskRevision(v []byte, rev *taskmodel.TaskRevision) error {
+	if err := json.Unmarshal(v, rev); err != nil {
+		return err
+	}
+
+	var err error
+	rev.Flux, err = s.decryptTaskValue(rev.Flux)
+	return err
+}
+
+func (s *Service) encryptTaskValue(v string) (string, error) {
+	aead := s.Config.TaskCipher
+
+	// The nonce is stored in front of the sealed value.
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(v)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(v), nil)
+	return encryptedTaskValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *Service) decryptTaskValue(v string) (string, error) {
+	if !strings.HasPrefix(v, encryptedTaskValuePrefix) {
+		return v, nil
+	}
+
+	aead := s.Config.TaskCipher
+	if aead == nil {
+		return "", fmt.Errorf("task value is encrypted but no task encryption key is configured")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(v, encryptedTaskValuePrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("encrypted task value is too short")
+	}
+
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	b, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt task value: %w", err)
+	}
+	return string(b), nil
+}
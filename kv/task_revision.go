@@ -0,0 +1,191 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+)
+
+// Task Revision Storage Schema
+// taskRevisionBucket:
+//   <taskID>/<revision>: flux script of the task prior to the update that created the revision
+
+var taskRevisionBucket = []byte("taskRevisionsv1")
+
+var _ taskmodel.TaskRevisionService = (*Service)(nil)
+
+// FindTaskRevisions returns the previous scripts of a task, oldest first.
+func (s *Service) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*taskmodel.TaskRevision, error) {
+	var revs []*taskmodel.TaskRevision
+	err := s.kv.View(ctx, func(tx Tx) error {
+		// ensure the task exists
+		if _, err := s.findTaskByID(ctx, tx, taskID, true); err != nil {
+			return err
+		}
+
+		rs, err := s.findTaskRevisions(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		revs = rs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return revs, nil
+}
+
+func (s *Service) findTaskRevisions(ctx context.Context, tx Tx, taskID platform.ID) ([]*taskmodel.TaskRevision, error) {
+	b, err := tx.Bucket(taskRevisionBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	prefix, err := taskRevisionPrefix(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := b.ForwardCursor(prefix, WithCursorPrefix(prefix))
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// free cursor resources
+	defer c.Close()
+
+	var revs []*taskmodel.TaskRevision
+	for k, v := c.Next(); k != nil; k, v = c.Next() {
+		rev := &taskmodel.TaskRevision{}
+		if err := s.unmarshalTaskRevision(v, rev); err != nil {
+			return nil, taskmodel.ErrInternalTaskServiceError(err)
+		}
+		revs = append(revs, rev)
+	}
+
+	return revs, c.Err()
+}
+
+func (s *Service) findTaskRevision(ctx context.Context, tx Tx, taskID platform.ID, revision int64) (*taskmodel.TaskRevision, error) {
+	b, err := tx.Bucket(taskRevisionBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskRevisionKey(taskID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, taskmodel.ErrTaskRevisionNotFound
+	}
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	rev := &taskmodel.TaskRevision{}
+	if err := s.unmarshalTaskRevision(v, rev); err != nil {
+		return nil, taskmodel.ErrInternalTaskServiceError(err)
+	}
+
+	return rev, nil
+}
+
+func (s *Service) putTaskRevision(ctx context.Context, tx Tx, rev *taskmodel.TaskRevision) error {
+	b, err := tx.Bucket(taskRevisionBucket)
+	if err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskRevisionKey(rev.TaskID, rev.Revision)
+	if err != nil {
+		return err
+	}
+
+	revBytes, err := s.marshalTaskRevision(rev)
+	if err != nil {
+		return taskmodel.ErrInternalTaskServiceError(err)
+	}
+
+	if err := b.Put(key, revBytes); err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	return nil
+}
+
+func (s *Service) deleteTaskRevisions(ctx context.Context, tx Tx, taskID platform.ID) error {
+	revs, err := s.findTaskRevisions(ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(taskRevisionBucket)
+	if err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	for _, rev := range revs {
+		key, err := taskRevisionKey(taskID, rev.Revision)
+		if err != nil {
+			return err
+		}
+
+		if err := b.Delete(key); err != nil {
+			return taskmodel.ErrUnexpectedTaskBucketErr(err)
+		}
+	}
+
+	return nil
+}
+
+// RollbackTask restores the script of a task to the given revision.
+// The script being replaced is kept as a new revision.
+func (s *Service) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*taskmodel.Task, error) {
+	var t *taskmodel.Task
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		rev, err := s.findTaskRevision(ctx, tx, taskID, revision)
+		if err != nil {
+			return err
+		}
+
+		task, err := s.updateTask(ctx, tx, taskID, taskmodel.TaskUpdate{Flux: &rev.Flux})
+		if err != nil {
+			return err
+		}
+		t = task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func taskRevisionPrefix(taskID platform.ID) ([]byte, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, taskmodel.ErrInvalidTaskID
+	}
+	return append(encodedID, '/'), nil
+}
+
+func taskRevisionKey(taskID platform.ID, revision int64) ([]byte, error) {
+	prefix, err := taskRevisionPrefix(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// big endian keeps the revisions of a task ordered in the bucket
+	var encodedRev [8]byte
+	binary.BigEndian.PutUint64(encodedRev[:], uint64(revision))
+
+	return append(prefix, encodedRev[:]...), nil
+}
@@ -0,0 +1,167 @@
+package kv
+
+import (
+	"context"
+	"sort"
+
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+)
+
+var taskTagIndexBucket = []byte("taskTagIndexv1")
+
+// findTasksByTags is a subset of the find tasks function. It walks the tag index
+// of the organization for one of the requested tags and matches the remaining
+// filters against each task.
+func (s *Service) findTasksByTags(ctx context.Context, tx Tx, orgID platform.ID, filter taskmodel.TaskFilter) ([]*taskmodel.Task, int, error) {
+	indexBucket, err := tx.Bucket(taskTagIndexBucket)
+	if err != nil {
+		return nil, 0, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// pick the tag deterministically, all of them are checked by the match function.
+	keys := make([]string, 0, len(filter.Tags))
+	for k := range filter.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	prefix, err := taskTagIndexPrefix(orgID, keys[0], filter.Tags[keys[0]])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		key  = prefix
+		opts []CursorOption
+	)
+	if filter.After != nil {
+		key, err = taskTagIndexKey(orgID, keys[0], filter.Tags[keys[0]], *filter.After)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		opts = append(opts, WithCursorSkipFirstItem())
+	}
+
+	c, err := indexBucket.ForwardCursor(key, append(opts, WithCursorPrefix(prefix))...)
+	if err != nil {
+		return nil, 0, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// free cursor resources
+	defer c.Close()
+
+	matchFn := newTaskMatchFn(filter)
+
+	var ts []*taskmodel.Task
+	for k, v := c.Next(); k != nil; k, v = c.Next() {
+		var id platform.ID
+		if err := id.Decode(v); err != nil {
+			return nil, 0, taskmodel.ErrInvalidTaskID
+		}
+
+		t, err := s.findTaskByID(ctx, tx, id, filter.Type != nil && *filter.Type == taskmodel.TaskBasicType)
+		if err != nil {
+			if err == taskmodel.ErrTaskNotFound {
+				// we might have some crufty index's
+				continue
+			}
+			return nil, 0, err
+		}
+
+		if matchFn(t) {
+			ts = append(ts, t.ToInfluxDB())
+			// Check if we are over running the limit
+			if len(ts) >= filter.Limit {
+				break
+			}
+		}
+	}
+
+	return ts, len(ts), c.Err()
+}
+
+func (s *Service) putTaskTags(ctx context.Context, tx Tx, orgID, taskID platform.ID, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	indexBucket, err := tx.Bucket(taskTagIndexBucket)
+	if err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return taskmodel.ErrInvalidTaskID
+	}
+
+	for k, v := range tags {
+		key, err := taskTagIndexKey(orgID, k, v, taskID)
+		if err != nil {
+			return err
+		}
+
+		if err := indexBucket.Put(key, encodedID); err != nil {
+			return taskmodel.ErrUnexpectedTaskBucketErr(err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) deleteTaskTags(ctx context.Context, tx Tx, orgID, taskID platform.ID, tags map[string]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	indexBucket, err := tx.Bucket(taskTagIndexBucket)
+	if err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	for k, v := range tags {
+		key, err := taskTagIndexKey(orgID, k, v, taskID)
+		if err != nil {
+			return err
+		}
+
+		if err := indexBucket.Delete(key); err != nil {
+			return taskmodel.ErrUnexpectedTaskBucketErr(err)
+		}
+	}
+
+	return nil
+}
+
+// taskTagIndexPrefix scopes the index to the organization, and separates the tag
+// key and value by a NUL byte so that neither can be confused with the other,
+// regardless of their contents.
+func taskTagIndexPrefix(orgID platform.ID, key, value string) ([]byte, error) {
+	encodedOrgID, err := orgID.Encode()
+	if err != nil {
+		return nil, taskmodel.ErrInvalidTaskID
+	}
+
+	prefix := make([]byte, 0, len(encodedOrgID)+len(key)+len(value)+2)
+	prefix = append(prefix, encodedOrgID...)
+	prefix = append(prefix, key...)
+	prefix = append(prefix, 0)
+	prefix = append(prefix, value...)
+	return append(prefix, 0), nil
+}
+
+func taskTagIndexKey(orgID platform.ID, key, value string, taskID platform.ID) ([]byte, error) {
+	prefix, err := taskTagIndexPrefix(orgID, key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, taskmodel.ErrInvalidTaskID
+	}
+
+	return append(prefix, encodedID...), nil
+}
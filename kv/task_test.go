@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 	icontext "github.com/influxdata/influxdb/v2/context"
 	_ "github.com/influxdata/influxdb/v2/fluxinit/static"
 	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kit/platform/errors"
 	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/query/fluxlang"
 	"github.com/influxdata/influxdb/v2/task/options"
@@ -72,7 +76,7 @@ type testService struct {
 	Clock   clock.Clock
 }
 
-func newService(t *testing.T, ctx context.Context, c clock.Clock) *testService {
+func newService(t testing.TB, ctx context.Context, c clock.Clock) *testService {
 	t.Helper()
 
 	if c == nil {
@@ -256,6 +260,720 @@ func TestService_UpdateTask_InactiveToActive(t *testing.T) {
 	}
 }
 
+func TestService_UpdateTask_VersionConflict(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+		Status:         string(taskmodel.TaskActive),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), task.Version)
+
+	// two users read the same version of the task
+	version := task.Version
+	first, second := "first", "second"
+
+	updated, err := ts.Service.UpdateTask(ctx, task.ID, taskmodel.TaskUpdate{Description: &first, Version: &version})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), updated.Version)
+
+	// the second update is based on a stale version and must be rejected
+	_, err = ts.Service.UpdateTask(ctx, task.ID, taskmodel.TaskUpdate{Description: &second, Version: &version})
+	require.Equal(t, taskmodel.ErrTaskConflict, err)
+
+	found, err := ts.Service.FindTaskByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.Equal(t, first, found.Description)
+	require.Equal(t, int64(2), found.Version)
+
+	// updates without a version are applied unconditionally
+	updated, err = ts.Service.UpdateTask(ctx, task.ID, taskmodel.TaskUpdate{Description: &second})
+	require.NoError(t, err)
+	require.Equal(t, second, updated.Description)
+	require.Equal(t, int64(3), updated.Version)
+}
+
+func TestService_TaskRevisions(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	original := `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           original,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+		Status:         string(taskmodel.TaskActive),
+	})
+	require.NoError(t, err)
+
+	revs, err := ts.Service.FindTaskRevisions(ctx, task.ID)
+	require.NoError(t, err)
+	require.Empty(t, revs)
+
+	// editing the description does not record a revision
+	desc := "description"
+	_, err = ts.Service.UpdateTask(ctx, task.ID, taskmodel.TaskUpdate{Description: &desc})
+	require.NoError(t, err)
+
+	edited := `option task = {name: "a task",every: 1h} from(bucket:"oops") |> range(start:-1h)`
+	updated, err := ts.Service.UpdateTask(ctx, task.ID, taskmodel.TaskUpdate{Flux: &edited})
+	require.NoError(t, err)
+	require.Equal(t, edited, updated.Flux)
+
+	revs, err = ts.Service.FindTaskRevisions(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, revs, 1)
+	assert.Equal(t, task.ID, revs[0].TaskID)
+	assert.Equal(t, int64(2), revs[0].Revision)
+	assert.Equal(t, original, revs[0].Flux)
+
+	restored, err := ts.Service.RollbackTask(ctx, task.ID, revs[0].Revision)
+	require.NoError(t, err)
+	assert.Equal(t, original, restored.Flux)
+
+	// the rolled back script is kept as well
+	revs, err = ts.Service.FindTaskRevisions(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, revs, 2)
+	assert.Equal(t, edited, revs[1].Flux)
+
+	_, err = ts.Service.RollbackTask(ctx, task.ID, 100)
+	require.Equal(t, taskmodel.ErrTaskRevisionNotFound, err)
+
+	require.NoError(t, ts.Service.DeleteTask(ctx, task.ID))
+	_, err = ts.Service.FindTaskRevisions(ctx, task.ID)
+	require.Equal(t, taskmodel.ErrTaskNotFound, err)
+}
+
+func TestService_FindTasks_Tags(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	create := func(name string, tags map[string]string) *taskmodel.Task {
+		t.Helper()
+
+		task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+			Flux:           fmt.Sprintf(`option task = {name: %q,every: 1h} from(bucket:"test") |> range(start:-1h)`, name),
+			OrganizationID: ts.Org.ID,
+			OwnerID:        ts.User.ID,
+			Tags:           tags,
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	a := create("a", map[string]string{"team": "ops", "env": "prod"})
+	b := create("b", map[string]string{"team": "ops", "env": "dev"})
+	c := create("c", map[string]string{"team": "web", "env": "prod"})
+	create("d", nil)
+
+	find := func(tags map[string]string) []platform.ID {
+		t.Helper()
+
+		tasks, _, err := ts.Service.FindTasks(ctx, taskmodel.TaskFilter{OrganizationID: &ts.Org.ID, Tags: tags})
+		require.NoError(t, err)
+
+		ids := make([]platform.ID, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		return ids
+	}
+
+	assert.Equal(t, []platform.ID{a.ID, b.ID}, find(map[string]string{"team": "ops"}))
+	assert.Equal(t, []platform.ID{a.ID}, find(map[string]string{"team": "ops", "env": "prod"}))
+	assert.Empty(t, find(map[string]string{"team": "db"}))
+
+	// the tags of the tasks of other orgs are not visible
+	other := influxdb.Organization{Name: t.Name() + "-other-org"}
+	require.NoError(t, tenant.NewService(tenant.NewStore(ts.Store.(kv.SchemaStore))).CreateOrganization(ctx, &other))
+	_, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           `option task = {name: "e",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: other.ID,
+		OwnerID:        ts.User.ID,
+		Tags:           map[string]string{"team": "ops"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []platform.ID{a.ID, b.ID}, find(map[string]string{"team": "ops"}))
+
+	// retagging a task moves it in the index
+	_, err = ts.Service.UpdateTask(ctx, b.ID, taskmodel.TaskUpdate{Tags: &map[string]string{"team": "web"}})
+	require.NoError(t, err)
+	assert.Equal(t, []platform.ID{a.ID}, find(map[string]string{"team": "ops"}))
+
+	// an empty map clears the tags
+	b, err = ts.Service.UpdateTask(ctx, b.ID, taskmodel.TaskUpdate{Tags: &map[string]string{}})
+	require.NoError(t, err)
+	assert.Empty(t, b.Tags)
+	assert.Equal(t, []platform.ID{c.ID}, find(map[string]string{"team": "web"}))
+
+	require.NoError(t, ts.Service.DeleteTask(ctx, a.ID))
+	assert.Empty(t, find(map[string]string{"team": "ops"}))
+}
+
+func TestService_ExpireRuns(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	c := clock.NewMock()
+	c.Set(time.Unix(10000, 0))
+
+	ts := newService(t, ctx, c)
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+		Status:         string(taskmodel.TaskActive),
+	})
+	require.NoError(t, err)
+
+	// the runs are scheduled after the task was created
+	c.Add(4 * time.Hour)
+
+	stale, err := ts.Service.CreateRun(ctx, task.ID, c.Now().Add(-time.Hour), c.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, ts.Service.UpdateRunState(ctx, task.ID, stale.ID, c.Now().Add(-time.Hour), taskmodel.RunStarted))
+
+	logged, err := ts.Service.CreateRun(ctx, task.ID, c.Now().Add(-2*time.Hour), c.Now().Add(-2*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, ts.Service.UpdateRunState(ctx, task.ID, logged.ID, c.Now().Add(-2*time.Hour), taskmodel.RunStarted))
+	require.NoError(t, ts.Service.AddRunLog(ctx, task.ID, logged.ID, c.Now().Add(-2*time.Hour), "Started task from script"))
+
+	// a run that was never started is still waiting for an executor
+	queued, err := ts.Service.CreateRun(ctx, task.ID, c.Now().Add(-3*time.Hour), c.Now().Add(-3*time.Hour))
+	require.NoError(t, err)
+
+	fresh, err := ts.Service.CreateRun(ctx, task.ID, c.Now(), c.Now())
+	require.NoError(t, err)
+	require.NoError(t, ts.Service.UpdateRunState(ctx, task.ID, fresh.ID, c.Now(), taskmodel.RunStarted))
+
+	expired, err := ts.Service.ExpireRuns(ctx, 10*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, expired, 2)
+	for _, r := range expired {
+		assert.Contains(t, []platform.ID{stale.ID, logged.ID}, r.ID)
+		assert.Equal(t, taskmodel.RunFail.String(), r.Status)
+		if r.ID == logged.ID {
+			require.Len(t, r.Log, 3)
+			assert.Equal(t, "Run expired before it finished", r.Log[1].Message)
+		}
+	}
+
+	runs, err := ts.Service.CurrentlyRunning(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	for _, r := range runs {
+		assert.Contains(t, []platform.ID{queued.ID, fresh.ID}, r.ID)
+	}
+
+	task, err = ts.Service.FindTaskByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, taskmodel.RunFail.String(), task.LastRunStatus)
+	assert.Equal(t, "Run expired before it finished", task.LastRunError)
+}
+
+func TestService_AddRunLog_Bounded(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	run, err := ts.Service.CreateRun(ctx, task.ID, now, now)
+	require.NoError(t, err)
+
+	for i := 0; i < kv.MaxRunLogs+5; i++ {
+		require.NoError(t, ts.Service.AddRunLog(ctx, task.ID, run.ID, now, fmt.Sprintf("line %d", i)))
+	}
+
+	logs, n, err := ts.Service.FindLogs(ctx, taskmodel.LogFilter{Task: task.ID, Run: &run.ID})
+	require.NoError(t, err)
+	require.Equal(t, kv.MaxRunLogs, n)
+	assert.Equal(t, "line 5", logs[0].Message)
+	assert.Equal(t, fmt.Sprintf("line %d", kv.MaxRunLogs+4), logs[n-1].Message)
+}
+
+func TestService_CreateRun_Idempotent(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	run, err := ts.Service.CreateRun(ctx, task.ID, now, now)
+	require.NoError(t, err)
+
+	// creating the run for the same time again returns the queued run
+	again, err := ts.Service.CreateRun(ctx, task.ID, now, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, run.ID, again.ID)
+
+	running, err := ts.Service.CurrentlyRunning(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, running, 1)
+
+	// once the run finished it is not created again
+	_, err = ts.Service.FinishRun(ctx, task.ID, run.ID)
+	require.NoError(t, err)
+
+	_, err = ts.Service.CreateRun(ctx, task.ID, now, now)
+	assert.Equal(t, errors.EConflict, errors.ErrorCode(err))
+
+	// neither is a run scheduled before it
+	_, err = ts.Service.CreateRun(ctx, task.ID, now.Add(-time.Second), now)
+	assert.Equal(t, errors.EConflict, errors.ErrorCode(err))
+
+	next, err := ts.Service.CreateRun(ctx, task.ID, now.Add(time.Second), now)
+	require.NoError(t, err)
+	assert.NotEqual(t, run.ID, next.ID)
+}
+
+func TestService_CreateRunsFinishRuns(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	creates := make([]taskmodel.RunCreate, 3)
+	for i := range creates {
+		creates[i] = taskmodel.RunCreate{TaskID: task.ID, ScheduledFor: now.Add(time.Duration(i+1) * time.Hour), RunAt: now}
+	}
+
+	runs, err := ts.Service.CreateRuns(ctx, creates)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+
+	running, err := ts.Service.CurrentlyRunning(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, running, 3)
+
+	// a failing run aborts the whole batch
+	refs := []taskmodel.RunRef{{TaskID: task.ID, RunID: runs[0].ID}, {TaskID: task.ID, RunID: platform.ID(math.MaxUint64)}}
+	_, err = ts.Service.FinishRuns(ctx, refs)
+	require.Error(t, err)
+
+	running, err = ts.Service.CurrentlyRunning(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, running, 3)
+
+	refs = make([]taskmodel.RunRef, len(runs))
+	for i, r := range runs {
+		refs[i] = taskmodel.RunRef{TaskID: task.ID, RunID: r.ID}
+	}
+	finished, err := ts.Service.FinishRuns(ctx, refs)
+	require.NoError(t, err)
+	require.Len(t, finished, 3)
+
+	running, err = ts.Service.CurrentlyRunning(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, running, 0)
+
+	found, err := ts.Service.FindTaskByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(3*time.Hour), found.LatestCompleted)
+}
+
+func BenchmarkService_CreateFinishRuns(b *testing.B) {
+	const runsPerOp = 100
+
+	for _, batch := range []bool{false, true} {
+		name := "single"
+		if batch {
+			name = "batch"
+		}
+		b.Run(name, func(b *testing.B) {
+			ctx := context.Background()
+			ts := newService(b, ctx, nil)
+			ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+			task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+				Flux:           `option task = {name: "a task",every: 1s} from(bucket:"test") |> range(start:-1h)`,
+				OrganizationID: ts.Org.ID,
+				OwnerID:        ts.User.ID,
+			})
+			require.NoError(b, err)
+
+			start := time.Now().UTC().Truncate(time.Second)
+			creates := make([]taskmodel.RunCreate, runsPerOp)
+			refs := make([]taskmodel.RunRef, runsPerOp)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := range creates {
+					scheduledFor := start.Add(time.Duration(i*runsPerOp+j+1) * time.Second)
+					creates[j] = taskmodel.RunCreate{TaskID: task.ID, ScheduledFor: scheduledFor, RunAt: scheduledFor}
+				}
+
+				if batch {
+					runs, err := ts.Service.CreateRuns(ctx, creates)
+					require.NoError(b, err)
+					for j, r := range runs {
+						refs[j] = taskmodel.RunRef{TaskID: task.ID, RunID: r.ID}
+					}
+					_, err = ts.Service.FinishRuns(ctx, refs)
+					require.NoError(b, err)
+					continue
+				}
+
+				for j, c := range creates {
+					r, err := ts.Service.CreateRun(ctx, c.TaskID, c.ScheduledFor, c.RunAt)
+					require.NoError(b, err)
+					refs[j] = taskmodel.RunRef{TaskID: task.ID, RunID: r.ID}
+				}
+				for _, ref := range refs {
+					_, err := ts.Service.FinishRun(ctx, ref.TaskID, ref.RunID)
+					require.NoError(b, err)
+				}
+			}
+		})
+	}
+}
+
+func TestService_OrgQuota(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	ts.Service.Config.TaskQuota = taskmodel.OrgQuota{MaxTasks: 1, MaxConcurrency: 2}
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	tc := taskmodel.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	}
+	task, err := ts.Service.CreateTask(ctx, tc)
+	require.NoError(t, err)
+
+	_, err = ts.Service.CreateTask(ctx, tc)
+	require.Error(t, err)
+	assert.Equal(t, errors.ETooManyRequests, errors.ErrorCode(err))
+
+	now := time.Now().UTC()
+	var runs []*taskmodel.Run
+	for i := 0; i < 2; i++ {
+		run, err := ts.Service.CreateRun(ctx, task.ID, now.Add(time.Duration(i)*time.Hour), now)
+		require.NoError(t, err)
+		runs = append(runs, run)
+	}
+
+	_, err = ts.Service.CreateRun(ctx, task.ID, now.Add(2*time.Hour), now)
+	require.Error(t, err)
+	assert.Equal(t, errors.ETooManyRequests, errors.ErrorCode(err))
+
+	// finished runs no longer count against the quota
+	_, err = ts.Service.FinishRun(ctx, task.ID, runs[0].ID)
+	require.NoError(t, err)
+	_, err = ts.Service.CreateRun(ctx, task.ID, now.Add(2*time.Hour), now)
+	require.NoError(t, err)
+	_, err = ts.Service.CreateRun(ctx, task.ID, now.Add(3*time.Hour), now)
+	assert.Equal(t, errors.ETooManyRequests, errors.ErrorCode(err))
+
+	// other organizations can be given a different quota
+	ts.Service.Config.TaskQuotaOverrides = map[platform.ID]taskmodel.OrgQuota{ts.Org.ID: {}}
+	_, err = ts.Service.CreateRun(ctx, task.ID, now.Add(3*time.Hour), now)
+	require.NoError(t, err)
+
+	// the runs of a deleted task no longer count either
+	require.NoError(t, ts.Service.DeleteTask(ctx, task.ID))
+	ts.Service.Config.TaskQuotaOverrides = nil
+	task, err = ts.Service.CreateTask(ctx, tc)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err := ts.Service.CreateRun(ctx, task.ID, now.Add(time.Duration(i+1)*time.Hour), now)
+		require.NoError(t, err)
+	}
+}
+
+func TestService_OrgQuotaManualRuns(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	ts.Service.Config.TaskQuota = taskmodel.OrgQuota{MaxConcurrency: 1}
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	manual, err := ts.Service.ForceRun(ctx, task.ID, now.Unix())
+	require.NoError(t, err)
+	run, err := ts.Service.StartManualRun(ctx, task.ID, manual.ID)
+	require.NoError(t, err)
+
+	// manual runs cannot be queued or started past the quota either
+	_, err = ts.Service.ForceRun(ctx, task.ID, now.Add(time.Hour).Unix())
+	assert.Equal(t, errors.ETooManyRequests, errors.ErrorCode(err))
+	_, err = ts.Service.RetryRun(ctx, task.ID, run.ID)
+	assert.Equal(t, errors.ETooManyRequests, errors.ErrorCode(err))
+
+	ts.Service.Config.TaskQuota = taskmodel.OrgQuota{}
+	queued, err := ts.Service.ForceRun(ctx, task.ID, now.Add(time.Hour).Unix())
+	require.NoError(t, err)
+	ts.Service.Config.TaskQuota = taskmodel.OrgQuota{MaxConcurrency: 1}
+	_, err = ts.Service.StartManualRun(ctx, task.ID, queued.ID)
+	assert.Equal(t, errors.ETooManyRequests, errors.ErrorCode(err))
+
+	// the runs finished while the quota is off are still accounted for once it is set again
+	ts.Service.Config.TaskQuota = taskmodel.OrgQuota{}
+	_, err = ts.Service.FinishRun(ctx, task.ID, run.ID)
+	require.NoError(t, err)
+	ts.Service.Config.TaskQuota = taskmodel.OrgQuota{MaxConcurrency: 1}
+	_, err = ts.Service.StartManualRun(ctx, task.ID, queued.ID)
+	require.NoError(t, err)
+}
+
+func TestService_TaskEncryption(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+	aead, err := kv.NewTaskCipher([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+	ts.Service.Config.TaskCipher = aead
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	script := `option task = {name: "secret task",every: 1h} from(bucket:"test") |> range(start:-1h) |> yield(name: "secret-token")`
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           script,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+	})
+	require.NoError(t, err)
+
+	newScript := strings.Replace(script, "secret-token", "other-secret-token", 1)
+	_, err = ts.Service.UpdateTask(ctx, task.ID, taskmodel.TaskUpdate{Flux: &newScript})
+	require.NoError(t, err)
+
+	// neither the name nor the scripts are stored in plain text
+	err = ts.Store.View(ctx, func(tx kv.Tx) error {
+		for _, bucket := range []string{"tasksv1", "taskRevisionsv1"} {
+			b, err := tx.Bucket([]byte(bucket))
+			require.NoError(t, err)
+			c, err := b.ForwardCursor(nil)
+			require.NoError(t, err)
+			for k, v := c.Next(); k != nil; k, v = c.Next() {
+				assert.NotContains(t, string(v), "secret")
+			}
+			require.NoError(t, c.Close())
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	found, err := ts.Service.FindTaskByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "secret task", found.Name)
+	assert.Equal(t, newScript, found.Flux)
+
+	name := "secret task"
+	tasks, _, err := ts.Service.FindTasks(ctx, taskmodel.TaskFilter{Name: &name})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task.ID, tasks[0].ID)
+
+	revs, err := ts.Service.FindTaskRevisions(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, revs, 1)
+	assert.Equal(t, script, revs[0].Flux)
+
+	// the tasks cannot be read without the key
+	ts.Service.Config.TaskCipher = nil
+	_, err = ts.Service.FindTaskByID(ctx, task.ID)
+	require.Error(t, err)
+}
+
+func TestService_DeleteRestorePurgeTask(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	c := clock.NewMock()
+	c.Set(time.Unix(1000, 0))
+	ts := newService(t, ctx, c)
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	tags := map[string]string{"team": "db"}
+	script := `option task = {name: "a task",every: 1h} from(bucket:"test") |> range(start:-1h)`
+	task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+		Flux:           script,
+		OrganizationID: ts.Org.ID,
+		OwnerID:        ts.User.ID,
+		Tags:           tags,
+	})
+	require.NoError(t, err)
+
+	newScript := strings.Replace(script, "-1h", "-2h", 1)
+	_, err = ts.Service.UpdateTask(ctx, task.ID, taskmodel.TaskUpdate{Flux: &newScript})
+	require.NoError(t, err)
+
+	c.Add(time.Hour)
+	_, err = ts.Service.CreateRun(ctx, task.ID, c.Now(), c.Now())
+	require.NoError(t, err)
+
+	require.NoError(t, ts.Service.DeleteTask(ctx, task.ID))
+
+	// a deleted task cannot be found through the task service or its indexes
+	_, err = ts.Service.FindTaskByID(ctx, task.ID)
+	assert.Equal(t, taskmodel.ErrTaskNotFound, err)
+	tasks, _, err := ts.Service.FindTasks(ctx, taskmodel.TaskFilter{OrganizationID: &ts.Org.ID})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+	tasks, _, err = ts.Service.FindTasks(ctx, taskmodel.TaskFilter{OrganizationID: &ts.Org.ID, Tags: tags})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	deleted, err := ts.Service.FindDeletedTasks(ctx, ts.Org.ID)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, task.ID, deleted[0].Task.ID)
+	assert.Equal(t, newScript, deleted[0].Task.Flux)
+	assert.True(t, deleted[0].DeletedAt.Equal(c.Now()))
+	found, err := ts.Service.FindDeletedTaskByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, found.Task.ID)
+
+	// restoring the task brings back the task and its indexes, but not its runs
+	restored, err := ts.Service.RestoreTask(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, restored.ID)
+
+	restoredTask, err := ts.Service.FindTaskByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, newScript, restoredTask.Flux)
+	tasks, _, err = ts.Service.FindTasks(ctx, taskmodel.TaskFilter{OrganizationID: &ts.Org.ID, Tags: tags})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	runs, err := ts.Service.CurrentlyRunning(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+	revs, err := ts.Service.FindTaskRevisions(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, revs, 1)
+
+	deleted, err = ts.Service.FindDeletedTasks(ctx, ts.Org.ID)
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	// only tasks deleted longer ago than the retention are purged
+	require.NoError(t, ts.Service.DeleteTask(ctx, task.ID))
+	purged, err := ts.Service.PurgeDeletedTasks(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, purged)
+
+	c.Add(2 * time.Hour)
+	purged, err = ts.Service.PurgeDeletedTasks(ctx, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, task.ID, purged[0].Task.ID)
+
+	_, err = ts.Service.RestoreTask(ctx, task.ID)
+	assert.Equal(t, taskmodel.ErrDeletedTaskNotFound, err)
+	_, err = ts.Service.FindDeletedTaskByID(ctx, task.ID)
+	assert.Equal(t, taskmodel.ErrDeletedTaskNotFound, err)
+	deleted, err = ts.Service.FindDeletedTasks(ctx, ts.Org.ID)
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+}
+
+func TestService_CurrentlyRunning_OrderedPerTask(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	ts := newService(t, ctx, nil)
+
+	ctx = icontext.SetAuthorizer(ctx, &ts.Auth)
+
+	var tasks []*taskmodel.Task
+	for i := 0; i < 2; i++ {
+		task, err := ts.Service.CreateTask(ctx, taskmodel.TaskCreate{
+			Flux:           fmt.Sprintf(`option task = {name: "task %d",every: 1h} from(bucket:"test") |> range(start:-1h)`, i),
+			OrganizationID: ts.Org.ID,
+			OwnerID:        ts.User.ID,
+		})
+		require.NoError(t, err)
+		tasks = append(tasks, task)
+	}
+
+	// interleave the runs of both tasks, the runs of each task are
+	// keyed under the task and ordered by their creation.
+	created := map[platform.ID][]platform.ID{}
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		for _, task := range tasks {
+			run, err := ts.Service.CreateRun(ctx, task.ID, now.Add(time.Duration(i)*time.Hour), now)
+			require.NoError(t, err)
+			created[task.ID] = append(created[task.ID], run.ID)
+		}
+	}
+
+	for _, task := range tasks {
+		runs, err := ts.Service.CurrentlyRunning(ctx, task.ID)
+		require.NoError(t, err)
+
+		var got []platform.ID
+		for _, r := range runs {
+			assert.Equal(t, task.ID, r.TaskID)
+			got = append(got, r.ID)
+		}
+		assert.Equal(t, created[task.ID], got)
+	}
+}
+
 func TestTaskRunCancellation(t *testing.T) {
 	store, closeSvc := itesting.NewTestBoltStore(t)
 	defer closeSvc()
@@ -0,0 +1,336 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/resource"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+)
+
+// Deleted Task Storage Schema
+// deletedTaskBucket:
+//   <taskID>: tombstone holding the deletion time and the task data as it was stored in taskBucket
+//
+// A deleted task is removed from the org and tag indexes and its runs are dropped.
+// Its script history is kept until the task is purged, so a restored task can still
+// be rolled back.
+
+var deletedTaskBucket = []byte("deletedTasksv1")
+
+var _ taskmodel.TaskTrashService = (*Service)(nil)
+
+type deletedKvTask struct {
+	DeletedAt time.Time       `json:"deletedAt"`
+	Task      json.RawMessage `json:"task"`
+}
+
+// FindDeletedTasks returns the deleted tasks of an organization, in ID order.
+func (s *Service) FindDeletedTasks(ctx context.Context, orgID platform.ID) ([]*taskmodel.DeletedTask, error) {
+	var tasks []*taskmodel.DeletedTask
+	err := s.kv.View(ctx, func(tx Tx) error {
+		ts, err := s.findDeletedTasks(ctx, tx, func(t *taskmodel.DeletedTask) bool {
+			return t.Task.OrganizationID == orgID
+		})
+		if err != nil {
+			return err
+		}
+		tasks = ts
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// FindDeletedTaskByID returns a single deleted task.
+func (s *Service) FindDeletedTaskByID(ctx context.Context, id platform.ID) (*taskmodel.DeletedTask, error) {
+	var task *taskmodel.DeletedTask
+	err := s.kv.View(ctx, func(tx Tx) error {
+		t, err := s.findDeletedTaskByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		task = t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (s *Service) findDeletedTaskByID(ctx context.Context, tx Tx, id platform.ID) (*taskmodel.DeletedTask, error) {
+	trash, err := tx.Bucket(deletedTaskBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := trash.Get(key)
+	if IsNotFound(err) {
+		return nil, taskmodel.ErrDeletedTaskNotFound
+	}
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	t, _, err := s.decodeDeletedTask(v)
+	return t, err
+}
+
+func (s *Service) findDeletedTasks(ctx context.Context, tx Tx, match func(*taskmodel.DeletedTask) bool) ([]*taskmodel.DeletedTask, error) {
+	b, err := tx.Bucket(deletedTaskBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	c, err := b.ForwardCursor(nil)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// free cursor resources
+	defer c.Close()
+
+	var tasks []*taskmodel.DeletedTask
+	for k, v := c.Next(); k != nil; k, v = c.Next() {
+		t, _, err := s.decodeDeletedTask(v)
+		if err != nil {
+			return nil, err
+		}
+		if match(t) {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return tasks, c.Err()
+}
+
+func (s *Service) decodeDeletedTask(v []byte) (*taskmodel.DeletedTask, *deletedKvTask, error) {
+	var dt deletedKvTask
+	if err := json.Unmarshal(v, &dt); err != nil {
+		return nil, nil, taskmodel.ErrInternalTaskServiceError(err)
+	}
+
+	t := &kvTask{}
+	if err := s.unmarshalTask(dt.Task, t); err != nil {
+		return nil, nil, taskmodel.ErrInternalTaskServiceError(err)
+	}
+
+	return &taskmodel.DeletedTask{
+		Task:      t.ToInfluxDB(),
+		DeletedAt: dt.DeletedAt,
+	}, &dt, nil
+}
+
+// trashTask moves the stored task into the deleted task bucket.
+func (s *Service) trashTask(ctx context.Context, tx Tx, taskID platform.ID) error {
+	taskBucket, err := tx.Bucket(taskBucket)
+	if err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	trash, err := tx.Bucket(deletedTaskBucket)
+	if err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskKey(taskID)
+	if err != nil {
+		return err
+	}
+
+	v, err := taskBucket.Get(key)
+	if IsNotFound(err) {
+		return taskmodel.ErrTaskNotFound
+	}
+	if err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// the stored bytes are kept as is so encrypted values stay encrypted
+	b, err := json.Marshal(deletedKvTask{
+		DeletedAt: s.clock.Now().UTC(),
+		Task:      v,
+	})
+	if err != nil {
+		return taskmodel.ErrInternalTaskServiceError(err)
+	}
+
+	if err := trash.Put(key, b); err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	if err := taskBucket.Delete(key); err != nil {
+		return taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	return nil
+}
+
+// RestoreTask returns a deleted task to the set of tasks and rebuilds its indexes.
+func (s *Service) RestoreTask(ctx context.Context, id platform.ID) (*taskmodel.Task, error) {
+	var t *taskmodel.Task
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		task, err := s.restoreTask(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		t = task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (s *Service) restoreTask(ctx context.Context, tx Tx, id platform.ID) (*taskmodel.Task, error) {
+	taskBucket, err := tx.Bucket(taskBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	indexBucket, err := tx.Bucket(taskIndexBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	trash, err := tx.Bucket(deletedTaskBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	key, err := taskKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := trash.Get(key)
+	if IsNotFound(err) {
+		return nil, taskmodel.ErrDeletedTaskNotFound
+	}
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	deleted, dt, err := s.decodeDeletedTask(v)
+	if err != nil {
+		return nil, err
+	}
+	task := deleted.Task
+
+	if quota := s.orgQuota(task.OrganizationID); quota.MaxTasks > 0 {
+		ids, err := s.orgTaskIDs(ctx, tx, task.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) >= quota.MaxTasks {
+			return nil, taskmodel.ErrOrgQuotaExceeded(task.OrganizationID, "tasks", quota.MaxTasks)
+		}
+	}
+
+	orgKey, err := taskOrgKey(task.OrganizationID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// write the task back
+	if err := taskBucket.Put(key, dt.Task); err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// write the org index
+	if err := indexBucket.Put(orgKey, key); err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	// write the tag index
+	if err := s.putTaskTags(ctx, tx, task.OrganizationID, task.ID, task.Tags); err != nil {
+		return nil, err
+	}
+
+	if err := trash.Delete(key); err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	uid, _ := icontext.GetUserID(ctx)
+	if err := s.audit.Log(resource.Change{
+		Type:           resource.Create,
+		ResourceID:     task.ID,
+		ResourceType:   influxdb.TasksResourceType,
+		OrganizationID: task.OrganizationID,
+		UserID:         uid,
+		ResourceBody:   dt.Task,
+		Time:           s.clock.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// PurgeDeletedTasks permanently removes the tasks deleted more than olderThan ago,
+// along with their script history.
+func (s *Service) PurgeDeletedTasks(ctx context.Context, olderThan time.Duration) ([]*taskmodel.DeletedTask, error) {
+	var tasks []*taskmodel.DeletedTask
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		ts, err := s.purgeDeletedTasks(ctx, tx, s.clock.Now().UTC().Add(-olderThan))
+		if err != nil {
+			return err
+		}
+		tasks = ts
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (s *Service) purgeDeletedTasks(ctx context.Context, tx Tx, before time.Time) ([]*taskmodel.DeletedTask, error) {
+	// collect the tasks before modifying the bucket the cursor is walking
+	tasks, err := s.findDeletedTasks(ctx, tx, func(t *taskmodel.DeletedTask) bool {
+		return t.DeletedAt.Before(before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trash, err := tx.Bucket(deletedTaskBucket)
+	if err != nil {
+		return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	for _, t := range tasks {
+		key, err := taskKey(t.Task.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := trash.Delete(key); err != nil {
+			return nil, taskmodel.ErrUnexpectedTaskBucketErr(err)
+		}
+
+		// remove the script history
+		if err := s.deleteTaskRevisions(ctx, tx, t.Task.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return tasks, nil
+}
@@ -132,10 +132,12 @@ func (s *TaskService) ForceRun(ctx context.Context, taskID platform.ID, schedule
 
 type TaskControlService struct {
 	CreateRunFn        func(ctx context.Context, taskID platform.ID, scheduledFor time.Time, runAt time.Time) (*taskmodel.Run, error)
+	CreateRunsFn       func(ctx context.Context, creates []taskmodel.RunCreate) ([]*taskmodel.Run, error)
 	CurrentlyRunningFn func(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error)
 	ManualRunsFn       func(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error)
 	StartManualRunFn   func(ctx context.Context, taskID, runID platform.ID) (*taskmodel.Run, error)
 	FinishRunFn        func(ctx context.Context, taskID, runID platform.ID) (*taskmodel.Run, error)
+	FinishRunsFn       func(ctx context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error)
 	UpdateRunStateFn   func(ctx context.Context, taskID, runID platform.ID, when time.Time, state taskmodel.RunStatus) error
 	AddRunLogFn        func(ctx context.Context, taskID, runID platform.ID, when time.Time, log string) error
 }
@@ -143,6 +145,9 @@ type TaskControlService struct {
 func (tcs *TaskControlService) CreateRun(ctx context.Context, taskID platform.ID, scheduledFor time.Time, runAt time.Time) (*taskmodel.Run, error) {
 	return tcs.CreateRunFn(ctx, taskID, scheduledFor, runAt)
 }
+func (tcs *TaskControlService) CreateRuns(ctx context.Context, creates []taskmodel.RunCreate) ([]*taskmodel.Run, error) {
+	return tcs.CreateRunsFn(ctx, creates)
+}
 func (tcs *TaskControlService) CurrentlyRunning(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error) {
 	return tcs.CurrentlyRunningFn(ctx, taskID)
 }
@@ -155,6 +160,9 @@ func (tcs *TaskControlService) StartManualRun(ctx context.Context, taskID, runID
 func (tcs *TaskControlService) FinishRun(ctx context.Context, taskID, runID platform.ID) (*taskmodel.Run, error) {
 	return tcs.FinishRunFn(ctx, taskID, runID)
 }
+func (tcs *TaskControlService) FinishRuns(ctx context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error) {
+	return tcs.FinishRunsFn(ctx, refs)
+}
 func (tcs *TaskControlService) UpdateRunState(ctx context.Context, taskID, runID platform.ID, when time.Time, state taskmodel.RunStatus) error {
 	return tcs.UpdateRunStateFn(ctx, taskID, runID, when, state)
 }
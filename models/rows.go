@@ -2,6 +2,8 @@ package models
 
 import (
 	"sort"
+	"strings"
+	"time"
 )
 
 // Row represents a single row returned from the execution of a statement.
@@ -60,3 +62,128 @@ func (p Rows) Less(i, j int) bool {
 
 // Swap implements sort.Interface.
 func (p Rows) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+// LexicalRows sorts rows by name and then by their tag keys and values in
+// lexical order. Unlike Rows, the resulting order is predictable by a reader.
+type LexicalRows []*Row
+
+// Len implements sort.Interface.
+func (p LexicalRows) Len() int { return len(p) }
+
+// Less implements sort.Interface.
+func (p LexicalRows) Less(i, j int) bool {
+	if p[i].Name != p[j].Name {
+		return p[i].Name < p[j].Name
+	}
+
+	ikeys, jkeys := p[i].tagsKeys(), p[j].tagsKeys()
+	for n := 0; n < len(ikeys) && n < len(jkeys); n++ {
+		if ikeys[n] != jkeys[n] {
+			return ikeys[n] < jkeys[n]
+		}
+		if iv, jv := p[i].Tags[ikeys[n]], p[j].Tags[jkeys[n]]; iv != jv {
+			return iv < jv
+		}
+	}
+	return len(ikeys) < len(jkeys)
+}
+
+// Swap implements sort.Interface.
+func (p LexicalRows) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+// MergeRows combines the rows produced by several executors, such as one per
+// shard or one per node, into a single set of rows.
+//
+// Rows for the same series with the same columns are merged into one row and
+// their values are ordered by time, in the direction given by ascending, so
+// that the intervals of every input line up. Values that share a timestamp
+// are kept in the order of the inputs. The merged rows are sorted in the same
+// order as Rows.
+func MergeRows(ascending bool, inputs ...Rows) Rows {
+	var (
+		merged Rows
+		index  = make(map[rowKey]*Row)
+	)
+	for _, rows := range inputs {
+		for _, r := range rows {
+			key := rowKey{name: r.Name, tags: r.tagsHash(), columns: strings.Join(r.Columns, "\x00")}
+			if m, ok := index[key]; ok {
+				m.Values = append(m.Values, r.Values...)
+				m.Partial = m.Partial || r.Partial
+				continue
+			}
+
+			m := &Row{
+				Name:    r.Name,
+				Tags:    r.Tags,
+				Columns: r.Columns,
+				Values:  append([][]interface{}(nil), r.Values...),
+				Partial: r.Partial,
+			}
+			index[key] = m
+			merged = append(merged, m)
+		}
+	}
+
+	for _, r := range merged {
+		r.sortValuesByTime(ascending)
+	}
+	sort.Stable(merged)
+	return merged
+}
+
+// rowKey identifies the rows that MergeRows combines.
+type rowKey struct {
+	name    string
+	tags    uint64
+	columns string
+}
+
+// sortValuesByTime orders the values of the row by its time column.
+// Rows without a time column, or with times of an unknown type, are left as they are.
+func (r *Row) sortValuesByTime(ascending bool) {
+	col := -1
+	for i, c := range r.Columns {
+		if c == "time" {
+			col = i
+			break
+		}
+	}
+	if col < 0 {
+		return
+	}
+
+	times := make([]int64, len(r.Values))
+	for i, values := range r.Values {
+		if col >= len(values) {
+			return
+		}
+		switch v := values[col].(type) {
+		case time.Time:
+			times[i] = v.UnixNano()
+		case int64:
+			times[i] = v
+		default:
+			return
+		}
+	}
+
+	var sorted sort.Interface = valuesByTime{values: r.Values, times: times}
+	if !ascending {
+		sorted = sort.Reverse(sorted)
+	}
+	sort.Stable(sorted)
+}
+
+// valuesByTime sorts the values of a row by their precomputed times.
+type valuesByTime struct {
+	values [][]interface{}
+	times  []int64
+}
+
+func (a valuesByTime) Len() int           { return len(a.values) }
+func (a valuesByTime) Less(i, j int) bool { return a.times[i] < a.times[j] }
+func (a valuesByTime) Swap(i, j int) {
+	a.values[i], a.values[j] = a.values[j], a.values[i]
+	a.times[i], a.times[j] = a.times[j], a.times[i]
+}
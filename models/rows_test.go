@@ -0,0 +1,105 @@
+package models_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/v2/models"
+)
+
+func TestMergeRows(t *testing.T) {
+	ts := func(sec int64) time.Time { return time.Unix(sec, 0).UTC() }
+
+	shard1 := models.Rows{
+		{Name: "mem", Columns: []string{"time", "value"}, Values: [][]interface{}{{ts(0), 1.0}}},
+		{Name: "cpu", Tags: map[string]string{"host": "a"}, Columns: []string{"time", "value"}, Values: [][]interface{}{
+			{ts(10), 2.0},
+			{ts(30), 4.0},
+		}},
+	}
+	shard2 := models.Rows{
+		{Name: "cpu", Tags: map[string]string{"host": "a"}, Columns: []string{"time", "value"}, Values: [][]interface{}{
+			{ts(0), 1.0},
+			{ts(20), 3.0},
+		}},
+		{Name: "cpu", Tags: map[string]string{"host": "b"}, Columns: []string{"time", "value"}, Values: [][]interface{}{
+			{ts(0), 5.0},
+		}},
+	}
+
+	got := models.MergeRows(true, shard1, shard2)
+
+	exp := models.Rows{
+		{Name: "cpu", Tags: map[string]string{"host": "a"}, Columns: []string{"time", "value"}, Values: [][]interface{}{
+			{ts(0), 1.0},
+			{ts(10), 2.0},
+			{ts(20), 3.0},
+			{ts(30), 4.0},
+		}},
+		{Name: "cpu", Tags: map[string]string{"host": "b"}, Columns: []string{"time", "value"}, Values: [][]interface{}{
+			{ts(0), 5.0},
+		}},
+		{Name: "mem", Columns: []string{"time", "value"}, Values: [][]interface{}{{ts(0), 1.0}}},
+	}
+	// the order of series with the same name follows Rows.Less
+	if !exp[0:2].Less(0, 1) {
+		exp[0], exp[1] = exp[1], exp[0]
+	}
+
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Fatalf("unexpected rows (-want +got):\n%s", diff)
+	}
+
+	// the inputs must not be modified
+	if len(shard1[1].Values) != 2 || shard1[1].Values[0][0] != ts(10) {
+		t.Fatalf("input rows were modified: %v", shard1[1].Values)
+	}
+}
+
+func TestMergeRows_Descending(t *testing.T) {
+	a := models.Rows{{Name: "cpu", Columns: []string{"time", "count"}, Values: [][]interface{}{
+		{int64(30), int64(1)},
+		{int64(10), int64(1)},
+	}}}
+	b := models.Rows{{Name: "cpu", Columns: []string{"time", "count"}, Values: [][]interface{}{
+		{int64(20), int64(2)},
+	}, Partial: true}}
+
+	got := models.MergeRows(false, a, b)
+
+	exp := models.Rows{{Name: "cpu", Columns: []string{"time", "count"}, Values: [][]interface{}{
+		{int64(30), int64(1)},
+		{int64(20), int64(2)},
+		{int64(10), int64(1)},
+	}, Partial: true}}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Fatalf("unexpected rows (-want +got):\n%s", diff)
+	}
+}
+
+func TestLexicalRows(t *testing.T) {
+	rows := models.Rows{
+		{Name: "cpu", Tags: map[string]string{"host": "b", "region": "east"}},
+		{Name: "cpu", Tags: map[string]string{"host": "a", "region": "west"}},
+		{Name: "cpu", Tags: map[string]string{"host": "a"}},
+		{Name: "cpu", Tags: map[string]string{"dc": "z"}},
+		{Name: "cpu"},
+		{Name: "alpha", Tags: map[string]string{"host": "z"}},
+	}
+
+	sort.Sort(models.LexicalRows(rows))
+
+	exp := models.Rows{
+		{Name: "alpha", Tags: map[string]string{"host": "z"}},
+		{Name: "cpu"},
+		{Name: "cpu", Tags: map[string]string{"dc": "z"}},
+		{Name: "cpu", Tags: map[string]string{"host": "a"}},
+		{Name: "cpu", Tags: map[string]string{"host": "a", "region": "west"}},
+		{Name: "cpu", Tags: map[string]string{"host": "b", "region": "east"}},
+	}
+	if diff := cmp.Diff(exp, rows); diff != "" {
+		t.Fatalf("unexpected order (-want +got):\n%s", diff)
+	}
+}
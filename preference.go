@@ -0,0 +1,31 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kit/platform/errors"
+)
+
+// ErrPreferenceNotFound is the error for a missing preference.
+var ErrPreferenceNotFound = &errors.Error{
+	Code: errors.ENotFound,
+	Msg:  "preference not found",
+}
+
+// PreferenceService stores the preferences of the users, such as the settings and the
+// state of the UI, as JSON documents identified by a key.
+type PreferenceService interface {
+	// FindPreferenceKeys returns the keys of the preferences of the user.
+	FindPreferenceKeys(ctx context.Context, userID platform.ID) ([]string, error)
+
+	// FindPreference returns the preference of the user at key.
+	FindPreference(ctx context.Context, userID platform.ID, key string) (json.RawMessage, error)
+
+	// PutPreference creates or replaces the preference of the user at key.
+	PutPreference(ctx context.Context, userID platform.ID, key string, doc json.RawMessage) error
+
+	// DeletePreference removes the preference of the user at key.
+	DeletePreference(ctx context.Context, userID platform.ID, key string) error
+}
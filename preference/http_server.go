@@ -0,0 +1,189 @@
+package preference
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kit/platform/errors"
+	kithttp "github.com/influxdata/influxdb/v2/kit/transport/http"
+	"go.uber.org/zap"
+)
+
+const (
+	prefixPreferences = "/api/v2/preferences"
+
+	// maxBodySize bounds the request bodies read before the service checks
+	// the size of the preferences.
+	maxBodySize = 1024 * 1024
+)
+
+// PreferenceHandler is the handler for the preferences of the user making the request.
+type PreferenceHandler struct {
+	chi.Router
+
+	log *zap.Logger
+	api *kithttp.API
+
+	preferenceService influxdb.PreferenceService
+}
+
+// NewPreferenceHandler creates a new handler for the preference service.
+func NewPreferenceHandler(log *zap.Logger, preferenceService influxdb.PreferenceService) *PreferenceHandler {
+	h := &PreferenceHandler{
+		log:               log,
+		api:               kithttp.NewAPI(kithttp.WithLog(log)),
+		preferenceService: preferenceService,
+	}
+
+	r := chi.NewRouter()
+	r.Use(
+		middleware.Recoverer,
+		middleware.RequestID,
+		middleware.RealIP,
+	)
+
+	r.Route("/", func(r chi.Router) {
+		r.Get("/", h.handleGetPreferences)
+
+		r.Route("/{key}", func(r chi.Router) {
+			r.Get("/", h.handleGetPreference)
+			r.Put("/", h.handlePutPreference)
+			r.Delete("/", h.handleDeletePreference)
+		})
+	})
+
+	h.Router = r
+
+	return h
+}
+
+// Prefix returns the mounting prefix for the handler
+func (h *PreferenceHandler) Prefix() string {
+	return prefixPreferences
+}
+
+type preferencesResponse struct {
+	Links       map[string]string `json:"links"`
+	Preferences []string          `json:"preferences"`
+}
+
+// handleGetPreferences is the HTTP handler for the GET /api/v2/preferences route.
+func (h *PreferenceHandler) handleGetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := decodeUserID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	keys, err := h.preferenceService.FindPreferenceKeys(r.Context(), userID)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+	if keys == nil {
+		keys = []string{}
+	}
+
+	h.api.Respond(w, r, http.StatusOK, preferencesResponse{
+		Links: map[string]string{
+			"self": prefixPreferences,
+		},
+		Preferences: keys,
+	})
+}
+
+// handleGetPreference is the HTTP handler for the GET /api/v2/preferences/:key route.
+func (h *PreferenceHandler) handleGetPreference(w http.ResponseWriter, r *http.Request) {
+	userID, err := decodeUserID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	doc, err := h.preferenceService.FindPreference(r.Context(), userID, decodeKey(r))
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusOK, doc)
+}
+
+// handlePutPreference is the HTTP handler for the PUT /api/v2/preferences/:key route.
+func (h *PreferenceHandler) handlePutPreference(w http.ResponseWriter, r *http.Request) {
+	userID, err := decodeUserID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		h.api.Err(w, r, &errors.Error{
+			Code: errors.EInvalid,
+			Msg:  "unable to read request body",
+			Err:  err,
+		})
+		return
+	}
+	if len(body) > maxBodySize {
+		h.api.Err(w, r, &errors.Error{
+			Code: errors.ETooLarge,
+			Msg:  "request body is too large",
+		})
+		return
+	}
+
+	if err := h.preferenceService.PutPreference(r.Context(), userID, decodeKey(r), json.RawMessage(body)); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusNoContent, nil)
+}
+
+// handleDeletePreference is the HTTP handler for the DELETE /api/v2/preferences/:key route.
+func (h *PreferenceHandler) handleDeletePreference(w http.ResponseWriter, r *http.Request) {
+	userID, err := decodeUserID(r)
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	if err := h.preferenceService.DeletePreference(r.Context(), userID, decodeKey(r)); err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
+	h.api.Respond(w, r, http.StatusNoContent, nil)
+}
+
+// decodeUserID returns the user making the request, whose preferences are managed.
+func decodeUserID(r *http.Request) (platform.ID, error) {
+	userID, err := icontext.GetUserID(r.Context())
+	if err != nil {
+		return platform.InvalidID(), err
+	}
+	if !userID.Valid() {
+		return platform.InvalidID(), &errors.Error{
+			Code: errors.EForbidden,
+			Msg:  "preferences can only be managed by a user",
+		}
+	}
+	return userID, nil
+}
+
+func decodeKey(r *http.Request) string {
+	key := chi.URLParam(r, "key")
+	if k, err := url.PathUnescape(key); err == nil {
+		return k
+	}
+	return key
+}
@@ -0,0 +1,54 @@
+package preference_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/preference"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPreferenceHandler(t *testing.T) {
+	h := preference.NewPreferenceHandler(zaptest.NewLogger(t), newTestService(t))
+
+	do := func(a influxdb.Authorizer, method, path, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r = r.WithContext(icontext.SetAuthorizer(r.Context(), a))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+		return rr
+	}
+	user := &influxdb.Session{ID: 1, UserID: 2}
+
+	rr := do(user, http.MethodPut, "/theme", `{"dark": true}`)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+
+	rr = do(user, http.MethodGet, "/theme", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.JSONEq(t, `{"dark": true}`, rr.Body.String())
+
+	rr = do(user, http.MethodGet, "/", "")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.JSONEq(t, `{"links": {"self": "/api/v2/preferences"}, "preferences": ["theme"]}`, rr.Body.String())
+
+	// The preferences of other users are not visible.
+	rr = do(&influxdb.Session{ID: 3, UserID: 4}, http.MethodGet, "/theme", "")
+	require.Equal(t, http.StatusNotFound, rr.Code, rr.Body.String())
+
+	rr = do(user, http.MethodPut, "/theme", `not json`)
+	require.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+
+	rr = do(user, http.MethodDelete, "/theme", "")
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+	rr = do(user, http.MethodGet, "/theme", "")
+	require.Equal(t, http.StatusNotFound, rr.Code, rr.Body.String())
+
+	// Tokens that are not owned by a user have no preferences.
+	rr = do(&influxdb.Authorization{ID: 5, OrgID: 6}, http.MethodGet, "/", "")
+	require.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+}
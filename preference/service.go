@@ -0,0 +1,129 @@
+package preference
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kit/platform/errors"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+const (
+	// DefaultMaxDocumentSize is the default maximum size in bytes of a preference.
+	DefaultMaxDocumentSize = 64 * 1024
+	// DefaultMaxKeys is the default maximum number of preferences of a user.
+	DefaultMaxKeys = 100
+	// MaxKeyLength is the maximum length of the key of a preference.
+	MaxKeyLength = 128
+)
+
+var _ influxdb.PreferenceService = (*Service)(nil)
+
+// Service stores the preferences of the users, limiting the size of the
+// documents and the number of documents of each user.
+type Service struct {
+	s *Storage
+
+	// MaxDocumentSize is the maximum size in bytes of a preference.
+	MaxDocumentSize int
+	// MaxKeys is the maximum number of preferences of a user.
+	MaxKeys int
+}
+
+// NewService creates a new service implementation for preferences
+func NewService(s *Storage) *Service {
+	return &Service{
+		s:               s,
+		MaxDocumentSize: DefaultMaxDocumentSize,
+		MaxKeys:         DefaultMaxKeys,
+	}
+}
+
+// FindPreferenceKeys returns the keys of the preferences of the user.
+func (s *Service) FindPreferenceKeys(ctx context.Context, userID platform.ID) ([]string, error) {
+	var keys []string
+	err := s.s.View(ctx, func(tx kv.Tx) error {
+		var err error
+		keys, err = s.s.ListPreferences(ctx, tx, userID)
+		return err
+	})
+	return keys, err
+}
+
+// FindPreference returns the preference of the user at key.
+func (s *Service) FindPreference(ctx context.Context, userID platform.ID, key string) (json.RawMessage, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+
+	var doc json.RawMessage
+	err := s.s.View(ctx, func(tx kv.Tx) error {
+		var err error
+		doc, err = s.s.GetPreference(ctx, tx, userID, key)
+		return err
+	})
+	return doc, err
+}
+
+// PutPreference creates or replaces the preference of the user at key.
+func (s *Service) PutPreference(ctx context.Context, userID platform.ID, key string, doc json.RawMessage) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	if len(doc) > s.MaxDocumentSize {
+		return &errors.Error{
+			Code: errors.ETooLarge,
+			Msg:  fmt.Sprintf("preference is larger than %d bytes", s.MaxDocumentSize),
+		}
+	}
+	if !json.Valid(doc) {
+		return &errors.Error{
+			Code: errors.EInvalid,
+			Msg:  "preference must be a valid JSON document",
+		}
+	}
+
+	return s.s.Update(ctx, func(tx kv.Tx) error {
+		_, err := s.s.GetPreference(ctx, tx, userID, key)
+		if errors.ErrorCode(err) == errors.ENotFound {
+			keys, err := s.s.ListPreferences(ctx, tx, userID)
+			if err != nil {
+				return err
+			}
+			if len(keys) >= s.MaxKeys {
+				return &errors.Error{
+					Code: errors.EUnprocessableEntity,
+					Msg:  fmt.Sprintf("user cannot have more than %d preferences", s.MaxKeys),
+				}
+			}
+		} else if err != nil {
+			return err
+		}
+
+		return s.s.PutPreference(ctx, tx, userID, key, doc)
+	})
+}
+
+// DeletePreference removes the preference of the user at key.
+func (s *Service) DeletePreference(ctx context.Context, userID platform.ID, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	return s.s.Update(ctx, func(tx kv.Tx) error {
+		return s.s.DeletePreference(ctx, tx, userID, key)
+	})
+}
+
+func validKey(key string) error {
+	if key == "" || len(key) > MaxKeyLength {
+		return &errors.Error{
+			Code: errors.EInvalid,
+			Msg:  fmt.Sprintf("preference key must be between 1 and %d bytes long", MaxKeyLength),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package preference_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/inmem"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kit/platform/errors"
+	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	"github.com/influxdata/influxdb/v2/preference"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestService(t *testing.T) *preference.Service {
+	t.Helper()
+
+	s := inmem.NewKVStore()
+	require.NoError(t, all.Up(context.Background(), zaptest.NewLogger(t), s))
+
+	storage, err := preference.NewStore(s)
+	require.NoError(t, err)
+
+	return preference.NewService(storage)
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	user1, user2 := platform.ID(1), platform.ID(2)
+
+	keys, err := svc.FindPreferenceKeys(ctx, user1)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	require.NoError(t, svc.PutPreference(ctx, user1, "theme", json.RawMessage(`{"dark":true}`)))
+	require.NoError(t, svc.PutPreference(ctx, user1, "dashboards/sort", json.RawMessage(`"name"`)))
+	require.NoError(t, svc.PutPreference(ctx, user2, "theme", json.RawMessage(`{"dark":false}`)))
+
+	// The preferences are scoped to each user.
+	keys, err = svc.FindPreferenceKeys(ctx, user1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dashboards/sort", "theme"}, keys)
+	doc, err := svc.FindPreference(ctx, user2, "theme")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"dark":false}`, string(doc))
+
+	require.NoError(t, svc.PutPreference(ctx, user1, "theme", json.RawMessage(`{"dark":false}`)))
+	doc, err = svc.FindPreference(ctx, user1, "theme")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"dark":false}`, string(doc))
+
+	require.NoError(t, svc.DeletePreference(ctx, user1, "theme"))
+	_, err = svc.FindPreference(ctx, user1, "theme")
+	require.Equal(t, errors.ENotFound, errors.ErrorCode(err))
+	err = svc.DeletePreference(ctx, user1, "theme")
+	require.Equal(t, errors.ENotFound, errors.ErrorCode(err))
+	_, err = svc.FindPreference(ctx, user2, "theme")
+	require.NoError(t, err)
+}
+
+func TestService_Limits(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t)
+	svc.MaxDocumentSize = 16
+	svc.MaxKeys = 2
+	userID := platform.ID(1)
+
+	for _, tt := range []struct {
+		name string
+		key  string
+		doc  string
+		code string
+	}{
+		{name: "empty key", key: "", doc: `1`, code: errors.EInvalid},
+		{name: "key too long", key: strings.Repeat("k", preference.MaxKeyLength+1), doc: `1`, code: errors.EInvalid},
+		{name: "invalid JSON", key: "a", doc: `{`, code: errors.EInvalid},
+		{name: "document too large", key: "a", doc: `"` + strings.Repeat("x", 16) + `"`, code: errors.ETooLarge},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.PutPreference(ctx, userID, tt.key, json.RawMessage(tt.doc))
+			require.Equal(t, tt.code, errors.ErrorCode(err))
+		})
+	}
+
+	require.NoError(t, svc.PutPreference(ctx, userID, "a", json.RawMessage(`1`)))
+	require.NoError(t, svc.PutPreference(ctx, userID, "b", json.RawMessage(`2`)))
+	err := svc.PutPreference(ctx, userID, "c", json.RawMessage(`3`))
+	require.Equal(t, errors.EUnprocessableEntity, errors.ErrorCode(err))
+
+	// The existing preferences can still be replaced.
+	require.NoError(t, svc.PutPreference(ctx, userID, "b", json.RawMessage(`4`)))
+	_, err = svc.FindPreference(ctx, userID, "c")
+	require.Equal(t, influxdb.ErrPreferenceNotFound, err)
+}
@@ -0,0 +1,159 @@
+package preference
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/influxdata/influxdb/v2"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kv"
+)
+
+var preferenceBucket = []byte("preferencesv1")
+
+// Storage is a store translation layer between the data storage unit and the
+// service layer.
+type Storage struct {
+	store kv.Store
+}
+
+// NewStore creates a new storage system
+func NewStore(s kv.Store) (*Storage, error) {
+	return &Storage{s}, nil
+}
+
+func (s *Storage) View(ctx context.Context, fn func(kv.Tx) error) error {
+	return s.store.View(ctx, fn)
+}
+
+func (s *Storage) Update(ctx context.Context, fn func(kv.Tx) error) error {
+	return s.store.Update(ctx, fn)
+}
+
+// GetPreference returns the preference of the user at key k.
+func (s *Storage) GetPreference(ctx context.Context, tx kv.Tx, userID platform.ID, k string) (json.RawMessage, error) {
+	key, err := encodePreferenceKey(userID, k)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(preferenceBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := b.Get(key)
+	if kv.IsNotFound(err) {
+		return nil, influxdb.ErrPreferenceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(val), nil
+}
+
+// ListPreferences returns the keys of the preferences of the user.
+func (s *Storage) ListPreferences(ctx context.Context, tx kv.Tx, userID platform.ID) ([]string, error) {
+	b, err := tx.Bucket(preferenceBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, err := userID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.ForwardCursor(prefix, kv.WithCursorPrefix(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+
+	err = kv.WalkCursor(ctx, cur, func(k, v []byte) (bool, error) {
+		id, key, err := decodePreferenceKey(k)
+		if err != nil {
+			return false, err
+		}
+
+		if id != userID {
+			// We've reached the end of the keyspace for the provided userID
+			return false, nil
+		}
+
+		keys = append(keys, key)
+
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// PutPreference sets the preference of the user at key k.
+func (s *Storage) PutPreference(ctx context.Context, tx kv.Tx, userID platform.ID, k string, doc json.RawMessage) error {
+	key, err := encodePreferenceKey(userID, k)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(preferenceBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(key, doc)
+}
+
+// DeletePreference removes the preference of the user at key k.
+func (s *Storage) DeletePreference(ctx context.Context, tx kv.Tx, userID platform.ID, k string) error {
+	key, err := encodePreferenceKey(userID, k)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(preferenceBucket)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.Get(key); kv.IsNotFound(err) {
+		return influxdb.ErrPreferenceNotFound
+	} else if err != nil {
+		return err
+	}
+
+	return b.Delete(key)
+}
+
+func encodePreferenceKey(userID platform.ID, k string) ([]byte, error) {
+	buf, err := userID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 0, platform.IDLength+len(k))
+	key = append(key, buf...)
+	key = append(key, k...)
+
+	return key, nil
+}
+
+func decodePreferenceKey(key []byte) (platform.ID, string, error) {
+	if len(key) < platform.IDLength {
+		// This should not happen.
+		return platform.InvalidID(), "", errors.New("provided key is too short to contain an ID (please report this error)")
+	}
+
+	var id platform.ID
+	if err := id.Decode(key[:platform.IDLength]); err != nil {
+		return platform.InvalidID(), "", err
+	}
+
+	return id, string(key[platform.IDLength:]), nil
+}
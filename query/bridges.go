@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"io"
+	"sync"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/csv"
@@ -168,6 +169,119 @@ func (ProxyQueryServiceAsyncBridge) Check(context.Context) check.Response {
 	return check.Response{Name: "Query Service", Status: check.StatusPass}
 }
 
+// AsyncQueryServiceBridge implements AsyncQueryService while consuming a QueryService.
+// Combined with a QueryService that talks to a remote server, it lets callers that
+// expect the asynchronous interface of the controller run their queries remotely.
+type AsyncQueryServiceBridge struct {
+	QueryService QueryService
+}
+
+func (b AsyncQueryServiceBridge) Query(ctx context.Context, req *Request) (flux.Query, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	ri, err := b.QueryService.Query(ctx, req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	q := &resultIteratorQuery{
+		ri:      ri,
+		cancel:  cancel,
+		results: make(chan flux.Result),
+		done:    make(chan struct{}),
+	}
+	go q.run()
+	return q, nil
+}
+
+// resultIteratorQuery adapts a flux.ResultIterator to the flux.Query interface.
+//
+// A result iterator may not be advanced while the previous result is still
+// being read, so each result must be drained with Tables().Do before the next
+// one is sent. Calling Done stops the query without draining the results.
+type resultIteratorQuery struct {
+	ri      flux.ResultIterator
+	cancel  context.CancelFunc
+	results chan flux.Result
+
+	// done is closed when the caller is done with the query.
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func (q *resultIteratorQuery) run() {
+	defer close(q.results)
+	for q.ri.More() {
+		r := &drainedResult{
+			Result:  q.ri.Next(),
+			drained: make(chan struct{}),
+		}
+		select {
+		case q.results <- r:
+		case <-q.done:
+			return
+		}
+		select {
+		case <-r.drained:
+		case <-q.done:
+			return
+		}
+	}
+}
+
+// drainedResult is a flux.Result that signals when its tables have been read.
+type drainedResult struct {
+	flux.Result
+	drained chan struct{}
+	once    sync.Once
+}
+
+func (r *drainedResult) Tables() flux.TableIterator {
+	return drainedTables{r: r}
+}
+
+type drainedTables struct {
+	r *drainedResult
+}
+
+func (t drainedTables) Do(f func(flux.Table) error) error {
+	defer t.r.once.Do(func() { close(t.r.drained) })
+	return t.r.Result.Tables().Do(f)
+}
+
+func (q *resultIteratorQuery) Results() <-chan flux.Result {
+	return q.results
+}
+
+func (q *resultIteratorQuery) Done() {
+	q.doneOnce.Do(func() {
+		close(q.done)
+		// Wait for the producer to stop before releasing the iterator it reads from.
+		for range q.results {
+		}
+		q.ri.Release()
+		q.cancel()
+	})
+}
+
+func (q *resultIteratorQuery) Cancel() {
+	q.cancel()
+}
+
+func (q *resultIteratorQuery) Err() error {
+	return q.ri.Err()
+}
+
+func (q *resultIteratorQuery) Statistics() flux.Statistics {
+	return q.ri.Statistics()
+}
+
+// ProfilerResults returns nil since profiler results are encoded
+// into the results of the remote query.
+func (q *resultIteratorQuery) ProfilerResults() (flux.ResultIterator, error) {
+	return nil, nil
+}
+
 // REPLQuerier implements the repl.Querier interface while consuming a QueryService
 type REPLQuerier struct {
 	// Authorization is the authorization to provide for all requests
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/metadata"
 	"github.com/influxdata/influxdb/v2/query"
@@ -66,3 +68,136 @@ func TestProxyQueryServiceAsyncBridge_StatsOnClientDisconnect(t *testing.T) {
 		t.Fatalf("stats were missing or had wrong metadata: exp metadata[foo]=[bar], got %v", md)
 	}
 }
+
+func TestAsyncQueryServiceBridge(t *testing.T) {
+	a := executetest.NewResult([]*executetest.Table{{}})
+	a.Nm = "a"
+	b := executetest.NewResult([]*executetest.Table{{}})
+	b.Nm = "b"
+
+	var released bool
+	mockSvc := &mock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			if req.OrganizationID != 0x1234 {
+				panic(fmt.Errorf("unexpected request: %v", req))
+			}
+			return &releaseResultIterator{
+				ResultIterator: flux.NewSliceResultIterator([]flux.Result{a, b}),
+				released:       &released,
+			}, nil
+		},
+	}
+
+	bridge := query.AsyncQueryServiceBridge{QueryService: mockSvc}
+	q, err := bridge.Query(context.Background(), &query.Request{OrganizationID: 0x1234})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for r := range q.Results() {
+		names = append(names, r.Name())
+		if err := r.Tables().Do(func(flux.Table) error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(names, ","), "a,b"; got != want {
+		t.Fatalf("unexpected results: got %s, want %s", got, want)
+	}
+	if !released {
+		t.Fatal("expected the result iterator to be released")
+	}
+}
+
+func TestAsyncQueryServiceBridge_DoneBeforeResults(t *testing.T) {
+	r := executetest.NewResult([]*executetest.Table{{}})
+	var released bool
+	mockSvc := &mock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			return &releaseResultIterator{
+				ResultIterator: flux.NewSliceResultIterator([]flux.Result{r, r, r}),
+				released:       &released,
+			}, nil
+		},
+	}
+
+	bridge := query.AsyncQueryServiceBridge{QueryService: mockSvc}
+	q, err := bridge.Query(context.Background(), &query.Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Done must not block when the results were never read.
+	q.Done()
+	if !released {
+		t.Fatal("expected the result iterator to be released")
+	}
+}
+
+func TestAsyncQueryServiceBridge_MultipleResults(t *testing.T) {
+	// The CSV result iterator reads the results from a single stream, so it
+	// must not be advanced while the previous result is being read.
+	const data = `#datatype,string,long,dateTime:RFC3339,double
+#group,false,false,false,false
+#default,a,,,
+,result,table,_time,_value
+,,0,2018-05-22T19:53:26Z,1
+,,0,2018-05-22T19:53:36Z,2
+
+#datatype,string,long,dateTime:RFC3339,double
+#group,false,false,false,false
+#default,b,,,
+,result,table,_time,_value
+,,0,2018-05-22T19:53:26Z,3
+,,0,2018-05-22T19:53:36Z,4
+
+`
+	mockSvc := &mock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			dec := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{})
+			return dec.Decode(io.NopCloser(strings.NewReader(data)))
+		},
+	}
+
+	bridge := query.AsyncQueryServiceBridge{QueryService: mockSvc}
+	q, err := bridge.Query(context.Background(), &query.Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for r := range q.Results() {
+		if err := r.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				vs := cr.Floats(execute.ColIdx("_value", cr.Cols()))
+				for i := 0; i < vs.Len(); i++ {
+					got = append(got, fmt.Sprintf("%s=%v", r.Name(), vs.Value(i)))
+				}
+				return nil
+			})
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(got, ","), "a=1,a=2,b=3,b=4"; got != want {
+		t.Fatalf("unexpected values: got %s, want %s", got, want)
+	}
+}
+
+type releaseResultIterator struct {
+	flux.ResultIterator
+	released *bool
+}
+
+func (i *releaseResultIterator) Release() {
+	i.ResultIterator.Release()
+	*i.released = true
+}
@@ -24,6 +24,7 @@ import (
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
@@ -33,6 +34,7 @@ import (
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/influxdb/v2/kit/errors"
+	"github.com/influxdata/influxdb/v2/kit/platform"
 	errors2 "github.com/influxdata/influxdb/v2/kit/platform/errors"
 	"github.com/influxdata/influxdb/v2/kit/prom"
 	"github.com/influxdata/influxdb/v2/kit/tracing"
@@ -61,6 +63,9 @@ type Controller struct {
 	abort      chan struct{}
 	memory     *memoryManager
 
+	orgQuotasMu sync.RWMutex
+	orgQuotas   map[platform.ID]OrgQuota
+
 	metrics   *controllerMetrics
 	labelKeys []string
 
@@ -120,6 +125,11 @@ type Config struct {
 
 	// FluxLogEnabled logs any in-progress queries that get cancelled due to the server being shut down.
 	FluxLogEnabled bool
+
+	// OrgQuotas overrides the quotas above for the queries of some organizations.
+	// The overrides can be changed at runtime with SetOrgQuota and DeleteOrgQuota,
+	// but those changes are kept in memory only: OrgQuotas applies again on restart.
+	OrgQuotas map[platform.ID]OrgQuota
 }
 
 // complete will fill in the defaults, validate the configuration, and
@@ -175,6 +185,11 @@ func (c *Config) validate() error {
 			return fmt.Errorf("MaxMemoryBytes must be greater than or equal to the ConcurrencyQuota * InitialMemoryBytesQuotaPerQuery: %d < %d (%d * %d)", c.MaxMemoryBytes, minMemory, c.ConcurrencyQuota, c.InitialMemoryBytesQuotaPerQuery)
 		}
 	}
+	for orgID, quota := range c.OrgQuotas {
+		if err := quota.validate(c); err != nil {
+			return fmt.Errorf("invalid quota for organization %s: %w", orgID, err)
+		}
+	}
 	return nil
 }
 
@@ -209,8 +224,13 @@ func New(config Config, logger *zap.Logger) (*Controller, error) {
 	if c.ConcurrencyQuota == 0 {
 		queryQueue = nil
 	}
+	orgQuotas := make(map[platform.ID]OrgQuota, len(c.OrgQuotas))
+	for orgID, quota := range c.OrgQuotas {
+		orgQuotas[orgID] = quota
+	}
 	ctrl := &Controller{
 		config:         c,
+		orgQuotas:      orgQuotas,
 		queries:        make(map[QueryID]*Query),
 		queryQueue:     queryQueue,
 		done:           make(chan struct{}),
@@ -240,6 +260,14 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	// Pin now() on a copy of the request, so the request records the time the query is
+	// evaluated at without changing the request of the caller.
+	pinned := *req
+	if pinned.Now.IsZero() {
+		pinned.Now = time.Now()
+	}
+	pinned.PinNow(pinned.Now)
+	req = &pinned
 	// Set the request on the context so platform specific Flux operations can retrieve it later.
 	ctx = query.ContextWithRequest(ctx, req)
 	// Set the org label value for controller metrics
@@ -324,6 +352,9 @@ func (c *Controller) createQuery(ctx context.Context, compiler flux.Compiler, de
 		deps:               deps,
 		compiler:           compiler,
 	}
+	if req := query.RequestFromContext(ctx); req != nil {
+		q.orgID = req.OrganizationID
+	}
 
 	// Lock the queries mutex for the rest of this method.
 	c.queriesMu.Lock()
@@ -339,6 +370,14 @@ func (c *Controller) createQuery(ctx context.Context, compiler flux.Compiler, de
 		q.setErr(err)
 		return nil, err
 	}
+	if err := c.checkOrgConcurrency(q.orgID); err != nil {
+		err := &flux.Error{
+			Code: codes.ResourceExhausted,
+			Msg:  err.Error(),
+		}
+		q.setErr(err)
+		return nil, err
+	}
 	c.queries[id] = q
 	return q, nil
 }
@@ -601,7 +640,8 @@ func (c *Controller) GetUsedMemoryBytes() int64 {
 
 // Query represents a single request.
 type Query struct {
-	id QueryID
+	id    QueryID
+	orgID platform.ID
 
 	labelValues        []string
 	compileLabelValues []string
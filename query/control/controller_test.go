@@ -23,6 +23,7 @@ import (
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
 	_ "github.com/influxdata/influxdb/v2/fluxinit/static"
+	"github.com/influxdata/influxdb/v2/kit/platform"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/query/control"
 	"github.com/prometheus/client_golang/prometheus"
@@ -805,6 +806,150 @@ func TestController_ConcurrencyQuota(t *testing.T) {
 	}
 }
 
+func TestController_OrgQuota_Concurrency(t *testing.T) {
+	ctrl, err := control.New(config, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	orgID := platform.ID(10)
+	if err := ctrl.SetOrgQuota(orgID, control.OrgQuota{ConcurrencyQuota: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc memory.Allocator) {
+					<-q.Canceled
+				},
+			}, nil
+		},
+	}
+	cancelQuery := func(q flux.Query) {
+		q.Cancel()
+		for range q.Results() {
+			// discard the results
+		}
+		q.Done()
+	}
+
+	q, err := ctrl.Query(context.Background(), makeOrgRequest(orgID, compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A second query of the organization is rejected while the first one is in flight.
+	if _, err := ctrl.Query(context.Background(), makeOrgRequest(orgID, compiler)); err == nil {
+		t.Fatal("expected error about the concurrency quota of the organization")
+	} else if want := "organization 000000000000000a has reached its limit of 1 concurrent queries"; err.Error() != want {
+		t.Fatalf("unexpected error -want/+got:\n\t- %v\n\t+ %v", want, err)
+	}
+
+	// Queries of other organizations are not limited.
+	other, err := ctrl.Query(context.Background(), makeOrgRequest(orgID+1, compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancelQuery(other)
+
+	cancelQuery(q)
+
+	// Once the first query is done, the organization can submit another one.
+	q, err = ctrl.Query(context.Background(), makeOrgRequest(orgID, compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancelQuery(q)
+}
+
+func TestController_OrgQuota_MemoryLimit(t *testing.T) {
+	const memoryBytesQuotaPerQuery = 64
+
+	config := config
+	config.MemoryBytesQuotaPerQuery = memoryBytesQuotaPerQuery
+	ctrl, err := control.New(config, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	orgID := platform.ID(10)
+	if err := ctrl.SetOrgQuota(orgID, control.OrgQuota{MemoryBytesQuotaPerQuery: 4 * memoryBytesQuotaPerQuery}); err != nil {
+		t.Fatal(err)
+	}
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc memory.Allocator) {
+					if err := alloc.Account(2 * memoryBytesQuotaPerQuery); err != nil {
+						q.SetErr(err)
+					}
+				},
+			}, nil
+		},
+	}
+
+	// The organization with an override may use more memory than the default.
+	q, err := ctrl.Query(context.Background(), makeOrgRequest(orgID, compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumeResults(t, q)
+
+	// Other organizations are held to the default.
+	q, err = ctrl.Query(context.Background(), makeOrgRequest(orgID+1, compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+	if q.Err() == nil {
+		t.Fatal("expected error about memory limit exceeded")
+	}
+
+	// Removing the override restores the default.
+	ctrl.DeleteOrgQuota(orgID)
+	q, err = ctrl.Query(context.Background(), makeOrgRequest(orgID, compiler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+	if q.Err() == nil {
+		t.Fatal("expected error about memory limit exceeded")
+	}
+}
+
+func TestController_SetOrgQuota_Invalid(t *testing.T) {
+	config := config
+	config.InitialMemoryBytesQuotaPerQuery = 1024
+	ctrl, err := control.New(config, zaptest.NewLogger(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	for _, quota := range []control.OrgQuota{
+		{MemoryBytesQuotaPerQuery: -1},
+		{MemoryBytesQuotaPerQuery: 512},
+		{ConcurrencyQuota: -1},
+	} {
+		if err := ctrl.SetOrgQuota(10, quota); err == nil {
+			t.Errorf("expected error for quota %+v", quota)
+		}
+	}
+	if quotas := ctrl.OrgQuotas(); len(quotas) != 0 {
+		t.Fatalf("unexpected quotas: %v", quotas)
+	}
+}
+
 func TestController_QueueSize(t *testing.T) {
 	const (
 		concurrencyQuota = 2
@@ -1532,3 +1677,10 @@ func makeRequest(c flux.Compiler) *query.Request {
 		Compiler: c,
 	}
 }
+
+func makeOrgRequest(orgID platform.ID, c flux.Compiler) *query.Request {
+	return &query.Request{
+		OrganizationID: orgID,
+		Compiler:       c,
+	}
+}
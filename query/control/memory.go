@@ -45,6 +45,7 @@ func (m *memoryManager) addUnusedMemoryBytes(amount int64) int64 {
 func (c *Controller) createAllocator(q *Query) {
 	q.memoryManager = &queryMemoryManager{
 		m:     c.memory,
+		quota: c.memoryBytesQuotaPerQuery(q.orgID),
 		limit: c.memory.initialBytesQuotaPerQuery,
 	}
 	q.alloc = &memory.ResourceAllocator{
@@ -56,7 +57,14 @@ func (c *Controller) createAllocator(q *Query) {
 
 // queryMemoryManager is a memory manager for a specific query.
 type queryMemoryManager struct {
-	m     *memoryManager
+	m *memoryManager
+
+	// quota is the maximum amount of memory that may be
+	// allocated to this query. It is the memoryBytesQuotaPerQuery
+	// of the memory manager unless the organization of the
+	// query overrides it.
+	quota int64
+
 	limit int64
 	given int64
 }
@@ -72,8 +80,8 @@ type queryMemoryManager struct {
 // too much about the specific message or structure.
 func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 	// It can be determined statically if we are going to violate
-	// the memoryBytesQuotaPerQuery.
-	if q.limit+want > q.m.memoryBytesQuotaPerQuery {
+	// the memory quota of the query.
+	if q.limit+want > q.quota {
 		return 0, errors.New("query hit hard limit")
 	}
 
@@ -118,12 +126,12 @@ func (q *queryMemoryManager) RequestMemory(want int64) (got int64, err error) {
 func (q *queryMemoryManager) giveMemory(want, unused int64) int64 {
 	// If we can safely double the limit, then just do that.
 	if q.limit > want && q.limit < unused {
-		if q.limit*2 <= q.m.memoryBytesQuotaPerQuery {
+		if q.limit*2 <= q.quota {
 			return q.limit
 		}
 		// Doubling the limit sends us over the quota.
 		// Determine what would be our maximum amount.
-		max := q.m.memoryBytesQuotaPerQuery - q.limit
+		max := q.quota - q.limit
 		if max > want {
 			return max
 		}
@@ -0,0 +1,101 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/influxdata/influxdb/v2/kit/platform"
+)
+
+// OrgQuota overrides the controller defaults for the queries of an organization.
+// A field left to zero keeps the default of the controller.
+type OrgQuota struct {
+	// MemoryBytesQuotaPerQuery is the maximum number of bytes (in table memory)
+	// a query of the organization is allowed to use at any given time.
+	// It must not be lower than the InitialMemoryBytesQuotaPerQuery of the controller.
+	MemoryBytesQuotaPerQuery int64 `json:"memoryBytesQuotaPerQuery,omitempty"`
+
+	// ConcurrencyQuota is the number of queries of the organization that may be
+	// in flight at the same time. Further queries are rejected until one finishes.
+	// The queries of the organization still count against the ConcurrencyQuota
+	// of the controller, so this can only lower the concurrency of an organization.
+	ConcurrencyQuota int32 `json:"concurrencyQuota,omitempty"`
+}
+
+func (q OrgQuota) validate(c *Config) error {
+	if q.MemoryBytesQuotaPerQuery < 0 {
+		return errors.New("MemoryBytesQuotaPerQuery must be positive")
+	}
+	if q.MemoryBytesQuotaPerQuery != 0 && q.MemoryBytesQuotaPerQuery < c.InitialMemoryBytesQuotaPerQuery {
+		return fmt.Errorf("MemoryBytesQuotaPerQuery must be greater than or equal to the InitialMemoryBytesQuotaPerQuery: %d < %d", q.MemoryBytesQuotaPerQuery, c.InitialMemoryBytesQuotaPerQuery)
+	}
+	if q.ConcurrencyQuota < 0 {
+		return errors.New("ConcurrencyQuota must not be negative")
+	}
+	return nil
+}
+
+// OrgQuotas returns the quota overrides of every organization that has one.
+func (c *Controller) OrgQuotas() map[platform.ID]OrgQuota {
+	c.orgQuotasMu.RLock()
+	defer c.orgQuotasMu.RUnlock()
+	quotas := make(map[platform.ID]OrgQuota, len(c.orgQuotas))
+	for orgID, quota := range c.orgQuotas {
+		quotas[orgID] = quota
+	}
+	return quotas
+}
+
+// SetOrgQuota overrides the defaults of the controller for the queries of an organization.
+// It applies to the queries submitted after it is set, until the controller is
+// created again from its Config.
+func (c *Controller) SetOrgQuota(orgID platform.ID, quota OrgQuota) error {
+	if err := quota.validate(&c.config); err != nil {
+		return fmt.Errorf("invalid quota for organization %s: %w", orgID, err)
+	}
+	c.orgQuotasMu.Lock()
+	c.orgQuotas[orgID] = quota
+	c.orgQuotasMu.Unlock()
+	return nil
+}
+
+// DeleteOrgQuota removes the quota overrides of an organization
+// so its queries use the defaults of the controller again.
+func (c *Controller) DeleteOrgQuota(orgID platform.ID) {
+	c.orgQuotasMu.Lock()
+	delete(c.orgQuotas, orgID)
+	c.orgQuotasMu.Unlock()
+}
+
+func (c *Controller) orgQuota(orgID platform.ID) OrgQuota {
+	c.orgQuotasMu.RLock()
+	defer c.orgQuotasMu.RUnlock()
+	return c.orgQuotas[orgID]
+}
+
+// memoryBytesQuotaPerQuery returns the memory quota of the queries of an organization.
+func (c *Controller) memoryBytesQuotaPerQuery(orgID platform.ID) int64 {
+	if quota := c.orgQuota(orgID).MemoryBytesQuotaPerQuery; quota > 0 {
+		return quota
+	}
+	return c.memory.memoryBytesQuotaPerQuery
+}
+
+// checkOrgConcurrency returns an error if the organization already has as many
+// queries in flight as its quota allows. The queries mutex must be held.
+func (c *Controller) checkOrgConcurrency(orgID platform.ID) error {
+	quota := c.orgQuota(orgID).ConcurrencyQuota
+	if quota == 0 {
+		return nil
+	}
+	var n int32
+	for _, q := range c.queries {
+		if q.orgID == orgID {
+			n++
+		}
+	}
+	if n >= quota {
+		return fmt.Errorf("organization %s has reached its limit of %d concurrent queries", orgID, quota)
+	}
+	return nil
+}
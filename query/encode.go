@@ -1,6 +1,7 @@
 package query
 
 import (
+	"bytes"
 	"io"
 	"net/http"
 
@@ -11,17 +12,28 @@ import (
 const (
 	NoContentDialectType     = "no-content"
 	NoContentWErrDialectType = "no-content-with-error"
+	LFDialectType            = "csv-lf"
 )
 
-// AddDialectMappings adds the mappings for the no-content dialects.
+// AddDialectMappings adds the mappings for the no-content, csv-lf and table dialects.
 func AddDialectMappings(mappings flux.DialectMappings) error {
 	if err := mappings.Add(NoContentDialectType, func() flux.Dialect {
 		return NewNoContentDialect()
 	}); err != nil {
 		return err
 	}
-	return mappings.Add(NoContentWErrDialectType, func() flux.Dialect {
+	if err := mappings.Add(NoContentWErrDialectType, func() flux.Dialect {
 		return NewNoContentWithErrorDialect()
+	}); err != nil {
+		return err
+	}
+	if err := mappings.Add(LFDialectType, func() flux.Dialect {
+		return NewLFDialect()
+	}); err != nil {
+		return err
+	}
+	return mappings.Add(TableDialectType, func() flux.Dialect {
+		return NewTableDialect()
 	})
 }
 
@@ -130,3 +142,92 @@ func (e *NoContentWithErrorEncoder) Encode(w io.Writer, results flux.ResultItera
 	}
 	return 0, nil
 }
+
+// LFDialect is the annotated CSV dialect with its lines terminated by "\n"
+// instead of the "\r\n" required by RFC 4180, as expected by most
+// spreadsheet and command line tools.
+type LFDialect struct {
+	csv.ResultEncoderConfig
+}
+
+func NewLFDialect() *LFDialect {
+	return &LFDialect{
+		ResultEncoderConfig: csv.DefaultEncoderConfig(),
+	}
+}
+
+func (d *LFDialect) Encoder() flux.MultiResultEncoder {
+	return &LFEncoder{
+		encoder: csv.NewMultiResultEncoder(d.ResultEncoderConfig),
+	}
+}
+
+func (d *LFDialect) DialectType() flux.DialectType {
+	return LFDialectType
+}
+
+func (d *LFDialect) SetHeaders(w http.ResponseWriter) {
+	csv.Dialect{ResultEncoderConfig: d.ResultEncoderConfig}.SetHeaders(w)
+}
+
+// LFEncoder encodes the results as annotated CSV and replaces each "\r\n" with "\n".
+type LFEncoder struct {
+	encoder flux.MultiResultEncoder
+}
+
+func (e *LFEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	lw := &lfWriter{w: w}
+	n, err := e.encoder.Encode(lw, results)
+	if ferr := lw.flush(); err == nil {
+		err = ferr
+	}
+	return n - lw.removed, err
+}
+
+// lfWriter replaces each "\r\n" written to it with "\n". A "\r" ending a
+// write is held until the next write tells whether it ends a line.
+type lfWriter struct {
+	w       io.Writer
+	cr      bool
+	removed int64
+}
+
+func (w *lfWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	buf := make([]byte, 0, len(p)+1)
+	if w.cr {
+		if p[0] != '\n' {
+			buf = append(buf, '\r')
+		} else {
+			w.removed++
+		}
+		w.cr = false
+	}
+	if bytes.HasSuffix(p, []byte{'\r'}) {
+		w.cr = true
+		p = p[:len(p)-1]
+	}
+	n := len(buf)
+	buf = append(buf, p...)
+	buf = bytes.ReplaceAll(buf, []byte("\r\n"), []byte("\n"))
+	w.removed += int64(n + len(p) - len(buf))
+	if _, err := w.w.Write(buf); err != nil {
+		return 0, err
+	}
+	if w.cr {
+		return len(p) + 1, nil
+	}
+	return len(p), nil
+}
+
+// flush writes the "\r" held by the last write.
+func (w *lfWriter) flush() error {
+	if !w.cr {
+		return nil
+	}
+	w.cr = false
+	_, err := w.w.Write([]byte{'\r'})
+	return err
+}
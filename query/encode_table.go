@@ -0,0 +1,210 @@
+package query
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	TableDialectType = "table"
+
+	// DefaultTableMaxColumnWidth is the width the values are truncated to by default.
+	DefaultTableMaxColumnWidth = 40
+)
+
+// TableDialect renders the results as aligned text tables, to be read in a terminal.
+// It is an HTTPDialect that sets the response content type to text/plain.
+type TableDialect struct {
+	// MaxColumnWidth is the number of characters the values longer than it
+	// are truncated to. The values are not truncated when it is zero.
+	MaxColumnWidth int
+}
+
+func NewTableDialect() *TableDialect {
+	return &TableDialect{
+		MaxColumnWidth: DefaultTableMaxColumnWidth,
+	}
+}
+
+func (d *TableDialect) Encoder() flux.MultiResultEncoder {
+	return &TableEncoder{MaxColumnWidth: d.MaxColumnWidth}
+}
+
+func (d *TableDialect) DialectType() flux.DialectType {
+	return TableDialectType
+}
+
+func (d *TableDialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Transfer-Encoding", "chunked")
+}
+
+// TableEncoder renders each table of the results with a line naming its group key,
+// a header with the label and type of each column and a line for each row.
+// The columns are aligned within each table, so its rows are buffered.
+//
+//	Result: _result
+//	Table: keys: [_measurement, host]
+//	_measurement:string  host:string  _time:time            _value:float
+//	-------------------  -----------  --------------------  ------------
+//	cpu                  a            2018-08-29T13:08:47Z  10.2
+type TableEncoder struct {
+	MaxColumnWidth int
+}
+
+func (e *TableEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	defer results.Release()
+
+	cw := &iocounter.Writer{Writer: w}
+	bw := bufio.NewWriter(cw)
+	err := e.encode(bw, results)
+	if ferr := bw.Flush(); err == nil {
+		err = ferr
+	} else if ferr == nil && cw.Count() > 0 {
+		// Like the CSV encoder, report the error in the response once a part of
+		// it was sent; an error before that is left to the caller.
+		_, _ = fmt.Fprintf(cw, "\nError: %s\n", err)
+	}
+	return cw.Count(), err
+}
+
+func (e *TableEncoder) encode(w *bufio.Writer, results flux.ResultIterator) error {
+	first := true
+	for results.More() {
+		res := results.Next()
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			if !first {
+				if err := w.WriteByte('\n'); err != nil {
+					return err
+				}
+			}
+			first = false
+			if err := e.encodeTable(w, res.Name(), tbl); err != nil {
+				return err
+			}
+			// Send each table to the client as soon as it is rendered.
+			return w.Flush()
+		}); err != nil {
+			return err
+		}
+	}
+	results.Release()
+	return results.Err()
+}
+
+func (e *TableEncoder) encodeTable(w *bufio.Writer, result string, tbl flux.Table) error {
+	cols := tbl.Cols()
+	header := make([]string, len(cols))
+	for j, c := range cols {
+		header[j] = c.Label + ":" + c.Type.String()
+	}
+	rows := [][]string{header}
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			row := make([]string, len(cols))
+			for j := range cols {
+				row[j] = e.truncate(formatTableValue(cols[j].Type, execute.ValueForRow(cr, i, j)))
+			}
+			rows = append(rows, row)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	widths := make([]int, len(cols))
+	for _, row := range rows {
+		for j, v := range row {
+			if n := utf8.RuneCountInString(v); n > widths[j] {
+				widths[j] = n
+			}
+		}
+	}
+	separator := make([]string, len(cols))
+	for j, n := range widths {
+		separator[j] = strings.Repeat("-", n)
+	}
+
+	keys := make([]string, len(tbl.Key().Cols()))
+	for j, c := range tbl.Key().Cols() {
+		keys[j] = c.Label
+	}
+	if _, err := fmt.Fprintf(w, "Result: %s\nTable: keys: [%s]\n", result, strings.Join(keys, ", ")); err != nil {
+		return err
+	}
+	if err := writeTableRow(w, header, widths); err != nil {
+		return err
+	}
+	if err := writeTableRow(w, separator, widths); err != nil {
+		return err
+	}
+	for _, row := range rows[1:] {
+		if err := writeTableRow(w, row, widths); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncate shortens v to the maximum column width, ending it with "...".
+func (e *TableEncoder) truncate(v string) string {
+	if e.MaxColumnWidth <= 0 || utf8.RuneCountInString(v) <= e.MaxColumnWidth {
+		return v
+	}
+	if e.MaxColumnWidth <= 3 {
+		return string([]rune(v)[:e.MaxColumnWidth])
+	}
+	return string([]rune(v)[:e.MaxColumnWidth-3]) + "..."
+}
+
+// writeTableRow writes the values of a row, each padded to the width of its column.
+// The trailing spaces are trimmed, so the last column is not padded.
+func writeTableRow(w *bufio.Writer, row []string, widths []int) error {
+	var b strings.Builder
+	for j, v := range row {
+		if j > 0 {
+			b.WriteString("  ")
+		}
+		b.WriteString(v)
+		b.WriteString(strings.Repeat(" ", widths[j]-utf8.RuneCountInString(v)))
+	}
+	if _, err := w.WriteString(strings.TrimRight(b.String(), " ")); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+// formatTableValue formats a value of a column of type typ like the annotated
+// CSV encoder does. A null value is rendered empty.
+func formatTableValue(typ flux.ColType, v values.Value) string {
+	if v.IsNull() {
+		return ""
+	}
+	switch typ {
+	case flux.TBool:
+		return strconv.FormatBool(v.Bool())
+	case flux.TInt:
+		return strconv.FormatInt(v.Int(), 10)
+	case flux.TUInt:
+		return strconv.FormatUint(v.UInt(), 10)
+	case flux.TFloat:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case flux.TString:
+		return v.Str()
+	case flux.TTime:
+		return v.Time().Time().UTC().Format(time.RFC3339Nano)
+	default:
+		return ""
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math/rand"
+	"reflect"
 	"testing"
+	"testing/quick"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/flux"
@@ -127,3 +130,245 @@ func TestReturnNoContent(t *testing.T) {
 		})
 	}
 }
+
+// csvTables is a randomly generated set of result tables used to verify
+// that the annotated CSV encoder and decoder round trip exactly.
+type csvTables []*executetest.Table
+
+var csvColumnTypes = []flux.ColType{
+	flux.TBool,
+	flux.TInt,
+	flux.TUInt,
+	flux.TFloat,
+	flux.TString,
+	flux.TTime,
+}
+
+// Generate implements quick.Generator.
+func (csvTables) Generate(r *rand.Rand, size int) reflect.Value {
+	// Every table shares the same schema, but has a distinct group key.
+	cols := []flux.ColMeta{
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "_time", Type: flux.TTime},
+	}
+	keyCols := []string{"_measurement"}
+	for i, n := 0, r.Intn(3); i < n; i++ {
+		label := fmt.Sprintf("k%d", i)
+		cols = append(cols, flux.ColMeta{Label: label, Type: csvColumnTypes[r.Intn(len(csvColumnTypes))]})
+		keyCols = append(keyCols, label)
+	}
+	for i, n := 0, 1+r.Intn(4); i < n; i++ {
+		cols = append(cols, flux.ColMeta{
+			Label: fmt.Sprintf("v%d", i),
+			Type:  csvColumnTypes[r.Intn(len(csvColumnTypes))],
+		})
+	}
+	r.Shuffle(len(cols), func(i, j int) {
+		cols[i], cols[j] = cols[j], cols[i]
+	})
+
+	isKey := func(label string) bool {
+		for _, k := range keyCols {
+			if k == label {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The decoder reports the group key columns in column order.
+	var orderedKeyCols []string
+	for _, c := range cols {
+		if isKey(c.Label) {
+			orderedKeyCols = append(orderedKeyCols, c.Label)
+		}
+	}
+	keyCols = orderedKeyCols
+
+	tables := make(csvTables, 1+r.Intn(4))
+	for i := range tables {
+		key := make([]interface{}, len(cols))
+		for j, c := range cols {
+			if c.Label == "_measurement" {
+				key[j] = fmt.Sprintf("%s-%d", randomCSVString(r), i)
+			} else if isKey(c.Label) {
+				key[j] = randomCSVValue(r, c.Type)
+			}
+		}
+
+		tbl := &executetest.Table{
+			KeyCols: keyCols,
+			ColMeta: cols,
+		}
+		for n := 1 + r.Intn(size+1); n > 0; n-- {
+			row := make([]interface{}, len(cols))
+			for j, c := range cols {
+				switch {
+				case isKey(c.Label):
+					row[j] = key[j]
+				case c.Label != "_time" && r.Intn(5) == 0:
+					// leave the value null
+				default:
+					row[j] = randomCSVValue(r, c.Type)
+				}
+			}
+			tbl.Data = append(tbl.Data, row)
+		}
+		tables[i] = tbl
+	}
+	return reflect.ValueOf(tables)
+}
+
+// result returns a new result backed by a copy of the tables, since
+// reading an executetest.Table marks it as done.
+func (ts csvTables) result() *executetest.Result {
+	tables := make([]*executetest.Table, len(ts))
+	for i, t := range ts {
+		tables[i] = &executetest.Table{
+			KeyCols: t.KeyCols,
+			ColMeta: t.ColMeta,
+			Data:    t.Data,
+		}
+	}
+	return &executetest.Result{Nm: "_result", Tbls: tables}
+}
+
+func randomCSVValue(r *rand.Rand, typ flux.ColType) interface{} {
+	switch typ {
+	case flux.TBool:
+		return r.Intn(2) == 0
+	case flux.TInt:
+		return r.Int63() - r.Int63()
+	case flux.TUInt:
+		return r.Uint64()
+	case flux.TFloat:
+		return r.NormFloat64() * 1e6
+	case flux.TString:
+		return randomCSVString(r)
+	case flux.TTime:
+		return execute.Time(r.Int63n(1 << 62))
+	}
+	panic(fmt.Sprintf("unexpected column type %v", typ))
+}
+
+// randomCSVString returns a non-empty string, since an empty string cannot
+// be distinguished from a null value in annotated CSV. The alphabet contains
+// the characters that require quoting.
+func randomCSVString(r *rand.Rand) string {
+	const alphabet = "abcXYZ09 ,\"\n#=_-\u00e9\u65e5"
+	runes := []rune(alphabet)
+	s := make([]rune, 1+r.Intn(12))
+	for i := range s {
+		s[i] = runes[r.Intn(len(runes))]
+	}
+	return string(s)
+}
+
+func TestCSVEncodeDecodeRoundTrip(t *testing.T) {
+	roundTrip := func(tables csvTables) bool {
+		var buf bytes.Buffer
+		enc := csv.NewResultEncoder(csv.DefaultEncoderConfig())
+		if _, err := enc.Encode(&buf, tables.result()); err != nil {
+			t.Logf("unexpected encoding error: %v", err)
+			return false
+		}
+		encoded := buf.String()
+
+		got, err := csv.NewResultDecoder(csv.ResultDecoderConfig{}).Decode(&buf)
+		if err != nil {
+			t.Logf("unexpected decoding error: %v\n%s", err, encoded)
+			return false
+		}
+		if err := executetest.EqualResults([]flux.Result{tables.result()}, []flux.Result{got}); err != nil {
+			t.Logf("%v\n%s", err, encoded)
+			return false
+		}
+		return true
+	}
+
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 200}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLFDialect(t *testing.T) {
+	var tables csvTables
+	r := rand.New(rand.NewSource(1))
+	tables = tables.Generate(r, 10).Interface().(csvTables)
+
+	encode := func(d flux.Dialect) ([]byte, int64) {
+		var buf bytes.Buffer
+		n, err := d.Encoder().Encode(&buf, flux.NewSliceResultIterator([]flux.Result{tables.result()}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes(), n
+	}
+	crlf, _ := encode(csv.DefaultDialect())
+	lf, n := encode(query.NewLFDialect())
+
+	if want := bytes.ReplaceAll(crlf, []byte("\r\n"), []byte("\n")); !bytes.Equal(lf, want) {
+		t.Fatalf("unexpected csv, -want/+got:\n%s", cmp.Diff(string(want), string(lf)))
+	}
+	if bytes.Contains(lf, []byte("\r")) {
+		t.Fatal("unexpected carriage return in csv")
+	}
+	if n != int64(len(lf)) {
+		t.Fatalf("unexpected number of bytes: got %d, want %d", n, len(lf))
+	}
+}
+
+func TestTableDialect(t *testing.T) {
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "host", Type: flux.TString},
+		{Label: "msg", Type: flux.TString},
+	}
+	r := executetest.NewResult([]*executetest.Table{
+		{
+			KeyCols: []string{"host"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(0), 1.5, "a", "ok"},
+				{execute.Time(10e9), nil, "a", "a message longer than the column width"},
+			},
+		},
+		{
+			KeyCols: []string{"host"},
+			ColMeta: cols,
+			Data: [][]interface{}{
+				{execute.Time(0), 20.0, "server-b", nil},
+			},
+		},
+	})
+	r.Nm = "_result"
+
+	d := query.NewTableDialect()
+	d.MaxColumnWidth = 20
+	var buf bytes.Buffer
+	n, err := d.Encoder().Encode(&buf, flux.NewSliceResultIterator([]flux.Result{r}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Result: _result
+Table: keys: [host]
+_time:time            _value:float  host:string  msg:string
+--------------------  ------------  -----------  --------------------
+1970-01-01T00:00:00Z  1.5           a            ok
+1970-01-01T00:00:10Z                a            a message longer ...
+
+Result: _result
+Table: keys: [host]
+_time:time            _value:float  host:string  msg:string
+--------------------  ------------  -----------  ----------
+1970-01-01T00:00:00Z  20            server-b
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected table, -want/+got:\n%s", cmp.Diff(want, got))
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("unexpected number of bytes: got %d, want %d", n, buf.Len())
+	}
+}
@@ -5,12 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
 	platform "github.com/influxdata/influxdb/v2"
 	platform2 "github.com/influxdata/influxdb/v2/kit/platform"
 )
 
+// RequestVersion is the version of the JSON format of a Request written by MarshalJSON.
+// Requests are persisted and exchanged between nodes, so the version must be increased
+// whenever the format changes and UnmarshalJSON must keep reading the previous version.
+//
+// Version 0 is the format written before the version was recorded.
+// It has the same layout as version 1.
+const RequestVersion = 1
+
 const (
 	PreferHeaderKey                = "Prefer"
 	PreferNoContentHeaderValue     = "return-no-content"
@@ -34,6 +44,10 @@ type Request struct {
 	// Source represents the ultimate source of the request.
 	Source string `json:"source"`
 
+	// Now is the time now() evaluates to when the compiler does not have one.
+	// When it is zero, now() evaluates to the time the request is submitted.
+	Now time.Time `json:"-"`
+
 	// compilerMappings maps compiler types to creation methods
 	compilerMappings flux.CompilerMappings
 
@@ -80,9 +94,58 @@ func (r *Request) WithCompilerMappings(mappings flux.CompilerMappings) {
 	r.compilerMappings = mappings
 }
 
+// PinNow sets the value of now() for the query to now, unless the compiler already has one.
+// Once pinned, encoding and replaying the request evaluates now() to the same time,
+// which makes task runs and replayed queries deterministic.
+// Compilers that do not have a now() value are left unchanged.
+// The compiler is replaced with a pinned copy, as it may be shared with other requests.
+func (r *Request) PinNow(now time.Time) {
+	switch c := r.Compiler.(type) {
+	case lang.FluxCompiler:
+		if c.Now.IsZero() {
+			c.Now = now
+			r.Compiler = c
+		}
+	case *lang.FluxCompiler:
+		if c.Now.IsZero() {
+			pinned := *c
+			pinned.Now = now
+			r.Compiler = &pinned
+		}
+	case lang.ASTCompiler:
+		if c.Now.IsZero() {
+			c.Now = now
+			r.Compiler = c
+		}
+	case *lang.ASTCompiler:
+		if c.Now.IsZero() {
+			pinned := *c
+			pinned.Now = now
+			r.Compiler = &pinned
+		}
+	}
+}
+
 // UnmarshalJSON populates the request from the JSON data.
+// It reads the current and the previous version of the format.
 // WithCompilerMappings must have been called or an error will occur.
 func (r *Request) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch v.Version {
+	case 0, 1:
+		return r.unmarshalJSONv1(data)
+	default:
+		return fmt.Errorf("unsupported request version %d, the newest supported version is %d", v.Version, RequestVersion)
+	}
+}
+
+func (r *Request) unmarshalJSONv1(data []byte) error {
 	type Alias Request
 	raw := struct {
 		*Alias
@@ -112,9 +175,11 @@ func (r *Request) UnmarshalJSON(data []byte) error {
 func (r Request) MarshalJSON() ([]byte, error) {
 	type Alias Request
 	raw := struct {
+		Version int `json:"version"`
 		Alias
 		CompilerType flux.CompilerType `json:"compiler_type"`
 	}{
+		Version:      RequestVersion,
 		Alias:        (Alias)(r),
 		CompilerType: r.Compiler.CompilerType(),
 	}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/influxdb/v2/query"
 	platformtesting "github.com/influxdata/influxdb/v2/testing"
 )
@@ -57,7 +59,7 @@ func TestRequest_JSON(t *testing.T) {
 	}{
 		{
 			name: "simple",
-			data: `{"organization_id":"aaaaaaaaaaaaaaaa","compiler":{"a":"my custom compiler"},"source":"source","compiler_type":"compilerA"}`,
+			data: `{"version":1,"organization_id":"aaaaaaaaaaaaaaaa","compiler":{"a":"my custom compiler"},"source":"source","compiler_type":"compilerA"}`,
 			want: query.Request{
 				OrganizationID: platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa"),
 				Compiler: &compilerA{
@@ -87,6 +89,102 @@ func TestRequest_JSON(t *testing.T) {
 	}
 }
 
+func TestRequest_JSON_Versions(t *testing.T) {
+	want := query.Request{
+		OrganizationID: platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa"),
+		Compiler: &compilerA{
+			A: "my custom compiler",
+		},
+		Source: "source",
+	}
+
+	// requests written before the version was recorded are still read
+	var r query.Request
+	r.WithCompilerMappings(compilerMappings)
+	if err := json.Unmarshal([]byte(`{"organization_id":"aaaaaaaaaaaaaaaa","compiler":{"a":"my custom compiler"},"source":"source","compiler_type":"compilerA"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(want, r, CmpOpts...) {
+		t.Fatalf("unexpected request: -want/+got:\n%s", cmp.Diff(want, r, CmpOpts...))
+	}
+
+	// and are written with the current version
+	marshalled, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(marshalled), `{"version":1,"organization_id":"aaaaaaaaaaaaaaaa","compiler":{"a":"my custom compiler"},"source":"source","compiler_type":"compilerA"}`; got != want {
+		t.Fatalf("unexpected marshalled request: -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	// requests from a newer version are rejected instead of being misread
+	var next query.Request
+	next.WithCompilerMappings(compilerMappings)
+	err = json.Unmarshal([]byte(`{"version":2,"orgID":"aaaaaaaaaaaaaaaa"}`), &next)
+	if err == nil || err.Error() != "unsupported request version 2, the newest supported version is 1" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRequest_PinNow(t *testing.T) {
+	now := time.Date(2018, 9, 13, 0, 0, 0, 0, time.UTC)
+	pinned := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		compiler flux.Compiler
+		want     flux.Compiler
+	}{
+		{
+			name:     "flux",
+			compiler: lang.FluxCompiler{Query: "buckets()"},
+			want:     lang.FluxCompiler{Query: "buckets()", Now: now},
+		},
+		{
+			name:     "flux pointer",
+			compiler: &lang.FluxCompiler{Query: "buckets()"},
+			want:     &lang.FluxCompiler{Query: "buckets()", Now: now},
+		},
+		{
+			name:     "flux already pinned",
+			compiler: lang.FluxCompiler{Query: "buckets()", Now: pinned},
+			want:     lang.FluxCompiler{Query: "buckets()", Now: pinned},
+		},
+		{
+			name:     "ast",
+			compiler: lang.ASTCompiler{},
+			want:     lang.ASTCompiler{Now: now},
+		},
+		{
+			name:     "ast already pinned",
+			compiler: &lang.ASTCompiler{Now: pinned},
+			want:     &lang.ASTCompiler{Now: pinned},
+		},
+		{
+			name:     "without now",
+			compiler: &compilerA{A: "my custom compiler"},
+			want:     &compilerA{A: "my custom compiler"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := query.Request{Compiler: tc.compiler}
+			r.PinNow(now)
+			if !cmp.Equal(tc.want, r.Compiler) {
+				t.Fatalf("unexpected compiler: -want/+got:\n%s", cmp.Diff(tc.want, r.Compiler))
+			}
+		})
+	}
+
+	// the compiler of the caller is not changed
+	compiler := &lang.FluxCompiler{Query: "buckets()"}
+	r := query.Request{Compiler: compiler}
+	r.PinNow(now)
+	if !compiler.Now.IsZero() {
+		t.Fatalf("unexpected change of the compiler: %v", compiler.Now)
+	}
+}
+
 func TestProxyRequest_JSON(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -95,7 +193,7 @@ func TestProxyRequest_JSON(t *testing.T) {
 	}{
 		{
 			name: "simple",
-			data: `{"request":{"organization_id":"aaaaaaaaaaaaaaaa","compiler":{"a":"my custom compiler"},"source":"source","compiler_type":"compilerA"},"dialect":{"b":42},"dialect_type":"dialectB"}`,
+			data: `{"request":{"version":1,"organization_id":"aaaaaaaaaaaaaaaa","compiler":{"a":"my custom compiler"},"source":"source","compiler_type":"compilerA"},"dialect":{"b":42},"dialect_type":"dialectB"}`,
 			want: query.ProxyRequest{
 				Request: query.Request{
 					OrganizationID: platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa"),
@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"context"
+	nethttp "net/http"
 
 	"github.com/influxdata/flux"
 	fluxfeature "github.com/influxdata/flux/dependencies/feature"
@@ -14,6 +15,7 @@ import (
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/storage"
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
 )
 
 type key int
@@ -79,13 +81,62 @@ func (d Dependencies) PrometheusCollectors() []prometheus.Collector {
 	return collectors
 }
 
-type FluxDepOption func(*flux.Deps)
+type FluxDepOption func(*fluxDepOptions)
+
+type fluxDepOptions struct {
+	deps flux.Deps
+
+	httpRequestsPerSecond int
+}
 
 func WithURLValidator(v url.Validator) FluxDepOption {
-	return func(d *flux.Deps) {
-		d.Deps.URLValidator = v
-		d.Deps.HTTPClient = http.NewDefaultClient(d.Deps.URLValidator)
+	return func(o *fluxDepOptions) {
+		o.deps.Deps.URLValidator = v
+		o.deps.Deps.HTTPClient = http.NewDefaultClient(o.deps.Deps.URLValidator)
+	}
+}
+
+// WithHTTPRequestLimit limits the HTTP requests of the queries, such as the
+// http.post calls notifying webhooks from alerting tasks, to requestsPerSecond.
+// A request over the limit waits for its turn until its context is done.
+func WithHTTPRequestLimit(requestsPerSecond int) FluxDepOption {
+	return func(o *fluxDepOptions) {
+		o.httpRequestsPerSecond = requestsPerSecond
+	}
+}
+
+// limitedHTTPClient is an HTTP client shared by all the queries
+// which sends at most the requests allowed by its limiter.
+type limitedHTTPClient struct {
+	client  http.Client
+	limiter *rate.Limiter
+}
+
+func (c *limitedHTTPClient) Do(req *nethttp.Request) (*nethttp.Response, error) {
+	if err := c.limiter.Wait(req.Context()); err != nil {
+		return nil, err
 	}
+	return c.client.Do(req)
+}
+
+// newFluxDeps returns the flux dependencies configured by fluxopts.
+func newFluxDeps(ss influxdb.SecretService, fluxopts ...FluxDepOption) flux.Deps {
+	o := fluxDepOptions{deps: flux.NewDefaultDependencies()}
+	o.deps.Deps.HTTPClient = http.NewDefaultClient(url.PassValidator{})
+	o.deps.Deps.SecretService = query.FromSecretService(ss)
+	for _, opt := range fluxopts {
+		opt(&o)
+	}
+
+	// The limit applies to the HTTP client chosen by the other options,
+	// whatever their order.
+	if o.httpRequestsPerSecond > 0 {
+		o.deps.Deps.HTTPClient = &limitedHTTPClient{
+			client:  o.deps.Deps.HTTPClient,
+			limiter: rate.NewLimiter(rate.Limit(o.httpRequestsPerSecond), o.httpRequestsPerSecond),
+		}
+	}
+	return o.deps
 }
 
 func NewDependencies(
@@ -97,15 +148,7 @@ func NewDependencies(
 	metricLabelKeys []string,
 	fluxopts ...FluxDepOption,
 ) (Dependencies, error) {
-	fdeps := flux.NewDefaultDependencies()
-	fdeps.Deps.HTTPClient = http.NewDefaultClient(url.PassValidator{})
-	fdeps.Deps.SecretService = query.FromSecretService(ss)
-	// apply fluxopts before assigning fdeps to deps (ie, before casting)
-	for _, opt := range fluxopts {
-		opt(&fdeps)
-	}
-
-	deps := Dependencies{FluxDeps: fdeps}
+	deps := Dependencies{FluxDeps: newFluxDeps(ss, fluxopts...)}
 	bucketLookupSvc := query.FromBucketService(bucketSvc)
 	orgLookupSvc := query.FromOrganizationService(orgSvc)
 	metrics := NewMetrics(metricLabelKeys)
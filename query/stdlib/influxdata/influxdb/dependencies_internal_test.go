@@ -0,0 +1,62 @@
+package influxdb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/dependencies/url"
+)
+
+func TestWithHTTPRequestLimit(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []FluxDepOption
+	}{
+		{name: "limit last", opts: []FluxDepOption{WithURLValidator(url.PassValidator{}), WithHTTPRequestLimit(2)}},
+		{name: "limit first", opts: []FluxDepOption{WithHTTPRequestLimit(2), WithURLValidator(url.PassValidator{})}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// The limit applies whatever the order of the options replacing the client.
+			client, ok := newFluxDeps(nil, tt.opts...).Deps.HTTPClient.(*limitedHTTPClient)
+			if !ok {
+				t.Fatal("expected the HTTP client to be limited")
+			}
+			var count countingHTTPClient
+			client.client = &count
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/alert", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The burst is allowed right away, the next request waits past the deadline.
+			for i := 0; i < 2; i++ {
+				if _, err := client.Do(req); err != nil {
+					t.Fatalf("unexpected error on request %d: %s", i, err)
+				}
+			}
+			if _, err := client.Do(req); err == nil {
+				t.Fatal("expected the request over the limit to fail")
+			}
+			if count != 2 {
+				t.Fatalf("unexpected number of requests: got %d, want 2", count)
+			}
+		})
+	}
+
+	// No limit keeps the client.
+	if _, ok := newFluxDeps(nil, WithHTTPRequestLimit(0)).Deps.HTTPClient.(*limitedHTTPClient); ok {
+		t.Fatal("expected the HTTP client not to be limited")
+	}
+}
+
+type countingHTTPClient int
+
+func (c *countingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	*c++
+	return &http.Response{StatusCode: http.StatusNoContent}, nil
+}
@@ -72,20 +72,26 @@ func (s *ReadRangePhysSpec) Copy() plan.ProcedureSpec {
 }
 
 func (s *ReadRangePhysSpec) LookupBucketID(ctx context.Context, orgID platform.ID, buckets BucketLookup) (platform.ID, error) {
-	// Determine bucketID
+	return lookupBucketID(ctx, orgID, buckets, s.Bucket, s.BucketID)
+}
+
+// lookupBucketID resolves the bucket passed to from() to its ID.
+// A bucket name is resolved within the organization submitting the query,
+// so a bucket with the same name in another organization is never matched.
+func lookupBucketID(ctx context.Context, orgID platform.ID, buckets BucketLookup, name, id string) (platform.ID, error) {
 	switch {
-	case s.Bucket != "":
-		b, ok := buckets.Lookup(ctx, orgID, s.Bucket)
+	case name != "":
+		b, ok := buckets.Lookup(ctx, orgID, name)
 		if !ok {
 			return 0, &flux.Error{
 				Code: codes.NotFound,
-				Msg:  fmt.Sprintf("could not find bucket %q", s.Bucket),
+				Msg:  fmt.Sprintf("could not find bucket %q in organization %s", name, orgID),
 			}
 		}
 		return b, nil
-	case len(s.BucketID) != 0:
+	case len(id) != 0:
 		var b platform.ID
-		if err := b.DecodeFromString(s.BucketID); err != nil {
+		if err := b.DecodeFromString(id); err != nil {
 			return 0, &flux.Error{
 				Code: codes.Invalid,
 				Msg:  "invalid bucket id",
@@ -7,6 +7,7 @@ import (
 
 	"github.com/apache/arrow/go/v7/arrow/memory"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/table"
 	"github.com/influxdata/flux/execute/table/static"
@@ -223,6 +224,59 @@ func TestProvider_SeriesCardinalityReader_MissingRequestContext(t *testing.T) {
 	require.Equal(t, wantErr, gotErr)
 }
 
+func TestProvider_SeriesCardinalityReader_BucketNotFound(t *testing.T) {
+	t.Parallel()
+
+	store := &mock.ReadsStore{
+		ReadSeriesCardinalityFn: func(ctx context.Context, req *datatypes.ReadSeriesCardinalityRequest) (cursors.Int64Iterator, error) {
+			return nil, &errors.Error{
+				Code: errors.EInvalid,
+				Msg:  "unexpected read",
+			}
+		},
+		SupportReadSeriesCardinalityFn: func(ctx context.Context) bool {
+			return true
+		},
+	}
+
+	provider := influxdb.Provider{
+		Reader:       storageflux.NewReader(store),
+		BucketLookup: mock.BucketLookup{},
+	}
+
+	ctx := query.ContextWithRequest(
+		context.Background(),
+		&query.Request{
+			OrganizationID: orgID,
+		},
+	)
+
+	wantErr := &flux.Error{
+		Code: codes.NotFound,
+		Msg:  `could not find bucket "other-bucket" in organization 000000000000000a`,
+	}
+
+	_, gotErr := provider.SeriesCardinalityReaderFor(
+		ctx,
+		influxdb.Config{
+			Bucket: influxdb.NameOrID{
+				Name: "other-bucket",
+			},
+		},
+		flux.Bounds{
+			Start: flux.Time{
+				Absolute: time.Unix(1, 0),
+			},
+			Stop: flux.Time{
+				Absolute: time.Unix(2, 0),
+			},
+		},
+		nil,
+	)
+
+	require.Equal(t, wantErr, gotErr)
+}
+
 func TestWriterFor(t *testing.T) {
 	t.Parallel()
 
@@ -713,23 +713,58 @@ func canPushWindowedAggregate(ctx context.Context, fnNode plan.Node) bool {
 	return true
 }
 
-func isPushableWindow(windowSpec *universe.WindowProcedureSpec) bool {
+// pushableWindowOffset returns the offset of the window in UTC, and whether
+// the window can be pushed down to storage.
+func pushableWindowOffset(windowSpec *universe.WindowProcedureSpec) (flux.Duration, bool) {
 	// every and period must be equal
 	// every.isNegative must be false
 	// offset.isNegative must be false
-	// location must be UTC with no offset
+	// location must be UTC, with a fixed offset
 	// timeColumn: must be "_time"
 	// startColumn: must be "_start"
 	// stopColumn: must be "_stop"
 	// createEmpty: must be false
 	window := windowSpec.Window
-	return window.Every.Equal(window.Period) &&
-		!window.Every.IsNegative() &&
-		!window.Offset.IsNegative() &&
-		window.Location.IsUTC() &&
-		windowSpec.TimeColumn == "_time" &&
-		windowSpec.StartColumn == "_start" &&
-		windowSpec.StopColumn == "_stop"
+	if !window.Every.Equal(window.Period) ||
+		window.Every.IsNegative() ||
+		windowSpec.TimeColumn != "_time" ||
+		windowSpec.StartColumn != "_start" ||
+		windowSpec.StopColumn != "_stop" {
+		return flux.Duration{}, false
+	}
+	offset, ok := utcWindowOffset(window)
+	if !ok || offset.IsNegative() {
+		return flux.Duration{}, false
+	}
+	return offset, true
+}
+
+// utcWindowOffset folds the fixed offset of the location of the window into
+// the offset of the window, so windows aligned to the local midnight of a
+// timezone like UTC+02:00 are aligned the same way in UTC. Named locations
+// can have daylight saving time, which storage does not know about.
+func utcWindowOffset(window plan.WindowSpec) (flux.Duration, bool) {
+	if name := window.Location.Name; name != "" && name != "UTC" {
+		return flux.Duration{}, false
+	}
+	locOffset := window.Location.Offset
+	if locOffset.IsZero() {
+		return window.Offset, true
+	}
+	if !locOffset.NanoOnly() || window.Offset.Months() != 0 {
+		return flux.Duration{}, false
+	}
+
+	// The windows start at the epoch minus the location offset plus the window offset.
+	offset := window.Offset.Duration() - locOffset.Duration()
+	if window.Every.NanoOnly() {
+		// Any multiple of every can be added to the offset.
+		every := window.Every.Duration()
+		if offset %= every; offset < 0 {
+			offset += every
+		}
+	}
+	return values.ConvertDurationNsecs(offset), true
 }
 
 func (PushDownWindowAggregateRule) Rewrite(ctx context.Context, pn plan.Node) (plan.Node, bool, error) {
@@ -743,7 +778,8 @@ func (PushDownWindowAggregateRule) Rewrite(ctx context.Context, pn plan.Node) (p
 	fromNode := windowNode.Predecessors()[0]
 	fromSpec := fromNode.ProcedureSpec().(*ReadRangePhysSpec)
 
-	if !isPushableWindow(windowSpec) {
+	offset, ok := pushableWindowOffset(windowSpec)
+	if !ok {
 		return pn, false, nil
 	}
 
@@ -752,7 +788,7 @@ func (PushDownWindowAggregateRule) Rewrite(ctx context.Context, pn plan.Node) (p
 		ReadRangePhysSpec: *fromSpec.Copy().(*ReadRangePhysSpec),
 		Aggregates:        []plan.ProcedureKind{fnNode.Kind()},
 		WindowEvery:       windowSpec.Window.Every,
-		Offset:            windowSpec.Window.Offset,
+		Offset:            offset,
 		CreateEmpty:       windowSpec.CreateEmpty,
 	}), true, nil
 }
@@ -874,7 +910,8 @@ func (p PushDownAggregateWindowRule) Rewrite(ctx context.Context, pn plan.Node)
 	fromNode := pn.Predecessors()[0]
 	fromSpec := fromNode.ProcedureSpec().(*ReadRangePhysSpec)
 
-	if !isPushableWindow(aggregateWindowSpec.WindowSpec) {
+	offset, ok := pushableWindowOffset(aggregateWindowSpec.WindowSpec)
+	if !ok {
 		return pn, false, nil
 	}
 
@@ -897,7 +934,7 @@ func (p PushDownAggregateWindowRule) Rewrite(ctx context.Context, pn plan.Node)
 			aggregateWindowSpec.AggregateKind,
 		},
 		WindowEvery:    aggregateWindowSpec.WindowSpec.Window.Every,
-		Offset:         aggregateWindowSpec.WindowSpec.Window.Offset,
+		Offset:         offset,
 		CreateEmpty:    aggregateWindowSpec.WindowSpec.CreateEmpty,
 		TimeColumn:     execute.DefaultStopColLabel,
 		ForceAggregate: aggregateWindowSpec.ForceAggregate,
@@ -979,7 +1016,8 @@ func (p GroupWindowAggregateTransposeRule) Rewrite(ctx context.Context, pn plan.
 	windowNode := fnNode.Predecessors()[0]
 	windowSpec := windowNode.ProcedureSpec().(*universe.WindowProcedureSpec)
 
-	if !isPushableWindow(windowSpec) {
+	offset, ok := pushableWindowOffset(windowSpec)
+	if !ok {
 		return pn, false, nil
 	}
 
@@ -1000,7 +1038,7 @@ func (p GroupWindowAggregateTransposeRule) Rewrite(ctx context.Context, pn plan.
 		ReadRangePhysSpec: *fromSpec.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec),
 		Aggregates:        []plan.ProcedureKind{fnNode.Kind()},
 		WindowEvery:       windowSpec.Window.Every,
-		Offset:            windowSpec.Window.Offset,
+		Offset:            offset,
 		CreateEmpty:       windowSpec.CreateEmpty,
 	})
 
@@ -1508,11 +1508,32 @@ func TestPushDownWindowAggregateRule(t *testing.T) {
 	badWindow6.Window.Location.Name = "America/Los_Angeles"
 	simpleMinUnchanged("BadLocation", badWindow6)
 
-	// Condition not met: non-zero location offset
-	badWindow7 := window1m
+	// Condition not met: location offset before the epoch with calendar windows
+	badWindow7 := window1mo
 	badWindow7.Window.Location.Offset = values.ConvertDurationNsecs(time.Hour)
 	simpleMinUnchanged("BadLocationOffset", badWindow7)
 
+	// Condition met: the location offset is folded into the window offset
+	// ReadRange -> window(every: 1d, location: UTC+02:00) -> min => ReadWindowAggregate(offset: 22h)
+	window1dLocationOffset := window(values.ConvertDurationNsecs(24 * time.Hour))
+	window1dLocationOffset.Window.Location.Offset = values.ConvertDurationNsecs(2 * time.Hour)
+	tests = append(tests, plantest.RuleTestCase{
+		Context: context.Background(),
+		Name:    "LocationOffsetPassMin",
+		Rules:   rules,
+		Before:  simplePlanWithWindowAgg(window1dLocationOffset, universe.MinKind, minProcedureSpec()),
+		After: &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("ReadWindowAggregate", &influxdb.ReadWindowAggregatePhysSpec{
+					ReadRangePhysSpec: *createRangeSpec(),
+					Aggregates:        []plan.ProcedureKind{universe.MinKind},
+					WindowEvery:       values.ConvertDurationNsecs(24 * time.Hour),
+					Offset:            values.ConvertDurationNsecs(22 * time.Hour),
+				}),
+			},
+		},
+	})
+
 	// Condition met: createEmpty is true.
 	windowCreateEmpty1m := window1m
 	windowCreateEmpty1m.CreateEmpty = true
@@ -2428,10 +2449,19 @@ func TestTransposeGroupToWindowAggregateRule(t *testing.T) {
 	badWindow6.Window.Location.Name = "America/Los_Angeles"
 	simpleMinUnchanged("BadLocation", badWindow6)
 
-	// Condition not met: non-zero location offset
-	badWindow7 := window1m
-	badWindow7.Window.Location.Offset = values.ConvertDurationNsecs(time.Hour)
-	simpleMinUnchanged("BadLocationOffset", badWindow7)
+	// Condition met: the location offset is a multiple of every
+	windowLocationOffset := window1m
+	windowLocationOffset.Window.Location.Offset = values.ConvertDurationNsecs(time.Hour)
+	tests = append(tests, plantest.RuleTestCase{
+		Context: haveCaps,
+		Name:    "LocationOffsetPassMin",
+		Rules:   rules,
+		Before:  simplePlan(windowLocationOffset, "min", minProcedureSpec()),
+		After: simpleResult("min", dur1m, false,
+			plan.CreatePhysicalNode("group", groupResult()),
+			plan.CreatePhysicalNode("min", minProcedureSpec()),
+		),
+	})
 
 	// Condition met: createEmpty is true.
 	windowCreateEmpty1m := window1m
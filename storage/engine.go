@@ -98,6 +98,7 @@ type MetaClient interface {
 type TSDBStore interface {
 	DeleteMeasurement(ctx context.Context, database, name string) error
 	DeleteSeries(ctx context.Context, database string, sources []influxql.Source, condition influxql.Expr) error
+	MeasurementsCardinality(ctx context.Context, database string) (int64, error)
 	MeasurementNames(ctx context.Context, auth query.Authorizer, database string, cond influxql.Expr) ([][]byte, error)
 	ShardGroup(ids []uint64) tsdb.ShardGroup
 	Shards(ids []uint64) []*tsdb.Shard
@@ -28,6 +28,7 @@ const (
 	scheduledForField = "scheduledFor"
 	startedAtField    = "startedAt"
 	finishedAtField   = "finishedAt"
+	durationField     = "duration"
 	requestedAtField  = "requestedAt"
 	logField          = "logs"
 	fluxField         = "flux"
@@ -42,14 +43,31 @@ type RunRecorder interface {
 	Record(ctx context.Context, bucketID platform.ID, bucket string, task *taskmodel.Task, run *taskmodel.Run) error
 }
 
+// AnalyticalStorageOption configures an AnalyticalStorage.
+type AnalyticalStorageOption func(*StoragePointsWriterRecorder)
+
+// WithRedactedScripts leaves the names and scripts of tasks out of the runs
+// recorded in the system bucket when redact is true, for tasks that are
+// encrypted at rest.
+func WithRedactedScripts(redact bool) AnalyticalStorageOption {
+	return func(rr *StoragePointsWriterRecorder) {
+		rr.RedactScripts = redact
+	}
+}
+
 // NewAnalyticalStorage creates a new analytical store with access to the necessary systems for storing data and to act as a middleware (deprecated)
-func NewAnalyticalStorage(log *zap.Logger, ts taskmodel.TaskService, bs influxdb.BucketService, tcs TaskControlService, pw storage.PointsWriter, qs query.QueryService) *AnalyticalStorage {
+func NewAnalyticalStorage(log *zap.Logger, ts taskmodel.TaskService, bs influxdb.BucketService, tcs TaskControlService, pw storage.PointsWriter, qs query.QueryService, opts ...AnalyticalStorageOption) *AnalyticalStorage {
+	rr := NewStoragePointsWriterRecorder(log, pw)
+	for _, opt := range opts {
+		opt(rr)
+	}
+
 	return &AnalyticalStorage{
 		log:                log,
 		TaskService:        ts,
 		BucketService:      bs,
 		TaskControlService: tcs,
-		rr:                 NewStoragePointsWriterRecorder(log, pw),
+		rr:                 rr,
 		qs:                 qs,
 	}
 }
@@ -67,20 +85,95 @@ type AnalyticalStorage struct {
 func (as *AnalyticalStorage) FinishRun(ctx context.Context, taskID, runID platform.ID) (*taskmodel.Run, error) {
 	run, err := as.TaskControlService.FinishRun(ctx, taskID, runID)
 	if run != nil && run.ID.String() != "" {
-		task, err := as.TaskService.FindTaskByID(ctx, run.TaskID)
-		if err != nil {
-			return run, err
-		}
+		return run, as.record(ctx, run)
+	}
 
-		sb, err := as.BucketService.FindBucketByName(ctx, task.OrganizationID, influxdb.TasksSystemBucketName)
-		if err != nil {
-			return run, err
+	return run, err
+}
+
+// FinishRuns finishes the runs and records each of them in the tasks system bucket.
+// The finished runs are returned along with the first error recording them.
+func (as *AnalyticalStorage) FinishRuns(ctx context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error) {
+	runs, err := as.TaskControlService.FinishRuns(ctx, refs)
+	if err != nil {
+		return runs, err
+	}
+
+	for _, run := range runs {
+		if rerr := as.record(ctx, run); rerr != nil && err == nil {
+			err = rerr
 		}
+	}
+	return runs, err
+}
 
-		return run, as.rr.Record(ctx, sb.ID, influxdb.TasksSystemBucketName, task, run)
+// record writes the finished run to the tasks system bucket of its organization.
+func (as *AnalyticalStorage) record(ctx context.Context, run *taskmodel.Run) error {
+	task, err := as.TaskService.FindTaskByID(ctx, run.TaskID)
+	if err != nil {
+		return err
 	}
 
-	return run, err
+	sb, err := as.BucketService.FindBucketByName(ctx, task.OrganizationID, influxdb.TasksSystemBucketName)
+	if err != nil {
+		return err
+	}
+
+	return as.rr.Record(ctx, sb.ID, influxdb.TasksSystemBucketName, task, run)
+}
+
+// FindTaskRevisions returns the previous scripts of a task from the wrapped task service.
+func (as *AnalyticalStorage) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*taskmodel.TaskRevision, error) {
+	revisions, ok := as.TaskService.(taskmodel.TaskRevisionService)
+	if !ok {
+		return nil, taskmodel.ErrTaskRevisionsNotSupported
+	}
+	return revisions.FindTaskRevisions(ctx, taskID)
+}
+
+// RollbackTask restores the script of a task in the wrapped task service.
+func (as *AnalyticalStorage) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*taskmodel.Task, error) {
+	revisions, ok := as.TaskService.(taskmodel.TaskRevisionService)
+	if !ok {
+		return nil, taskmodel.ErrTaskRevisionsNotSupported
+	}
+	return revisions.RollbackTask(ctx, taskID, revision)
+}
+
+// FindDeletedTasks returns the deleted tasks of an organization from the wrapped task service.
+func (as *AnalyticalStorage) FindDeletedTasks(ctx context.Context, orgID platform.ID) ([]*taskmodel.DeletedTask, error) {
+	trash, ok := as.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+	return trash.FindDeletedTasks(ctx, orgID)
+}
+
+// FindDeletedTaskByID returns a single deleted task from the wrapped task service.
+func (as *AnalyticalStorage) FindDeletedTaskByID(ctx context.Context, id platform.ID) (*taskmodel.DeletedTask, error) {
+	trash, ok := as.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+	return trash.FindDeletedTaskByID(ctx, id)
+}
+
+// RestoreTask restores a deleted task in the wrapped task service.
+func (as *AnalyticalStorage) RestoreTask(ctx context.Context, id platform.ID) (*taskmodel.Task, error) {
+	trash, ok := as.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+	return trash.RestoreTask(ctx, id)
+}
+
+// PurgeDeletedTasks purges the deleted tasks of the wrapped task service.
+func (as *AnalyticalStorage) PurgeDeletedTasks(ctx context.Context, olderThan time.Duration) ([]*taskmodel.DeletedTask, error) {
+	trash, ok := as.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+	return trash.PurgeDeletedTasks(ctx, olderThan)
 }
 
 // FindLogs returns logs for a run.
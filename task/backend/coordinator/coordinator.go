@@ -45,7 +45,7 @@ type SchedulableTask struct {
 }
 
 func (t SchedulableTask) ID() scheduler.ID {
-	return scheduler.ID(t.Task.ID)
+	return t.Task.ID
 }
 
 // Schedule takes the time a Task is scheduled for and returns a Schedule object
@@ -89,9 +89,41 @@ func NewSchedulableTask(task *taskmodel.Task) (SchedulableTask, error) {
 	if err != nil {
 		return SchedulableTask{}, err
 	}
+
+	ts, err = catchUp(task.CatchUp, sch, task.Offset, ts, time.Now().UTC())
+	if err != nil {
+		return SchedulableTask{}, err
+	}
 	return SchedulableTask{Task: task, sch: sch, lsc: ts}, nil
 }
 
+// catchUp moves the last scheduled time of a task past the schedule times it
+// missed before now, as far as its catch-up policy asks for. With the default
+// policy every missed schedule time is left for the scheduler to run.
+func catchUp(policy string, sch scheduler.Schedule, offset time.Duration, last, now time.Time) (time.Time, error) {
+	if policy != taskmodel.TaskCatchUpOnce && policy != taskmodel.TaskCatchUpSkip {
+		return last, nil
+	}
+
+	prev := last
+	for {
+		next, err := sch.Next(last)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if next.Add(offset).After(now) {
+			break
+		}
+		prev, last = last, next
+	}
+
+	if policy == taskmodel.TaskCatchUpOnce {
+		// leave the latest missed schedule time to be run
+		return prev, nil
+	}
+	return last, nil
+}
+
 func NewCoordinator(log *zap.Logger, scheduler scheduler.Scheduler, executor Executor, opts ...CoordinatorOption) *Coordinator {
 	c := &Coordinator{
 		log:   log,
@@ -125,7 +157,6 @@ func (c *Coordinator) TaskCreated(ctx context.Context, task *taskmodel.Task) err
 
 // TaskUpdated releases the task if it is being disabled, and schedules it otherwise
 func (c *Coordinator) TaskUpdated(ctx context.Context, from, to *taskmodel.Task) error {
-	sid := scheduler.ID(to.ID)
 	t, err := NewSchedulableTask(to)
 	if err != nil {
 		return err
@@ -138,7 +169,7 @@ func (c *Coordinator) TaskUpdated(ctx context.Context, from, to *taskmodel.Task)
 
 	// if disabling the task, release it before schedule update
 	if to.Status != from.Status && to.Status == string(taskmodel.TaskInactive) {
-		if err := c.sch.Release(sid); err != nil && err != taskmodel.ErrTaskNotClaimed {
+		if err := c.sch.Release(to.ID); err != nil && err != taskmodel.ErrTaskNotClaimed {
 			return err
 		}
 	} else {
@@ -152,8 +183,7 @@ func (c *Coordinator) TaskUpdated(ctx context.Context, from, to *taskmodel.Task)
 
 // TaskDeleted asks the Scheduler to release the deleted task
 func (c *Coordinator) TaskDeleted(ctx context.Context, id platform.ID) error {
-	tid := scheduler.ID(id)
-	if err := c.sch.Release(tid); err != nil && err != taskmodel.ErrTaskNotClaimed {
+	if err := c.sch.Release(id); err != nil && err != taskmodel.ErrTaskNotClaimed {
 		return err
 	}
 
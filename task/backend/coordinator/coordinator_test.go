@@ -122,6 +122,47 @@ func TestNewSchedulableTask(t *testing.T) {
 
 }
 
+func TestCatchUp(t *testing.T) {
+	sch, _, err := scheduler.NewSchedule("@every 1m", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := last.Add(10*time.Minute + 30*time.Second)
+
+	for _, tt := range []struct {
+		policy string
+		offset time.Duration
+		exp    time.Time
+	}{
+		{policy: "", exp: last},
+		{policy: taskmodel.TaskCatchUpAll, exp: last},
+		{policy: taskmodel.TaskCatchUpOnce, exp: last.Add(9 * time.Minute)},
+		{policy: taskmodel.TaskCatchUpSkip, exp: last.Add(10 * time.Minute)},
+		{policy: taskmodel.TaskCatchUpSkip, offset: time.Minute, exp: last.Add(9 * time.Minute)},
+	} {
+		t.Run(fmt.Sprintf("%s/%s", tt.policy, tt.offset), func(t *testing.T) {
+			got, err := catchUp(tt.policy, sch, tt.offset, last, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.exp) {
+				t.Fatalf("expected last scheduled to be %s but it was %s", tt.exp, got)
+			}
+		})
+	}
+
+	// nothing was missed
+	got, err := catchUp(taskmodel.TaskCatchUpOnce, sch, 0, now.Add(-time.Second), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(now.Add(-time.Second)) {
+		t.Fatalf("expected last scheduled to be unchanged but it was %s", got)
+	}
+}
+
 func Test_Coordinator_Scheduler_Methods(t *testing.T) {
 
 	var (
@@ -199,7 +240,7 @@ func Test_Coordinator_Scheduler_Methods(t *testing.T) {
 			},
 			scheduler: &schedulerC{
 				calls: []interface{}{
-					releaseCallC{scheduler.ID(taskTwo.ID)},
+					releaseCallC{taskTwo.ID},
 				},
 			},
 		},
@@ -247,7 +288,7 @@ func Test_Coordinator_Scheduler_Methods(t *testing.T) {
 			},
 			scheduler: &schedulerC{
 				calls: []interface{}{
-					releaseCallC{scheduler.ID(taskOne.ID)},
+					releaseCallC{taskOne.ID},
 				},
 			},
 		},
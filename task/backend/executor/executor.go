@@ -63,6 +63,7 @@ type executorConfig struct {
 	systemBuildCompiler    CompilerBuilderFunc
 	nonSystemBuildCompiler CompilerBuilderFunc
 	flagger                feature.Flagger
+	redactScripts          bool
 }
 
 type executorOption func(*executorConfig)
@@ -126,6 +127,15 @@ func WithFlagger(flagger feature.Flagger) executorOption {
 	}
 }
 
+// WithRedactedScripts is an Executor option that leaves the scripts of tasks
+// out of the logs of their runs when redact is true, so the scripts of
+// encrypted tasks are not stored in plain text with the runs.
+func WithRedactedScripts(redact bool) executorOption {
+	return func(o *executorConfig) {
+		o.redactScripts = redact
+	}
+}
+
 // NewExecutor creates a new task executor
 func NewExecutor(log *zap.Logger, qs query.QueryService, us PermissionService, ts taskmodel.TaskService, tcs backend.TaskControlService, opts ...executorOption) (*Executor, *ExecutorMetrics) {
 	cfg := &executorConfig{
@@ -138,11 +148,12 @@ func NewExecutor(log *zap.Logger, qs query.QueryService, us PermissionService, t
 	}
 
 	e := &Executor{
-		log: log,
-		ts:  ts,
-		tcs: tcs,
-		qs:  qs,
-		ps:  us,
+		log:  log,
+		ts:   ts,
+		tcs:  tcs,
+		runs: newRunBatcher(tcs),
+		qs:   qs,
+		ps:   us,
 
 		currentPromises:        sync.Map{},
 		futurePromises:         sync.Map{},
@@ -152,6 +163,7 @@ func NewExecutor(log *zap.Logger, qs query.QueryService, us PermissionService, t
 		systemBuildCompiler:    cfg.systemBuildCompiler,
 		nonSystemBuildCompiler: cfg.nonSystemBuildCompiler,
 		flagger:                cfg.flagger,
+		redactScripts:          cfg.redactScripts,
 	}
 
 	e.metrics = NewExecutorMetrics(e)
@@ -171,6 +183,8 @@ type Executor struct {
 	log *zap.Logger
 	ts  taskmodel.TaskService
 	tcs backend.TaskControlService
+	// runs creates and finishes the runs of the scheduled tasks in batches.
+	runs *runBatcher
 
 	qs query.QueryService
 	ps PermissionService
@@ -195,6 +209,13 @@ type Executor struct {
 	nonSystemBuildCompiler CompilerBuilderFunc
 	systemBuildCompiler    CompilerBuilderFunc
 	flagger                feature.Flagger
+	redactScripts          bool
+}
+
+// Close stops creating and finishing runs in batches. The runs created or
+// finished afterwards fail, so it must be called once the scheduler is stopped.
+func (e *Executor) Close() {
+	e.runs.Close()
 }
 
 func (e *Executor) LoadExistingScheduleRuns(ctx context.Context) error {
@@ -260,9 +281,8 @@ func (e *Executor) Execute(ctx context.Context, id scheduler.ID, scheduledFor ti
 // If the queue is full the call to execute should hang and apply back pressure to the caller
 // We then start a worker to work the newly queued jobs.
 func (e *Executor) PromisedExecute(ctx context.Context, id scheduler.ID, scheduledFor time.Time, runAt time.Time) (Promise, error) {
-	iid := platform.ID(id)
 	// create a run
-	p, err := e.createRun(ctx, iid, scheduledFor, runAt)
+	p, err := e.createRun(ctx, id, scheduledFor, runAt)
 	if err != nil {
 		return nil, err
 	}
@@ -368,7 +388,7 @@ func (e *Executor) ResumeCurrentRun(ctx context.Context, id platform.ID, runID p
 }
 
 func (e *Executor) createRun(ctx context.Context, id platform.ID, scheduledFor time.Time, runAt time.Time) (*promise, error) {
-	r, err := e.tcs.CreateRun(ctx, id, scheduledFor.UTC(), runAt.UTC())
+	r, err := e.runs.CreateRun(ctx, taskmodel.RunCreate{TaskID: id, ScheduledFor: scheduledFor.UTC(), RunAt: runAt.UTC()})
 	if err != nil {
 		return nil, err
 	}
@@ -527,12 +547,19 @@ func (w *worker) work() {
 		}
 
 		// check to make sure we are below the limits.
+		limited := false
 		for {
 			err := w.e.limitFunc(prom.task, prom.run)
 			if err == nil {
 				break
 			}
 
+			// count each run held back once, not every time its limits are checked again.
+			if !limited {
+				w.e.metrics.LimitReached(prom.task)
+				limited = true
+			}
+
 			// add to the run log
 			w.e.tcs.AddRunLog(prom.ctx, prom.task.ID, prom.run.ID, time.Now().UTC(), fmt.Sprintf("Task limit reached: %s", err.Error()))
 
@@ -566,7 +593,11 @@ func (w *worker) start(p *promise) {
 	defer span.Finish()
 
 	// add to run log
-	if err := w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), fmt.Sprintf("Started task from script: %q", p.task.Flux)); err != nil {
+	msg := fmt.Sprintf("Started task from script: %q", p.task.Flux)
+	if w.e.redactScripts {
+		msg = "Started task"
+	}
+	if err := w.e.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now().UTC(), msg); err != nil {
 		tid := zap.String("taskID", p.task.ID.String())
 		rid := zap.String("runID", p.run.ID.String())
 		w.e.log.With(zap.Error(err)).With(tid).With(rid).Warn("error adding run log: ")
@@ -620,7 +651,8 @@ func (w *worker) finish(p *promise, rs taskmodel.RunStatus, err error) {
 		w.e.log.Debug("Completed successfully", zap.String("taskID", p.task.ID.String()))
 	}
 
-	if _, err := w.e.tcs.FinishRun(p.ctx, p.task.ID, p.run.ID); err != nil {
+	// The context of a canceled run is done, but the run must still be finished.
+	if _, err := w.e.runs.FinishRun(context.Background(), taskmodel.RunRef{TaskID: p.task.ID, RunID: p.run.ID}); err != nil {
 		w.e.log.Error("Failed to finish run", zap.String("taskID", p.task.ID.String()), zap.String("runID", p.run.ID.String()), zap.Error(err))
 	}
 }
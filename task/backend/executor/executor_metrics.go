@@ -18,6 +18,7 @@ type ExecutorMetrics struct {
 	manualRunsCounter    *prometheus.CounterVec
 	resumeRunsCounter    *prometheus.CounterVec
 	unrecoverableCounter *prometheus.CounterVec
+	limitRejections      *prometheus.CounterVec
 	runLatency           *prometheus.HistogramVec
 }
 
@@ -86,6 +87,13 @@ func NewExecutorMetrics(ex *Executor) *ExecutorMetrics {
 			Help:      "Total number of runs resumed by task ID",
 		}, []string{"taskID"}),
 
+		limitRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "concurrency_rejections_total",
+			Help:      "Total number of times a run was held back because its task was at its concurrency limit, by task type",
+		}, []string{"task_type"}),
+
 		runLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
@@ -131,6 +139,7 @@ func (em *ExecutorMetrics) PrometheusCollectors() []prometheus.Collector {
 		em.manualRunsCounter,
 		em.resumeRunsCounter,
 		em.unrecoverableCounter,
+		em.limitRejections,
 		em.runLatency,
 	}
 }
@@ -152,6 +161,11 @@ func (em *ExecutorMetrics) FinishRun(task *taskmodel.Task, status taskmodel.RunS
 	em.runDuration.WithLabelValues("", task.ID.String()).Observe(runDuration.Seconds())
 }
 
+// LimitReached increments the count of runs held back by the task's limits.
+func (em *ExecutorMetrics) LimitReached(task *taskmodel.Task) {
+	em.limitRejections.WithLabelValues(task.Type).Inc()
+}
+
 // LogError increments the count of errors by error code.
 func (em *ExecutorMetrics) LogError(taskType string, err error) {
 	switch e := err.(type) {
@@ -22,11 +22,12 @@ import (
 	tracetest "github.com/influxdata/influxdb/v2/kit/tracing/testing"
 	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/kv/migration/all"
+	platformmock "github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
 	"github.com/influxdata/influxdb/v2/query"
 	"github.com/influxdata/influxdb/v2/query/fluxlang"
 	"github.com/influxdata/influxdb/v2/task/backend"
 	"github.com/influxdata/influxdb/v2/task/backend/executor/mock"
-	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
 	"github.com/influxdata/influxdb/v2/task/taskmodel"
 	"github.com/influxdata/influxdb/v2/tenant"
 	"github.com/opentracing/opentracing-go"
@@ -47,6 +48,13 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+// The runs of the tests are scheduled after their task is created, since
+// no run is created at or before the latest completed run of a task.
+var (
+	testScheduledFor = time.Now().Add(time.Hour).Truncate(time.Second)
+	testRunAt        = testScheduledFor.Add(3 * time.Second)
+)
+
 type tes struct {
 	svc     *fakeQueryService
 	ex      *Executor
@@ -54,9 +62,16 @@ type tes struct {
 	i       *kv.Service
 	tcs     *taskControlService
 	tc      testCreds
+	store   *inmem.KVStore
 }
 
 func taskExecutorSystem(t *testing.T) tes {
+	return newTaskExecutorSystem(t, kv.ServiceConfig{
+		FluxLanguageService: fluxlang.DefaultService,
+	})
+}
+
+func newTaskExecutorSystem(t *testing.T, cfg kv.ServiceConfig, opts ...executorOption) tes {
 	var (
 		aqs = newFakeQueryService()
 		qs  = query.QueryServiceBridge{
@@ -82,12 +97,10 @@ func taskExecutorSystem(t *testing.T) tes {
 	authSvc := authorization.NewService(authStore, tenantSvc)
 
 	var (
-		svc = kv.NewService(logger, store, tenantSvc, kv.ServiceConfig{
-			FluxLanguageService: fluxlang.DefaultService,
-		})
+		svc = kv.NewService(logger, store, tenantSvc, cfg)
 
 		tcs         = &taskControlService{TaskControlService: svc}
-		ex, metrics = NewExecutor(zaptest.NewLogger(t), qs, ps, svc, tcs)
+		ex, metrics = NewExecutor(zaptest.NewLogger(t), qs, ps, svc, tcs, opts...)
 	)
 	return tes{
 		svc:     aqs,
@@ -96,6 +109,7 @@ func taskExecutorSystem(t *testing.T) tes {
 		i:       svc,
 		tcs:     tcs,
 		tc:      createCreds(t, tenantSvc, tenantSvc, authSvc),
+		store:   store,
 	}
 }
 
@@ -109,6 +123,7 @@ func TestTaskExecutor(t *testing.T) {
 	t.Run("Metrics", testMetrics)
 	t.Run("IteratorFailure", testIteratorFailure)
 	t.Run("ErrorHandling", testErrorHandling)
+	t.Run("RedactedScripts", testRedactedScripts)
 }
 
 func testQuerySuccess(t *testing.T) {
@@ -128,7 +143,7 @@ func testQuerySuccess(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -143,7 +158,7 @@ func testQuerySuccess(t *testing.T) {
 		t.Fatal("promise and run dont match")
 	}
 
-	if run.RunAt != time.Unix(126, 0).UTC() {
+	if run.RunAt != testRunAt.UTC() {
 		t.Fatalf("did not correctly set RunAt value, got: %v", run.RunAt)
 	}
 
@@ -179,6 +194,70 @@ func testQuerySuccess(t *testing.T) {
 	}
 }
 
+func testRedactedScripts(t *testing.T) {
+	t.Parallel()
+
+	aead, err := kv.NewTaskCipher([]byte("0123456789abcdef"))
+	require.NoError(t, err)
+	tes := newTaskExecutorSystem(t, kv.ServiceConfig{
+		FluxLanguageService: fluxlang.DefaultService,
+		TaskCipher:          aead,
+	}, WithRedactedScripts(true))
+
+	// the name of the task is part of the script
+	script := fmt.Sprintf(fmtTestScript, t.Name())
+	secret := "RedactedScripts"
+	ctx := icontext.SetAuthorizer(context.Background(), tes.tc.Auth)
+	task, err := tes.i.CreateTask(ctx, taskmodel.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
+	require.NoError(t, err)
+
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
+	require.NoError(t, err)
+
+	tes.svc.WaitForQueryLive(t, script)
+
+	// the run has been started, none of the stored values hold the script
+	err = tes.store.View(ctx, func(tx kv.Tx) error {
+		for _, bucket := range []string{"tasksv1", "taskRunsv1"} {
+			b, err := tx.Bucket([]byte(bucket))
+			require.NoError(t, err)
+			c, err := b.ForwardCursor(nil)
+			require.NoError(t, err)
+			for k, v := c.Next(); k != nil; k, v = c.Next() {
+				assert.NotContains(t, string(v), secret)
+			}
+			require.NoError(t, c.Close())
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	tes.svc.SucceedQuery(script)
+	<-promise.Done()
+	require.NoError(t, promise.Error())
+
+	run := tes.tcs.run
+	require.NotNil(t, run)
+	require.NotEmpty(t, run.Log)
+	assert.Equal(t, "Started task", run.Log[0].Message)
+
+	// nor do the points recording the run in the system bucket
+	var written models.Points
+	pw := &platformmock.PointsWriter{
+		WritePointsFn: func(ctx context.Context, orgID platform.ID, bucketID platform.ID, points []models.Point) error {
+			written = points
+			return nil
+		},
+	}
+	rr := backend.NewStoragePointsWriterRecorder(zaptest.NewLogger(t), pw)
+	rr.RedactScripts = true
+	found, err := tes.i.FindTaskByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NoError(t, rr.Record(ctx, 1, influxdb.TasksSystemBucketName, found, run))
+	require.Len(t, written, 1)
+	assert.NotContains(t, written[0].String(), secret)
+}
+
 func testQueryFailure(t *testing.T) {
 	t.Parallel()
 	tes := taskExecutorSystem(t)
@@ -190,7 +269,7 @@ func testQueryFailure(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -273,7 +352,7 @@ func testResumingRun(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	stalledRun, err := tes.i.CreateRun(ctx, task.ID, time.Unix(123, 0), time.Unix(126, 0))
+	stalledRun, err := tes.i.CreateRun(ctx, task.ID, testScheduledFor, testRunAt)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -316,7 +395,7 @@ func testWorkerLimit(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -358,7 +437,7 @@ func testLimitFunc(t *testing.T) {
 		return nil
 	})
 
-	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -390,7 +469,7 @@ func testMetrics(t *testing.T) {
 	task, err := tes.i.CreateTask(ctx, taskmodel.TaskCreate{OrganizationID: tes.tc.OrgID, OwnerID: tes.tc.Auth.GetUserID(), Flux: script})
 	assert.NoError(t, err)
 
-	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
 	assert.NoError(t, err)
 	promiseID := promise.ID()
 
@@ -468,7 +547,7 @@ func testIteratorFailure(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -512,7 +591,7 @@ func testErrorHandling(t *testing.T) {
 	forcedErr := errors.New("could not find bucket")
 	tes.svc.FailNextQuery(forcedErr)
 
-	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -560,7 +639,7 @@ func TestPromiseFailure(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	promise, err := tes.ex.PromisedExecute(ctx, scheduler.ID(task.ID), time.Unix(123, 0), time.Unix(126, 0))
+	promise, err := tes.ex.PromisedExecute(ctx, task.ID, testScheduledFor, testRunAt)
 	if err == nil {
 		t.Fatal("failed to error on promise create")
 	}
@@ -600,3 +679,17 @@ func (t *taskControlService) FinishRun(ctx context.Context, taskID platform.ID,
 	t.run, err = t.TaskControlService.FinishRun(ctx, taskID, runID)
 	return t.run, err
 }
+
+func (t *taskControlService) FinishRuns(ctx context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error) {
+	// ensure auth set on context
+	_, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	runs, err := t.TaskControlService.FinishRuns(ctx, refs)
+	if len(runs) > 0 {
+		t.run = runs[len(runs)-1]
+	}
+	return runs, err
+}
@@ -0,0 +1,194 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/influxdata/influxdb/v2/task/backend"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+)
+
+// maxRunBatchSize is the maximum number of runs created or finished in a
+// single call to the TaskControlService.
+const maxRunBatchSize = 64
+
+// errRunBatcherClosed is returned for the runs requested once the executor is closed.
+var errRunBatcherClosed = errors.New("task executor is closed")
+
+type runRequest struct {
+	ctx    context.Context
+	create taskmodel.RunCreate
+	ref    taskmodel.RunRef
+
+	run  *taskmodel.Run
+	err  error
+	done chan struct{}
+}
+
+// runBatcher groups the runs that the workers of the executor create and
+// finish concurrently, so that they are stored in a single transaction
+// rather than in one transaction per run.
+//
+// A request is never delayed waiting for others: each batch holds the
+// requests that queued up while the previous batch was being stored.
+type runBatcher struct {
+	tcs backend.TaskControlService
+
+	creates  chan *runRequest
+	finishes chan *runRequest
+
+	closing chan struct{}
+	closed  chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+func newRunBatcher(tcs backend.TaskControlService) *runBatcher {
+	b := &runBatcher{
+		tcs:      tcs,
+		creates:  make(chan *runRequest, maxRunBatchSize),
+		finishes: make(chan *runRequest, maxRunBatchSize),
+		closing:  make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+
+	b.wg.Add(2)
+	go b.process(b.creates, b.createRuns)
+	go b.process(b.finishes, b.finishRuns)
+
+	return b
+}
+
+// Close stops the batches once the batches being stored are done.
+// The runs requested afterwards fail.
+func (b *runBatcher) Close() {
+	b.once.Do(func() {
+		close(b.closing)
+		b.wg.Wait()
+		close(b.closed)
+	})
+}
+
+// CreateRun creates a run with the next batch of runs.
+func (b *runBatcher) CreateRun(ctx context.Context, c taskmodel.RunCreate) (*taskmodel.Run, error) {
+	return b.do(ctx, b.creates, &runRequest{ctx: ctx, create: c, done: make(chan struct{})})
+}
+
+// FinishRun finishes a run with the next batch of runs.
+func (b *runBatcher) FinishRun(ctx context.Context, ref taskmodel.RunRef) (*taskmodel.Run, error) {
+	return b.do(ctx, b.finishes, &runRequest{ctx: ctx, ref: ref, done: make(chan struct{})})
+}
+
+func (b *runBatcher) do(ctx context.Context, queue chan *runRequest, req *runRequest) (*taskmodel.Run, error) {
+	select {
+	case queue <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.closing:
+		return nil, errRunBatcherClosed
+	}
+
+	// A queued request whose caller gives up is dropped from its batch.
+	// Once its batch is stored, it is done even if the caller gave up.
+	select {
+	case <-req.done:
+		return req.run, req.err
+	case <-b.closed:
+		select {
+		case <-req.done:
+			return req.run, req.err
+		default:
+			return nil, errRunBatcherClosed
+		}
+	}
+}
+
+func (b *runBatcher) process(queue chan *runRequest, fn func([]*runRequest)) {
+	defer b.wg.Done()
+
+	batch := make([]*runRequest, 0, maxRunBatchSize)
+	for {
+		var req *runRequest
+		select {
+		case req = <-queue:
+		case <-b.closing:
+			return
+		}
+
+		batch = append(batch[:0], req)
+	drain:
+		for len(batch) < maxRunBatchSize {
+			select {
+			case req := <-queue:
+				batch = append(batch, req)
+			default:
+				break drain
+			}
+		}
+
+		// Drop the requests whose caller gave up while they were queued.
+		live := batch[:0]
+		for _, req := range batch {
+			if err := req.ctx.Err(); err != nil {
+				req.err = err
+				close(req.done)
+				continue
+			}
+			live = append(live, req)
+		}
+
+		if len(live) > 0 {
+			fn(live)
+		}
+		for _, req := range live {
+			close(req.done)
+		}
+	}
+}
+
+func (b *runBatcher) createRuns(batch []*runRequest) {
+	if len(batch) > 1 {
+		creates := make([]taskmodel.RunCreate, 0, len(batch))
+		for _, req := range batch {
+			creates = append(creates, req.create)
+		}
+		// The batch is stored on behalf of several callers, so it must not
+		// carry the authorizer, span or cancellation of any one of them.
+		if runs, err := b.tcs.CreateRuns(context.Background(), creates); len(runs) == len(batch) {
+			for i, req := range batch {
+				req.run, req.err = runs[i], err
+			}
+			return
+		}
+		// A single run that cannot be created fails the whole batch, so
+		// create the runs one by one to report the error to its caller only.
+	}
+
+	for _, req := range batch {
+		req.run, req.err = b.tcs.CreateRun(req.ctx, req.create.TaskID, req.create.ScheduledFor, req.create.RunAt)
+	}
+}
+
+func (b *runBatcher) finishRuns(batch []*runRequest) {
+	if len(batch) > 1 {
+		refs := make([]taskmodel.RunRef, 0, len(batch))
+		for _, req := range batch {
+			refs = append(refs, req.ref)
+		}
+		// The runs are returned once they are finished, even if an error
+		// happened afterwards, in which case they must not be finished again.
+		if runs, err := b.tcs.FinishRuns(context.Background(), refs); len(runs) == len(batch) {
+			for i, req := range batch {
+				req.run, req.err = runs[i], err
+			}
+			return
+		}
+		// A single run that cannot be finished fails the whole batch, so
+		// finish the runs one by one to report the error to its caller only.
+	}
+
+	for _, req := range batch {
+		req.run, req.err = b.tcs.FinishRun(req.ctx, req.ref.TaskID, req.ref.RunID)
+	}
+}
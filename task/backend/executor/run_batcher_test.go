@@ -0,0 +1,164 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2"
+	icontext "github.com/influxdata/influxdb/v2/context"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBatcher_CreateRuns(t *testing.T) {
+	var (
+		blocked = make(chan struct{})
+		release = make(chan struct{})
+		batches [][]taskmodel.RunCreate
+	)
+	tcs := &mock.TaskControlService{
+		CreateRunFn: func(_ context.Context, taskID platform.ID, scheduledFor time.Time, runAt time.Time) (*taskmodel.Run, error) {
+			if taskID == 1 {
+				close(blocked)
+				<-release
+			}
+			return &taskmodel.Run{TaskID: taskID, ScheduledFor: scheduledFor}, nil
+		},
+		CreateRunsFn: func(ctx context.Context, creates []taskmodel.RunCreate) ([]*taskmodel.Run, error) {
+			// The batch does not run on behalf of any one of its callers.
+			if _, err := icontext.GetAuthorizer(ctx); err == nil {
+				t.Error("batch of runs created with the authorizer of a caller")
+			}
+			batches = append(batches, creates)
+			runs := make([]*taskmodel.Run, 0, len(creates))
+			for _, c := range creates {
+				runs = append(runs, &taskmodel.Run{TaskID: c.TaskID, ScheduledFor: c.ScheduledFor})
+			}
+			return runs, nil
+		},
+	}
+	b := newRunBatcher(tcs)
+
+	ctx := context.Background()
+	go b.CreateRun(ctx, taskmodel.RunCreate{TaskID: 1})
+	<-blocked
+
+	// The runs queued while the first one is being created are created together.
+	runs := make([]*taskmodel.Run, 4)
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := icontext.SetAuthorizer(ctx, &influxdb.Authorization{ID: platform.ID(i + 1)})
+			runs[i], _ = b.CreateRun(ctx, taskmodel.RunCreate{TaskID: platform.ID(i + 2)})
+		}(i)
+	}
+	require.Eventually(t, func() bool { return len(b.creates) == 4 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 4)
+	for i, r := range runs {
+		require.Equal(t, platform.ID(i+2), r.TaskID)
+	}
+}
+
+func TestRunBatcher_FinishRunsFallback(t *testing.T) {
+	var (
+		blocked = make(chan struct{})
+		release = make(chan struct{})
+		errRun  = errors.New("cannot finish run")
+	)
+	tcs := &mock.TaskControlService{
+		FinishRunFn: func(_ context.Context, taskID, runID platform.ID) (*taskmodel.Run, error) {
+			if runID == 1 {
+				close(blocked)
+				<-release
+			}
+			if runID == 3 {
+				return nil, errRun
+			}
+			return &taskmodel.Run{ID: runID, TaskID: taskID}, nil
+		},
+		FinishRunsFn: func(_ context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error) {
+			return nil, errRun
+		},
+	}
+	b := newRunBatcher(tcs)
+
+	ctx := context.Background()
+	go b.FinishRun(ctx, taskmodel.RunRef{TaskID: 1, RunID: 1})
+	<-blocked
+
+	// A run that cannot be finished does not fail the other runs of its batch.
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = b.FinishRun(ctx, taskmodel.RunRef{TaskID: 1, RunID: platform.ID(i + 2)})
+		}(i)
+	}
+	require.Eventually(t, func() bool { return len(b.finishes) == 2 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.NoError(t, errs[0])
+	require.Equal(t, errRun, errs[1])
+}
+
+func TestRunBatcher_CanceledWhileQueued(t *testing.T) {
+	var (
+		blocked = make(chan struct{})
+		release = make(chan struct{})
+		created []platform.ID
+	)
+	tcs := &mock.TaskControlService{
+		CreateRunFn: func(_ context.Context, taskID platform.ID, scheduledFor time.Time, runAt time.Time) (*taskmodel.Run, error) {
+			if taskID == 1 {
+				close(blocked)
+				<-release
+			}
+			created = append(created, taskID)
+			return &taskmodel.Run{TaskID: taskID, ScheduledFor: scheduledFor}, nil
+		},
+	}
+	b := newRunBatcher(tcs)
+	defer b.Close()
+
+	go b.CreateRun(context.Background(), taskmodel.RunCreate{TaskID: 1})
+	<-blocked
+
+	// A request whose caller gives up while it is queued is not created.
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := b.CreateRun(ctx, taskmodel.RunCreate{TaskID: 2})
+		errc <- err
+	}()
+	require.Eventually(t, func() bool { return len(b.creates) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	close(release)
+
+	require.Equal(t, context.Canceled, <-errc)
+	require.Equal(t, []platform.ID{1}, created)
+}
+
+func TestRunBatcher_Close(t *testing.T) {
+	b := newRunBatcher(&mock.TaskControlService{})
+	b.Close()
+	b.Close()
+
+	_, err := b.CreateRun(context.Background(), taskmodel.RunCreate{TaskID: 1})
+	require.Equal(t, errRunBatcherClosed, err)
+	_, err = b.FinishRun(context.Background(), taskmodel.RunRef{TaskID: 1, RunID: 1})
+	require.Equal(t, errRunBatcherClosed, err)
+}
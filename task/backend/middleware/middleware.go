@@ -87,6 +87,88 @@ func (s *CoordinatingTaskService) DeleteTask(ctx context.Context, id platform.ID
 	return s.TaskService.DeleteTask(ctx, id)
 }
 
+// FindTaskRevisions returns the previous scripts of a task.
+// The wrapped task service must implement taskmodel.TaskRevisionService.
+func (s *CoordinatingTaskService) FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*taskmodel.TaskRevision, error) {
+	revisions, ok := s.TaskService.(taskmodel.TaskRevisionService)
+	if !ok {
+		return nil, taskmodel.ErrTaskRevisionsNotSupported
+	}
+
+	return revisions.FindTaskRevisions(ctx, taskID)
+}
+
+// RollbackTask restores the script of a task to a revision and publishes the change,
+// like any other update of the task.
+// The wrapped task service must implement taskmodel.TaskRevisionService.
+func (s *CoordinatingTaskService) RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*taskmodel.Task, error) {
+	revisions, ok := s.TaskService.(taskmodel.TaskRevisionService)
+	if !ok {
+		return nil, taskmodel.ErrTaskRevisionsNotSupported
+	}
+
+	from, err := s.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := revisions.RollbackTask(ctx, taskID, revision)
+	if err != nil {
+		return to, err
+	}
+
+	return to, s.coordinator.TaskUpdated(ctx, from, to)
+}
+
+// FindDeletedTasks returns the deleted tasks of an organization.
+// The wrapped task service must implement taskmodel.TaskTrashService.
+func (s *CoordinatingTaskService) FindDeletedTasks(ctx context.Context, orgID platform.ID) ([]*taskmodel.DeletedTask, error) {
+	trash, ok := s.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+
+	return trash.FindDeletedTasks(ctx, orgID)
+}
+
+// FindDeletedTaskByID returns a single deleted task.
+// The wrapped task service must implement taskmodel.TaskTrashService.
+func (s *CoordinatingTaskService) FindDeletedTaskByID(ctx context.Context, id platform.ID) (*taskmodel.DeletedTask, error) {
+	trash, ok := s.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+
+	return trash.FindDeletedTaskByID(ctx, id)
+}
+
+// PurgeDeletedTasks permanently removes the tasks deleted more than olderThan ago.
+// The wrapped task service must implement taskmodel.TaskTrashService.
+func (s *CoordinatingTaskService) PurgeDeletedTasks(ctx context.Context, olderThan time.Duration) ([]*taskmodel.DeletedTask, error) {
+	trash, ok := s.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+
+	return trash.PurgeDeletedTasks(ctx, olderThan)
+}
+
+// RestoreTask restores a deleted task and publishes it so it is scheduled again.
+// The wrapped task service must implement taskmodel.TaskTrashService.
+func (s *CoordinatingTaskService) RestoreTask(ctx context.Context, id platform.ID) (*taskmodel.Task, error) {
+	trash, ok := s.TaskService.(taskmodel.TaskTrashService)
+	if !ok {
+		return nil, taskmodel.ErrTaskTrashNotSupported
+	}
+
+	t, err := trash.RestoreTask(ctx, id)
+	if err != nil {
+		return t, err
+	}
+
+	return t, s.coordinator.TaskCreated(ctx, t)
+}
+
 // CancelRun Cancel the run and publish the cancellation.
 func (s *CoordinatingTaskService) CancelRun(ctx context.Context, taskID, runID platform.ID) error {
 	if err := s.TaskService.CancelRun(ctx, taskID, runID); err != nil {
@@ -131,7 +131,7 @@ func TestCoordinatingTaskService(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if id != scheduler.ID(task.ID) {
+	if id != task.ID {
 		t.Fatalf("task given to scheduler not the same as task created. expected: %v, got: %v", task.ID, id)
 	}
 
@@ -0,0 +1,70 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+	"go.uber.org/zap"
+)
+
+// RunExpirer fails and finishes runs which have been claimed for longer than a given duration.
+type RunExpirer interface {
+	ExpireRuns(ctx context.Context, olderThan time.Duration) ([]*taskmodel.Run, error)
+}
+
+// RunReaper periodically expires runs that have been running for longer than a timeout.
+// Runs left behind by a process that stopped mid run would otherwise be kept forever
+// and count against the concurrency limit of their task.
+type RunReaper struct {
+	log      *zap.Logger
+	expirer  RunExpirer
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// NewRunReaper creates a RunReaper which expires runs older than timeout.
+// It sweeps for stale runs once every tenth of the timeout, but at most once a second.
+func NewRunReaper(log *zap.Logger, expirer RunExpirer, timeout time.Duration) *RunReaper {
+	interval := timeout / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	return &RunReaper{
+		log:      log,
+		expirer:  expirer,
+		timeout:  timeout,
+		interval: interval,
+	}
+}
+
+// Run sweeps for stale runs until ctx is done.
+func (r *RunReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.sweep(ctx)
+		}
+	}
+}
+
+func (r *RunReaper) sweep(ctx context.Context) {
+	runs, err := r.expirer.ExpireRuns(ctx, r.timeout)
+	if err != nil {
+		r.log.Error("Failed to expire stale runs", zap.Error(err))
+		return
+	}
+
+	for _, run := range runs {
+		r.log.Info("Expired stale run",
+			zap.String("taskID", run.TaskID.String()),
+			zap.String("runID", run.ID.String()),
+			zap.Time("scheduledFor", run.ScheduledFor))
+	}
+}
@@ -18,12 +18,16 @@ import (
 type StoragePointsWriterRecorder struct {
 	pw storage.PointsWriter
 
+	// RedactScripts leaves the name and script of the task out of the
+	// recorded runs, so encrypted tasks are not written in plain text.
+	RedactScripts bool
+
 	log *zap.Logger
 }
 
 // NewStoragePointsWriterRecorder configures and returns a new *StoragePointsWriterRecorder
 func NewStoragePointsWriterRecorder(log *zap.Logger, pw storage.PointsWriter) *StoragePointsWriterRecorder {
-	return &StoragePointsWriterRecorder{pw, log}
+	return &StoragePointsWriterRecorder{pw: pw, log: log}
 }
 
 // Record formats the provided run as a models.Point and writes the resulting
@@ -45,12 +49,18 @@ func (s *StoragePointsWriterRecorder) Record(ctx context.Context, bucketID platf
 
 	fields := map[string]interface{}{}
 	fields[runIDField] = run.ID.String()
-	fields[nameField] = task.Name
+	if !s.RedactScripts {
+		fields[nameField] = task.Name
+		fields[fluxField] = run.Flux
+	}
 	fields[startedAtField] = run.StartedAt.Format(time.RFC3339Nano)
 	fields[finishedAtField] = run.FinishedAt.Format(time.RFC3339Nano)
+	if !run.StartedAt.IsZero() && run.FinishedAt.After(run.StartedAt) {
+		// The duration in nanoseconds, so the runs can be aggregated by duration.
+		fields[durationField] = int64(run.FinishedAt.Sub(run.StartedAt))
+	}
 	fields[scheduledForField] = run.ScheduledFor.Format(time.RFC3339)
 	fields[requestedAtField] = run.RequestedAt.Format(time.RFC3339)
-	fields[fluxField] = run.Flux
 	fields[traceIDField] = run.TraceID
 	fields[traceSampledTag] = run.IsSampled
 
@@ -0,0 +1,58 @@
+package backend_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/mock"
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/task/backend"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestStoragePointsWriterRecorder_Record(t *testing.T) {
+	var written models.Points
+	pw := &mock.PointsWriter{
+		WritePointsFn: func(ctx context.Context, orgID platform.ID, bucketID platform.ID, points []models.Point) error {
+			require.Equal(t, platform.ID(1), orgID)
+			require.Equal(t, platform.ID(2), bucketID)
+			written = points
+			return nil
+		},
+	}
+
+	startedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := &taskmodel.Task{ID: 3, OrganizationID: 1, Name: "t"}
+	run := &taskmodel.Run{
+		ID:           4,
+		TaskID:       3,
+		Status:       taskmodel.RunFail.String(),
+		ScheduledFor: startedAt,
+		StartedAt:    startedAt,
+		FinishedAt:   startedAt.Add(1500 * time.Millisecond),
+	}
+
+	r := backend.NewStoragePointsWriterRecorder(zaptest.NewLogger(t), pw)
+	require.NoError(t, r.Record(context.Background(), 2, "_tasks", task, run))
+	require.Len(t, written, 1)
+
+	p := written[0]
+	require.Equal(t, "runs", string(p.Name()))
+	require.Equal(t, "failed", p.Tags().GetString("status"))
+	require.Equal(t, run.TaskID.String(), p.Tags().GetString("taskID"))
+	fields, err := p.Fields()
+	require.NoError(t, err)
+	require.Equal(t, int64(1500*time.Millisecond), fields["duration"])
+	require.Equal(t, run.ID.String(), fields["runID"])
+
+	// A run that has not finished has no duration.
+	run.FinishedAt = time.Time{}
+	require.NoError(t, r.Record(context.Background(), 2, "_tasks", task, run))
+	fields, err = written[0].Fields()
+	require.NoError(t, err)
+	require.NotContains(t, fields, "duration")
+}
@@ -29,7 +29,7 @@ func NewSchedulableTaskService(ts UpdateTaskService) SchedulableTaskService {
 
 // UpdateLastScheduled uses the task service to store the latest time a task was scheduled to run
 func (s SchedulableTaskService) UpdateLastScheduled(ctx context.Context, id scheduler.ID, t time.Time) error {
-	_, err := s.UpdateTask(ctx, platform.ID(id), taskmodel.TaskUpdate{
+	_, err := s.UpdateTask(ctx, id, taskmodel.TaskUpdate{
 		LatestScheduled: &t,
 	})
 
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/influxdata/influxdb/v2/kit/platform"
-	"github.com/influxdata/influxdb/v2/task/backend/scheduler"
 	"github.com/influxdata/influxdb/v2/task/taskmodel"
 )
 
@@ -37,7 +36,7 @@ func Test_Schedulable_Task_Service(t *testing.T) {
 
 			schedulableService := NewSchedulableTaskService(ts)
 
-			err := schedulableService.UpdateLastScheduled(context.Background(), scheduler.ID(mockTaskID), mockTimeNow)
+			err := schedulableService.UpdateLastScheduled(context.Background(), mockTaskID, mockTimeNow)
 			if err != nil {
 				t.Fatalf("expected nil error, got: %v", err)
 			}
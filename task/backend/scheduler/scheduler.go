@@ -6,12 +6,13 @@ import (
 	"time"
 
 	"github.com/influxdata/cron"
+	"github.com/influxdata/influxdb/v2/kit/platform"
 	"github.com/influxdata/influxdb/v2/task/options"
 )
 
-// ID duplicates the influxdb ID so users of the scheduler don't have to
-// import influxdb for the ID.
-type ID uint64
+// ID is the ID of a schedulable item. It is the ID of the task being scheduled,
+// so items can be passed between the scheduler and the task services without conversions.
+type ID = platform.ID
 
 // Executor is a system used by the scheduler to actually execute the scheduleable item.
 type Executor interface {
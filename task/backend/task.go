@@ -15,6 +15,10 @@ type TaskControlService interface {
 	// CreateRun creates a run with a scheduled for time.
 	CreateRun(ctx context.Context, taskID platform.ID, scheduledFor time.Time, runAt time.Time) (*taskmodel.Run, error)
 
+	// CreateRuns creates many runs at once.
+	// Either all of the runs are created or, if any of them fails, none of them.
+	CreateRuns(ctx context.Context, creates []taskmodel.RunCreate) ([]*taskmodel.Run, error)
+
 	CurrentlyRunning(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error)
 	ManualRuns(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error)
 
@@ -24,6 +28,10 @@ type TaskControlService interface {
 	// FinishRun removes runID from the list of running tasks and if its `ScheduledFor` is later then last completed update it.
 	FinishRun(ctx context.Context, taskID, runID platform.ID) (*taskmodel.Run, error)
 
+	// FinishRuns finishes many runs at once.
+	// Either all of the runs are finished or, if any of them fails, none of them.
+	FinishRuns(ctx context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error)
+
 	// UpdateRunState sets the run state at the respective time.
 	UpdateRunState(ctx context.Context, taskID, runID platform.ID, when time.Time, state taskmodel.RunStatus) error
 
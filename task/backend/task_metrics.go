@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/kit/metric"
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TaskControlMetrics records the call count, errors and latency of every call made to
+// the TaskControlService, as well as the number of runs created and finished.
+type TaskControlMetrics struct {
+	// RED metrics
+	rec *metric.REDClient
+
+	taskControlService TaskControlService
+}
+
+func NewTaskControlMetrics(reg prometheus.Registerer, s TaskControlService, opts ...metric.ClientOptFn) *TaskControlMetrics {
+	o := metric.ApplyMetricOpts(opts...)
+	return &TaskControlMetrics{
+		rec: metric.New(reg, o.ApplySuffix("task_control"),
+			metric.WithVec(metric.VecOpts{
+				Name: "runs_created_total",
+				Help: "Number of runs created",
+				CounterFn: func(vec *prometheus.CounterVec, o metric.CollectFnOpts) {
+					if o.Err != nil {
+						return
+					}
+					switch o.Method {
+					case "create_run":
+						vec.WithLabelValues().Inc()
+					case "create_runs":
+						n, _ := o.AdditionalProps["runs"].(int)
+						vec.WithLabelValues().Add(float64(n))
+					}
+				},
+			}),
+			metric.WithVec(metric.VecOpts{
+				Name:       "runs_finished_total",
+				Help:       "Number of runs finished, split out by the final status of the run",
+				LabelNames: []string{"status"},
+				CounterFn: func(vec *prometheus.CounterVec, o metric.CollectFnOpts) {
+					if o.Err != nil {
+						return
+					}
+					switch o.Method {
+					case "finish_run":
+						status, _ := o.AdditionalProps["status"].(string)
+						vec.With(prometheus.Labels{"status": status}).Inc()
+					case "finish_runs":
+						statuses, _ := o.AdditionalProps["statuses"].([]string)
+						for _, status := range statuses {
+							vec.With(prometheus.Labels{"status": status}).Inc()
+						}
+					}
+				},
+			}),
+		),
+		taskControlService: s,
+	}
+}
+
+var _ TaskControlService = (*TaskControlMetrics)(nil)
+
+func (m *TaskControlMetrics) CreateRun(ctx context.Context, taskID platform.ID, scheduledFor time.Time, runAt time.Time) (*taskmodel.Run, error) {
+	rec := m.rec.Record("create_run")
+	r, err := m.taskControlService.CreateRun(ctx, taskID, scheduledFor, runAt)
+	return r, rec(err)
+}
+
+func (m *TaskControlMetrics) CreateRuns(ctx context.Context, creates []taskmodel.RunCreate) ([]*taskmodel.Run, error) {
+	rec := m.rec.Record("create_runs")
+	rs, err := m.taskControlService.CreateRuns(ctx, creates)
+	if err != nil {
+		return rs, rec(err)
+	}
+	return rs, rec(err, metric.RecordAdditional(map[string]interface{}{
+		"runs": len(rs),
+	}))
+}
+
+func (m *TaskControlMetrics) CurrentlyRunning(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error) {
+	rec := m.rec.Record("currently_running")
+	rs, err := m.taskControlService.CurrentlyRunning(ctx, taskID)
+	return rs, rec(err)
+}
+
+func (m *TaskControlMetrics) ManualRuns(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error) {
+	rec := m.rec.Record("manual_runs")
+	rs, err := m.taskControlService.ManualRuns(ctx, taskID)
+	return rs, rec(err)
+}
+
+func (m *TaskControlMetrics) StartManualRun(ctx context.Context, taskID, runID platform.ID) (*taskmodel.Run, error) {
+	rec := m.rec.Record("start_manual_run")
+	r, err := m.taskControlService.StartManualRun(ctx, taskID, runID)
+	return r, rec(err)
+}
+
+func (m *TaskControlMetrics) FinishRun(ctx context.Context, taskID, runID platform.ID) (*taskmodel.Run, error) {
+	rec := m.rec.Record("finish_run")
+	r, err := m.taskControlService.FinishRun(ctx, taskID, runID)
+	if err != nil {
+		return r, rec(err)
+	}
+	return r, rec(err, metric.RecordAdditional(map[string]interface{}{
+		"status": r.Status,
+	}))
+}
+
+func (m *TaskControlMetrics) FinishRuns(ctx context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error) {
+	rec := m.rec.Record("finish_runs")
+	rs, err := m.taskControlService.FinishRuns(ctx, refs)
+	if err != nil {
+		return rs, rec(err)
+	}
+	statuses := make([]string, 0, len(rs))
+	for _, r := range rs {
+		statuses = append(statuses, r.Status)
+	}
+	return rs, rec(err, metric.RecordAdditional(map[string]interface{}{
+		"statuses": statuses,
+	}))
+}
+
+func (m *TaskControlMetrics) UpdateRunState(ctx context.Context, taskID, runID platform.ID, when time.Time, state taskmodel.RunStatus) error {
+	rec := m.rec.Record("update_run_state")
+	err := m.taskControlService.UpdateRunState(ctx, taskID, runID, when, state)
+	return rec(err)
+}
+
+func (m *TaskControlMetrics) AddRunLog(ctx context.Context, taskID, runID platform.ID, when time.Time, log string) error {
+	rec := m.rec.Record("add_run_log")
+	err := m.taskControlService.AddRunLog(ctx, taskID, runID, when, log)
+	return rec(err)
+}
@@ -0,0 +1,55 @@
+package backend_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kit/prom"
+	"github.com/influxdata/influxdb/v2/kit/prom/promtest"
+	"github.com/influxdata/influxdb/v2/task/backend"
+	"github.com/influxdata/influxdb/v2/task/mock"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTaskControlMetrics(t *testing.T) {
+	ctx := context.Background()
+	reg := prom.NewRegistry(zaptest.NewLogger(t))
+
+	tcs := mock.NewTaskControlService()
+	taskID := platform.ID(1)
+	tcs.SetTask(&taskmodel.Task{ID: taskID})
+
+	svc := backend.NewTaskControlMetrics(reg, tcs)
+
+	now := time.Now().UTC()
+	r1, err := svc.CreateRun(ctx, taskID, now, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.CreateRun(ctx, taskID, now.Add(time.Second), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UpdateRunState(ctx, taskID, r1.ID, now, taskmodel.RunSuccess); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FinishRun(ctx, taskID, r1.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	mg := promtest.MustGather(t, reg)
+	m := promtest.MustFindMetric(t, mg, "service_task_control_runs_created_total", nil)
+	if got := m.GetCounter().GetValue(); got != 2 {
+		t.Fatalf("expected 2 runs created, got %v", got)
+	}
+	m = promtest.MustFindMetric(t, mg, "service_task_control_runs_finished_total", map[string]string{"status": "success"})
+	if got := m.GetCounter().GetValue(); got != 1 {
+		t.Fatalf("expected 1 run finished, got %v", got)
+	}
+	m = promtest.MustFindMetric(t, mg, "service_task_control_call_total", map[string]string{"method": "create_run"})
+	if got := m.GetCounter().GetValue(); got != 2 {
+		t.Fatalf("expected 2 create_run calls, got %v", got)
+	}
+}
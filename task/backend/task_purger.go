@@ -0,0 +1,68 @@
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+	"go.uber.org/zap"
+)
+
+// DeletedTaskPurger permanently removes tasks which have been deleted for longer than a given duration.
+type DeletedTaskPurger interface {
+	PurgeDeletedTasks(ctx context.Context, olderThan time.Duration) ([]*taskmodel.DeletedTask, error)
+}
+
+// TaskPurger periodically purges the tasks that have been in the trash for longer than a retention period.
+type TaskPurger struct {
+	log       *zap.Logger
+	purger    DeletedTaskPurger
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewTaskPurger creates a TaskPurger which purges tasks deleted more than retention ago.
+// It sweeps the trash once every tenth of the retention period, but at most once a minute.
+func NewTaskPurger(log *zap.Logger, purger DeletedTaskPurger, retention time.Duration) *TaskPurger {
+	interval := retention / 10
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+
+	return &TaskPurger{
+		log:       log,
+		purger:    purger,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// Run sweeps the trash until ctx is done.
+func (p *TaskPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.sweep(ctx)
+		}
+	}
+}
+
+func (p *TaskPurger) sweep(ctx context.Context) {
+	tasks, err := p.purger.PurgeDeletedTasks(ctx, p.retention)
+	if err != nil {
+		p.log.Error("Failed to purge deleted tasks", zap.Error(err))
+		return
+	}
+
+	for _, t := range tasks {
+		p.log.Info("Purged deleted task",
+			zap.String("taskID", t.Task.ID.String()),
+			zap.String("orgID", t.Task.OrganizationID.String()),
+			zap.Time("deletedAt", t.DeletedAt))
+	}
+}
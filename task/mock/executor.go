@@ -94,7 +94,7 @@ func NewExecutor() *Executor {
 func (e *Executor) Execute(ctx context.Context, id scheduler.ID, scheduledAt time.Time, runAt time.Time) error {
 
 	select {
-	case e.ExecutedChan <- scheduler.ID(id):
+	case e.ExecutedChan <- id:
 	default:
 		return errors.New("could not add task ID to executedChan")
 	}
@@ -68,12 +68,26 @@ func (t *TaskControlService) CreateRun(_ context.Context, taskID platform.ID, sc
 	}
 	runs[runID] = &taskmodel.Run{
 		ID:           runID,
+		TaskID:       taskID,
 		ScheduledFor: scheduledFor,
+		RunAt:        runAt,
 	}
 	t.runs[taskID] = runs
 	return runs[runID], nil
 }
 
+func (t *TaskControlService) CreateRuns(ctx context.Context, creates []taskmodel.RunCreate) ([]*taskmodel.Run, error) {
+	runs := make([]*taskmodel.Run, 0, len(creates))
+	for _, c := range creates {
+		r, err := t.CreateRun(ctx, c.TaskID, c.ScheduledFor, c.RunAt)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, nil
+}
+
 func (t *TaskControlService) StartManualRun(_ context.Context, taskID, runID platform.ID) (*taskmodel.Run, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -110,6 +124,18 @@ func (d *TaskControlService) FinishRun(_ context.Context, taskID, runID platform
 	return r, nil
 }
 
+func (d *TaskControlService) FinishRuns(ctx context.Context, refs []taskmodel.RunRef) ([]*taskmodel.Run, error) {
+	runs := make([]*taskmodel.Run, 0, len(refs))
+	for _, ref := range refs {
+		r, err := d.FinishRun(ctx, ref.TaskID, ref.RunID)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, nil
+}
+
 func (t *TaskControlService) CurrentlyRunning(ctx context.Context, taskID platform.ID) ([]*taskmodel.Run, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
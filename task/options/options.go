@@ -228,100 +228,142 @@ var taskOptionExtractors = []extractFn{
 }
 
 func extractNameOption(opts *Options, objExpr *ast.ObjectExpression) error {
-	nameExpr, err := edit.GetProperty(objExpr, optName)
+	name, err := getRequiredString(objExpr, optName)
 	if err != nil {
-		return errMissingRequiredTaskOption(optName)
-	}
-	nameStr, ok := nameExpr.(*ast.StringLiteral)
-	if !ok {
-		return errParseTaskOptionField(optName)
+		return err
 	}
-	opts.Name = ast.StringFromLiteral(nameStr)
+	opts.Name = name
 
 	return nil
 }
 
 func extractScheduleOptions(opts *Options, objExpr *ast.ObjectExpression) error {
-	cronExpr, cronErr := edit.GetProperty(objExpr, optCron)
-	everyExpr, everyErr := edit.GetProperty(objExpr, optEvery)
-	if cronErr == nil && everyErr == nil {
+	cron, hasCron, cronErr := getString(objExpr, optCron)
+	every, hasEvery, everyErr := getDuration(objExpr, optEvery)
+	if hasCron && hasEvery {
 		return ErrDuplicateIntervalField
 	}
-	if cronErr != nil && everyErr != nil {
+	if !hasCron && !hasEvery {
 		return errMissingRequiredTaskOption("cron or every")
 	}
-
-	if cronErr == nil {
-		cronExprStr, ok := cronExpr.(*ast.StringLiteral)
-		if !ok {
-			return errParseTaskOptionField(optCron)
-		}
-		opts.Cron = ast.StringFromLiteral(cronExprStr)
+	if cronErr != nil {
+		return cronErr
+	}
+	if everyErr != nil {
+		return everyErr
 	}
 
-	if everyErr == nil {
-		everyDur, ok := everyExpr.(*ast.DurationLiteral)
-		if !ok {
-			return errParseTaskOptionField(optEvery)
-		}
-		opts.Every = Duration{Node: *everyDur}
+	if hasCron {
+		opts.Cron = normalizeCron(cron)
+	}
+	if hasEvery {
+		opts.Every = *every
 	}
 
 	return nil
 }
 
 func extractOffsetOption(opts *Options, objExpr *ast.ObjectExpression) error {
-	offsetExpr, offsetErr := edit.GetProperty(objExpr, optOffset)
-	if offsetErr != nil {
-		return nil
-	}
-
-	switch offsetExprV := offsetExpr.(type) {
-	case *ast.UnaryExpression:
-		offsetDur, err := ParseSignedDuration(offsetExprV.Loc.Source)
-		if err != nil {
-			return err
-		}
-		opts.Offset = &Duration{Node: *offsetDur}
-	case *ast.DurationLiteral:
-		opts.Offset = &Duration{Node: *offsetExprV}
-	default:
-		return errParseTaskOptionField(optOffset)
+	offset, _, err := getSignedDuration(objExpr, optOffset)
+	if err != nil {
+		return err
 	}
+	opts.Offset = offset
 
 	return nil
 }
 
 func extractConcurrencyOption(opts *Options, objExpr *ast.ObjectExpression) error {
-	concurExpr, err := edit.GetProperty(objExpr, optConcurrency)
+	concurrency, _, err := getInt(objExpr, optConcurrency)
 	if err != nil {
-		return nil
+		return err
 	}
+	opts.Concurrency = concurrency
 
-	concurInt, ok := concurExpr.(*ast.IntegerLiteral)
-	if !ok {
-		return errParseTaskOptionField(optConcurrency)
+	return nil
+}
+
+func extractRetryOption(opts *Options, objExpr *ast.ObjectExpression) error {
+	retry, _, err := getInt(objExpr, optRetry)
+	if err != nil {
+		return err
 	}
-	val := ast.IntegerFromLiteral(concurInt)
-	opts.Concurrency = &val
+	opts.Retry = retry
 
 	return nil
 }
 
-func extractRetryOption(opts *Options, objExpr *ast.ObjectExpression) error {
-	retryExpr, err := edit.GetProperty(objExpr, optRetry)
+// getRequiredString returns the string literal of the option.
+// It returns an error when the option is not set.
+func getRequiredString(objExpr *ast.ObjectExpression, opt string) (string, error) {
+	v, ok, err := getString(objExpr, opt)
 	if err != nil {
-		return nil
+		return "", err
 	}
+	if !ok {
+		return "", errMissingRequiredTaskOption(opt)
+	}
+	return v, nil
+}
 
-	retryInt, ok := retryExpr.(*ast.IntegerLiteral)
+// getString returns the string literal of the option, and whether the option is set.
+func getString(objExpr *ast.ObjectExpression, opt string) (string, bool, error) {
+	expr, err := edit.GetProperty(objExpr, opt)
+	if err != nil {
+		return "", false, nil
+	}
+	lit, ok := expr.(*ast.StringLiteral)
 	if !ok {
-		return errParseTaskOptionField(optRetry)
+		return "", true, errParseTaskOptionField(opt, "string", expr)
 	}
-	val := ast.IntegerFromLiteral(retryInt)
-	opts.Retry = &val
+	return ast.StringFromLiteral(lit), true, nil
+}
 
-	return nil
+// getDuration returns the duration literal of the option, and whether the option is set.
+func getDuration(objExpr *ast.ObjectExpression, opt string) (*Duration, bool, error) {
+	expr, err := edit.GetProperty(objExpr, opt)
+	if err != nil {
+		return nil, false, nil
+	}
+	lit, ok := expr.(*ast.DurationLiteral)
+	if !ok {
+		return nil, true, errParseTaskOptionField(opt, "duration", expr)
+	}
+	return &Duration{Node: *lit}, true, nil
+}
+
+// getSignedDuration is like getDuration but also accepts a negated duration literal.
+func getSignedDuration(objExpr *ast.ObjectExpression, opt string) (*Duration, bool, error) {
+	expr, err := edit.GetProperty(objExpr, opt)
+	if err != nil {
+		return nil, false, nil
+	}
+	switch e := expr.(type) {
+	case *ast.UnaryExpression:
+		lit, err := ParseSignedDuration(e.Loc.Source)
+		if err != nil {
+			return nil, true, err
+		}
+		return &Duration{Node: *lit}, true, nil
+	case *ast.DurationLiteral:
+		return &Duration{Node: *e}, true, nil
+	default:
+		return nil, true, errParseTaskOptionField(opt, "duration", expr)
+	}
+}
+
+// getInt returns the integer literal of the option, and whether the option is set.
+func getInt(objExpr *ast.ObjectExpression, opt string) (*int64, bool, error) {
+	expr, err := edit.GetProperty(objExpr, opt)
+	if err != nil {
+		return nil, false, nil
+	}
+	lit, ok := expr.(*ast.IntegerLiteral)
+	if !ok {
+		return nil, true, errParseTaskOptionField(opt, "integer", expr)
+	}
+	v := ast.IntegerFromLiteral(lit)
+	return &v, true, nil
 }
 
 // Validate returns an error if the options aren't valid.
@@ -395,7 +437,7 @@ func (o *Options) Validate() error {
 // Do not use this if you haven't checked for validity already.
 func (o *Options) EffectiveCronString() string {
 	if o.Cron != "" {
-		return o.Cron
+		return normalizeCron(o.Cron)
 	}
 	every, _ := o.Every.DurationFrom(time.Now()) // we can ignore errors here because we have already checked for validity.
 	if every > 0 {
@@ -404,6 +446,12 @@ func (o *Options) EffectiveCronString() string {
 	return ""
 }
 
+// normalizeCron trims a cron string and collapses the whitespace between its fields,
+// so equivalent schedules are stored in task meta the same way.
+func normalizeCron(c string) string {
+	return strings.Join(strings.Fields(c), " ")
+}
+
 // parse will take flux source code and produce a package.
 // If there are errors when parsing, the first error is returned.
 // An ast.Package may be returned when a parsing error occurs,
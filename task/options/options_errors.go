@@ -3,12 +3,14 @@ package options
 import (
 	"errors"
 	"fmt"
+
+	"github.com/influxdata/flux/ast"
 )
 
 // errParseTaskOptionField is returned when we fail to parse a single field in
-// task options.
-func errParseTaskOptionField(opt string) error {
-	return fmt.Errorf("failed to parse field '%s' in task options", opt)
+// task options because its value is not of the expected type.
+func errParseTaskOptionField(opt, expected string, actual ast.Expression) error {
+	return fmt.Errorf("failed to parse field '%s' in task options: expected %s, found %s", opt, expected, actual.Type())
 }
 
 // errMissingRequiredTaskOption is returned when we a required option is
@@ -0,0 +1,76 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+)
+
+func TestGetOptionValues(t *testing.T) {
+	property := func(key string, value ast.Expression) *ast.Property {
+		return &ast.Property{Key: &ast.Identifier{Name: key}, Value: value}
+	}
+	objExpr := &ast.ObjectExpression{
+		Properties: []*ast.Property{
+			property("name", &ast.StringLiteral{Value: "task"}),
+			property("every", &ast.DurationLiteral{Values: []ast.Duration{{Magnitude: 1, Unit: "h"}}}),
+			property("retry", &ast.IntegerLiteral{Value: 3}),
+			property("cron", &ast.IntegerLiteral{Value: 1}),
+		},
+	}
+
+	if v, err := getRequiredString(objExpr, "name"); err != nil || v != "task" {
+		t.Fatalf("unexpected name: %q, %v", v, err)
+	}
+	if v, ok, err := getDuration(objExpr, "every"); err != nil || !ok || v.String() != "1h" {
+		t.Fatalf("unexpected every: %v, %t, %v", v, ok, err)
+	}
+	if v, ok, err := getInt(objExpr, "retry"); err != nil || !ok || *v != 3 {
+		t.Fatalf("unexpected retry: %v, %t, %v", v, ok, err)
+	}
+
+	// Optional options that are not set are not an error.
+	if v, ok, err := getInt(objExpr, "concurrency"); err != nil || ok || v != nil {
+		t.Fatalf("unexpected concurrency: %v, %t, %v", v, ok, err)
+	}
+	if v, ok, err := getSignedDuration(objExpr, "offset"); err != nil || ok || v != nil {
+		t.Fatalf("unexpected offset: %v, %t, %v", v, ok, err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		get  func() error
+		want string
+	}{
+		{
+			name: "missing required",
+			get: func() error {
+				_, err := getRequiredString(objExpr, "description")
+				return err
+			},
+			want: "missing required option: description",
+		},
+		{
+			name: "wrong type",
+			get: func() error {
+				_, _, err := getString(objExpr, "cron")
+				return err
+			},
+			want: "failed to parse field 'cron' in task options: expected string, found IntegerLiteral",
+		},
+		{
+			name: "wrong duration type",
+			get: func() error {
+				_, _, err := getDuration(objExpr, "name")
+				return err
+			},
+			want: "failed to parse field 'name' in task options: expected duration, found StringLiteral",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.get(); err == nil || err.Error() != tt.want {
+				t.Fatalf("unexpected error: got %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -209,6 +209,7 @@ func TestEffectiveCronString(t *testing.T) {
 		exp string
 	}{
 		{c: "10 * * * *", exp: "10 * * * *"},
+		{c: " 0  5 *\t* 1 ", exp: "0 5 * * 1"},
 		{e: *(options.MustParseDuration("10s")), exp: "@every 10s"},
 		{exp: ""},
 		{e: *(options.MustParseDuration("10d")), exp: "@every 10d"},
@@ -300,6 +300,7 @@ func testTaskCRUD(t *testing.T, sys *System) {
 		Status:          string(taskmodel.DefaultTaskStatus),
 		Flux:            fmt.Sprintf(scriptFmt, 0),
 		Type:            taskmodel.TaskSystemType,
+		Version:         1,
 	}
 
 	for fn, f := range found {
@@ -817,7 +818,7 @@ func testUpdate(t *testing.T, sys *System) {
 		t.Fatal("executed task has updated last run error on success")
 	}
 
-	rc2, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt)
+	rc2, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt.Add(time.Second), requestedAt.Add(time.Second))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -932,7 +933,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 			t.Fatalf("failed to error with out of bounds run limit: %d", taskmodel.TaskMaxPageSize+1)
 		}
 
-		requestedAt := time.Now().Add(time.Hour * -1).UTC() // This should guarantee we can make two runs.
+		requestedAt := time.Now().Add(time.Hour).UTC() // This should guarantee we can make two runs.
 
 		rc0, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
 		if err != nil {
@@ -949,7 +950,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 			t.Fatal(err)
 		}
 
-		rc1, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
+		rc1, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt.Add(time.Second), requestedAt.Add(2*time.Second))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -1046,7 +1047,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 		}
 
 		// set to one hour before now because of bucket retention policy
-		scheduledFor := time.Now().Add(time.Hour * -1).UTC()
+		scheduledFor := time.Now().Add(time.Hour).UTC()
 		runs := make([]*taskmodel.Run, 0, 5)
 		// create runs to put into Context
 		for i := 5; i > 0; i-- {
@@ -1118,6 +1119,9 @@ func testTaskRuns(t *testing.T, sys *System) {
 		if r.ScheduledFor != exp {
 			t.Fatalf("expected: 1970-01-01T00:01:17Z, got %s", r.ScheduledFor)
 		}
+		if !r.Manual {
+			t.Fatal("expected forced run to be marked as manual")
+		}
 
 		// Forcing the same run before it's executed should be rejected.
 		if _, err = sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor); err == nil {
@@ -1138,7 +1142,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 			t.Fatal(err)
 		}
 
-		requestedAt := time.Now().Add(time.Hour * -1).UTC() // This should guarantee we can make a run.
+		requestedAt := time.Now().Add(time.Hour).UTC() // This should guarantee we can make a run.
 
 		// Create two runs.
 		rc1, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
@@ -1149,7 +1153,7 @@ func testTaskRuns(t *testing.T, sys *System) {
 			t.Fatal(err)
 		}
 
-		rc2, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
+		rc2, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt.Add(time.Second), requestedAt.Add(2*time.Second))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -1447,7 +1451,7 @@ func testRunStorage(t *testing.T, sys *System) {
 		t.Fatalf("failed to error with out of bounds run limit: %d", taskmodel.TaskMaxPageSize+1)
 	}
 
-	requestedAt := time.Now().Add(time.Hour * -1).UTC() // This should guarantee we can make two runs.
+	requestedAt := time.Now().Add(time.Hour).UTC() // This should guarantee we can make two runs.
 
 	rc0, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
 	if err != nil {
@@ -1464,7 +1468,7 @@ func testRunStorage(t *testing.T, sys *System) {
 		t.Fatal(err)
 	}
 
-	rc1, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
+	rc1, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt.Add(time.Second), requestedAt.Add(2*time.Second))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1509,7 +1513,7 @@ func testRunStorage(t *testing.T, sys *System) {
 	}
 
 	// Create 3rd run and test limiting to 2 runs
-	rc2, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
+	rc2, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt.Add(2*time.Second), requestedAt.Add(3*time.Second))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1621,7 +1625,7 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 		t.Errorf("expected retrying run that doesn't exist to return %v, got %v", taskmodel.ErrRunNotFound, err)
 	}
 
-	requestedAt := time.Now().Add(time.Hour * -1).UTC() // This should guarantee we can make a run.
+	requestedAt := time.Now().Add(time.Hour).UTC() // This should guarantee we can make a run.
 
 	rc, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
 	if err != nil {
@@ -1683,7 +1687,7 @@ func testLogsAcrossStorage(t *testing.T, sys *System) {
 		t.Fatal(err)
 	}
 
-	requestedAt := time.Now().Add(time.Hour * -1).UTC() // This should guarantee we can make two runs.
+	requestedAt := time.Now().Add(time.Hour).UTC() // This should guarantee we can make two runs.
 
 	rc0, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
 	if err != nil {
@@ -1700,7 +1704,7 @@ func testLogsAcrossStorage(t *testing.T, sys *System) {
 		t.Fatal(err)
 	}
 
-	rc1, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt, requestedAt.Add(time.Second))
+	rc1, err := sys.TaskControlService.CreateRun(sys.Ctx, task.ID, requestedAt.Add(time.Second), requestedAt.Add(2*time.Second))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,148 @@
+package taskmodel
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/task/options"
+)
+
+// DownsampleAggregates are the functions a downsampling task can aggregate its windows with.
+var DownsampleAggregates = []string{"count", "first", "last", "max", "mean", "median", "min", "spread", "stddev", "sum"}
+
+// DownsampleTaskCreate is the set of values to create a task that periodically
+// aggregates the data written to a bucket into another bucket.
+type DownsampleTaskCreate struct {
+	Name           string      `json:"name,omitempty"`
+	Description    string      `json:"description,omitempty"`
+	Status         string      `json:"status,omitempty"`
+	OrganizationID platform.ID `json:"orgID,omitempty"`
+	Organization   string      `json:"org,omitempty"`
+	OwnerID        platform.ID `json:"-"`
+
+	// SourceBucket is the name of the bucket read by the task.
+	SourceBucket string `json:"sourceBucket"`
+	// DestinationBucket is the name of the bucket the aggregated data is written to.
+	DestinationBucket string `json:"destinationBucket"`
+	// Aggregate is the function aggregating each window, one of DownsampleAggregates.
+	Aggregate string `json:"aggregate"`
+	// Every is both the size of the windows and the interval the task runs at.
+	Every string `json:"every"`
+	// Offset delays each run of the task so late data is included in its window.
+	Offset string `json:"offset,omitempty"`
+}
+
+// Validate checks the values of the downsampling task.
+func (d DownsampleTaskCreate) Validate() error {
+	switch {
+	case d.SourceBucket == "":
+		return errors.New("missing sourceBucket")
+	case d.DestinationBucket == "":
+		return errors.New("missing destinationBucket")
+	case d.SourceBucket == d.DestinationBucket:
+		return errors.New("sourceBucket and destinationBucket must be different")
+	case d.Every == "":
+		return errors.New("missing every")
+	case !d.OrganizationID.Valid() && d.Organization == "":
+		return errors.New("missing orgID and org")
+	case d.Status != "" && d.Status != TaskStatusActive && d.Status != TaskStatusInactive:
+		return fmt.Errorf("invalid task status: %q", d.Status)
+	}
+
+	if i := sort.SearchStrings(DownsampleAggregates, d.Aggregate); i == len(DownsampleAggregates) || DownsampleAggregates[i] != d.Aggregate {
+		return fmt.Errorf("invalid aggregate %q, must be one of %s", d.Aggregate, strings.Join(DownsampleAggregates, ", "))
+	}
+	_, _, err := d.durations()
+	return err
+}
+
+// durations parses Every and Offset. The offset is nil when it is not set.
+func (d DownsampleTaskCreate) durations() (every, offset *options.Duration, err error) {
+	every = &options.Duration{}
+	if err := every.Parse(d.Every); err != nil {
+		return nil, nil, fmt.Errorf("invalid every: %w", err)
+	}
+	if v, err := every.DurationFrom(time.Unix(0, 0)); err != nil || v <= 0 {
+		return nil, nil, fmt.Errorf("every must be positive: %q", d.Every)
+	}
+	if d.Offset == "" {
+		return every, nil, nil
+	}
+
+	offset = &options.Duration{}
+	if err := offset.Parse(d.Offset); err != nil {
+		return nil, nil, fmt.Errorf("invalid offset: %w", err)
+	}
+	if v, err := offset.DurationFrom(time.Unix(0, 0)); err != nil || v < 0 {
+		return nil, nil, fmt.Errorf("offset must not be negative: %q", d.Offset)
+	}
+	return every, offset, nil
+}
+
+// TaskCreate generates the script of the downsampling task and returns the values to create it.
+// Each run aggregates the window of data that ended when it was scheduled.
+func (d DownsampleTaskCreate) TaskCreate() (TaskCreate, error) {
+	if err := d.Validate(); err != nil {
+		return TaskCreate{}, err
+	}
+	every, offset, _ := d.durations()
+
+	name := d.Name
+	if name == "" {
+		name = fmt.Sprintf("Downsample %s to %s", d.SourceBucket, d.DestinationBucket)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "option task = {name: %s, every: %s", quoteFluxString(name), every)
+	if offset != nil {
+		fmt.Fprintf(&b, ", offset: %s", offset)
+	}
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "from(bucket: %s)\n", quoteFluxString(d.SourceBucket))
+	b.WriteString("    |> range(start: -task.every)\n")
+	fmt.Fprintf(&b, "    |> aggregateWindow(every: task.every, fn: %s, createEmpty: false)\n", d.Aggregate)
+	fmt.Fprintf(&b, "    |> to(bucket: %s)\n", quoteFluxString(d.DestinationBucket))
+
+	return TaskCreate{
+		Type:           TaskSystemType,
+		Flux:           b.String(),
+		Description:    d.Description,
+		Status:         d.Status,
+		OrganizationID: d.OrganizationID,
+		Organization:   d.Organization,
+		OwnerID:        d.OwnerID,
+	}, nil
+}
+
+// quoteFluxString returns s as a Flux string literal.
+func quoteFluxString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '$':
+			// Escape interpolations.
+			if i+1 < len(s) && s[i+1] == '{' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
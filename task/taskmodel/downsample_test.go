@@ -0,0 +1,81 @@
+package taskmodel_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb/v2/query/fluxlang"
+	"github.com/influxdata/influxdb/v2/task/options"
+	"github.com/influxdata/influxdb/v2/task/taskmodel"
+)
+
+func TestDownsampleTaskCreate(t *testing.T) {
+	d := taskmodel.DownsampleTaskCreate{
+		Organization:      "o",
+		SourceBucket:      `raw "data"`,
+		DestinationBucket: "rollup",
+		Aggregate:         "mean",
+		Every:             "1h",
+		Offset:            "5m",
+	}
+	tc, err := d.TaskCreate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const want = `option task = {name: "Downsample raw \"data\" to rollup", every: 1h, offset: 5m}
+
+from(bucket: "raw \"data\"")
+    |> range(start: -task.every)
+    |> aggregateWindow(every: task.every, fn: mean, createEmpty: false)
+    |> to(bucket: "rollup")
+`
+	if tc.Flux != want {
+		t.Fatalf("unexpected script:\n got: %s\nwant: %s", tc.Flux, want)
+	}
+	if tc.Type != taskmodel.TaskSystemType || tc.Organization != "o" {
+		t.Fatalf("unexpected task create: %+v", tc)
+	}
+
+	opts, err := options.FromScriptAST(fluxlang.DefaultService, tc.Flux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Name != `Downsample raw "data" to rollup` || opts.Every.String() != "1h" || opts.Offset.String() != "5m" {
+		t.Fatalf("unexpected task options: %+v", opts)
+	}
+}
+
+func TestDownsampleTaskCreate_Validate(t *testing.T) {
+	valid := taskmodel.DownsampleTaskCreate{
+		Organization:      "o",
+		SourceBucket:      "raw",
+		DestinationBucket: "rollup",
+		Aggregate:         "max",
+		Every:             "10m",
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		update func(d *taskmodel.DownsampleTaskCreate)
+	}{
+		{name: "missing source", update: func(d *taskmodel.DownsampleTaskCreate) { d.SourceBucket = "" }},
+		{name: "same buckets", update: func(d *taskmodel.DownsampleTaskCreate) { d.DestinationBucket = "raw" }},
+		{name: "missing org", update: func(d *taskmodel.DownsampleTaskCreate) { d.Organization = "" }},
+		{name: "unknown aggregate", update: func(d *taskmodel.DownsampleTaskCreate) { d.Aggregate = "fn: (r) => r" }},
+		{name: "invalid every", update: func(d *taskmodel.DownsampleTaskCreate) { d.Every = "often" }},
+		{name: "zero every", update: func(d *taskmodel.DownsampleTaskCreate) { d.Every = "0s" }},
+		{name: "negative offset", update: func(d *taskmodel.DownsampleTaskCreate) { d.Offset = "-1m" }},
+		{name: "invalid status", update: func(d *taskmodel.DownsampleTaskCreate) { d.Status = "paused" }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid
+			tt.update(&d)
+			if err := d.Validate(); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -25,6 +25,16 @@ const (
 
 	TaskStatusActive   = "active"
 	TaskStatusInactive = "inactive"
+
+	// The catch-up policy of a task decides which of the schedule times it
+	// missed while it was not scheduled, e.g. while the server was down, are run.
+
+	// TaskCatchUpAll runs every missed schedule time. It is the default policy.
+	TaskCatchUpAll = "all"
+	// TaskCatchUpOnce runs only the latest missed schedule time.
+	TaskCatchUpOnce = "once"
+	// TaskCatchUpSkip runs none of the missed schedule times.
+	TaskCatchUpSkip = "skip"
 )
 
 var (
@@ -48,6 +58,7 @@ type Task struct {
 	Every           string                 `json:"every,omitempty"`
 	Cron            string                 `json:"cron,omitempty"`
 	Offset          time.Duration          `json:"offset,omitempty"`
+	CatchUp         string                 `json:"catchUp,omitempty"`
 	LatestCompleted time.Time              `json:"latestCompleted,omitempty"`
 	LatestScheduled time.Time              `json:"latestScheduled,omitempty"`
 	LatestSuccess   time.Time              `json:"latestSuccess,omitempty"`
@@ -57,6 +68,13 @@ type Task struct {
 	CreatedAt       time.Time              `json:"createdAt,omitempty"`
 	UpdatedAt       time.Time              `json:"updatedAt,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+
+	// Tags are arbitrary key/value pairs used to group and filter tasks.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Version is incremented every time the task is modified by a user. It is
+	// used to detect concurrent modifications of the same task.
+	Version int64 `json:"version,omitempty"`
 }
 
 // EffectiveCron returns the effective cron string of the options.
@@ -74,6 +92,16 @@ func (t *Task) EffectiveCron() string {
 	return ""
 }
 
+// OrgQuota limits the tasks of an organization so that a single organization
+// cannot monopolize the scheduler. A zero limit is not enforced.
+type OrgQuota struct {
+	// MaxTasks is the number of tasks the organization can own.
+	MaxTasks int
+	// MaxConcurrency is the number of runs of all the tasks of the organization
+	// that can be scheduled or running at the same time.
+	MaxConcurrency int
+}
+
 // Run is a record createId when a run of a task is scheduled.
 type Run struct {
 	ID           platform.ID `json:"id,omitempty"`
@@ -85,12 +113,26 @@ type Run struct {
 	StartedAt    time.Time   `json:"startedAt,omitempty"`   // StartedAt is the time the executor begins running the task
 	FinishedAt   time.Time   `json:"finishedAt,omitempty"`  // FinishedAt is the time the executor finishes running the task
 	RequestedAt  time.Time   `json:"requestedAt,omitempty"` // RequestedAt is the time the coordinator told the scheduler to schedule the task
+	Manual       bool        `json:"manual,omitempty"`      // Manual is set for runs requested by a user rather than created by the schedule
 	Log          []Log       `json:"log,omitempty"`
 
 	TraceID   string `json:"traceID"`   // TraceID preserves the trace id
 	IsSampled bool   `json:"isSampled"` // IsSampled preserves whether this run was sampled
 }
 
+// RunCreate describes a run to create with CreateRuns.
+type RunCreate struct {
+	TaskID       platform.ID
+	ScheduledFor time.Time
+	RunAt        time.Time
+}
+
+// RunRef identifies a run of a task.
+type RunRef struct {
+	TaskID platform.ID
+	RunID  platform.ID
+}
+
 // Log represents a link to a log resource
 type Log struct {
 	RunID   platform.ID `json:"runID,omitempty"`
@@ -141,6 +183,47 @@ type TaskService interface {
 	ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*Run, error)
 }
 
+// TaskRevision is a previous version of a task's flux script.
+type TaskRevision struct {
+	TaskID    platform.ID `json:"taskID"`
+	Revision  int64       `json:"revision"`
+	Flux      string      `json:"flux"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// TaskRevisionService represents a service for managing the script history of tasks.
+type TaskRevisionService interface {
+	// FindTaskRevisions returns the previous scripts of a task, oldest first.
+	FindTaskRevisions(ctx context.Context, taskID platform.ID) ([]*TaskRevision, error)
+
+	// RollbackTask restores the script of a task to the given revision.
+	// The script being replaced is kept as a new revision.
+	RollbackTask(ctx context.Context, taskID platform.ID, revision int64) (*Task, error)
+}
+
+// DeletedTask is a task that has been deleted but can still be restored.
+type DeletedTask struct {
+	Task      *Task     `json:"task"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// TaskTrashService represents a service for recovering deleted tasks.
+// Deleted tasks are kept until they are purged.
+type TaskTrashService interface {
+	// FindDeletedTasks returns the deleted tasks of an organization, in ID order.
+	FindDeletedTasks(ctx context.Context, orgID platform.ID) ([]*DeletedTask, error)
+
+	// FindDeletedTaskByID returns a single deleted task.
+	FindDeletedTaskByID(ctx context.Context, id platform.ID) (*DeletedTask, error)
+
+	// RestoreTask returns a deleted task to the set of tasks.
+	RestoreTask(ctx context.Context, id platform.ID) (*Task, error)
+
+	// PurgeDeletedTasks permanently removes the tasks deleted more than olderThan ago,
+	// along with their script history.
+	PurgeDeletedTasks(ctx context.Context, olderThan time.Duration) ([]*DeletedTask, error)
+}
+
 // TaskCreate is the set of values to create a task.
 type TaskCreate struct {
 	Type           string                 `json:"type,omitempty"`
@@ -151,9 +234,17 @@ type TaskCreate struct {
 	Organization   string                 `json:"org,omitempty"`
 	OwnerID        platform.ID            `json:"-"`
 	Metadata       map[string]interface{} `json:"-"` // not to be set through a web request but rather used by a http service using tasks backend.
+	Tags           map[string]string      `json:"tags,omitempty"`
+	CatchUp        string                 `json:"catchUp,omitempty"`
 }
 
 func (t TaskCreate) Validate() error {
+	if err := validateTags(t.Tags); err != nil {
+		return err
+	}
+	if err := validateCatchUp(t.CatchUp); err != nil {
+		return err
+	}
 	switch {
 	case t.Flux == "":
 		return errors.New("missing flux")
@@ -171,6 +262,16 @@ type TaskUpdate struct {
 	Status      *string `json:"status,omitempty"`
 	Description *string `json:"description,omitempty"`
 
+	// Tags replaces the tags of the task when not nil. An empty map removes all the tags.
+	Tags *map[string]string `json:"tags,omitempty"`
+
+	// Version is the version of the task the update is based on. When set, the
+	// update is rejected with ErrTaskConflict if the task has since been modified.
+	Version *int64 `json:"version,omitempty"`
+
+	// CatchUp replaces the catch-up policy of the task when not nil.
+	CatchUp *string `json:"catchUp,omitempty"`
+
 	// LatestCompleted us to set latest completed on startup to skip task catchup
 	LatestCompleted *time.Time             `json:"-"`
 	LatestScheduled *time.Time             `json:"-"`
@@ -206,6 +307,12 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		Tags *map[string]string `json:"tags,omitempty"`
+
+		Version *int64 `json:"version,omitempty"`
+
+		CatchUp *string `json:"catchUp,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &jo); err != nil {
@@ -223,6 +330,9 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 	t.Options.Retry = jo.Retry
 	t.Flux = jo.Flux
 	t.Status = jo.Status
+	t.Tags = jo.Tags
+	t.Version = jo.Version
+	t.CatchUp = jo.CatchUp
 	return nil
 }
 
@@ -245,6 +355,12 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 		Concurrency *int64 `json:"concurrency,omitempty"`
 
 		Retry *int64 `json:"retry,omitempty"`
+
+		Tags *map[string]string `json:"tags,omitempty"`
+
+		Version *int64 `json:"version,omitempty"`
+
+		CatchUp *string `json:"catchUp,omitempty"`
 	}{}
 	jo.Name = t.Options.Name
 	jo.Cron = t.Options.Cron
@@ -258,10 +374,23 @@ func (t *TaskUpdate) MarshalJSON() ([]byte, error) {
 	jo.Retry = t.Options.Retry
 	jo.Flux = t.Flux
 	jo.Status = t.Status
+	jo.Tags = t.Tags
+	jo.Version = t.Version
+	jo.CatchUp = t.CatchUp
 	return json.Marshal(jo)
 }
 
 func (t *TaskUpdate) Validate() error {
+	if t.Tags != nil {
+		if err := validateTags(*t.Tags); err != nil {
+			return err
+		}
+	}
+	if t.CatchUp != nil {
+		if err := validateCatchUp(*t.CatchUp); err != nil {
+			return err
+		}
+	}
 	switch {
 	case !t.Options.Every.IsZero() && t.Options.Cron != "":
 		return errors.New("cannot specify both every and cron")
@@ -273,7 +402,7 @@ func (t *TaskUpdate) Validate() error {
 		if _, err := time.ParseDuration(t.Options.Offset.String()); err != nil {
 			return fmt.Errorf("offset: %s, %s is invalid, the largest unit supported is h", t.Options.Offset.String(), err)
 		}
-	case t.Flux == nil && t.Status == nil && t.Options.IsZero():
+	case t.Flux == nil && t.Status == nil && t.Tags == nil && t.CatchUp == nil && t.Options.IsZero():
 		return errors.New("cannot update task without content")
 	case t.Status != nil && *t.Status != TaskStatusActive && *t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", *t.Status)
@@ -281,6 +410,24 @@ func (t *TaskUpdate) Validate() error {
 	return nil
 }
 
+// validateTags ensures every tag has a key.
+func validateTags(tags map[string]string) error {
+	for k := range tags {
+		if k == "" {
+			return errors.New("tag key cannot be empty")
+		}
+	}
+	return nil
+}
+
+func validateCatchUp(policy string) error {
+	switch policy {
+	case "", TaskCatchUpAll, TaskCatchUpOnce, TaskCatchUpSkip:
+		return nil
+	}
+	return fmt.Errorf("invalid catch-up policy: %q", policy)
+}
+
 // safeParseSource calls the Flux parser.ParseSource function
 // and is guaranteed not to panic.
 func safeParseSource(parser fluxlang.FluxLanguageService, f string) (pkg *ast.Package, err error) {
@@ -427,6 +574,9 @@ type TaskFilter struct {
 	User           *platform.ID
 	Limit          int
 	Status         *string
+
+	// Tags restricts the results to tasks having all of the given tags.
+	Tags map[string]string
 }
 
 // QueryParams Converts TaskFilter fields to url query params.
@@ -452,6 +602,10 @@ func (f TaskFilter) QueryParams() map[string][]string {
 		qp["limit"] = []string{strconv.Itoa(f.Limit)}
 	}
 
+	for k, v := range f.Tags {
+		qp["tag"] = append(qp["tag"], k+"="+v)
+	}
+
 	return qp
 }
 
@@ -3,6 +3,7 @@ package taskmodel
 import (
 	"fmt"
 
+	"github.com/influxdata/influxdb/v2/kit/platform"
 	"github.com/influxdata/influxdb/v2/kit/platform/errors"
 )
 
@@ -74,11 +75,47 @@ var (
 		Code: errors.ENotFound,
 	}
 
+	// ErrTaskConflict is returned when a task update is based on a stale version of the task.
+	ErrTaskConflict = &errors.Error{
+		Code: errors.EConflict,
+		Msg:  "task has been modified since it was last read",
+	}
+
+	// ErrTaskRevisionNotFound is returned when searching for a task revision that doesn't exist.
+	ErrTaskRevisionNotFound = &errors.Error{
+		Code: errors.ENotFound,
+		Msg:  "task revision not found",
+	}
+
+	// ErrTaskRevisionsNotSupported is returned when the task service does not keep the script history of tasks.
+	ErrTaskRevisionsNotSupported = &errors.Error{
+		Code: errors.ENotImplemented,
+		Msg:  "task service does not support task revisions",
+	}
+
+	// ErrDeletedTaskNotFound is returned when restoring a task that is not in the trash.
+	ErrDeletedTaskNotFound = &errors.Error{
+		Code: errors.ENotFound,
+		Msg:  "deleted task not found",
+	}
+
+	// ErrTaskTrashNotSupported is returned when the task service does not keep deleted tasks.
+	ErrTaskTrashNotSupported = &errors.Error{
+		Code: errors.ENotImplemented,
+		Msg:  "task service does not support restoring deleted tasks",
+	}
+
 	ErrTaskRunAlreadyQueued = &errors.Error{
 		Msg:  "run already queued",
 		Code: errors.EConflict,
 	}
 
+	// ErrTaskRunAlreadyCompleted is returned when creating a run for a time at or before the latest completed run of the task.
+	ErrTaskRunAlreadyCompleted = &errors.Error{
+		Msg:  "run already completed",
+		Code: errors.EConflict,
+	}
+
 	// ErrOutOfBoundsLimit is returned with FindRuns is called with an invalid filter limit.
 	ErrOutOfBoundsLimit = &errors.Error{
 		Code: errors.EUnprocessableEntity,
@@ -92,6 +129,14 @@ var (
 	}
 )
 
+// ErrOrgQuotaExceeded is returned when an action would exceed one of the task quotas of an organization.
+func ErrOrgQuotaExceeded(orgID platform.ID, quota string, limit int) *errors.Error {
+	return &errors.Error{
+		Code: errors.ETooManyRequests,
+		Msg:  fmt.Sprintf("organization %s exceeded its quota of %d %s", orgID, limit, quota),
+	}
+}
+
 // ErrFluxParseError is returned when an error is thrown by Flux.Parse in the task executor
 func ErrFluxParseError(err error) *errors.Error {
 	return &errors.Error{
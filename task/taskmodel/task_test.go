@@ -53,6 +53,24 @@ func TestOptionsMarshal(t *testing.T) {
 	}
 }
 
+func TestUpdateMarshalClearTags(t *testing.T) {
+	b, err := json.Marshal(&taskmodel.TaskUpdate{Tags: &map[string]string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tu := &taskmodel.TaskUpdate{}
+	if err := json.Unmarshal(b, tu); err != nil {
+		t.Fatal(err)
+	}
+	if tu.Tags == nil || len(*tu.Tags) != 0 {
+		t.Fatalf("expected an empty tags update to clear the tags, got %s", b)
+	}
+	if err := tu.Validate(); err != nil {
+		t.Fatalf("expected task update to be valid but it was not: %s", err)
+	}
+}
+
 func TestOptionsEditWithAST(t *testing.T) {
 	tu := &taskmodel.TaskUpdate{}
 	tu.Options.Every = *(options.MustParseDuration("10s"))
@@ -20,6 +20,12 @@ var (
 		Msg:  "user to resource mapping not found",
 		Code: errors.ENotFound,
 	}
+
+	// ErrLastOwner is used when the last owner of a resource would be made a member.
+	ErrLastOwner = &errors.Error{
+		Msg:  "the last owner of a resource cannot be made a member",
+		Code: errors.EConflict,
+	}
 )
 
 // UnavailableURMServiceError is used if we aren't able to interact with the
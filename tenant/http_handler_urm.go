@@ -124,6 +124,18 @@ func (h *urmHandler) postURMByType(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The role of a user that is already a member or an owner is changed,
+	// and the previous role is returned.
+	existing, _, err := h.svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceID:   req.ResourceID,
+		ResourceType: h.rt,
+		UserID:       req.UserID,
+	})
+	if err != nil {
+		h.api.Err(w, r, err)
+		return
+	}
+
 	mapping := &influxdb.UserResourceMapping{
 		ResourceID:   req.ResourceID,
 		ResourceType: h.rt,
@@ -136,7 +148,11 @@ func (h *urmHandler) postURMByType(w http.ResponseWriter, r *http.Request) {
 	}
 	h.log.Debug("Member/owner created", zap.String("mapping", fmt.Sprint(mapping)))
 
-	h.api.Respond(w, r, http.StatusCreated, newResourceUserResponse(user, userType))
+	res := newResourceUserResponse(user, userType)
+	if len(existing) > 0 {
+		res.PreviousRole = existing[0].UserType
+	}
+	h.api.Respond(w, r, http.StatusCreated, res)
 }
 
 type postRequest struct {
@@ -232,7 +248,8 @@ func (h *urmHandler) decodeDeleteRequest(ctx context.Context, r *http.Request) (
 }
 
 type resourceUserResponse struct {
-	Role influxdb.UserType `json:"role"`
+	Role         influxdb.UserType `json:"role"`
+	PreviousRole influxdb.UserType `json:"previousRole,omitempty"`
 	*influxdb.UserResponse
 }
 
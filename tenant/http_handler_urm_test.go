@@ -249,6 +249,9 @@ func TestUserResourceMappingService_PostMembersHandler(t *testing.T) {
 					},
 				},
 				userResourceMappingService: &mock.UserResourceMappingService{
+					FindMappingsFn: func(ctx context.Context, f influxdb.UserResourceMappingFilter) ([]*influxdb.UserResourceMapping, int, error) {
+						return nil, 0, nil
+					},
 					CreateMappingFn: func(ctx context.Context, m *influxdb.UserResourceMapping) error {
 						return nil
 					},
@@ -287,6 +290,55 @@ func TestUserResourceMappingService_PostMembersHandler(t *testing.T) {
 					},
 				},
 				userResourceMappingService: &mock.UserResourceMappingService{
+					FindMappingsFn: func(ctx context.Context, f influxdb.UserResourceMappingFilter) ([]*influxdb.UserResourceMapping, int, error) {
+						return nil, 0, nil
+					},
+					CreateMappingFn: func(ctx context.Context, m *influxdb.UserResourceMapping) error {
+						return nil
+					},
+				},
+			},
+			args: args{
+				resourceID: "0000000000000099",
+				user: influxdb.User{
+					ID:     2,
+					Name:   "user0000000000000002",
+					Status: influxdb.Active,
+				},
+				userType: influxdb.Owner,
+			},
+			wants: wants{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json; charset=utf-8",
+				body: `{
+	"role": "owner",
+	"links": {
+		"self": "/api/v2/users/0000000000000002"
+	},
+	"id": "0000000000000002",
+	"name": "user0000000000000002",
+	"status": "active"
+}`,
+			},
+		},
+
+		{
+			name: "post owners changing the role of a member",
+			fields: fields{
+				userService: &mock.UserService{
+					FindUserByIDFn: func(ctx context.Context, id platform.ID) (*influxdb.User, error) {
+						return &influxdb.User{ID: id, Name: fmt.Sprintf("user%s", id), Status: influxdb.Active}, nil
+					},
+				},
+				userResourceMappingService: &mock.UserResourceMappingService{
+					FindMappingsFn: func(ctx context.Context, f influxdb.UserResourceMappingFilter) ([]*influxdb.UserResourceMapping, int, error) {
+						return []*influxdb.UserResourceMapping{{
+							ResourceID:   f.ResourceID,
+							ResourceType: f.ResourceType,
+							UserID:       f.UserID,
+							UserType:     influxdb.Member,
+						}}, 1, nil
+					},
 					CreateMappingFn: func(ctx context.Context, m *influxdb.UserResourceMapping) error {
 						return nil
 					},
@@ -306,6 +358,7 @@ func TestUserResourceMappingService_PostMembersHandler(t *testing.T) {
 				contentType: "application/json; charset=utf-8",
 				body: `{
 	"role": "owner",
+	"previousRole": "member",
 	"links": {
 		"self": "/api/v2/users/0000000000000002"
 	},
@@ -38,8 +38,30 @@ func (s *URMSvc) FindUserResourceMappings(ctx context.Context, filter influxdb.U
 }
 
 // CreateUserResourceMapping creates a user resource mapping.
+// A user is either a member or an owner of a resource: when the user already
+// has the other role on the resource, the mapping is replaced to change it.
+// The last owner of a resource cannot be made a member.
 func (s *URMSvc) CreateUserResourceMapping(ctx context.Context, m *influxdb.UserResourceMapping) error {
 	err := s.store.Update(ctx, func(tx kv.Tx) error {
+		existing, err := s.store.GetURM(ctx, tx, m.ResourceID, m.UserID)
+		if err == nil && existing.ResourceType == m.ResourceType && existing.UserType != m.UserType {
+			if existing.UserType == influxdb.Owner {
+				owners, err := s.store.ListURMs(ctx, tx, influxdb.UserResourceMappingFilter{
+					ResourceID:   m.ResourceID,
+					ResourceType: m.ResourceType,
+					UserType:     influxdb.Owner,
+				}, influxdb.FindOptions{Limit: 2})
+				if err != nil {
+					return err
+				}
+				if len(owners) < 2 {
+					return ErrLastOwner
+				}
+			}
+			if err := s.store.DeleteURM(ctx, tx, m.ResourceID, m.UserID); err != nil {
+				return err
+			}
+		}
 		return s.store.CreateURM(ctx, tx, m)
 	})
 	return err
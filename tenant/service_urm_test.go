@@ -6,6 +6,7 @@ import (
 
 	"github.com/influxdata/influxdb/v2"
 	"github.com/influxdata/influxdb/v2/kit/platform"
+	"github.com/influxdata/influxdb/v2/kit/platform/errors"
 	"github.com/influxdata/influxdb/v2/kv"
 	"github.com/influxdata/influxdb/v2/mock"
 	"github.com/influxdata/influxdb/v2/tenant"
@@ -94,3 +95,69 @@ func initUserResourceMappingService(s kv.Store, f influxdbtesting.UserResourceFi
 		}
 	}
 }
+
+func TestUserResourceMappingService_ChangeRole(t *testing.T) {
+	s, closeBolt := influxdbtesting.NewTestBoltStore(t)
+	defer closeBolt()
+
+	var (
+		ctx     = context.Background()
+		storage = tenant.NewStore(s)
+		svc     = tenant.NewService(storage)
+	)
+
+	u := &influxdb.User{Name: "user"}
+	if err := svc.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &influxdb.UserResourceMapping{
+		ResourceID:   1,
+		ResourceType: influxdb.OrgsResourceType,
+		UserID:       u.ID,
+		UserType:     influxdb.Member,
+	}
+	if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	// The same role cannot be granted twice.
+	if err := svc.CreateUserResourceMapping(ctx, m); err == nil {
+		t.Fatal("expected an error creating a duplicate mapping")
+	}
+
+	// Granting the other role changes the role of the user.
+	owner := *m
+	owner.UserType = influxdb.Owner
+	if err := svc.CreateUserResourceMapping(ctx, &owner); err != nil {
+		t.Fatal(err)
+	}
+
+	ms, _, err := svc.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{UserID: u.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 1 || ms[0].UserType != influxdb.Owner {
+		t.Fatalf("unexpected mappings: %+v", ms)
+	}
+
+	// The last owner cannot be made a member.
+	if err := svc.CreateUserResourceMapping(ctx, m); errors.ErrorCode(err) != errors.EConflict {
+		t.Fatalf("unexpected error demoting the last owner: %v", err)
+	}
+
+	other := &influxdb.User{Name: "other"}
+	if err := svc.CreateUser(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+		ResourceID:   1,
+		ResourceType: influxdb.OrgsResourceType,
+		UserID:       other.ID,
+		UserType:     influxdb.Owner,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+		t.Fatalf("unexpected error demoting an owner: %v", err)
+	}
+}
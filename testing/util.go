@@ -19,7 +19,7 @@ import (
 	"go.uber.org/zap/zaptest"
 )
 
-func NewTestBoltStore(t *testing.T) (kv.SchemaStore, func()) {
+func NewTestBoltStore(t testing.TB) (kv.SchemaStore, func()) {
 	f, err := os.CreateTemp("", "influxdata-bolt-")
 	require.NoError(t, err, "unable to create temporary boltdb file")
 	require.NoError(t, f.Close())
@@ -39,7 +39,7 @@ func NewTestBoltStore(t *testing.T) (kv.SchemaStore, func()) {
 	return s, close
 }
 
-func NewTestInmemStore(t *testing.T) kv.SchemaStore {
+func NewTestInmemStore(t testing.TB) kv.SchemaStore {
 	s := inmem.NewKVStore()
 	// apply all kv migrations
 	require.NoError(t, all.Up(context.Background(), zaptest.NewLogger(t), s))
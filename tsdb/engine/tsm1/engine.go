@@ -2670,13 +2670,24 @@ func (e *Engine) createTagSetIterators(ctx context.Context, ref *influxql.VarRef
 func (e *Engine) createTagSetGroupIterators(ctx context.Context, ref *influxql.VarRef, name string, seriesKeys []string, t *query.TagSet, filters []influxql.Expr, opt query.IteratorOptions) ([]query.Iterator, error) {
 	itrs := make([]query.Iterator, 0, len(seriesKeys))
 	for i, seriesKey := range seriesKeys {
+		filter := filters[i]
+		if filter != nil {
+			// Evaluate the tag comparisons of the filter once for the series
+			// instead of for every point, and skip series that cannot match.
+			_, tfs := models.ParseKey([]byte(seriesKey))
+			var match bool
+			if filter, match = query.ReduceSeriesFilter(filter, tfs.Map()); !match {
+				continue
+			}
+		}
+
 		var conditionFields []influxql.VarRef
-		if filters[i] != nil {
+		if filter != nil {
 			// Retrieve non-time fields from this series filter and filter out tags.
-			conditionFields = influxql.ExprNames(filters[i])
+			conditionFields = influxql.ExprNames(filter)
 		}
 
-		itr, err := e.createVarRefSeriesIterator(ctx, ref, name, seriesKey, t, filters[i], conditionFields, opt)
+		itr, err := e.createVarRefSeriesIterator(ctx, ref, name, seriesKey, t, filter, conditionFields, opt)
 		if err != nil {
 			return itrs, err
 		} else if itr == nil {
@@ -1635,34 +1635,132 @@ func (a Shards) CreateIterator(ctx context.Context, measurement *influxql.Measur
 		return a.createSeriesIterator(ctx, opt)
 	}
 
-	itrs := make([]query.Iterator, 0, len(a))
-	for _, sh := range a {
-		itr, err := sh.CreateIterator(ctx, measurement, opt)
-		if err != nil {
-			query.Iterators(itrs).Close()
-			return nil, err
-		} else if itr == nil {
-			continue
-		}
-		itrs = append(itrs, itr)
+	itrs, err := a.createShardIterators(ctx, measurement, opt)
+	if err != nil {
+		return nil, err
+	}
 
-		select {
-		case <-opt.InterruptCh:
-			query.Iterators(itrs).Close()
-			return nil, query.ErrQueryInterrupted
-		default:
+	// Read each shard in its own goroutine so reading the next points
+	// overlaps with merging and processing the current ones.
+	if opt.ReadAhead && len(itrs) > 1 {
+		for i, itr := range itrs {
+			itrs[i] = query.NewReadAheadIterator(itr)
 		}
+	}
+	return query.Iterators(itrs).Merge(opt)
+}
 
-		// Enforce series limit at creation time.
-		if opt.MaxSeriesN > 0 {
-			stats := itr.Stats()
-			if stats.SeriesN > opt.MaxSeriesN {
+// createShardIterators creates an iterator for every shard, returned in the
+// order of the shards so the merged output does not depend on which shard
+// finished first. Up to opt.ShardConcurrency shards are processed at once.
+func (a Shards) createShardIterators(ctx context.Context, measurement *influxql.Measurement, opt query.IteratorOptions) ([]query.Iterator, error) {
+	if opt.ShardConcurrency <= 1 || len(a) <= 1 {
+		itrs := make([]query.Iterator, 0, len(a))
+		for _, sh := range a {
+			itr, err := createShardIterator(ctx, sh, measurement, opt)
+			if err != nil {
 				query.Iterators(itrs).Close()
-				return nil, fmt.Errorf("max-select-series limit exceeded: (%d/%d)", stats.SeriesN, opt.MaxSeriesN)
+				return nil, err
+			} else if itr == nil {
+				continue
+			}
+			itrs = append(itrs, itr)
+
+			select {
+			case <-opt.InterruptCh:
+				query.Iterators(itrs).Close()
+				return nil, query.ErrQueryInterrupted
+			default:
 			}
 		}
+		return itrs, nil
 	}
-	return query.Iterators(itrs).Merge(opt)
+
+	var (
+		itrs   = make([]query.Iterator, len(a))
+		mu     sync.Mutex
+		itrErr error
+		wg     sync.WaitGroup
+	)
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if itrErr == nil {
+			itrErr = err
+		}
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return itrErr != nil
+	}
+
+	limit := limiter.NewFixed(opt.ShardConcurrency)
+	for i, sh := range a {
+		if err := limit.Take(ctx); err != nil {
+			setErr(err)
+			break
+		}
+		if failed() {
+			limit.Release()
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, sh *Shard) {
+			defer limit.Release()
+			defer wg.Done()
+
+			select {
+			case <-opt.InterruptCh:
+				setErr(query.ErrQueryInterrupted)
+				return
+			default:
+			}
+
+			itr, err := createShardIterator(ctx, sh, measurement, opt)
+			if err != nil {
+				setErr(err)
+				return
+			}
+			itrs[i] = itr
+		}(i, sh)
+	}
+	wg.Wait()
+
+	// Drop the shards without data while keeping the order of the others.
+	n := 0
+	for _, itr := range itrs {
+		if itr != nil {
+			itrs[n] = itr
+			n++
+		}
+	}
+	itrs = itrs[:n]
+
+	if itrErr != nil {
+		query.Iterators(itrs).Close()
+		return nil, itrErr
+	}
+	return itrs, nil
+}
+
+// createShardIterator creates the iterator of a single shard and enforces the series limit.
+func createShardIterator(ctx context.Context, sh *Shard, measurement *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+	itr, err := sh.CreateIterator(ctx, measurement, opt)
+	if err != nil || itr == nil {
+		return nil, err
+	}
+
+	// Enforce series limit at creation time.
+	if opt.MaxSeriesN > 0 {
+		stats := itr.Stats()
+		if stats.SeriesN > opt.MaxSeriesN {
+			itr.Close()
+			return nil, fmt.Errorf("max-select-series limit exceeded: (%d/%d)", stats.SeriesN, opt.MaxSeriesN)
+		}
+	}
+	return itr, nil
 }
 
 func (a Shards) createSeriesIterator(ctx context.Context, opt query.IteratorOptions) (_ query.Iterator, err error) {
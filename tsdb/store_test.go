@@ -642,6 +642,65 @@ func TestShards_CreateIterator(t *testing.T) {
 	}
 }
 
+// Ensure creating the shard iterators concurrently produces the same points in the same order.
+func TestShards_CreateIterator_ShardConcurrency(t *testing.T) {
+
+	test := func(t *testing.T, index string) {
+		s := MustOpenStore(t, index)
+		defer s.Close()
+
+		ids := []uint64{0, 1, 2, 3}
+		for _, id := range ids {
+			s.MustCreateShardWithData("db0", "rp0", int(id),
+				fmt.Sprintf(`cpu,host=serverA value=%d %d`, id, id*10),
+				fmt.Sprintf(`cpu,host=serverB value=%d %d`, id+10, id*10+5),
+			)
+		}
+		shards := s.ShardGroup(ids)
+
+		readAll := func(concurrency int) []*query.FloatPoint {
+			itr, err := shards.CreateIterator(context.Background(), &influxql.Measurement{Name: "cpu"}, query.IteratorOptions{
+				Expr:             influxql.MustParseExpr(`value`),
+				Dimensions:       []string{"host"},
+				Ascending:        true,
+				StartTime:        influxql.MinTime,
+				EndTime:          influxql.MaxTime,
+				ShardConcurrency: concurrency,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer itr.Close()
+
+			var points []*query.FloatPoint
+			fitr := itr.(query.FloatIterator)
+			for {
+				p, err := fitr.Next()
+				if err != nil {
+					t.Fatal(err)
+				} else if p == nil {
+					return points
+				}
+				points = append(points, p)
+			}
+		}
+
+		exp := readAll(0)
+		if len(exp) != 8 {
+			t.Fatalf("unexpected number of points: %d", len(exp))
+		}
+		for i := 0; i < 5; i++ {
+			if got := readAll(3); !deep.Equal(got, exp) {
+				t.Fatalf("unexpected points:\n%s\nexp:\n%s", spew.Sdump(got), spew.Sdump(exp))
+			}
+		}
+	}
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) { test(t, index) })
+	}
+}
+
 // Ensure the store can backup a shard and another store can restore it.
 func TestStore_BackupRestoreShard(t *testing.T) {
 	test := func(t *testing.T, index string) {
@@ -22,11 +22,13 @@ const (
 
 // Config represents the configuration for the coordinator service.
 type Config struct {
-	MaxConcurrentQueries int           `toml:"max-concurrent-queries"`
-	LogQueriesAfter      toml.Duration `toml:"log-queries-after"`
-	MaxSelectPointN      int           `toml:"max-select-point"`
-	MaxSelectSeriesN     int           `toml:"max-select-series"`
-	MaxSelectBucketsN    int           `toml:"max-select-buckets"`
+	MaxConcurrentQueries   int           `toml:"max-concurrent-queries"`
+	LogQueriesAfter        toml.Duration `toml:"log-queries-after"`
+	MaxSelectPointN        int           `toml:"max-select-point"`
+	MaxSelectSeriesN       int           `toml:"max-select-series"`
+	MaxSelectBucketsN      int           `toml:"max-select-buckets"`
+	SelectReadAhead        bool          `toml:"select-read-ahead"`
+	SelectShardConcurrency int           `toml:"select-shard-concurrency"`
 }
 
 // NewConfig returns an instance of Config with defaults.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/influxdata/influx-cli/v2/api"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
@@ -141,3 +142,79 @@ func TestLocalShardMapper(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalShardMapping_RegexMeasurement(t *testing.T) {
+	cond := influxql.MustParseExpr(`host =~ /web-\d+/`)
+
+	var created []string
+	sh := &MockShard{
+		Measurements: []string{"cpu", "cpu_idle", "mem"},
+		FieldDimensionsFn: func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+			if !reflect.DeepEqual(measurements, []string{"cpu", "cpu_idle"}) {
+				t.Errorf("unexpected measurements: %v", measurements)
+			}
+			return map[string]influxql.DataType{"value": influxql.Float}, map[string]struct{}{"host": {}}, nil
+		},
+		CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+			// The tag filter is passed down to every matching measurement.
+			if got, want := opt.Condition.String(), cond.String(); got != want {
+				t.Errorf("unexpected condition: got %s, want %s", got, want)
+			}
+			created = append(created, m.Name)
+			return &FloatIterator{Points: []query.FloatPoint{{Name: m.Name, Value: 1}}}, nil
+		},
+		IteratorCostFn: func(ctx context.Context, m string, opt query.IteratorOptions) (query.IteratorCost, error) {
+			return query.IteratorCost{NumShards: 1, NumSeries: 2}, nil
+		},
+	}
+
+	m := &coordinator.LocalShardMapping{
+		ShardMap: map[coordinator.Source]tsdb.ShardGroup{
+			{Database: "db0", RetentionPolicy: "rp0"}: sh,
+		},
+	}
+	mm := &influxql.Measurement{
+		Database:        "db0",
+		RetentionPolicy: "rp0",
+		Regex:           &influxql.RegexLiteral{Val: regexp.MustCompile(`^cpu`)},
+	}
+
+	fields, dimensions, err := m.FieldDimensions(context.Background(), mm)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if fields["value"] != influxql.Float {
+		t.Errorf("unexpected fields: %v", fields)
+	} else if _, ok := dimensions["host"]; !ok {
+		t.Errorf("unexpected dimensions: %v", dimensions)
+	}
+
+	opt := query.IteratorOptions{Condition: cond, Ascending: true, Ordered: true}
+	itr, err := m.CreateIterator(context.Background(), mm, opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer itr.Close()
+
+	var names []string
+	for fitr := itr.(query.FloatIterator); ; {
+		p, err := fitr.Next()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if p == nil {
+			break
+		}
+		names = append(names, p.Name)
+	}
+	if want := []string{"cpu", "cpu_idle"}; !reflect.DeepEqual(created, want) {
+		t.Errorf("unexpected iterators: got %v, want %v", created, want)
+	} else if !reflect.DeepEqual(names, want) {
+		t.Errorf("unexpected points: got %v, want %v", names, want)
+	}
+
+	cost, err := m.IteratorCost(context.Background(), mm, opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if cost.NumShards != 2 || cost.NumSeries != 4 {
+		t.Errorf("unexpected cost: %+v", cost)
+	}
+}
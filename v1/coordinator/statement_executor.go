@@ -41,6 +41,12 @@ type StatementExecutor struct {
 	MaxSelectPointN   int
 	MaxSelectSeriesN  int
 	MaxSelectBucketsN int
+
+	// Read ahead on each shard iterator of a select statement.
+	SelectReadAhead bool
+
+	// Maximum number of shards of a select statement to create iterators for at once.
+	SelectShardConcurrency int
 }
 
 // ExecuteStatement executes the given statement with the given execution context.
@@ -109,11 +115,11 @@ func (e *StatementExecutor) ExecuteStatement(ctx context.Context, stmt influxql.
 	case *influxql.ShowMeasurementsStatement:
 		return e.executeShowMeasurementsStatement(ctx, stmt, ectx)
 	case *influxql.ShowMeasurementCardinalityStatement:
-		rows, err = nil, iql.ErrNotImplemented("SHOW MEASUREMENT CARDINALITY")
+		return e.executeShowMeasurementCardinalityStatement(ctx, stmt, ectx)
 	case *influxql.ShowRetentionPoliciesStatement:
 		rows, err = e.executeShowRetentionPoliciesStatement(ctx, stmt, ectx)
 	case *influxql.ShowSeriesCardinalityStatement:
-		rows, err = nil, iql.ErrNotImplemented("SHOW SERIES CARDINALITY")
+		return e.executeShowSeriesCardinalityStatement(ctx, stmt, ectx)
 	case *influxql.ShowShardsStatement:
 		rows, err = nil, iql.ErrNotImplemented("SHOW SHARDS")
 	case *influxql.ShowShardGroupsStatement:
@@ -147,11 +153,13 @@ func (e *StatementExecutor) ExecuteStatement(ctx context.Context, stmt influxql.
 }
 
 func (e *StatementExecutor) executeExplainStatement(ctx context.Context, q *influxql.ExplainStatement, ectx *query.ExecutionContext) (models.Rows, error) {
+	var trace query.PlanTrace
 	opt := query.SelectOptions{
 		OrgID:       ectx.OrgID,
 		NodeID:      ectx.ExecutionOptions.NodeID,
 		MaxSeriesN:  e.MaxSelectSeriesN,
 		MaxBucketsN: e.MaxSelectBucketsN,
+		Trace:       &trace,
 	}
 
 	// Prepare the query for execution, but do not actually execute it.
@@ -168,6 +176,11 @@ func (e *StatementExecutor) executeExplainStatement(ctx context.Context, q *infl
 	}
 	plan = strings.TrimSpace(plan)
 
+	// Show the decisions made while planning the statement after the plan.
+	if events := trace.Events(); len(events) > 0 {
+		plan += "\n\nPLANNING DECISIONS:\n" + strings.Join(events, "\n")
+	}
+
 	row := &models.Row{
 		Columns: []string{"QUERY PLAN"},
 	}
@@ -248,7 +261,7 @@ func (e *StatementExecutor) executeSelectStatement(ctx context.Context, stmt *in
 	}
 
 	// Generate a row emitter from the iterator set.
-	em := query.NewEmitter(cur, ectx.ChunkSize)
+	em := query.NewAdaptiveEmitter(cur, ectx.InitialChunkSize, ectx.ChunkSize)
 	defer em.Close()
 
 	// Emit rows to the results channel.
@@ -301,12 +314,20 @@ func (e *StatementExecutor) createIterators(ctx context.Context, stmt *influxql.
 	}(time.Now())
 
 	sopt := query.SelectOptions{
-		OrgID:              opt.OrgID,
-		NodeID:             opt.NodeID,
-		MaxSeriesN:         e.MaxSelectSeriesN,
-		MaxPointN:          e.MaxSelectPointN,
-		MaxBucketsN:        e.MaxSelectBucketsN,
-		StatisticsGatherer: gatherer,
+		OrgID:               opt.OrgID,
+		NodeID:              opt.NodeID,
+		MaxSeriesN:          e.MaxSelectSeriesN,
+		MaxPointN:           e.MaxSelectPointN,
+		MaxBucketsN:         e.MaxSelectBucketsN,
+		DefaultTimeRange:    opt.DefaultTimeRange,
+		ReadAhead:           e.SelectReadAhead,
+		MaxShardConcurrency: e.SelectShardConcurrency,
+		StatisticsGatherer:  gatherer,
+	}
+
+	// A request can only lower the bucket limit of the server.
+	if opt.MaxBucketsN > 0 && (sopt.MaxBucketsN <= 0 || opt.MaxBucketsN < sopt.MaxBucketsN) {
+		sopt.MaxBucketsN = opt.MaxBucketsN
 	}
 
 	// Create a set of iterators from a selection.
@@ -403,6 +424,52 @@ func (e *StatementExecutor) executeDropMeasurementStatement(ctx context.Context,
 	return e.TSDBStore.DeleteMeasurement(ctx, mapping.BucketID.String(), q.Name)
 }
 
+// executeShowMeasurementCardinalityStatement estimates the number of measurements
+// of a database. The statements asking for an exact count are rewritten into a SELECT.
+func (e *StatementExecutor) executeShowMeasurementCardinalityStatement(ctx context.Context, q *influxql.ShowMeasurementCardinalityStatement, ectx *query.ExecutionContext) error {
+	if q.Database == "" {
+		return ErrDatabaseNameRequired
+	}
+	return e.sendCardinalityEstimation(ctx, q.Database, e.TSDBStore.MeasurementsCardinality, ectx)
+}
+
+// executeShowSeriesCardinalityStatement estimates the number of series
+// of a database. The statements asking for an exact count are rewritten into a SELECT.
+func (e *StatementExecutor) executeShowSeriesCardinalityStatement(ctx context.Context, q *influxql.ShowSeriesCardinalityStatement, ectx *query.ExecutionContext) error {
+	if q.Database == "" {
+		return ErrDatabaseNameRequired
+	}
+	return e.sendCardinalityEstimation(ctx, q.Database, e.TSDBStore.SeriesCardinality, ectx)
+}
+
+func (e *StatementExecutor) sendCardinalityEstimation(ctx context.Context, database string, estimate func(ctx context.Context, database string) (int64, error), ectx *query.ExecutionContext) error {
+	mapping, err := e.getDefaultRP(ctx, database, ectx)
+	if err != nil {
+		return err
+	}
+
+	// Require read for the estimations, as for the exact counts
+	if _, _, err := authorizer.AuthorizeRead(ctx, influxdb.BucketsResourceType, mapping.BucketID, ectx.OrgID); err != nil {
+		return ectx.Send(ctx, &query.Result{
+			Err: fmt.Errorf("insufficient permissions"),
+		})
+	}
+
+	n, err := estimate(ctx, mapping.BucketID.String())
+	if err != nil {
+		return ectx.Send(ctx, &query.Result{
+			Err: err,
+		})
+	}
+
+	return ectx.Send(ctx, &query.Result{
+		Series: []*models.Row{{
+			Columns: []string{"cardinality estimation"},
+			Values:  [][]interface{}{{n}},
+		}},
+	})
+}
+
 type measurementRow struct {
 	name   []byte
 	db, rp string
@@ -848,6 +915,8 @@ func (m mappings) DefaultRetentionPolicy(db string) string {
 type TSDBStore interface {
 	DeleteMeasurement(ctx context.Context, database, name string) error
 	DeleteSeries(ctx context.Context, database string, sources []influxql.Source, condition influxql.Expr) error
+	MeasurementsCardinality(ctx context.Context, database string) (int64, error)
+	SeriesCardinality(ctx context.Context, database string) (int64, error)
 	MeasurementNames(ctx context.Context, auth query.Authorizer, database string, cond influxql.Expr) ([][]byte, error)
 	TagKeys(ctx context.Context, auth query.Authorizer, shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagKeys, error)
 	TagValues(ctx context.Context, auth query.Authorizer, shardIDs []uint64, cond influxql.Expr) ([]tsdb.TagValues, error)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -103,6 +104,63 @@ func TestQueryExecutor_ExecuteQuery_SelectStatement(t *testing.T) {
 	}
 }
 
+// Ensure EXPLAIN shows the decisions made while planning the statement.
+func TestQueryExecutor_ExecuteQuery_ExplainStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbrp := mocks.NewMockDBRPMappingService(ctrl)
+	orgID := platform.ID(0xff00)
+	empty := ""
+	filt := influxdb.DBRPMappingFilter{OrgID: &orgID, Database: &empty, RetentionPolicy: &empty, Virtual: nil}
+	res := []*influxdb.DBRPMapping{{}}
+	dbrp.EXPECT().
+		FindMany(gomock.Any(), filt).
+		Return(res, 1, nil)
+
+	e := DefaultQueryExecutor(t, WithDBRP(dbrp))
+
+	e.MetaClient.ShardGroupsByTimeRangeFn = func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
+		return []meta.ShardGroupInfo{
+			{ID: 1, Shards: []meta.ShardInfo{
+				{ID: 100, Owners: []meta.ShardOwner{{NodeID: 0}}},
+			}},
+		}, nil
+	}
+
+	e.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		var sh MockShard
+		sh.IteratorCostFn = func(_ context.Context, _ string, _ query.IteratorOptions) (query.IteratorCost, error) {
+			return query.IteratorCost{NumShards: 1, NumSeries: 2}, nil
+		}
+		sh.FieldDimensionsFn = func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+			return map[string]influxql.DataType{"value": influxql.Float}, nil, nil
+		}
+		return &sh
+	}
+
+	results := ReadAllResults(e.ExecuteQuery(context.Background(), `EXPLAIN SELECT max(value) FROM cpu WHERE time >= '2000-01-01T00:00:00Z' AND time < '2000-01-01T00:01:00Z'`, "db0", 0, orgID))
+	if len(results) != 1 || results[0].Err != nil || len(results[0].Series) != 1 {
+		t.Fatalf("unexpected results: %s", spew.Sdump(results))
+	}
+
+	var lines []string
+	for _, v := range results[0].Series[0].Values {
+		lines = append(lines, v[0].(string))
+	}
+	plan := strings.Join(lines, "\n")
+	for _, exp := range []string{
+		"NUMBER OF SERIES: 2",
+		"PLANNING DECISIONS:",
+		"map shards: sources=cpu time range=[2000-01-01T00:00:00Z, 2000-01-01T00:00:59.999999999Z]",
+		"rewritten fields: max(value::float)",
+	} {
+		if !strings.Contains(plan, exp) {
+			t.Fatalf("expected %q in the plan:\n%s", exp, plan)
+		}
+	}
+}
+
 // Ensure query executor can enforce a maximum bucket selection count.
 func TestQueryExecutor_ExecuteQuery_MaxSelectBucketsN(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -161,6 +219,69 @@ func TestQueryExecutor_ExecuteQuery_MaxSelectBucketsN(t *testing.T) {
 	}
 }
 
+// Ensure a query request can lower the bucket limit and set a default time range.
+func TestQueryExecutor_ExecuteQuery_RequestLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dbrp := mocks.NewMockDBRPMappingService(ctrl)
+	orgID := platform.ID(0xff00)
+	empty := ""
+	filt := influxdb.DBRPMappingFilter{OrgID: &orgID, Database: &empty, RetentionPolicy: &empty, Virtual: nil}
+	res := []*influxdb.DBRPMapping{{}}
+	dbrp.EXPECT().
+		FindMany(gomock.Any(), filt).
+		Return(res, 1, nil).
+		Times(2)
+
+	e := DefaultQueryExecutor(t, WithDBRP(dbrp))
+	e.StatementExecutor.MaxSelectBucketsN = 10
+
+	var minTime time.Time
+	e.MetaClient.ShardGroupsByTimeRangeFn = func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
+		minTime = min
+		return []meta.ShardGroupInfo{
+			{ID: 1, Shards: []meta.ShardInfo{
+				{ID: 100, Owners: []meta.ShardOwner{{NodeID: 0}}},
+			}},
+		}, nil
+	}
+
+	e.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		var sh MockShard
+		sh.CreateIteratorFn = func(_ context.Context, _ *influxql.Measurement, _ query.IteratorOptions) (query.Iterator, error) {
+			return &FloatIterator{}, nil
+		}
+		sh.FieldDimensionsFn = func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+			return map[string]influxql.DataType{"value": influxql.Float}, nil, nil
+		}
+		return &sh
+	}
+
+	// The request lowers the bucket limit of the server.
+	if a := ReadAllResults(e.Executor.ExecuteQuery(context.Background(), MustParseQuery(`SELECT count(value) FROM cpu WHERE time >= '2000-01-01T00:00:05Z' AND time < '2000-01-01T00:00:35Z' GROUP BY time(10s)`), query.ExecutionOptions{
+		OrgID:       orgID,
+		MaxBucketsN: 3,
+	})); !reflect.DeepEqual(a, []*query.Result{
+		{
+			StatementID: 0,
+			Err:         errors.New("max-select-buckets limit exceeded: (4/3)"),
+		},
+	}) {
+		t.Fatalf("unexpected results: %s", spew.Sdump(a))
+	}
+
+	// A statement without a lower time bound only reads the default time range.
+	now := time.Now()
+	ReadAllResults(e.Executor.ExecuteQuery(context.Background(), MustParseQuery(`SELECT value FROM cpu`), query.ExecutionOptions{
+		OrgID:            orgID,
+		DefaultTimeRange: time.Hour,
+	}))
+	if want := now.Add(-time.Hour); minTime.Before(want) || minTime.After(time.Now().Add(-time.Hour)) {
+		t.Fatalf("unexpected min time: got %s, want about %s", minTime, want)
+	}
+}
+
 func TestStatementExecutor_NormalizeStatement(t *testing.T) {
 
 	testCases := []struct {
@@ -386,6 +507,84 @@ func TestQueryExecutor_ExecuteQuery_ShowDatabases(t *testing.T) {
 	}
 }
 
+// Ensure the cardinality of a database can be estimated by users allowed to read it.
+func TestQueryExecutor_ExecuteQuery_ShowCardinalityEstimation(t *testing.T) {
+	orgID := platform.ID(0xff00)
+	bucketID := platform.ID(0xffee)
+
+	for _, tt := range []struct {
+		query       string
+		permissions []influxdb.Permission
+		exp         *query.Result
+	}{
+		{
+			query: "SHOW SERIES CARDINALITY",
+			permissions: []influxdb.Permission{
+				*itesting.MustNewPermissionAtID(bucketID, influxdb.ReadAction, influxdb.BucketsResourceType, orgID),
+			},
+			exp: &query.Result{Series: []*models.Row{{
+				Columns: []string{"cardinality estimation"},
+				Values:  [][]interface{}{{int64(100)}},
+			}}},
+		},
+		{
+			query: "SHOW MEASUREMENT CARDINALITY",
+			permissions: []influxdb.Permission{
+				*itesting.MustNewPermissionAtID(bucketID, influxdb.ReadAction, influxdb.BucketsResourceType, orgID),
+			},
+			exp: &query.Result{Series: []*models.Row{{
+				Columns: []string{"cardinality estimation"},
+				Values:  [][]interface{}{{int64(3)}},
+			}}},
+		},
+		{
+			query: "SHOW SERIES CARDINALITY",
+			permissions: []influxdb.Permission{
+				*itesting.MustNewPermissionAtID(bucketID, influxdb.WriteAction, influxdb.BucketsResourceType, orgID),
+			},
+			exp: &query.Result{Err: errors.New("insufficient permissions")},
+		},
+	} {
+		t.Run(tt.query, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			dbrp := mocks.NewMockDBRPMappingService(ctrl)
+			db := "db0"
+			isDefault := true
+			filt := influxdb.DBRPMappingFilter{OrgID: &orgID, Database: &db, Default: &isDefault}
+			res := []*influxdb.DBRPMapping{{Database: db, OrganizationID: orgID, BucketID: bucketID, Default: isDefault}}
+			dbrp.EXPECT().
+				FindMany(gomock.Any(), filt).
+				Return(res, 1, nil)
+
+			qe := DefaultQueryExecutor(t, WithDBRP(dbrp))
+			qe.StatementNormalizer = qe.StatementExecutor
+			qe.TSDBStore.SeriesCardinalityFn = func(_ context.Context, database string) (int64, error) {
+				if database != bucketID.String() {
+					t.Fatalf("unexpected database: %s", database)
+				}
+				return 100, nil
+			}
+			qe.TSDBStore.MeasurementsCardinalityFn = func(_ context.Context, database string) (int64, error) {
+				return 3, nil
+			}
+
+			ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{
+				ID:          orgID,
+				OrgID:       orgID,
+				Status:      influxdb.Active,
+				Permissions: tt.permissions,
+			})
+
+			results := ReadAllResults(qe.ExecuteQuery(ctx, tt.query, "db0", 0, orgID))
+			if exp := []*query.Result{tt.exp}; !reflect.DeepEqual(results, exp) {
+				t.Fatalf("unexpected results: exp %s, got %s", spew.Sdump(exp), spew.Sdump(results))
+			}
+		})
+	}
+}
+
 func testExecDeleteSeriesOrDropMeasurement(t *testing.T, qType string) {
 	orgID := platform.ID(0xff00)
 	otherOrgID := platform.ID(0xff01)
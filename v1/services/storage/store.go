@@ -649,6 +649,10 @@ func (s *Store) tagValuesSlow(ctx context.Context, mqAttrs *metaqueryAttributes,
 	return cursors.NewStringSliceIterator(names), nil
 }
 
+// cardinalityCheckInterval is the number of series counted between checks
+// for the cancellation of a series cardinality request.
+const cardinalityCheckInterval = 1000
+
 func (s *Store) ReadSeriesCardinality(ctx context.Context, req *datatypes.ReadSeriesCardinalityRequest) (cursors.Int64Iterator, error) {
 	if req.ReadSource == nil {
 		return nil, ErrMissingReadSource
@@ -735,9 +739,17 @@ func (s *Store) seriesCardinalityWithPredicate(ctx context.Context, shards []*ts
 	if err != nil {
 		return nil, err
 	}
+	defer cur.Close()
 
 	buf := make([]byte, 1024)
-	for {
+	for i := 0; ; i++ {
+		// Stop counting when the query is canceled, there may be a very large number of series.
+		if i%cardinalityCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
 		r := cur.Next()
 		if r == nil {
 			break
@@ -746,7 +758,7 @@ func (s *Store) seriesCardinalityWithPredicate(ctx context.Context, shards []*ts
 		ss.Add(skey)
 	}
 
-	return ss, nil
+	return ss, cur.Err()
 }
 
 func (s *Store) seriesCardinalityWithPredicateAndTime(ctx context.Context, shards []*tsdb.Shard, expr influxql.Expr, sfile *tsdb.SeriesFile, start, end int64) (*tsdb.SeriesIDSet, error) {
@@ -762,7 +774,14 @@ func (s *Store) seriesCardinalityWithPredicateAndTime(ctx context.Context, shard
 
 	buf := make([]byte, 1024)
 	rs := reads.NewFilteredResultSet(ctx, start, end, cur)
-	for rs.Next() {
+	defer rs.Close()
+	for i := 0; rs.Next(); i++ {
+		if i%cardinalityCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
 		func() {
 			c := rs.Cursor()
 			if c == nil {
@@ -779,7 +798,10 @@ func (s *Store) seriesCardinalityWithPredicateAndTime(ctx context.Context, shard
 		}()
 	}
 
-	return ss, nil
+	if err := rs.Err(); err != nil {
+		return nil, err
+	}
+	return ss, cur.Err()
 }
 
 func (s *Store) SupportReadSeriesCardinality(ctx context.Context) bool {
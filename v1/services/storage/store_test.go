@@ -1,11 +1,19 @@
 package storage
 
 import (
+	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/influxdata/influxdb/v2/models"
+	"github.com/influxdata/influxdb/v2/tsdb"
+	_ "github.com/influxdata/influxdb/v2/tsdb/engine"
+	_ "github.com/influxdata/influxdb/v2/tsdb/index"
 	"github.com/influxdata/influxdb/v2/v1/services/meta"
+	"github.com/influxdata/influxql"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 )
 
 func TestGroupShardsByTime(t *testing.T) {
@@ -123,3 +131,40 @@ func TestGroupShardsByTime(t *testing.T) {
 		require.Equal(t, tt.wantNotInRange, gotNotInRange)
 	}
 }
+
+func TestStore_SeriesCardinalityWithPredicate(t *testing.T) {
+	dir := t.TempDir()
+	ts := tsdb.NewStore(dir)
+	ts.EngineOptions.Config.WALDir = filepath.Join(dir, "wal")
+	ts.WithLogger(zaptest.NewLogger(t))
+	require.NoError(t, ts.Open(context.Background()))
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateShard(context.Background(), "db0", "rp0", 1, true))
+	points, err := models.ParsePointsString("cpu,host=a v=1 10\ncpu,host=b v=1 10\nmem,host=a v=1 10\n")
+	require.NoError(t, err)
+	require.NoError(t, ts.WriteToShard(context.Background(), 1, points))
+
+	s := &Store{TSDBStore: ts}
+	shards := ts.Shards([]uint64{1})
+	expr := influxql.MustParseExpr(`"_name"::tag = 'cpu'`)
+
+	for _, count := range []func(ctx context.Context) (*tsdb.SeriesIDSet, error){
+		func(ctx context.Context) (*tsdb.SeriesIDSet, error) {
+			return s.seriesCardinalityWithPredicate(ctx, shards, expr, ts.SeriesFile("db0"))
+		},
+		func(ctx context.Context) (*tsdb.SeriesIDSet, error) {
+			return s.seriesCardinalityWithPredicateAndTime(ctx, shards, expr, ts.SeriesFile("db0"), 0, 20)
+		},
+	} {
+		ss, err := count(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), ss.Cardinality())
+
+		// Counting stops once the query is canceled.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = count(ctx)
+		require.Equal(t, context.Canceled, err)
+	}
+}